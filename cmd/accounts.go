@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/datateamsix/email-sentinel/internal/accounts"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 	"github.com/datateamsix/email-sentinel/internal/ui"
 )
@@ -29,14 +30,21 @@ Email Sentinel automatically detects account-related emails and tracks:
 Available Commands:
   list     List all accounts or filter by type
   search   Search for a specific service
-  remove   Remove an account by ID
+  cancel   Open the cancellation page for a subscription or trial
+  review   Confirm, edit or delete detected accounts interactively
+  remove   Remove an account by ID (alias: forget)
+  merge    Merge two duplicate accounts into one
+  spending Show monthly and annual subscription costs
+  export   Export accounts to CSV or JSON
   refresh  Re-scan Gmail to detect accounts
 
 Examples:
   email-sentinel accounts list
   email-sentinel accounts list --trials
+  email-sentinel accounts list --expiring 7
   email-sentinel accounts list --paid
-  email-sentinel accounts search netflix`,
+  email-sentinel accounts search netflix
+  email-sentinel accounts review`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
@@ -54,6 +62,8 @@ func formatAccount(acc storage.Account, index int) string {
 	statusIcon := "✅"
 	if acc.Status == "cancelled" {
 		statusIcon = "❌"
+	} else if acc.Status == "expired" {
+		statusIcon = "⌛"
 	}
 
 	// Type icon
@@ -93,7 +103,7 @@ func formatAccount(acc storage.Account, index int) string {
 
 	// Show price if available
 	if acc.PriceMonthly > 0 {
-		sb.WriteString(fmt.Sprintf("  $%.2f/mo", acc.PriceMonthly))
+		sb.WriteString(fmt.Sprintf("  %s/mo", accounts.FormatPrice(acc.PriceMonthly, acc.Currency)))
 	}
 
 	sb.WriteString("\n")
@@ -118,7 +128,7 @@ func formatAccount(acc storage.Account, index int) string {
 }
 
 // formatAccountSummary formats a summary of accounts
-func formatAccountSummary(accounts []Account, totalSpend float64) string {
+func formatAccountSummary(accounts []Account, totalSpend []storage.CurrencySpend) string {
 	var sb strings.Builder
 
 	trialCount := 0
@@ -162,13 +172,22 @@ func formatAccountSummary(accounts []Account, totalSpend float64) string {
 	sb.WriteString(fmt.Sprintf("   Free: %d\n", freeCount))
 	sb.WriteString(fmt.Sprintf("   Emails used: %d\n", len(emailsUsed)))
 
-	if totalSpend > 0 {
-		sb.WriteString(fmt.Sprintf("\n💰 Total: $%.2f/month ($%.2f/year)\n", totalSpend, totalSpend*12))
+	if len(totalSpend) > 0 {
+		sb.WriteString(fmt.Sprintf("\n💰 Total: %s/month (%s/year)\n", formatSpend(totalSpend, 1), formatSpend(totalSpend, 12)))
 	}
 
 	return sb.String()
 }
 
+// formatSpend renders per-currency totals over a number of months, e.g. "$24.98 + €12.00"
+func formatSpend(spends []storage.CurrencySpend, months int) string {
+	parts := make([]string, 0, len(spends))
+	for _, cs := range spends {
+		parts = append(parts, accounts.FormatPrice(cs.Monthly*float64(months), cs.Currency))
+	}
+	return strings.Join(parts, " + ")
+}
+
 // Account type for display (mirrors storage.Account)
 type Account struct {
 	ID             int64
@@ -177,6 +196,7 @@ type Account struct {
 	AccountType    string
 	Status         string
 	PriceMonthly   float64
+	Currency       string
 	TrialEndDate   *time.Time
 	DetectedAt     time.Time
 	Category       string
@@ -0,0 +1,148 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/accounts"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+var cancelPrintOnly bool
+
+// accountsCancelCmd represents the accounts cancel command
+var accountsCancelCmd = &cobra.Command{
+	Use:   "cancel <service|id>",
+	Short: "Open the cancellation page for a subscription or trial",
+	Long: `Open the cancellation link captured for an account in your browser.
+
+The account is looked up by service name (case-insensitive) or by the ID
+shown in 'accounts list'. Only https links to a public website are opened.
+If no link was captured from the service's emails, a known account page is
+suggested for common services.
+
+Once the service sends its cancellation confirmation, Email Sentinel marks
+the account as cancelled automatically.
+
+Examples:
+  email-sentinel accounts cancel netflix
+  email-sentinel accounts cancel 3
+  email-sentinel accounts cancel spotify --print   # Show the link without opening it`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		db, err := storage.InitDB()
+		if err != nil {
+			fmt.Printf("%s Failed to initialize database: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+		defer storage.CloseDB(db)
+
+		acc, matches, err := findAccountToCancel(db, args[0])
+		if err != nil {
+			fmt.Printf("%s Failed to look up account: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+		if acc == nil {
+			if len(matches) == 0 {
+				fmt.Printf("%s No accounts found matching '%s'\n", ui.ColorYellow.Sprint("ℹ"), args[0])
+				return
+			}
+
+			fmt.Printf("%s '%s' matches %d accounts - pick one by ID:\n\n", ui.ColorYellow.Sprint("ℹ"), args[0], len(matches))
+			for _, m := range matches {
+				fmt.Printf("  [%d] %s <%s> (%s, %s)\n", m.ID, ui.ColorBold.Sprint(m.ServiceName), m.EmailAddress, m.AccountType, m.Status)
+			}
+			fmt.Printf("\nExample: email-sentinel accounts cancel %d\n", matches[0].ID)
+			return
+		}
+
+		fmt.Printf("\n%s %s <%s>\n", ui.ColorBold.Sprint("🛑"), ui.ColorBold.Sprint(acc.ServiceName), ui.ColorCyan.Sprint(acc.EmailAddress))
+		if acc.Status != "active" {
+			fmt.Printf("   %s\n", ui.ColorYellow.Sprintf("Already marked %s", acc.Status))
+		}
+
+		link := acc.CancelURL
+		if link != "" {
+			if err := accounts.ValidateCancelURL(link); err != nil {
+				fmt.Printf("%s The captured cancellation link was not opened: %v\n", ui.ColorRed.Sprint("✗"), err)
+				fmt.Printf("   Link: %s\n", ui.ColorGray.Sprint(link))
+				link = ""
+			}
+		}
+
+		if link == "" {
+			if page, ok := accounts.ManagePageFor(acc.ServiceName); ok {
+				fmt.Printf("%s No cancellation link was captured from %s's emails.\n", ui.ColorYellow.Sprint("ℹ"), acc.ServiceName)
+				fmt.Printf("   Manage the subscription at: %s\n", ui.ColorCyan.Sprint(page))
+			} else {
+				fmt.Printf("%s No cancellation link found for %s.\n", ui.ColorYellow.Sprint("ℹ"), acc.ServiceName)
+				fmt.Println("   Look for an account or billing page on the service's website, or search your email:")
+				fmt.Printf("   %s\n", ui.ColorCyan.Sprint(gmailSearchLink(acc.ServiceName+" cancel")))
+			}
+			return
+		}
+
+		fmt.Printf("   Cancel: %s\n", ui.ColorCyan.Sprint(link))
+		if cancelPrintOnly {
+			return
+		}
+
+		if err := accounts.OpenCancelURL(link); err != nil {
+			fmt.Printf("%s %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+		fmt.Printf("%s Opened the cancellation page in your browser\n", ui.ColorGreen.Sprint("✓"))
+	},
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsCancelCmd)
+	accountsCancelCmd.Flags().BoolVar(&cancelPrintOnly, "print", false, "Print the cancellation link instead of opening it")
+}
+
+// findAccountToCancel resolves a service name or account ID to one account
+// Returns nil with the candidates when a name matches several accounts and none exactly.
+func findAccountToCancel(db *sql.DB, query string) (*storage.Account, []storage.Account, error) {
+	if id, err := strconv.ParseInt(query, 10, 64); err == nil {
+		acc, err := storage.GetAccountByID(db, id)
+		if err != nil || acc == nil {
+			return acc, nil, err
+		}
+		return acc, []storage.Account{*acc}, nil
+	}
+
+	matches, err := storage.SearchAccounts(db, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exact []storage.Account
+	for _, m := range matches {
+		if strings.EqualFold(m.ServiceName, query) {
+			exact = append(exact, m)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return &matches[0], matches, nil
+	case len(exact) == 1:
+		return &exact[0], matches, nil
+	default:
+		return nil, matches, nil
+	}
+}
+
+// gmailSearchLink returns a Gmail web link that searches for query
+func gmailSearchLink(query string) string {
+	return "https://mail.google.com/mail/u/0/#search/" + url.QueryEscape(query)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+var (
+	accountsExportFormat string
+	accountsExportOutput string
+)
+
+// accountExportColumns defines the CSV header order
+var accountExportColumns = []string{
+	"service_name", "email_address", "account_type", "status",
+	"price_monthly", "currency", "trial_end_date", "category", "cancel_url",
+}
+
+// accountExportRecord is the exported representation of an account
+type accountExportRecord struct {
+	ServiceName  string  `json:"service_name"`
+	EmailAddress string  `json:"email_address"`
+	AccountType  string  `json:"account_type"`
+	Status       string  `json:"status"`
+	PriceMonthly float64 `json:"price_monthly"`
+	Currency     string  `json:"currency"`
+	TrialEndDate string  `json:"trial_end_date"` // ISO 8601 date, empty if none
+	Category     string  `json:"category"`
+	CancelURL    string  `json:"cancel_url"`
+}
+
+// accountsExportCmd represents the accounts export command
+var accountsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export accounts to CSV or JSON",
+	Long: `Export all tracked accounts for use in a spreadsheet or budgeting app.
+
+Writes to stdout by default, or to a file with --output.
+
+Examples:
+  email-sentinel accounts export --format csv --output accounts.csv
+  email-sentinel accounts export --format json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := strings.ToLower(accountsExportFormat)
+		if format != "csv" && format != "json" {
+			fmt.Printf("%s Invalid format '%s' (expected csv or json)\n", ui.ColorRed.Sprint("✗"), accountsExportFormat)
+			return
+		}
+
+		// Initialize database
+		db, err := storage.InitDB()
+		if err != nil {
+			fmt.Printf("%s Failed to initialize database: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+		defer storage.CloseDB(db)
+
+		accounts, err := storage.GetAllAccounts(db)
+		if err != nil {
+			fmt.Printf("%s Failed to get accounts: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+
+		var out io.Writer = os.Stdout
+		if accountsExportOutput != "" {
+			file, err := os.OpenFile(accountsExportOutput, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+			if err != nil {
+				fmt.Printf("%s Failed to create %s: %v\n", ui.ColorRed.Sprint("✗"), accountsExportOutput, err)
+				return
+			}
+			defer file.Close()
+			out = file
+		}
+
+		if format == "csv" {
+			err = writeAccountsCSV(out, accounts)
+		} else {
+			err = writeAccountsJSON(out, accounts)
+		}
+		if err != nil {
+			fmt.Printf("%s Failed to export accounts: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+
+		if accountsExportOutput != "" {
+			fmt.Printf("%s Exported %d account(s) to %s\n", ui.ColorGreen.Sprint("✓"), len(accounts), accountsExportOutput)
+		}
+	},
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsExportCmd)
+	accountsExportCmd.Flags().StringVarP(&accountsExportFormat, "format", "f", "csv", "Export format: csv or json")
+	accountsExportCmd.Flags().StringVarP(&accountsExportOutput, "output", "o", "", "Write to this file instead of stdout")
+}
+
+// toExportRecord converts a stored account to its export form
+func toExportRecord(acc storage.Account) accountExportRecord {
+	trialEnd := ""
+	if acc.TrialEndDate != nil {
+		trialEnd = acc.TrialEndDate.Format(time.RFC3339)
+	}
+
+	return accountExportRecord{
+		ServiceName:  acc.ServiceName,
+		EmailAddress: acc.EmailAddress,
+		AccountType:  acc.AccountType,
+		Status:       acc.Status,
+		PriceMonthly: acc.PriceMonthly,
+		Currency:     acc.Currency,
+		TrialEndDate: trialEnd,
+		Category:     acc.Category,
+		CancelURL:    acc.CancelURL,
+	}
+}
+
+// writeAccountsCSV writes accounts as CSV (fields with commas/quotes are quoted)
+func writeAccountsCSV(w io.Writer, accounts []storage.Account) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(accountExportColumns); err != nil {
+		return err
+	}
+
+	for _, acc := range accounts {
+		rec := toExportRecord(acc)
+		row := []string{
+			rec.ServiceName,
+			rec.EmailAddress,
+			rec.AccountType,
+			rec.Status,
+			strconv.FormatFloat(rec.PriceMonthly, 'f', 2, 64),
+			rec.Currency,
+			rec.TrialEndDate,
+			rec.Category,
+			rec.CancelURL,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeAccountsJSON writes accounts as an indented JSON array
+func writeAccountsJSON(w io.Writer, accounts []storage.Account) error {
+	records := make([]accountExportRecord, 0, len(accounts))
+	for _, acc := range accounts {
+		records = append(records, toExportRecord(acc))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
@@ -4,7 +4,11 @@ Copyright © 2025 Datateamsix <research@dt6.io>
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -16,8 +20,18 @@ var (
 	listTrialsOnly bool
 	listPaidOnly   bool
 	listFreeOnly   bool
+	listExpiring   int
+	listJSON       bool
 )
 
+// accountJSON is the stable --json representation of an account
+type accountJSON struct {
+	ID int64 `json:"id"`
+	accountExportRecord
+	Confidence float64 `json:"confidence"`
+	DetectedAt string  `json:"detected_at"` // RFC 3339
+}
+
 // accountsListCmd represents the accounts list command
 var accountsListCmd = &cobra.Command{
 	Use:   "list",
@@ -27,8 +41,15 @@ var accountsListCmd = &cobra.Command{
 Examples:
   email-sentinel accounts list              # Show all accounts
   email-sentinel accounts list --trials     # Show only trials
-  email-sentinel accounts list --paid       # Show only paid subscriptions`,
+  email-sentinel accounts list --paid       # Show only paid subscriptions
+  email-sentinel accounts list --expiring 7 # Trials ending in the next 7 days
+  email-sentinel accounts list --json       # Print accounts as JSON`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if cmd.Flags().Changed("expiring") && listExpiring <= 0 {
+			fmt.Printf("%s --expiring must be a positive number of days\n", ui.ColorRed.Sprint("✗"))
+			return
+		}
+
 		// Initialize database
 		db, err := storage.InitDB()
 		if err != nil {
@@ -40,7 +61,9 @@ Examples:
 		var accounts []storage.Account
 
 		// Determine which accounts to show
-		if listTrialsOnly {
+		if listExpiring > 0 {
+			accounts, err = storage.GetTrialsExpiringWithin(db, listExpiring)
+		} else if listTrialsOnly {
 			accounts, err = storage.GetAccountsByType(db, "trial")
 		} else if listPaidOnly {
 			accounts, err = storage.GetAccountsByType(db, "paid")
@@ -55,6 +78,19 @@ Examples:
 			return
 		}
 
+		if listJSON {
+			ui.DisableColors()
+			if err := writeAccountsListJSON(os.Stdout, accounts); err != nil {
+				fmt.Fprintf(os.Stderr, "%s Failed to write accounts: %v\n", ui.ColorRed.Sprint("✗"), err)
+			}
+			return
+		}
+
+		if len(accounts) == 0 && listExpiring > 0 {
+			fmt.Println(ui.ColorGreen.Sprintf("No trials ending in the next %d day(s).", listExpiring))
+			return
+		}
+
 		if len(accounts) == 0 {
 			fmt.Println(ui.ColorYellow.Sprint("No accounts found."))
 			fmt.Println("\nEmail Sentinel will automatically detect accounts as you receive emails.")
@@ -65,12 +101,14 @@ Examples:
 		// Get total monthly spend
 		totalSpend, err := storage.GetTotalMonthlySpend(db)
 		if err != nil {
-			totalSpend = 0
+			totalSpend = nil
 		}
 
 		// Display header
 		title := "All Accounts"
-		if listTrialsOnly {
+		if listExpiring > 0 {
+			title = fmt.Sprintf("Trials Ending in the Next %d Day(s)", listExpiring)
+		} else if listTrialsOnly {
 			title = "Trial Accounts"
 		} else if listPaidOnly {
 			title = "Paid Subscriptions"
@@ -91,6 +129,7 @@ Examples:
 				AccountType:    acc.AccountType,
 				Status:         acc.Status,
 				PriceMonthly:   acc.PriceMonthly,
+				Currency:       acc.Currency,
 				TrialEndDate:   acc.TrialEndDate,
 				DetectedAt:     acc.DetectedAt,
 				Category:       acc.Category,
@@ -107,6 +146,7 @@ Examples:
 				AccountType:    acc.AccountType,
 				Status:         acc.Status,
 				PriceMonthly:   acc.PriceMonthly,
+				Currency:       acc.Currency,
 				TrialEndDate:   acc.TrialEndDate,
 				DetectedAt:     acc.DetectedAt,
 				Category:       acc.Category,
@@ -125,4 +165,24 @@ func init() {
 	accountsListCmd.Flags().BoolVar(&listTrialsOnly, "trials", false, "Show only trial accounts")
 	accountsListCmd.Flags().BoolVar(&listPaidOnly, "paid", false, "Show only paid subscriptions")
 	accountsListCmd.Flags().BoolVar(&listFreeOnly, "free", false, "Show only free accounts")
+	accountsListCmd.Flags().IntVar(&listExpiring, "expiring", 0, "Show only active trials ending within this many days, soonest first")
+	accountsListCmd.Flags().BoolVar(&listJSON, "json", false, "Print accounts as a JSON array")
+	accountsListCmd.MarkFlagsMutuallyExclusive("expiring", "paid", "free")
+}
+
+// writeAccountsListJSON writes accounts as an indented JSON array ([] when empty)
+func writeAccountsListJSON(w io.Writer, accounts []storage.Account) error {
+	records := make([]accountJSON, 0, len(accounts))
+	for _, acc := range accounts {
+		records = append(records, accountJSON{
+			ID:                  acc.ID,
+			accountExportRecord: toExportRecord(acc),
+			Confidence:          acc.Confidence,
+			DetectedAt:          acc.DetectedAt.Format(time.RFC3339),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
 }
@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+// accountsMergeCmd represents the accounts merge command
+var accountsMergeCmd = &cobra.Command{
+	Use:   "merge <id1> <id2>",
+	Short: "Merge two duplicate accounts into one",
+	Long: `Merge two accounts that describe the same service into a single entry.
+
+The first ID is kept and the second is deleted. The merged entry uses the
+service name and details of the higher-confidence detection, the latest
+detection and trial end dates, and whichever cancel URL is set.
+
+Example:
+  email-sentinel accounts list
+  email-sentinel accounts merge 3 7`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse IDs
+		keepID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Printf("%s Invalid account ID: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+		dropID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Printf("%s Invalid account ID: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+
+		// Initialize database
+		db, err := storage.InitDB()
+		if err != nil {
+			fmt.Printf("%s Failed to initialize database: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+		defer storage.CloseDB(db)
+
+		merged, err := storage.MergeAccounts(db, keepID, dropID)
+		if err != nil {
+			fmt.Printf("%s Failed to merge accounts: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+
+		fmt.Printf("%s Merged account #%d into #%d\n\n", ui.ColorGreen.Sprint("✓"), dropID, keepID)
+		fmt.Print(formatAccount(*merged, int(merged.ID)))
+	},
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsMergeCmd)
+}
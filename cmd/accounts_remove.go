@@ -15,15 +15,18 @@ import (
 
 // accountsRemoveCmd represents the accounts remove command
 var accountsRemoveCmd = &cobra.Command{
-	Use:   "remove <id>",
-	Short: "Remove an account by ID",
+	Use:     "remove <id>",
+	Aliases: []string{"forget"},
+	Short:   "Remove an account by ID",
 	Long: `Remove an account from the database by its ID.
 
-The ID is shown in brackets when you list accounts.
+The ID is shown in brackets when you list accounts. Use this to forget
+accounts that were misdetected from marketing or unrelated emails.
 
 Example:
   email-sentinel accounts list
-  email-sentinel accounts remove 3`,
+  email-sentinel accounts remove 3
+  email-sentinel accounts forget 3`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse ID
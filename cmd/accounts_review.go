@@ -0,0 +1,43 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+var reviewLimit int
+
+// accountsReviewCmd represents the accounts review command
+var accountsReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Interactively confirm, fix or delete detected accounts",
+	Long: `Walk through recently detected accounts that haven't been confirmed yet.
+
+For each account you can:
+  c  Confirm the detection as correct
+  e  Edit the service name and monthly price
+  r  Recategorize it (streaming, software, cloud, ...)
+  d  Delete a misdetection
+  s  Skip it for now
+  q  Stop reviewing
+
+Confirmed and edited accounts are marked as verified and won't come up
+again. Fixing names and prices here keeps 'accounts spending' accurate.
+
+Examples:
+  email-sentinel accounts review
+  email-sentinel accounts review --limit 10`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.RunAccountsReview(reviewLimit)
+	},
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsReviewCmd)
+	accountsReviewCmd.Flags().IntVar(&reviewLimit, "limit", 25, "Maximum number of accounts to review")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/accounts"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+// accountsSpendingCmd represents the accounts spending command
+var accountsSpendingCmd = &cobra.Command{
+	Use:   "spending",
+	Short: "Show subscription spending totals",
+	Long: `Show monthly and projected annual spending on active paid subscriptions,
+broken down by category. Prices in different currencies are totalled
+separately rather than added together.
+
+Trials with a known price are listed separately as upcoming charges
+that will apply if they are not cancelled.
+
+Example:
+  email-sentinel accounts spending`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize database
+		db, err := storage.InitDB()
+		if err != nil {
+			fmt.Printf("%s Failed to initialize database: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+		defer storage.CloseDB(db)
+
+		monthly, err := storage.GetMonthlySpend(db)
+		if err != nil {
+			fmt.Printf("%s Failed to calculate spending: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+
+		categories, err := storage.GetSpendByCategory(db)
+		if err != nil {
+			fmt.Printf("%s Failed to calculate spending: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+
+		trials, err := storage.GetPricedTrials(db)
+		if err != nil {
+			fmt.Printf("%s Failed to get trials: %v\n", ui.ColorRed.Sprint("✗"), err)
+			return
+		}
+
+		ui.PrintSection("Subscription Spending")
+		if len(monthly) == 0 {
+			ui.PrintKeyValue("Monthly total", accounts.FormatPrice(0, accounts.DefaultCurrency))
+		}
+		for _, cs := range monthly {
+			ui.PrintKeyValue(spendLabel("Monthly total", cs.Currency, len(monthly)), accounts.FormatPrice(cs.Monthly, cs.Currency))
+			ui.PrintKeyValue(spendLabel("Projected annual", cs.Currency, len(monthly)), accounts.FormatPrice(cs.Monthly*12, cs.Currency))
+		}
+		fmt.Println()
+
+		if len(categories) > 0 {
+			ui.PrintSubsection("By Category")
+			rows := make([][]string, 0, len(categories))
+			for _, cs := range categories {
+				rows = append(rows, []string{
+					cs.Category,
+					strconv.Itoa(cs.Count),
+					accounts.FormatPrice(cs.Monthly, cs.Currency),
+					accounts.FormatPrice(cs.Monthly*12, cs.Currency),
+				})
+			}
+			ui.PrintTable([]string{"Category", "Accounts", "Monthly", "Annual"}, rows)
+			fmt.Println()
+		}
+
+		if len(trials) > 0 {
+			ui.PrintSubsection("Upcoming Charges If Not Cancelled")
+			var upcoming []storage.CurrencySpend
+			rows := make([][]string, 0, len(trials))
+			for _, trial := range trials {
+				ends := "unknown"
+				if trial.TrialEndDate != nil {
+					ends = trial.TrialEndDate.Format("2006-01-02")
+				}
+				rows = append(rows, []string{
+					trial.ServiceName,
+					ends,
+					accounts.FormatPrice(trial.PriceMonthly, trial.Currency),
+				})
+				upcoming = addSpend(upcoming, trial.Currency, trial.PriceMonthly)
+			}
+			ui.PrintTable([]string{"Trial", "Ends", "Monthly"}, rows)
+			fmt.Println()
+			for _, cs := range upcoming {
+				ui.PrintKeyValue(spendLabel("Would add", cs.Currency, len(upcoming)), accounts.FormatPrice(cs.Monthly, cs.Currency)+"/month")
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// spendLabel names a total, adding the currency when there is more than one
+func spendLabel(label, currency string, currencies int) string {
+	if currencies > 1 {
+		return fmt.Sprintf("%s (%s)", label, currency)
+	}
+	return label
+}
+
+// addSpend adds amount to the running total for its currency
+func addSpend(spends []storage.CurrencySpend, currency string, amount float64) []storage.CurrencySpend {
+	for i := range spends {
+		if spends[i].Currency == currency {
+			spends[i].Monthly += amount
+			spends[i].Count++
+			return spends
+		}
+	}
+	return append(spends, storage.CurrencySpend{Currency: currency, Monthly: amount, Count: 1})
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsSpendingCmd)
+}
@@ -13,18 +13,28 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
 var (
-	filterName    string
-	filterFrom    string
-	filterSubject string
-	filterMatch   string
-	filterLabels  string
-	filterScope   string
-	filterExpires string
+	filterName       string
+	filterFrom       string
+	filterSubject    string
+	filterBody       string
+	filterMatch      string
+	filterMatchType  string
+	filterLabels     string
+	filterScope      string
+	filterExpires    string
+	filterNtfyTopic  string
+	filterGmailLabel string
+	filterGmailQuery string
+	filterMarkRead   bool
+	filterDigest     string
+	filterPriority   int
+	filterAttachment bool
 )
 
 var addCmd = &cobra.Command{
@@ -32,7 +42,7 @@ var addCmd = &cobra.Command{
 	Short: "Add a new email filter",
 	Long: `Add a new filter to match incoming emails.
 
-You can filter by sender (from), subject line keywords, or both.
+You can filter by sender (from), subject line keywords, body text, or any combination.
 When using both, choose whether ALL conditions must match (AND) 
 or ANY condition triggers a match (OR).
 
@@ -50,7 +60,34 @@ Examples:
   email-sentinel filter add --name "Job Alerts" --from "linkedin.com" --subject "interview" --match all
 
   # Either sender OR subject matches (default)
-  email-sentinel filter add --name "Recruiter" --from "greenhouse.io,lever.co" --subject "opportunity" --match any`,
+  email-sentinel filter add --name "Recruiter" --from "greenhouse.io,lever.co" --subject "opportunity" --match any
+
+  # Match text anywhere in the message body
+  email-sentinel filter add --name "Verification" --body "verify your account"
+
+  # Push this filter's alerts to its own ntfy topic
+  email-sentinel filter add --name "Work" --from "company.com" --ntfy-topic "work-alerts-x7k2"
+
+  # Apply a Gmail label to matches (requires monitoring.gmail.allow_modify)
+  email-sentinel filter add --name "Invoices" --subject "invoice" --apply-label "Sentinel/Invoices"
+
+  # Mark matches as read once alerted, e.g. one-time codes (requires monitoring.gmail.allow_modify)
+  email-sentinel filter add --name "Codes" --subject "verification code" --mark-read
+
+  # Batch a noisy filter's notifications into one summary every 15 minutes
+  email-sentinel filter add --name "Newsletters" --from "substack.com" --digest 15m
+
+  # Treat every match as critical (bypasses quiet hours with allow_urgent), regardless of priority rules
+  email-sentinel filter add --name "School" --from "school.edu" --force-priority 2
+
+  # Only alert when the email has an attachment (ANDed with the patterns)
+  email-sentinel filter add --name "Invoices" --subject "invoice" --has-attachment
+
+  # Let Gmail do the matching with search operators (ANDed with the scope)
+  email-sentinel filter add --name "Big Attachments" --gmail-query "has:attachment larger:5M from:(boss@co.com)"
+
+  # Regex patterns instead of substrings
+  email-sentinel filter add --name "Greenhouse" --from "jobs-[0-9]+@greenhouse\.io" --match-type regex`,
 	Run: runFilterAdd,
 }
 
@@ -60,9 +97,18 @@ func init() {
 	addCmd.Flags().StringVarP(&filterName, "name", "n", "", "Filter name")
 	addCmd.Flags().StringVarP(&filterFrom, "from", "f", "", "Sender patterns (comma-separated)")
 	addCmd.Flags().StringVarP(&filterSubject, "subject", "s", "", "Subject patterns (comma-separated)")
+	addCmd.Flags().StringVar(&filterBody, "body", "", "Body text patterns (comma-separated)")
 	addCmd.Flags().StringVarP(&filterMatch, "match", "m", "any", "Match mode: 'any' (OR) or 'all' (AND)")
+	addCmd.Flags().StringVar(&filterMatchType, "match-type", "contains", "Pattern type: 'contains' (substring) or 'regex'")
 	addCmd.Flags().StringVarP(&filterLabels, "labels", "l", "", "Labels/categories (comma-separated, e.g., work,urgent)")
 	addCmd.Flags().StringVar(&filterScope, "scope", "inbox", "Gmail scope: inbox, all, primary, social, promotions, updates, forums, primary+social, all-except-trash")
+	addCmd.Flags().StringVar(&filterGmailQuery, "gmail-query", "", "Raw Gmail search operators ANDed with the scope (e.g. 'has:attachment larger:5M')")
+	addCmd.Flags().StringVar(&filterNtfyTopic, "ntfy-topic", "", "ntfy.sh topic for this filter (default: global mobile topic)")
+	addCmd.Flags().StringVar(&filterGmailLabel, "apply-label", "", "Gmail label to apply to matching messages (requires monitoring.gmail.allow_modify)")
+	addCmd.Flags().BoolVar(&filterMarkRead, "mark-read", false, "Mark matching messages as read in Gmail (requires monitoring.gmail.allow_modify)")
+	addCmd.Flags().StringVar(&filterDigest, "digest", "", "Send one summary notification per interval instead of one per match (e.g. 5m, 1h)")
+	addCmd.Flags().BoolVar(&filterAttachment, "has-attachment", false, "Only match emails with an attachment (--has-attachment=false: only without)")
+	addCmd.Flags().IntVar(&filterPriority, "force-priority", 0, "Force match priority: 0 (normal), 1 (high) or 2 (critical) instead of priority rules")
 	addCmd.Flags().StringVarP(&filterExpires, "expires", "e", "", "Expiration: 1d, 7d, 30d, 60d, 90d, YYYY-MM-DD, or 'never' (default: never)")
 }
 
@@ -107,15 +153,17 @@ func runFilterAdd(cmd *cobra.Command, args []string) {
 		filterSubject = strings.TrimSpace(filterSubject)
 	}
 
-	// Validate at least one pattern
-	if filterFrom == "" && filterSubject == "" {
-		fmt.Println("\n❌ At least one 'from' or 'subject' pattern is required")
+	// Validate at least one pattern (a Gmail query or attachment condition alone is enough)
+	filterGmailQuery = strings.TrimSpace(filterGmailQuery)
+	if filterFrom == "" && filterSubject == "" && filterBody == "" && filterGmailQuery == "" && !cmd.Flags().Changed("has-attachment") {
+		fmt.Println("\n❌ At least one 'from', 'subject' or 'body' pattern, a --gmail-query or --has-attachment is required")
 		os.Exit(1)
 	}
 
 	// Parse comma-separated values
 	fromPatterns := parseCSV(filterFrom)
 	subjectPatterns := parseCSV(filterSubject)
+	bodyPatterns := parseCSV(filterBody)
 
 	// Get match mode (only ask if both from and subject are specified)
 	if !cmd.Flags().Changed("match") && len(fromPatterns) > 0 && len(subjectPatterns) > 0 && interactive {
@@ -215,13 +263,40 @@ func runFilterAdd(cmd *cobra.Command, args []string) {
 
 	// Create filter
 	f := filter.Filter{
-		Name:       filterName,
-		From:       fromPatterns,
-		Subject:    subjectPatterns,
-		Match:      filterMatch,
-		Labels:     labelsList,
-		GmailScope: filterScope,
-		ExpiresAt:  expiresAt,
+		Name:            filterName,
+		From:            fromPatterns,
+		Subject:         subjectPatterns,
+		Body:            bodyPatterns,
+		Match:           filterMatch,
+		MatchType:       strings.ToLower(strings.TrimSpace(filterMatchType)),
+		Labels:          labelsList,
+		GmailScope:      filterScope,
+		GmailQuery:      filterGmailQuery,
+		NtfyTopic:       strings.TrimSpace(filterNtfyTopic),
+		ApplyGmailLabel: strings.TrimSpace(filterGmailLabel),
+		MarkRead:        filterMarkRead,
+		Digest:          strings.TrimSpace(filterDigest),
+		ExpiresAt:       expiresAt,
+	}
+	if cmd.Flags().Changed("force-priority") {
+		priority := filterPriority
+		f.ForcePriority = &priority
+	}
+	if cmd.Flags().Changed("has-attachment") {
+		hasAttachment := filterAttachment
+		f.HasAttachment = &hasAttachment
+	}
+
+	// Reject bad match types and regexes up front instead of never matching
+	if err := filter.ValidatePatterns(f); err != nil {
+		fmt.Printf("\n❌ Invalid filter patterns: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Overlaps are only a warning: a broader and a narrower filter can both be intended
+	var overlaps []filter.Overlap
+	if existing, err := filter.ListFilters(); err == nil {
+		overlaps = filter.DetectOverlap(existing, f)
 	}
 
 	// Save filter
@@ -243,14 +318,37 @@ func runFilterAdd(cmd *cobra.Command, args []string) {
 	fmt.Println()
 	printFilter(f)
 
+	if len(overlaps) > 0 {
+		fmt.Println("\n⚠️  Overlaps with existing filters (consider merging or narrowing them):")
+		for _, o := range overlaps {
+			fmt.Printf("   • %s\n", o)
+		}
+	}
+
+	if f.ApplyGmailLabel != "" || f.MarkRead {
+		if appCfg, err := appconfig.Load(); err == nil && !appCfg.Monitoring.Gmail.AllowModify {
+			fmt.Println("\n⚠️  Gmail changes are disabled (email-sentinel is read-only by default)")
+			fmt.Println("   Enable with: email-sentinel init --reauth --scopes modify")
+		}
+	}
+
 	// Reset flags for next use
 	filterName = ""
 	filterFrom = ""
 	filterSubject = ""
+	filterBody = ""
 	filterMatch = "any"
+	filterMatchType = "contains"
 	filterLabels = ""
 	filterScope = "inbox"
 	filterExpires = ""
+	filterNtfyTopic = ""
+	filterGmailLabel = ""
+	filterGmailQuery = ""
+	filterMarkRead = false
+	filterDigest = ""
+	filterPriority = 0
+	filterAttachment = false
 }
 
 func parseCSV(s string) []string {
@@ -279,6 +377,9 @@ func printFilter(f filter.Filter) {
 	if len(f.Subject) > 0 {
 		fmt.Printf("  Subject: %s\n", strings.Join(f.Subject, ", "))
 	}
+	if len(f.Body) > 0 {
+		fmt.Printf("  Body:    %s\n", strings.Join(f.Body, ", "))
+	}
 	if len(f.Labels) > 0 {
 		fmt.Printf("  Labels:  %s\n", strings.Join(f.Labels, ", "))
 	}
@@ -289,6 +390,10 @@ func printFilter(f filter.Filter) {
 	}
 	fmt.Printf("  Match:   %s\n", matchDesc)
 
+	if f.MatchType == filter.MatchTypeRegex {
+		fmt.Printf("  Type:    regex\n")
+	}
+
 	// Show Gmail scope if not default
 	scope := f.GmailScope
 	if scope == "" {
@@ -296,10 +401,58 @@ func printFilter(f filter.Filter) {
 	}
 	fmt.Printf("  Scope:   %s\n", scope)
 
+	if f.GmailQuery != "" {
+		fmt.Printf("  Query:   %s\n", f.GmailQuery)
+	}
+
+	if f.NtfyTopic != "" {
+		fmt.Printf("  Topic:   %s\n", f.NtfyTopic)
+	}
+
+	if f.ApplyGmailLabel != "" {
+		fmt.Printf("  Gmail:   label '%s'\n", f.ApplyGmailLabel)
+	}
+
+	if f.MarkRead {
+		fmt.Printf("  Gmail:   mark as read\n")
+	}
+
+	if f.Digest != "" {
+		fmt.Printf("  Digest:  every %s\n", f.Digest)
+	}
+
+	if f.ForcePriority != nil {
+		fmt.Printf("  Urgency: %s\n", forcedPriorityDesc(*f.ForcePriority))
+	}
+
+	if f.HasAttachment != nil {
+		fmt.Printf("  Attach:  %s\n", attachmentDesc(*f.HasAttachment))
+	}
+
 	// Show expiration
 	fmt.Printf("  Expires: %s\n", filter.FormatExpiration(f.ExpiresAt))
 }
 
+// attachmentDesc describes a filter's has_attachment condition
+func attachmentDesc(hasAttachment bool) string {
+	if hasAttachment {
+		return "only emails with attachments"
+	}
+	return "only emails without attachments"
+}
+
+// forcedPriorityDesc describes a filter's force_priority setting
+func forcedPriorityDesc(priority int) string {
+	switch priority {
+	case storage.PriorityCritical:
+		return "forced critical (ignores priority rules)"
+	case storage.PriorityHigh:
+		return "forced high (ignores priority rules)"
+	default:
+		return "forced normal (ignores priority rules)"
+	}
+}
+
 // getDB initializes and returns a database connection
 func getDB() (*sql.DB, error) {
 	return storage.InitDB()
@@ -322,20 +475,8 @@ func normalizeGmailScope(scope string) string {
 		return "inbox"
 	}
 
-	// Valid single scopes
-	validScopes := []string{
-		"inbox", "all", "primary", "social", "promotions",
-		"updates", "forums", "all-except-trash", "spam-only",
-	}
-
-	for _, valid := range validScopes {
-		if scope == valid {
-			return scope
-		}
-	}
-
-	// Check for combined scopes (e.g., "primary+social")
-	if strings.Contains(scope, "+") {
+	// Single scopes and "+" combinations like "primary+social"
+	if filter.IsValidGmailScope(scope) {
 		return scope
 	}
 
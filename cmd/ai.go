@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// aiCmd represents the ai command
+var aiCmd = &cobra.Command{
+	Use:   "ai",
+	Short: "Work with AI email summaries",
+	Long: `Work with AI-powered email summaries.
+
+Summaries are normally generated live while monitoring (see ai_summary in
+app-config.yaml). These commands let you generate them on demand.
+
+Available Commands:
+  check      Verify the AI provider's API key and model
+  summarize  Summarize stored alerts
+  usage      Show AI token usage per provider
+
+Examples:
+  email-sentinel ai check
+  email-sentinel ai usage
+  email-sentinel ai summarize 18c2f4a9b1e3d7f0
+  email-sentinel ai summarize --filter "Job Alerts" --last 5`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aiCmd)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/ai"
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+)
+
+var aiCheckProvider string
+
+// aiCheckCmd represents the ai check command
+var aiCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify the AI provider's API key and model",
+	Long: `Verify that the configured AI provider can be reached.
+
+Makes one lightweight call to the provider (fetching the configured model's
+details, which uses no tokens) and reports whether the API key and model
+name work. Local Ollama servers are checked for the configured model.
+
+Works even while ai_summary.enabled is false, so you can test a key before
+turning summaries on. Use --provider to check a provider other than the
+configured one.
+
+Examples:
+  email-sentinel ai check
+  email-sentinel ai check --provider claude`,
+	Args: cobra.NoArgs,
+	Run:  runAICheck,
+}
+
+func init() {
+	aiCmd.AddCommand(aiCheckCmd)
+
+	aiCheckCmd.Flags().StringVar(&aiCheckProvider, "provider", "", "Provider to check: claude, openai, gemini or ollama (default: configured provider)")
+}
+
+func runAICheck(cmd *cobra.Command, args []string) {
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	aiConfig := createAIConfigFromAppConfig(appCfg)
+	if aiCheckProvider != "" {
+		aiConfig.AISummary.Provider = strings.ToLower(aiCheckProvider)
+	}
+	provider := aiConfig.AISummary.Provider
+
+	if !appCfg.AISummary.Enabled {
+		fmt.Println("ℹ️  AI summaries are disabled in app-config.yaml; checking the provider anyway")
+	}
+
+	// Validate as if enabled so a missing key or model is reported
+	aiConfig.AISummary.Enabled = true
+	if envVar := ai.APIKeyEnvVar(provider); envVar != "" && os.Getenv(envVar) == "" {
+		fmt.Printf("❌ %s is not set\n", envVar)
+		fmt.Printf("\nSet your %s API key first:\n  export %s=your-key\n", provider, envVar)
+		os.Exit(1)
+	}
+	if err := aiConfig.Validate(); err != nil {
+		fmt.Printf("❌ AI configuration error: %v\n", err)
+		os.Exit(1)
+	}
+
+	p, err := ai.NewProvider(aiConfig)
+	if err != nil {
+		fmt.Printf("❌ AI configuration error: %v\n", err)
+		os.Exit(1)
+	}
+
+	model := aiCheckModel(aiConfig)
+	fmt.Printf("🔍 Checking %s (%s)...\n", provider, model)
+
+	if err := p.Check(context.Background()); err != nil {
+		fmt.Printf("❌ %s check failed: %v\n", provider, err)
+		os.Exit(1)
+	}
+
+	if provider == "ollama" {
+		fmt.Printf("✅ Ollama is running and %s is available\n", model)
+	} else {
+		fmt.Printf("✅ %s API key works and %s is available\n", provider, model)
+	}
+}
+
+// aiCheckModel returns the model configured for the selected provider
+func aiCheckModel(cfg *ai.Config) string {
+	switch strings.ToLower(cfg.AISummary.Provider) {
+	case "claude":
+		return cfg.AISummary.API.Claude.Model
+	case "openai":
+		return cfg.AISummary.API.OpenAI.Model
+	case "ollama":
+		return cfg.AISummary.API.Ollama.Model
+	default:
+		return cfg.AISummary.API.Gemini.Model
+	}
+}
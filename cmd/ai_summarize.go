@@ -0,0 +1,196 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/ai"
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+var (
+	aiSummarizeFilter string
+	aiSummarizeLast   int
+)
+
+// aiSummarizeCmd represents the ai summarize command
+var aiSummarizeCmd = &cobra.Command{
+	Use:   "summarize [message-id]",
+	Short: "Generate AI summaries for stored alerts",
+	Long: `Generate an AI summary for alerts that are already in history.
+
+Useful when AI summaries were enabled after the alerts arrived. The full
+email body is fetched from Gmail, summarized with the configured provider,
+and saved so it shows up alongside the alert.
+
+Pick alerts either by Gmail message ID or by filter name. Alerts that
+already have a summary reuse it instead of calling the provider again.
+
+Requires ai_summary.enabled in app-config.yaml and the API key for the
+configured provider (GEMINI_API_KEY, ANTHROPIC_API_KEY or OPENAI_API_KEY).
+
+Examples:
+  email-sentinel ai summarize 18c2f4a9b1e3d7f0
+  email-sentinel ai summarize --filter "Job Alerts"
+  email-sentinel ai summarize --filter "Job Alerts" --last 5`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAISummarize,
+}
+
+func init() {
+	aiCmd.AddCommand(aiSummarizeCmd)
+
+	aiSummarizeCmd.Flags().StringVar(&aiSummarizeFilter, "filter", "", "Summarize recent alerts from this filter")
+	aiSummarizeCmd.Flags().IntVar(&aiSummarizeLast, "last", 1, "With --filter, number of most recent alerts to summarize")
+}
+
+func runAISummarize(cmd *cobra.Command, args []string) {
+	if (len(args) == 0) == (aiSummarizeFilter == "") {
+		fmt.Println("❌ Specify either a message ID or --filter NAME")
+		fmt.Println("\nUsage: email-sentinel ai summarize <message-id>")
+		fmt.Println("       email-sentinel ai summarize --filter NAME [--last N]")
+		os.Exit(1)
+	}
+	if aiSummarizeLast < 1 {
+		fmt.Println("❌ --last must be at least 1")
+		os.Exit(1)
+	}
+
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !appCfg.AISummary.Enabled {
+		fmt.Println("❌ AI summaries are disabled")
+		fmt.Println("\nEnable them with:")
+		fmt.Println("  email-sentinel config set ai_summary.enabled true")
+		os.Exit(1)
+	}
+
+	aiConfig := createAIConfigFromAppConfig(appCfg)
+	if err := aiConfig.Validate(); err != nil {
+		fmt.Printf("❌ AI configuration error: %v\n", err)
+		if envVar := ai.APIKeyEnvVar(appCfg.AISummary.Provider); envVar != "" && os.Getenv(envVar) == "" {
+			fmt.Printf("\nSet your API key first:\n  export %s=your-key\n", envVar)
+		}
+		os.Exit(1)
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+	defer storage.CloseDB(db)
+
+	var alerts []storage.Alert
+	if len(args) == 1 {
+		alert, err := storage.GetAlertByMessageID(db, args[0])
+		if err != nil {
+			fmt.Printf("❌ Error loading alert: %v\n", err)
+			os.Exit(1)
+		}
+		if alert == nil {
+			fmt.Printf("❌ No alert found for message ID: %s\n", args[0])
+			fmt.Println("\nView alert history with: email-sentinel alerts")
+			os.Exit(1)
+		}
+		alerts = append(alerts, *alert)
+	} else {
+		alerts, err = storage.GetRecentAlertsByFilter(db, aiSummarizeFilter, aiSummarizeLast)
+		if err != nil {
+			fmt.Printf("❌ Error loading alerts: %v\n", err)
+			os.Exit(1)
+		}
+		if len(alerts) == 0 {
+			fmt.Printf("📭 No alerts found for filter: %s\n", aiSummarizeFilter)
+			return
+		}
+	}
+
+	aiService, err := ai.NewService(aiConfig, db)
+	if err != nil {
+		fmt.Printf("❌ Error starting AI service: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A Gmail client is only needed for the full body; fall back to snippets without it
+	client, err := newGmailClient(appCfg)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Gmail unavailable, summarizing snippets only: %v", err))
+	}
+
+	for _, alert := range alerts {
+		summarizeStoredAlert(aiService, client, alert)
+	}
+}
+
+// summarizeStoredAlert generates (or reuses) the summary for one alert and prints it
+func summarizeStoredAlert(aiService *ai.Service, client *gmail.Client, alert storage.Alert) {
+	ui.PrintSubsection(alert.Subject)
+	ui.PrintKeyValue("From", alert.Sender)
+	ui.PrintKeyValue("Filter", alert.FilterName)
+	ui.PrintKeyValue("Message ID", alert.MessageID)
+
+	if !aiService.ShouldSummarize(alert.Priority) {
+		ui.PrintInfo("Skipped: ai_summary.priority_only is enabled and this alert is not high priority")
+		return
+	}
+
+	body := ""
+	if client != nil {
+		fetched, err := client.GetMessageBody(alert.MessageID)
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Could not fetch email body, using snippet: %v", err))
+		} else {
+			body = fetched
+		}
+	}
+
+	summary, err := aiService.GenerateSummary(
+		context.Background(),
+		alert.MessageID,
+		alert.Sender,
+		alert.Subject,
+		body,
+		alert.Snippet,
+		alert.FilterLabels,
+		alert.Priority,
+	)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("AI summary failed: %v", err))
+		return
+	}
+	if summary == nil {
+		ui.PrintWarning("Skipped: AI rate limit or daily token budget reached, try again later")
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("  🤖 %s\n", summary.Summary)
+
+	if len(summary.Questions) > 0 {
+		ui.PrintSubsection("Questions")
+		for _, q := range summary.Questions {
+			ui.PrintBullet(q)
+		}
+	}
+
+	if len(summary.ActionItems) > 0 {
+		ui.PrintSubsection("Action Items")
+		for _, item := range summary.ActionItems {
+			ui.PrintBullet(item)
+		}
+	}
+}
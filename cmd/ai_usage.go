@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+// aiUsageCmd represents the ai usage command
+var aiUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show AI token usage per provider",
+	Long: `Show how many tokens AI summaries have used today and this month.
+
+Totals come from the summaries stored in the database and are broken down
+by provider. Summaries reused from the cache count as zero tokens.
+
+If ai_summary.daily_token_budget is set in app-config.yaml, summaries stop
+for the rest of the day once the active provider reaches it.
+
+Examples:
+  email-sentinel ai usage`,
+	Args: cobra.NoArgs,
+	Run:  runAIUsage,
+}
+
+func init() {
+	aiCmd.AddCommand(aiUsageCmd)
+}
+
+func runAIUsage(cmd *cobra.Command, args []string) {
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+	defer storage.CloseDB(db)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	todayUsage, err := storage.GetTokenUsageByProvider(db, today)
+	if err != nil {
+		fmt.Printf("❌ Error loading token usage: %v\n", err)
+		os.Exit(1)
+	}
+	monthUsage, err := storage.GetTokenUsageByProvider(db, month)
+	if err != nil {
+		fmt.Printf("❌ Error loading token usage: %v\n", err)
+		os.Exit(1)
+	}
+
+	ui.PrintSection(fmt.Sprintf("AI Token Usage: %s", now.Format("January 2006")))
+
+	if len(monthUsage) == 0 {
+		ui.PrintInfo("No AI summaries generated this month")
+	} else {
+		providers := make([]string, 0, len(monthUsage))
+		for p := range monthUsage {
+			providers = append(providers, p)
+		}
+		sort.Strings(providers)
+
+		rows := make([][]string, 0, len(providers)+1)
+		var totalToday, totalMonth int
+		for _, p := range providers {
+			rows = append(rows, []string{p, strconv.Itoa(todayUsage[p]), strconv.Itoa(monthUsage[p])})
+			totalToday += todayUsage[p]
+			totalMonth += monthUsage[p]
+		}
+		if len(providers) > 1 {
+			rows = append(rows, []string{"Total", strconv.Itoa(totalToday), strconv.Itoa(totalMonth)})
+		}
+		ui.PrintTable([]string{"Provider", "Today", "This Month"}, rows)
+	}
+	fmt.Println()
+
+	provider := appCfg.AISummary.Provider
+	if budget := appCfg.AISummary.DailyTokenBudget; budget > 0 {
+		used := todayUsage[provider]
+		ui.PrintKeyValue("Daily budget", fmt.Sprintf("%d / %d tokens (%s)", used, budget, provider))
+		if used >= budget {
+			ui.PrintWarning("Budget exhausted - summaries resume at midnight")
+		}
+	} else {
+		ui.PrintKeyValue("Daily budget", "unlimited (set ai_summary.daily_token_budget to cap it)")
+	}
+}
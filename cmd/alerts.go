@@ -4,12 +4,17 @@ Copyright © 2025 Datateamsix <research@dt6.io>
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
 )
 
 // alertsCmd represents the alerts command
@@ -19,23 +24,63 @@ var alertsCmd = &cobra.Command{
 	Long: `Display all email alerts that were triggered today.
 
 This shows notifications that were sent during the current monitoring session,
-allowing you to review missed alerts. The database is automatically wiped
-at 12:00 AM daily.
+allowing you to review missed alerts. By default the database is wiped
+at 12:00 AM daily; set monitoring.database.retention_days to keep history.
 
 Examples:
   # View today's alerts
   email-sentinel alerts
 
   # View last 5 alerts
-  email-sentinel alerts --recent 5`,
+  email-sentinel alerts --recent 5
+
+  # Machine-readable output for scripts
+  email-sentinel alerts --json | jq '.[].subject'
+
+  # Follow new alerts while testing filters
+  email-sentinel alerts tail
+
+  # Restore alerts saved to failed_alerts.log during a database outage
+  email-sentinel alerts recover`,
 	Run: runAlerts,
 }
 
-var recentLimit int
+var (
+	recentLimit int
+	alertsJSON  bool
+)
+
+// alertJSON is the stable --json representation of an alert
+type alertJSON struct {
+	ID           int64             `json:"id"`
+	Timestamp    string            `json:"timestamp"` // RFC 3339
+	Sender       string            `json:"sender"`
+	Subject      string            `json:"subject"`
+	Snippet      string            `json:"snippet"`
+	GmailLabels  []string          `json:"gmail_labels"`
+	MessageID    string            `json:"message_id"`
+	GmailLink    string            `json:"gmail_link"`
+	Filter       string            `json:"filter"`  // all matched filters, comma-separated
+	Filters      []string          `json:"filters"` // each matched filter
+	FilterLabels []string          `json:"filter_labels"`
+	Priority     int               `json:"priority"` // 1 = high, 0 = normal
+	AISummary    *alertSummaryJSON `json:"ai_summary,omitempty"`
+}
+
+// alertSummaryJSON is the stable --json representation of an AI summary
+type alertSummaryJSON struct {
+	Summary     string   `json:"summary"`
+	Questions   []string `json:"questions"`
+	ActionItems []string `json:"action_items"`
+	Provider    string   `json:"provider"`
+	Model       string   `json:"model"`
+	GeneratedAt string   `json:"generated_at"` // RFC 3339
+}
 
 func init() {
 	rootCmd.AddCommand(alertsCmd)
 	alertsCmd.Flags().IntVarP(&recentLimit, "recent", "r", 0, "Show only N most recent alerts (0 = all today)")
+	alertsCmd.Flags().BoolVar(&alertsJSON, "json", false, "Print alerts as a JSON array")
 }
 
 func runAlerts(cmd *cobra.Command, args []string) {
@@ -65,6 +110,15 @@ func runAlerts(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if alertsJSON {
+		ui.DisableColors()
+		if err := writeAlertsJSON(os.Stdout, alerts); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error writing alerts: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if len(alerts) == 0 {
 		if recentLimit > 0 {
 			fmt.Println("📭 No alerts found")
@@ -86,13 +140,17 @@ func runAlerts(cmd *cobra.Command, args []string) {
 	for i, alert := range alerts {
 		// Add priority indicator
 		priorityIcon := "📩" // Normal priority
-		if alert.Priority == 1 {
+		if alert.Priority >= storage.PriorityCritical {
+			priorityIcon = "🚨" // Critical priority
+		} else if alert.Priority == storage.PriorityHigh {
 			priorityIcon = "🔥" // High priority
 		}
 
 		fmt.Printf("[%d] %s %s\n", i+1, priorityIcon, alert.Timestamp.Format("2006-01-02 15:04:05"))
 		fmt.Printf("    Filter: %s\n", alert.FilterName)
-		if alert.Priority == 1 {
+		if alert.Priority >= storage.PriorityCritical {
+			fmt.Printf("    Priority: CRITICAL\n")
+		} else if alert.Priority == storage.PriorityHigh {
 			fmt.Printf("    Priority: HIGH\n")
 		}
 		fmt.Printf("    From:   %s\n", alert.Sender)
@@ -111,3 +169,69 @@ func runAlerts(cmd *cobra.Command, args []string) {
 		fmt.Println()
 	}
 }
+
+// toAlertJSON converts a stored alert to its --json form
+func toAlertJSON(alert storage.Alert) alertJSON {
+	out := alertJSON{
+		ID:           alert.ID,
+		Timestamp:    alert.Timestamp.Format(time.RFC3339),
+		Sender:       alert.Sender,
+		Subject:      alert.Subject,
+		Snippet:      alert.Snippet,
+		GmailLabels:  splitLabels(alert.Labels),
+		MessageID:    alert.MessageID,
+		GmailLink:    alert.GmailLink,
+		Filter:       alert.FilterName,
+		Filters:      alert.FilterNames(),
+		FilterLabels: alert.FilterLabels,
+		Priority:     alert.Priority,
+	}
+	if out.Filters == nil {
+		out.Filters = []string{}
+	}
+	if out.FilterLabels == nil {
+		out.FilterLabels = []string{}
+	}
+
+	if s := alert.AISummary; s != nil {
+		out.AISummary = &alertSummaryJSON{
+			Summary:     s.Summary,
+			Questions:   s.Questions,
+			ActionItems: s.ActionItems,
+			Provider:    s.Provider,
+			Model:       s.Model,
+			GeneratedAt: s.GeneratedAt.Format(time.RFC3339),
+		}
+		if out.AISummary.Questions == nil {
+			out.AISummary.Questions = []string{}
+		}
+		if out.AISummary.ActionItems == nil {
+			out.AISummary.ActionItems = []string{}
+		}
+	}
+
+	return out
+}
+
+// splitLabels turns the stored comma-separated Gmail labels into a list
+func splitLabels(labels string) []string {
+	out := []string{}
+	for _, label := range strings.Split(labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			out = append(out, label)
+		}
+	}
+	return out
+}
+
+// writeAlertsJSON writes alerts as an indented JSON array ([] when empty)
+func writeAlertsJSON(w io.Writer, alerts []storage.Alert) error {
+	records := make([]alertJSON, 0, len(alerts))
+	for _, alert := range alerts {
+		records = append(records, toAlertJSON(alert))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
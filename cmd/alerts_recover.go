@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+// alertsRecoverCmd represents the alerts recover command
+var alertsRecoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Restore alerts saved to failed_alerts.log into the database",
+	Long: `Restore alerts that couldn't be saved while the database was unavailable.
+
+When an alert can't be written to the database, the watcher appends it to
+failed_alerts.log in the config directory so it isn't lost. Run this once
+the database is healthy again to move those alerts back into history.
+
+Alerts already in the database (same Gmail message) are skipped. Lines in
+the old plain-text format, and alerts logged with redact_logs on (their
+sender and subject were not kept), can't be restored and are left in the log.
+
+The log is moved aside while it is processed, so a running watcher can keep
+appending to it safely.
+
+Examples:
+  email-sentinel alerts recover`,
+	Args: cobra.NoArgs,
+	Run:  runAlertsRecover,
+}
+
+func init() {
+	alertsCmd.AddCommand(alertsRecoverCmd)
+}
+
+func runAlertsRecover(cmd *cobra.Command, args []string) {
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening alert database: %v\n", err)
+		os.Exit(1)
+	}
+	defer storage.CloseDB(db)
+
+	logPath, _ := storage.FailureLogPath()
+
+	result, err := storage.RecoverFailedAlerts(db)
+	if err != nil {
+		fmt.Printf("❌ Error recovering alerts: %v\n", err)
+		if result != nil && result.Requeued > 0 {
+			fmt.Printf("   %d alert(s) remain in %s\n", result.Requeued, logPath)
+		}
+		os.Exit(1)
+	}
+
+	if result.Recovered+result.Skipped+result.Redacted+result.Unreadable == 0 {
+		fmt.Println("📭 No failed alerts to recover")
+		return
+	}
+
+	if result.Recovered+result.Skipped > 0 {
+		fmt.Printf("✅ Recovered %d alert(s), skipped %d already in the database\n", result.Recovered, result.Skipped)
+	}
+	if result.Redacted > 0 {
+		fmt.Printf("⚠️  %d alert(s) were logged with redact_logs on and were left in %s\n", result.Redacted, logPath)
+		fmt.Println("   Their sender and subject weren't kept; open them from the Gmail link in the log")
+	}
+	if result.Unreadable > 0 {
+		fmt.Printf("⚠️  %d line(s) in the old text format were left in %s\n", result.Unreadable, logPath)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+// alertsTailCmd represents the alerts tail command
+var alertsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Follow new alerts as they arrive",
+	Long: `Print new alerts as they are stored, like 'tail -f'.
+
+Alerts written by a separately running 'email-sentinel start' show up here
+within a couple of seconds, since both processes share the same database.
+This is a debugging aid for trying out filters; it doesn't check Gmail or
+send notifications itself. Press Ctrl+C to stop.
+
+Examples:
+  # Show the last 10 alerts, then follow new ones
+  email-sentinel alerts tail
+
+  # Only follow new alerts, checking every 5 seconds
+  email-sentinel alerts tail -n 0 --interval 5s`,
+	Run: runAlertsTail,
+}
+
+var (
+	tailLines    int
+	tailInterval time.Duration
+)
+
+func init() {
+	alertsCmd.AddCommand(alertsTailCmd)
+	alertsTailCmd.Flags().IntVarP(&tailLines, "lines", "n", 10, "Number of recent alerts to show before following")
+	alertsTailCmd.Flags().DurationVar(&tailInterval, "interval", 2*time.Second, "How often to check for new alerts")
+}
+
+func runAlertsTail(cmd *cobra.Command, args []string) {
+	if tailInterval < 100*time.Millisecond {
+		fmt.Println("❌ --interval must be at least 100ms")
+		os.Exit(1)
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening alert database: %v\n", err)
+		os.Exit(1)
+	}
+	defer storage.CloseDB(db)
+
+	// Follow from the last inserted alert, not the newest by received date
+	lastID, err := storage.GetLastAlertID(db)
+	if err != nil {
+		fmt.Printf("❌ Error reading alert database: %v\n", err)
+		os.Exit(1)
+	}
+
+	if tailLines > 0 {
+		backlog, err := storage.GetRecentAlerts(db, tailLines)
+		if err != nil {
+			fmt.Printf("❌ Error fetching recent alerts: %v\n", err)
+			os.Exit(1)
+		}
+		// GetRecentAlerts is newest first; print oldest first like tail
+		for i := len(backlog) - 1; i >= 0; i-- {
+			// Alerts saved since lastID was read are printed by the follow loop
+			if backlog[i].ID <= lastID {
+				printTailAlert(backlog[i])
+			}
+		}
+	}
+
+	fmt.Println(ui.ColorDim.Sprintf("── following new alerts (Ctrl+C to stop) ──"))
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(tailInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigChan:
+			fmt.Println()
+			return
+		case <-ticker.C:
+			alerts, err := storage.GetAlertsAfter(db, lastID)
+			if err != nil {
+				// The monitor may hold a write lock briefly; try again next tick
+				fmt.Println(ui.ColorYellow.Sprintf("⚠️  %v", err))
+				continue
+			}
+			for _, alert := range alerts {
+				printTailAlert(alert)
+				lastID = alert.ID
+			}
+		}
+	}
+}
+
+// printTailAlert prints one alert as a compact, priority-colored block
+func printTailAlert(alert storage.Alert) {
+	icon, c := "📩", ui.ColorCyan
+	if alert.Priority >= storage.PriorityCritical {
+		icon, c = "🚨", ui.ColorRed
+	} else if alert.Priority == storage.PriorityHigh {
+		icon, c = "🔥", ui.ColorYellow
+	}
+
+	fmt.Printf("%s %s %s %s\n",
+		ui.ColorDim.Sprint(alert.Timestamp.Format("15:04:05")),
+		icon,
+		c.Sprintf("[%s]", alert.FilterName),
+		ui.ColorBold.Sprint(alert.Subject))
+	fmt.Printf("         From: %s\n", alert.Sender)
+	if alert.Snippet != "" {
+		fmt.Println(ui.ColorDim.Sprintf("         %s", gmail.TruncateWords(alert.Snippet, 100)))
+	}
+}
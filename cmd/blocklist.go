@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+)
+
+// blocklistCmd represents the blocklist command
+var blocklistCmd = &cobra.Command{
+	Use:   "blocklist",
+	Short: "Manage senders that are never processed",
+	Long: `Manage senders and domains that email-sentinel ignores completely.
+
+Mail from a blocklisted sender skips account detection, filters, AI
+summaries and alerts. Entries with an @ and a name part are matched as
+exact addresses; anything else is a domain and also covers its subdomains.
+Matching is case-insensitive.
+
+The lists live under monitoring in app-config.yaml. To process ONLY
+certain senders instead, set monitoring.allowlist_senders or
+monitoring.allowlist_domains there.
+
+Changes take effect the next time monitoring starts.
+
+Available Commands:
+  add     Add a sender or domain to the blocklist
+  remove  Remove a sender or domain from the blocklist
+  list    Show the blocklist and allowlist
+
+Examples:
+  email-sentinel blocklist add newsletter@example.com
+  email-sentinel blocklist add promos.example.com
+  email-sentinel blocklist remove newsletter@example.com
+  email-sentinel blocklist list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var blocklistAddCmd = &cobra.Command{
+	Use:   "add <sender-or-domain>",
+	Short: "Add a sender or domain to the blocklist",
+	Args:  cobra.ExactArgs(1),
+	Run:   runBlocklistAdd,
+}
+
+var blocklistRemoveCmd = &cobra.Command{
+	Use:   "remove <sender-or-domain>",
+	Short: "Remove a sender or domain from the blocklist",
+	Args:  cobra.ExactArgs(1),
+	Run:   runBlocklistRemove,
+}
+
+var blocklistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the blocklist and allowlist",
+	Run:   runBlocklistList,
+}
+
+func init() {
+	rootCmd.AddCommand(blocklistCmd)
+	blocklistCmd.AddCommand(blocklistAddCmd)
+	blocklistCmd.AddCommand(blocklistRemoveCmd)
+	blocklistCmd.AddCommand(blocklistListCmd)
+}
+
+func runBlocklistAdd(cmd *cobra.Command, args []string) {
+	appCfg := loadAppConfigOrExit()
+
+	added, err := appCfg.Monitoring.AddToBlocklist(args[0])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if !added {
+		fmt.Printf("ℹ️  %s is already blocklisted\n", args[0])
+		return
+	}
+
+	if err := appconfig.Save(appCfg); err != nil {
+		fmt.Printf("❌ Error saving configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("🚫 Blocklisted: %s\n", args[0])
+	fmt.Println("   Restart monitoring to apply: email-sentinel start")
+}
+
+func runBlocklistRemove(cmd *cobra.Command, args []string) {
+	appCfg := loadAppConfigOrExit()
+
+	if !appCfg.Monitoring.RemoveFromBlocklist(args[0]) {
+		fmt.Printf("❌ %s is not blocklisted\n", args[0])
+		fmt.Println("\nView the blocklist with: email-sentinel blocklist list")
+		os.Exit(1)
+	}
+
+	if err := appconfig.Save(appCfg); err != nil {
+		fmt.Printf("❌ Error saving configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Removed from blocklist: %s\n", args[0])
+	fmt.Println("   Restart monitoring to apply: email-sentinel start")
+}
+
+func runBlocklistList(cmd *cobra.Command, args []string) {
+	appCfg := loadAppConfigOrExit()
+	m := appCfg.Monitoring
+
+	if len(m.BlocklistSenders)+len(m.BlocklistDomains) == 0 {
+		fmt.Println("📭 Blocklist is empty")
+		fmt.Println("\nAdd a sender with: email-sentinel blocklist add newsletter@example.com")
+	} else {
+		fmt.Println("🚫 Blocklist")
+		printSenderList("Senders", m.BlocklistSenders)
+		printSenderList("Domains", m.BlocklistDomains)
+	}
+
+	if len(m.AllowlistSenders)+len(m.AllowlistDomains) > 0 {
+		fmt.Println("\n✅ Allowlist (only these senders are processed)")
+		printSenderList("Senders", m.AllowlistSenders)
+		printSenderList("Domains", m.AllowlistDomains)
+	}
+}
+
+// printSenderList prints one labelled sender or domain list, skipping empty ones
+func printSenderList(label string, entries []string) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Printf("\n  %s:\n", label)
+	for _, entry := range entries {
+		fmt.Printf("    - %s\n", entry)
+	}
+}
+
+// loadAppConfigOrExit loads app-config.yaml or exits with an error
+func loadAppConfigOrExit() *appconfig.AppConfig {
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+	return appCfg
+}
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/config"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 )
@@ -22,15 +24,29 @@ var configCmd = &cobra.Command{
 
 Subcommands:
   show      Display current configuration
+  list      List all app-config keys and values
+  get       Print a single app-config value
   set       Modify configuration values
+  validate  Check app-config.yaml for unknown keys and bad values
+
+Keys with dots (e.g. notifications.mobile.topic) refer to app-config.yaml.
 
 Examples:
   # Show current config
   email-sentinel config show
 
+  # List every app-config key
+  email-sentinel config list
+
+  # Read a single value
+  email-sentinel config get monitoring.polling_interval
+
   # Set polling interval
   email-sentinel config set polling 30
 
+  # Enable AI summaries
+  email-sentinel config set ai_summary.enabled true
+
   # Enable mobile notifications
   email-sentinel config set mobile true
 
@@ -57,25 +73,51 @@ var configSetCmd = &cobra.Command{
 	Short: "Set a configuration value",
 	Long: `Set a configuration value.
 
-Available keys:
+Dotted keys update app-config.yaml. Values are checked against the
+key's type (int, bool, number, string, or a comma-separated list).
+Run 'email-sentinel config list' to see every available key.
+
+Legacy keys (config.yaml):
   polling          Polling interval in seconds (default: 45)
   desktop          Enable/disable desktop notifications (true/false)
   mobile           Enable/disable mobile notifications (true/false)
   ntfy_topic       Set ntfy.sh topic for mobile notifications
 
 Examples:
+  email-sentinel config set notifications.mobile.topic "my-secret-topic"
+  email-sentinel config set monitoring.polling_interval 60
+  email-sentinel config set ai_summary.enabled true
   email-sentinel config set polling 60
-  email-sentinel config set desktop false
-  email-sentinel config set mobile true
-  email-sentinel config set ntfy_topic "my-secret-topic"`,
+  email-sentinel config set desktop false`,
 	Args: cobra.ExactArgs(2),
 	Run:  runConfigSet,
 }
 
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: `Print the current value of an app-config key.
+
+Examples:
+  email-sentinel config get notifications.mobile.topic
+  email-sentinel config get monitoring.polling_interval
+  email-sentinel config get ai_summary.enabled`,
+	Args: cobra.ExactArgs(1),
+	Run:  runConfigGet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all app-config keys and values",
+	Run:   runConfigList,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configListCmd)
 }
 
 func runConfigShow(cmd *cobra.Command, args []string) {
@@ -105,6 +147,11 @@ func runConfigSet(cmd *cobra.Command, args []string) {
 	key := args[0]
 	value := args[1]
 
+	if strings.Contains(key, ".") {
+		runAppConfigSet(key, value)
+		return
+	}
+
 	cfg, err := filter.LoadConfig()
 	if err != nil {
 		fmt.Printf("❌ Error loading config: %v\n", err)
@@ -156,6 +203,8 @@ func runConfigSet(cmd *cobra.Command, args []string) {
 	default:
 		fmt.Printf("❌ Unknown config key: %s\n", key)
 		fmt.Println("\nAvailable keys: polling, desktop, mobile, ntfy_topic")
+		fmt.Println("For app-config keys use dotted names, e.g. notifications.mobile.topic")
+		fmt.Println("Run 'email-sentinel config list' to see all keys")
 		os.Exit(1)
 	}
 
@@ -165,3 +214,76 @@ func runConfigSet(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 }
+
+func runAppConfigSet(key, value string) {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading app config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := appconfig.SetValue(cfg, key, value); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := cfg.Monitoring.GetLocation(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	// Reject out-of-range values for the key being set
+	for _, issue := range cfg.Validate() {
+		if issue.Key == key {
+			fmt.Printf("❌ %s\n", issue)
+			os.Exit(1)
+		}
+	}
+
+	if err := appconfig.Save(cfg); err != nil {
+		fmt.Printf("❌ Error saving app config: %v\n", err)
+		os.Exit(1)
+	}
+
+	newValue, _ := appconfig.GetValue(cfg, key)
+	fmt.Printf("✅ Set %s to %s\n", key, newValue)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading app config: %v\n", err)
+		os.Exit(1)
+	}
+
+	value, err := appconfig.GetValue(cfg, args[0])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(value)
+}
+
+func runConfigList(cmd *cobra.Command, args []string) {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading app config: %v\n", err)
+		os.Exit(1)
+	}
+
+	configPath, _ := appconfig.ConfigPath()
+
+	fmt.Println("\n⚙️  App Configuration Keys")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("\nConfig File: %s\n\n", configPath)
+
+	for _, kv := range appconfig.ListKeys(cfg) {
+		value := kv.Value
+		if value == "" {
+			value = "(empty)"
+		}
+		fmt.Printf("%-48s %-7s %s\n", kv.Key, kv.Type, value)
+	}
+	fmt.Println("")
+}
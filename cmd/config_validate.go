@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+)
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check app-config.yaml for unknown keys and bad values",
+	Long: `Strictly check app-config.yaml.
+
+While monitoring, email-sentinel ignores keys it doesn't know, so a typo
+like 'poling_interval' silently leaves the default in place. This command
+reports:
+  - unknown keys (with their line numbers)
+  - out-of-range values (e.g. polling_interval below 10, temperature above 2)
+  - durations, times and enums that can't be parsed
+  - a schema_version newer than this build understands
+
+Exits with status 1 if any problem is found.
+
+Examples:
+  email-sentinel config validate`,
+	Args: cobra.NoArgs,
+	Run:  runConfigValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) {
+	configPath, _ := appconfig.ConfigPath()
+	if !appconfig.ConfigExists() {
+		fmt.Printf("❌ %s not found (run 'email-sentinel config migrate' or 'email-sentinel init')\n", configPath)
+		os.Exit(1)
+	}
+
+	cfg, issues, err := appconfig.LoadStrict()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("🔍 Checking %s\n\n", configPath)
+
+	if cfg.SchemaVersion == 0 {
+		fmt.Printf("ℹ️  No schema_version yet; it is stamped (%d) the next time the config is saved\n\n", appconfig.CurrentSchemaVersion)
+	}
+
+	if len(issues) == 0 {
+		fmt.Println("✅ Configuration is valid")
+		return
+	}
+
+	for _, issue := range issues {
+		fmt.Printf("  ❌ %s\n", issue)
+	}
+	fmt.Printf("\n%d problem(s) found\n", len(issues))
+	os.Exit(1)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 	"github.com/spf13/cobra"
 )
@@ -20,12 +21,13 @@ var dbBackupCmd = &cobra.Command{
 The backup is created using SQLite's VACUUM INTO command, which produces
 a clean, defragmented copy of the database.
 
-Backups are stored in:
+Backups are stored in monitoring.backup.directory, or by default:
   - Windows: %APPDATA%\email-sentinel\backups\
   - macOS: ~/Library/Application Support/email-sentinel/backups/
   - Linux: ~/.config/email-sentinel/backups/
 
-The application automatically keeps the last 5 backups and removes older ones.
+Only the newest monitoring.backup.keep_last backups (default 5) are kept;
+older ones are removed.
 
 Example:
   email-sentinel db backup`,
@@ -40,17 +42,40 @@ Example:
 		}
 		defer storage.CloseDB(db)
 
+		appCfg, err := appconfig.Load()
+		if err != nil {
+			fmt.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		opts, err := backupOptions(appCfg)
+		if err != nil {
+			fmt.Printf("❌ Invalid monitoring.backup.directory: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Create backup
-		if err := storage.BackupDatabase(db); err != nil {
+		path, err := storage.BackupDatabase(db, opts)
+		if err != nil {
 			fmt.Printf("❌ Backup failed: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("\n✅ Backup completed successfully!")
-		fmt.Println("💡 Tip: Backups are created automatically on application startup")
+		fmt.Printf("\n✅ Backup completed successfully: %s\n", path)
+		if appCfg.Monitoring.Backup.Enabled {
+			fmt.Println("💡 Tip: Backups are also created automatically when monitoring starts")
+		}
 	},
 }
 
 func init() {
 	dbCmd.AddCommand(dbBackupCmd)
 }
+
+// backupOptions returns the database backup settings from monitoring.backup
+func backupOptions(appCfg *appconfig.AppConfig) (storage.BackupOptions, error) {
+	dir, err := appCfg.Monitoring.Backup.GetDirectory()
+	if err != nil {
+		return storage.BackupOptions{}, err
+	}
+	return storage.BackupOptions{Dir: dir, KeepLast: appCfg.Monitoring.Backup.KeepLast}, nil
+}
@@ -134,12 +134,6 @@ func runFilterEdit(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	// Validate at least one pattern
-	if len(selectedFilter.From) == 0 && len(selectedFilter.Subject) == 0 {
-		fmt.Println("\n❌ At least one 'from' or 'subject' pattern is required")
-		os.Exit(1)
-	}
-
 	// Edit labels/categories
 	currentLabels := strings.Join(selectedFilter.Labels, ", ")
 	if currentLabels == "" {
@@ -189,6 +183,30 @@ func runFilterEdit(cmd *cobra.Command, args []string) {
 		selectedFilter.GmailScope = normalizeGmailScope(input)
 	}
 
+	// Edit Gmail query operators
+	currentQuery := selectedFilter.GmailQuery
+	if currentQuery == "" {
+		currentQuery = "(none)"
+	}
+	fmt.Printf("\nGmail Query [%s]: ", currentQuery)
+	fmt.Println("\n   Gmail search operators ANDed with the scope, e.g. has:attachment larger:5M")
+	fmt.Print("   Enter new value: ")
+	input, _ = reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input != "" {
+		if input == "-" || input == "none" {
+			selectedFilter.GmailQuery = ""
+		} else {
+			selectedFilter.GmailQuery = input
+		}
+	}
+
+	// Validate at least one pattern or Gmail query
+	if len(selectedFilter.From) == 0 && len(selectedFilter.Subject) == 0 && len(selectedFilter.Body) == 0 && selectedFilter.GmailQuery == "" {
+		fmt.Println("\n❌ At least one 'from', 'subject' or 'body' pattern or a Gmail query is required")
+		os.Exit(1)
+	}
+
 	// Edit match mode (only if both from and subject exist)
 	if len(selectedFilter.From) > 0 && len(selectedFilter.Subject) > 0 {
 		fmt.Printf("\nMatch mode - 'any' (OR) or 'all' (AND) [%s]: ", selectedFilter.Match)
@@ -204,6 +222,27 @@ func runFilterEdit(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Edit notification digest
+	currentDigest := selectedFilter.Digest
+	if currentDigest == "" {
+		currentDigest = "off"
+	}
+	fmt.Printf("\nNotification Digest [%s]: ", currentDigest)
+	fmt.Println("\n   Batch this filter's notifications into one summary per interval, e.g. 5m or 1h ('off' to notify each match)")
+	fmt.Print("   Enter new value: ")
+	input, _ = reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input != "" {
+		if input == "-" || input == "none" || input == "off" {
+			selectedFilter.Digest = ""
+		} else if err := filter.ValidateDigest(input); err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		} else {
+			selectedFilter.Digest = input
+		}
+	}
+
 	// Edit expiration
 	currentExpiration := filter.FormatExpiration(selectedFilter.ExpiresAt)
 	fmt.Printf("\nExpiration [%s]: ", currentExpiration)
@@ -21,12 +21,24 @@ Available Commands:
   list    List all filters
   edit    Edit an existing filter
   remove  Remove a filter
+  enable  Resume a disabled filter
+  disable Pause a filter without deleting it
+  move    Move a filter up or down (for first-match routing)
+  report  Show per-filter match counts to find unused or noisy filters
+  export  Export filters to JSON
+  import  Import filters from JSON
+  test    Test filters against sample or recent real emails
 
 Examples:
   email-sentinel filter add --name "Jobs" --from "linkedin.com"
   email-sentinel filter list
   email-sentinel filter edit "Jobs"
-  email-sentinel filter remove "Jobs"`,
+  email-sentinel filter remove "Jobs"
+  email-sentinel filter disable "Jobs"
+  email-sentinel filter move "Jobs" up
+  email-sentinel filter test --live
+  email-sentinel filter report --days 30
+  email-sentinel filter export --output filters.json`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
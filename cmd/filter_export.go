@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/filter"
+)
+
+var exportOutput string
+
+var filterExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all filters to JSON",
+	Long: `Export all configured filters to JSON for backup or sharing.
+
+Writes to stdout by default, or to a file with --output.
+
+Examples:
+  email-sentinel filter export
+  email-sentinel filter export --output filters.json`,
+	Run: runFilterExport,
+}
+
+func init() {
+	filterCmd.AddCommand(filterExportCmd)
+
+	filterExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write filters to this file instead of stdout")
+}
+
+func runFilterExport(cmd *cobra.Command, args []string) {
+	if exportOutput == "" {
+		if err := filter.ExportFilters(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error exporting filters: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	file, err := os.OpenFile(exportOutput, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		fmt.Printf("❌ Error creating %s: %v\n", exportOutput, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if err := filter.ExportFilters(file); err != nil {
+		fmt.Printf("❌ Error exporting filters: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Filters exported to %s\n", exportOutput)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/filter"
+)
+
+var (
+	importMerge   bool
+	importReplace bool
+)
+
+var filterImportCmd = &cobra.Command{
+	Use:   "import <file.json>",
+	Short: "Import filters from JSON",
+	Long: `Import filters from a JSON file created by 'filter export'.
+
+Modes:
+  --merge    Add imported filters, skipping names that already exist (default)
+  --replace  Remove all existing filters before importing
+
+Examples:
+  email-sentinel filter import filters.json
+  email-sentinel filter import filters.json --replace`,
+	Args: cobra.ExactArgs(1),
+	Run:  runFilterImport,
+}
+
+func init() {
+	filterCmd.AddCommand(filterImportCmd)
+
+	filterImportCmd.Flags().BoolVar(&importMerge, "merge", false, "Merge with existing filters, skipping duplicates (default)")
+	filterImportCmd.Flags().BoolVar(&importReplace, "replace", false, "Replace all existing filters")
+}
+
+func runFilterImport(cmd *cobra.Command, args []string) {
+	if importMerge && importReplace {
+		fmt.Println("❌ Use either --merge or --replace, not both")
+		os.Exit(1)
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		fmt.Printf("❌ Error opening %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	result, err := filter.ImportFilters(file, importReplace)
+	if err != nil {
+		fmt.Printf("❌ Error importing filters: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, name := range result.Skipped {
+		fmt.Printf("⚠️  Skipped '%s': a filter with this name already exists\n", name)
+	}
+
+	fmt.Printf("✅ Imported %d filter(s)\n", result.Imported)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/filter"
+)
+
+// filterMoveCmd represents the filter move command
+var filterMoveCmd = &cobra.Command{
+	Use:   "move <filter-name> up|down",
+	Short: "Move a filter up or down in the evaluation order",
+	Long: `Move a filter one position up or down in the list shown by 'filter list'.
+
+Order only matters when filters.first_match_wins is enabled in
+app-config.yaml: filters are then checked top to bottom and only the first
+match alerts, so put specific filters (e.g. urgent mail from your boss)
+above general ones (e.g. all work mail).
+
+Examples:
+  email-sentinel filter move "Boss Urgent" up
+  email-sentinel filter move "Work" down
+  email-sentinel config set filters.first_match_wins true`,
+	Args: cobra.ExactArgs(2),
+	Run:  runFilterMove,
+}
+
+func init() {
+	filterCmd.AddCommand(filterMoveCmd)
+}
+
+func runFilterMove(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	var offset int
+	switch strings.ToLower(args[1]) {
+	case "up":
+		offset = -1
+	case "down":
+		offset = 1
+	default:
+		fmt.Printf("❌ Direction must be 'up' or 'down', got '%s'\n", args[1])
+		os.Exit(1)
+	}
+
+	index, err := filter.MoveFilter(name, offset)
+	if err != nil {
+		fmt.Printf("❌ Error moving filter: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Filter '%s' is now #%d\n", name, index+1)
+
+	if appCfg, err := appconfig.Load(); err == nil && !appCfg.Filters.FirstMatchWins {
+		fmt.Println("💡 Order only matters with first-match routing: email-sentinel config set filters.first_match_wins true")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/filter"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+var renameUpdateHistory bool
+
+// filterRenameCmd represents the filter rename command
+var filterRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a filter",
+	Long: `Rename a filter, keeping its patterns, labels and position.
+
+The new name must not already be used by another filter. By default past
+alerts recorded under the old name are updated too, so 'alerts' history and
+stats stay grouped under the new name. Use --update-history=false to leave
+them as they were.
+
+Examples:
+  email-sentinel filter rename "Work" "Work Email"
+  email-sentinel filter rename "Jobs" "Job Search" --update-history=false`,
+	Args: cobra.ExactArgs(2),
+	Run:  runFilterRename,
+}
+
+func init() {
+	filterCmd.AddCommand(filterRenameCmd)
+
+	filterRenameCmd.Flags().BoolVar(&renameUpdateHistory, "update-history", true, "Also rename the filter in past alerts")
+}
+
+func runFilterRename(cmd *cobra.Command, args []string) {
+	oldName, newName := args[0], args[1]
+
+	previous, newName, err := filter.RenameFilter(oldName, newName)
+	if err != nil {
+		fmt.Printf("❌ Error renaming filter: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Filter '%s' renamed to '%s'\n", previous, newName)
+
+	if !renameUpdateHistory {
+		return
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("⚠️  Could not open alert history: %v\n", err)
+		return
+	}
+	defer storage.CloseDB(db)
+
+	updated, err := storage.RenameFilterInAlerts(db, previous, newName)
+	if err != nil {
+		fmt.Printf("⚠️  Could not update past alerts: %v\n", err)
+		return
+	}
+	fmt.Printf("   Updated %d past alert(s)\n", updated)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/filter"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+var (
+	filterReportDays  int
+	filterReportNoisy float64
+)
+
+// filterReportCmd represents the filter report command
+var filterReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show which filters match often, rarely or never",
+	Long: `Show how many alerts each filter produced over the last N days.
+
+Filters with no matches are flagged as candidates for removal, and filters
+averaging more than --noisy matches per day as candidates for tightening
+(or for a digest). Filters are listed in 'filter list' order.
+
+The report can only cover alerts still in the database. By default alerts
+are wiped daily at midnight; set monitoring.database.retention_days in
+app-config.yaml to keep enough history for a useful report.
+
+Examples:
+  email-sentinel filter report
+  email-sentinel filter report --days 7 --noisy 10`,
+	Run: runFilterReport,
+}
+
+func init() {
+	filterCmd.AddCommand(filterReportCmd)
+	filterReportCmd.Flags().IntVar(&filterReportDays, "days", 30, "Number of days to include")
+	filterReportCmd.Flags().Float64Var(&filterReportNoisy, "noisy", 20, "Flag filters averaging more than this many matches per day")
+}
+
+func runFilterReport(cmd *cobra.Command, args []string) {
+	if filterReportDays <= 0 {
+		fmt.Println("❌ --days must be positive")
+		os.Exit(1)
+	}
+	if filterReportNoisy <= 0 {
+		fmt.Println("❌ --noisy must be positive")
+		os.Exit(1)
+	}
+
+	filters, err := filter.ListFilters()
+	if err != nil {
+		fmt.Printf("❌ Error loading filters: %v\n", err)
+		os.Exit(1)
+	}
+	if len(filters) == 0 {
+		fmt.Println("No filters configured.")
+		fmt.Println("\nAdd one with: email-sentinel filter add")
+		return
+	}
+
+	end := time.Now()
+	midnight := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	start := midnight.AddDate(0, 0, -(filterReportDays - 1))
+
+	// Only the days retention still keeps count towards matches/day
+	days := filterReportDays
+	if appCfg, err := appconfig.Load(); err == nil {
+		cutoff := storage.RetentionCutoff(end, appCfg.Monitoring.Database.RetentionDays)
+		if start.Before(cutoff) {
+			fmt.Printf("⚠️  Alerts before %s have been removed by cleanup (retention_days: %d)\n",
+				cutoff.Format("2006-01-02"), appCfg.Monitoring.Database.RetentionDays)
+			fmt.Println("   Matches/day is averaged over the days still in history")
+			fmt.Println()
+			start = cutoff
+			days = calendarDays(cutoff, end)
+		}
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening alert database: %v\n", err)
+		os.Exit(1)
+	}
+	defer storage.CloseDB(db)
+
+	alerts, err := storage.GetAlertsBetween(db, start, end)
+	if err != nil {
+		fmt.Printf("❌ Error fetching alerts: %v\n", err)
+		os.Exit(1)
+	}
+
+	names := make([]string, len(filters))
+	for i, f := range filters {
+		names[i] = f.Name
+	}
+	counts := storage.CountMatchesByFilter(alerts, names)
+
+	rows := make([][]string, 0, len(filters))
+	unused, noisy := 0, 0
+	for i, f := range filters {
+		perDay := float64(counts[i].Count) / float64(days)
+
+		note := ""
+		switch {
+		case !f.IsEnabled():
+			note = "⏸️  disabled"
+		case counts[i].Count == 0:
+			note = "🗑️  no matches - consider removing"
+			unused++
+		case perDay > filterReportNoisy:
+			note = "🔊 noisy - consider tightening"
+			noisy++
+		}
+
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			f.Name,
+			strconv.Itoa(counts[i].Count),
+			strconv.FormatFloat(perDay, 'f', 1, 64),
+			note,
+		})
+	}
+
+	ui.PrintSection(fmt.Sprintf("Filter Report: %s → %s", start.Format("2006-01-02"), end.Format("2006-01-02")))
+	ui.PrintTable([]string{"#", "Filter", "Matches", "Matches/day", "Note"}, rows)
+	fmt.Println()
+
+	if unused > 0 {
+		fmt.Printf("🗑️  %d filter(s) matched nothing: remove with 'email-sentinel filter remove <name>'\n", unused)
+	}
+	if noisy > 0 {
+		fmt.Printf("🔊 %d filter(s) average more than %s matches/day: tighten their patterns or add --digest\n",
+			noisy, strconv.FormatFloat(filterReportNoisy, 'f', -1, 64))
+	}
+	if unused == 0 && noisy == 0 {
+		ui.PrintSuccess("Every active filter is matching at a reasonable rate")
+	}
+}
+
+// calendarDays counts the calendar days from start's day through end's day, inclusive
+func calendarDays(start, end time.Time) int {
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	return int(math.Round(endDay.Sub(startDay).Hours()/24)) + 1
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	googlemail "google.golang.org/api/gmail/v1"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/filter"
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+var (
+	filterTestLive  bool
+	filterTestCount int64
+)
+
+// filterTestCmd represents the filter test command
+var filterTestCmd = &cobra.Command{
+	Use:   "test [filter-name from subject]",
+	Short: "Test filters against sample or recent real emails",
+	Long: `Test which filters would fire, either for a hypothetical email or
+for your actual recent messages.
+
+With --live, recent messages are fetched from Gmail for every scope your
+filters use and run through the same matching as 'start'. A filter only
+counts as matching a message that was found in that filter's scope.
+
+This is read-only: nothing is written to alert history and messages are
+not marked as seen, so a later 'start' still alerts on them.
+
+Examples:
+  email-sentinel filter test --live
+  email-sentinel filter test --live --count 100
+  email-sentinel filter test "Job Alerts" "recruiter@linkedin.com" "New job opportunity"`,
+	Run: runFilterTest,
+}
+
+func init() {
+	filterCmd.AddCommand(filterTestCmd)
+
+	filterTestCmd.Flags().BoolVar(&filterTestLive, "live", false, "Replay recent real emails from Gmail against all filters")
+	filterTestCmd.Flags().Int64Var(&filterTestCount, "count", 50, "With --live, number of recent messages to fetch per scope")
+}
+
+func runFilterTest(cmd *cobra.Command, args []string) {
+	if !filterTestLive {
+		if len(args) != 3 {
+			fmt.Println("❌ Specify a filter and sample email, or use --live")
+			fmt.Println("\nUsage: email-sentinel filter test <filter-name> <from> <subject>")
+			fmt.Println("       email-sentinel filter test --live [--count 50]")
+			os.Exit(1)
+		}
+		runTestFilter(cmd, args)
+		return
+	}
+
+	if len(args) > 0 {
+		fmt.Println("❌ --live does not take arguments")
+		os.Exit(1)
+	}
+	if filterTestCount < 1 {
+		fmt.Println("❌ --count must be at least 1")
+		os.Exit(1)
+	}
+
+	filters, err := filter.ListFilters()
+	if err != nil {
+		fmt.Printf("❌ Error loading filters: %v\n", err)
+		os.Exit(1)
+	}
+	if len(filters) == 0 {
+		fmt.Println("📭 No filters configured")
+		fmt.Println("\nAdd one with: email-sentinel filter add")
+		return
+	}
+
+	queries, err := filter.GetAllSearchQueries("")
+	if err != nil {
+		fmt.Printf("❌ Error getting filter queries: %v\n", err)
+		os.Exit(1)
+	}
+
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	client, err := newGmailClient(appCfg)
+	if err != nil {
+		fmt.Printf("❌ Error connecting to Gmail: %v\n", err)
+		os.Exit(1)
+	}
+
+	if appCfg.Filters.FirstMatchWins {
+		fmt.Println("ℹ️  filters.first_match_wins is on: only the first matching filter is shown")
+	}
+	fmt.Printf("🔍 Fetching up to %d recent messages per filter query (%s)...\n\n", filterTestCount, describeQueries(queries))
+
+	// Remember which queries each message was found by, keeping Gmail's order
+	var messages []*googlemail.Message
+	messageQueries := make(map[string]map[string]bool)
+	for _, query := range queries {
+		fetched, err := client.GetRecentMessagesWithQuery(filterTestCount, query)
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Error fetching messages for query '%s': %v", query, err))
+			continue
+		}
+
+		for _, msg := range fetched {
+			if _, ok := messageQueries[msg.Id]; !ok {
+				messageQueries[msg.Id] = make(map[string]bool)
+				messages = append(messages, msg)
+			}
+			messageQueries[msg.Id][query] = true
+		}
+	}
+
+	if len(messages) == 0 {
+		fmt.Println("📭 No messages found")
+		return
+	}
+
+	bodyCache := make(map[string]string)
+	hits := make(map[string]int)
+	matchedCount := 0
+	rows := make([][]string, 0, len(messages))
+
+	for _, msg := range messages {
+		email := gmail.ParseMessage(msg)
+		body := getMessageBody(client, msg, bodyCache)
+
+		matches, err := filter.CheckAllFiltersWithMetadata(email.From, email.Subject, body, messageHasAttachment(client, msg))
+		if err != nil {
+			fmt.Printf("❌ Error checking filters: %v\n", err)
+			os.Exit(1)
+		}
+
+		var inScope []filter.MatchResult
+		for _, m := range matches {
+			if messageQueries[msg.Id][filter.SearchQuery(m.GmailScope, m.GmailQuery, "")] {
+				inScope = append(inScope, m)
+			}
+		}
+		if appCfg.Filters.FirstMatchWins {
+			inScope = filter.FirstMatch(inScope)
+		}
+
+		var names []string
+		for _, m := range inScope {
+			names = append(names, m.Name)
+			hits[m.Name]++
+		}
+
+		result := "no match"
+		if len(names) > 0 {
+			result = strings.Join(names, ", ")
+			matchedCount++
+		}
+
+		rows = append(rows, []string{
+			shortenForTable(email.From, 40),
+			shortenForTable(email.Subject, 50),
+			result,
+		})
+	}
+
+	ui.PrintTable([]string{"From", "Subject", "Matched Filters"}, rows)
+	fmt.Println()
+	fmt.Printf("📊 %d of %d messages matched at least one filter\n", matchedCount, len(messages))
+
+	var idle []string
+	for _, f := range filters {
+		if hits[f.Name] == 0 {
+			idle = append(idle, f.Name)
+		}
+	}
+	if len(idle) > 0 {
+		fmt.Println("\nFilters with no matches:")
+		for _, name := range idle {
+			fmt.Printf("  - %s\n", name)
+		}
+	}
+}
+
+// describeQueries lists Gmail queries for display, showing the empty query as "all mail"
+func describeQueries(queries []string) string {
+	labels := make([]string, len(queries))
+	for i, query := range queries {
+		labels[i] = query
+		if query == "" {
+			labels[i] = "all mail"
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// shortenForTable trims text to max runes so table columns stay readable
+func shortenForTable(text string, max int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return string(runes[:max-3]) + "..."
+}
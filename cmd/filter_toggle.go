@@ -0,0 +1,63 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/filter"
+)
+
+// filterEnableCmd represents the filter enable command
+var filterEnableCmd = &cobra.Command{
+	Use:   "enable <filter-name>",
+	Short: "Resume a disabled filter",
+	Long: `Re-enable a filter that was paused with 'filter disable'.
+
+Examples:
+  email-sentinel filter enable "Job Alerts"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setFilterEnabled(args[0], true)
+	},
+}
+
+// filterDisableCmd represents the filter disable command
+var filterDisableCmd = &cobra.Command{
+	Use:   "disable <filter-name>",
+	Short: "Pause a filter without deleting it",
+	Long: `Pause a filter so it no longer matches incoming email.
+
+The filter keeps all its settings and can be turned back on with
+'filter enable'. Disabled filters are shown in 'filter list' but are
+skipped while monitoring, and their Gmail scope is no longer polled.
+
+Examples:
+  email-sentinel filter disable "Job Alerts"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setFilterEnabled(args[0], false)
+	},
+}
+
+func init() {
+	filterCmd.AddCommand(filterEnableCmd)
+	filterCmd.AddCommand(filterDisableCmd)
+}
+
+func setFilterEnabled(name string, enabled bool) {
+	if err := filter.SetFilterEnabled(name, enabled); err != nil {
+		fmt.Printf("❌ Error updating filter: %v\n", err)
+		os.Exit(1)
+	}
+
+	if enabled {
+		fmt.Printf("✅ Filter '%s' enabled.\n", name)
+	} else {
+		fmt.Printf("⏸️  Filter '%s' disabled. Re-enable it with: email-sentinel filter enable \"%s\"\n", name, name)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/config"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/gmail"
@@ -26,19 +28,52 @@ This command will:
 2. Open a browser for Google OAuth authorization
 3. Save your authentication token for future use
 
-You must have a credentials.json file from Google Cloud Console.`,
+You must have a credentials.json file from Google Cloud Console.
+
+Use --reauth when authorization breaks or the requested access changes
+(e.g. after enabling monitoring.gmail.allow_modify). It asks Google for
+consent again so a fresh refresh token is issued, and only revokes the
+previous token once the new one is saved. If authorization fails, the
+previous token is kept.
+
+Examples:
+  # First-time setup
+  email-sentinel init
+
+  # Force a clean re-authorization
+  email-sentinel init --reauth
+
+  # Re-authorize with gmail.modify (labels, mark-read) and enable it in app-config.yaml
+  email-sentinel init --reauth --scopes modify`,
 	Run: runInit,
 }
 
+var (
+	initReauth bool
+	initScopes string
+)
+
 func init() {
 	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolVar(&initReauth, "reauth", false, "Re-request consent, then revoke the previous token once the new one is saved")
+	initCmd.Flags().BoolVar(&initReauth, "force", false, "Alias for --reauth")
+	initCmd.Flags().StringVar(&initScopes, "scopes", "", "Gmail access to request: 'readonly' or 'modify' (default: from monitoring.gmail.allow_modify)")
 }
 
 func runInit(cmd *cobra.Command, args []string) {
 	fmt.Println("🚀 Initializing email-sentinel...")
 
+	scopes := strings.ToLower(strings.TrimSpace(initScopes))
+	if scopes != "" && scopes != "readonly" && scopes != "modify" {
+		fmt.Printf("❌ Invalid --scopes '%s' (use 'readonly' or 'modify')\n", initScopes)
+		os.Exit(1)
+	}
+
 	// Check if already initialized
-	if gmail.TokenExists() {
+	// With --reauth the old token is kept until the new one is saved, then revoked
+	reauthorizing := gmail.TokenExists()
+	if reauthorizing && !initReauth {
 		fmt.Println("\n⚠️  Already initialized! Token exists.")
 		fmt.Print("Do you want to re-authenticate? (y/N): ")
 
@@ -64,15 +99,28 @@ func runInit(cmd *cobra.Command, args []string) {
 	fmt.Printf("✓ Found credentials: %s\n", credPath)
 
 	// Load OAuth config
-	oauthConfig, err := gmail.LoadCredentials(credPath)
+	// Request gmail.modify only if the user opted in to applying labels or --scopes modify
+	allowModify := resolveInitScopes(scopes)
+	if allowModify {
+		fmt.Println("✓ Requesting gmail.modify access")
+	} else {
+		fmt.Println("✓ Requesting read-only access")
+	}
+
+	oauthConfig, err := gmail.LoadCredentialsWithModify(credPath, allowModify)
 	if err != nil {
 		fmt.Printf("\n❌ Error loading credentials: %v\n", err)
 		os.Exit(1)
 	}
 	fmt.Println("✓ Credentials loaded")
 
+	var oldToken *oauth2.Token
+	if reauthorizing && initReauth {
+		oldToken, _ = gmail.LoadToken()
+	}
+
 	// Run OAuth flow
-	token, err := gmail.GetTokenFromWeb(oauthConfig)
+	token, err := gmail.GetTokenFromWeb(oauthConfig, reauthorizing)
 	if err != nil {
 		fmt.Printf("\n❌ Error during authentication: %v\n", err)
 		os.Exit(1)
@@ -88,10 +136,55 @@ func runInit(cmd *cobra.Command, args []string) {
 	tokenPath, _ := config.TokenPath()
 	fmt.Printf("✓ Token saved to: %s\n", tokenPath)
 
+	if oldToken != nil {
+		revokeOldToken(oldToken, token)
+	}
+
 	fmt.Println("\n✅ Initialization complete!")
 	showPostInitMenu()
 }
 
+// revokeOldToken revokes the token that --reauth replaced, once the new one is saved
+// Revocation is best effort: an already-broken token can't always be revoked.
+func revokeOldToken(old, replacement *oauth2.Token) {
+	// Google may hand back the same refresh token; revoking it would undo the new authorization
+	if old.RefreshToken != "" && old.RefreshToken == replacement.RefreshToken {
+		return
+	}
+	if err := gmail.RevokeToken(old); err != nil {
+		fmt.Printf("⚠️  Could not revoke the previous token: %v\n", err)
+		return
+	}
+	fmt.Println("✓ Previous token revoked")
+}
+
+// resolveInitScopes decides whether to request gmail.modify
+// An explicit --scopes choice is saved to monitoring.gmail.allow_modify so
+// 'start' uses the access that was granted.
+func resolveInitScopes(scopes string) bool {
+	appCfg, err := appconfig.Load()
+	if scopes == "" {
+		return err == nil && appCfg.Monitoring.Gmail.AllowModify
+	}
+
+	allowModify := scopes == "modify"
+	if err != nil {
+		fmt.Printf("⚠️  Could not load app-config.yaml to save monitoring.gmail.allow_modify: %v\n", err)
+		return allowModify
+	}
+
+	if appCfg.Monitoring.Gmail.AllowModify != allowModify {
+		appCfg.Monitoring.Gmail.AllowModify = allowModify
+		if err := appconfig.Save(appCfg); err != nil {
+			fmt.Printf("⚠️  Could not save monitoring.gmail.allow_modify: %v\n", err)
+		} else {
+			fmt.Printf("✓ Set monitoring.gmail.allow_modify: %v\n", allowModify)
+		}
+	}
+
+	return allowModify
+}
+
 func showPostInitMenu() {
 	// Show existing filters if any
 	filters, err := filter.ListFilters()
@@ -141,3 +234,23 @@ func findCredentials() string {
 
 	return ""
 }
+
+// newGmailClient creates a Gmail client from the saved credentials and token
+func newGmailClient(appCfg *appconfig.AppConfig) (*gmail.Client, error) {
+	credPath := findCredentials()
+	if credPath == "" {
+		return nil, fmt.Errorf("credentials.json not found")
+	}
+
+	oauthConfig, err := gmail.LoadCredentialsWithModify(credPath, appCfg.Monitoring.Gmail.AllowModify)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	token, err := gmail.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token (run: email-sentinel init): %w", err)
+	}
+
+	return gmail.NewClient(token, oauthConfig)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 )
 
@@ -20,6 +21,8 @@ var listCmd = &cobra.Command{
 	Long: `Display all configured email filters with their settings.
 
 Shows filter names, sender patterns, subject patterns, and match modes.
+Filters are numbered in evaluation order; with filters.first_match_wins
+enabled only the first match alerts (reorder with 'filter move').
 
 Example:
   email-sentinel filter list`,
@@ -44,11 +47,18 @@ func runFilterList(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Printf("\n📋 Email Filters (%d)\n", len(filters))
+	if appCfg, err := appconfig.Load(); err == nil && appCfg.Filters.FirstMatchWins {
+		fmt.Println("🔀 First match wins: filters are checked top to bottom (reorder with 'filter move')")
+	}
 	fmt.Println(strings.Repeat("━", 60))
 
 	for i, f := range filters {
 		fmt.Printf("\n[%d] %s\n", i+1, f.Name)
 
+		if !f.IsEnabled() {
+			fmt.Println("    Status:  ⏸️  disabled (skipped while monitoring)")
+		}
+
 		if len(f.From) > 0 {
 			fmt.Printf("    From:    %s\n", strings.Join(f.From, ", "))
 		} else {
@@ -61,6 +71,10 @@ func runFilterList(cmd *cobra.Command, args []string) {
 			fmt.Println("    Subject: (any)")
 		}
 
+		if len(f.Body) > 0 {
+			fmt.Printf("    Body:    %s\n", strings.Join(f.Body, ", "))
+		}
+
 		if len(f.Labels) > 0 {
 			fmt.Printf("    Labels:  🏷️  %s\n", strings.Join(f.Labels, ", "))
 		}
@@ -71,6 +85,10 @@ func runFilterList(cmd *cobra.Command, args []string) {
 		}
 		fmt.Printf("    Match:   %s\n", matchDesc)
 
+		if f.MatchType == filter.MatchTypeRegex {
+			fmt.Println("    Type:    regex")
+		}
+
 		// Show Gmail scope
 		scope := f.GmailScope
 		if scope == "" {
@@ -78,6 +96,34 @@ func runFilterList(cmd *cobra.Command, args []string) {
 		}
 		fmt.Printf("    Scope:   📬 %s\n", scope)
 
+		if f.GmailQuery != "" {
+			fmt.Printf("    Query:   🔎 %s\n", f.GmailQuery)
+		}
+
+		if f.NtfyTopic != "" {
+			fmt.Printf("    Topic:   📱 %s\n", f.NtfyTopic)
+		}
+
+		if f.ApplyGmailLabel != "" {
+			fmt.Printf("    Gmail:   🏷️  label '%s'\n", f.ApplyGmailLabel)
+		}
+
+		if f.MarkRead {
+			fmt.Println("    Gmail:   👁️  mark as read")
+		}
+
+		if f.Digest != "" {
+			fmt.Printf("    Digest:  📥 every %s\n", f.Digest)
+		}
+
+		if f.ForcePriority != nil {
+			fmt.Printf("    Urgency: 🔥 %s\n", forcedPriorityDesc(*f.ForcePriority))
+		}
+
+		if f.HasAttachment != nil {
+			fmt.Printf("    Attach:  📎 %s\n", attachmentDesc(*f.HasAttachment))
+		}
+
 		// Show expiration status
 		expirationStatus := filter.FormatExpiration(f.ExpiresAt)
 		if filter.IsInGracePeriod(f.ExpiresAt) {
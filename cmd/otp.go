@@ -26,13 +26,17 @@ for quick access. This is useful for two-factor authentication codes.
 Available Commands:
   list    List recent OTP codes
   get     Get the most recent OTP and copy to clipboard
-  clear   Clear expired OTP codes
+  latest  Print the newest OTP (optionally --copy), exit 1 if none
+  clear   Clear expired (or --all) OTP codes
+  export  Export captured codes as CSV or JSON
   test    Test OTP extraction on sample text
 
 Examples:
   email-sentinel otp list
   email-sentinel otp get
-  email-sentinel otp clear`,
+  email-sentinel otp latest --copy
+  email-sentinel otp clear
+  email-sentinel otp export --json`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
@@ -17,19 +17,34 @@ import (
 // otpClearCmd represents the otp clear command
 var otpClearCmd = &cobra.Command{
 	Use:   "clear",
-	Short: "Clear expired OTP codes",
-	Long: `Delete all expired OTP codes from the database.
+	Short: "Clear expired (or all) OTP codes",
+	Long: `Delete OTP codes from the database.
 
-This helps keep the database clean by removing old codes that can
-no longer be used. You'll be prompted for confirmation.
+By default (or with --expired) only codes that have expired are removed.
+Use --all to purge the whole OTP history, including codes that are still
+valid. You'll be prompted for confirmation unless --force is used.
 
 Examples:
-  email-sentinel otp clear`,
+  # Clear expired codes
+  email-sentinel otp clear
+
+  # Purge every stored code without a prompt
+  email-sentinel otp clear --all --force`,
 	Run: runOTPClear,
 }
 
+var (
+	clearAllOTPs     bool
+	clearExpiredOTPs bool
+	forceOTPClear    bool
+)
+
 func init() {
 	otpCmd.AddCommand(otpClearCmd)
+	otpClearCmd.Flags().BoolVar(&clearAllOTPs, "all", false, "Delete all OTP codes, including active ones")
+	otpClearCmd.Flags().BoolVar(&clearExpiredOTPs, "expired", false, "Delete only expired codes (default)")
+	otpClearCmd.Flags().BoolVarP(&forceOTPClear, "force", "f", false, "Skip confirmation prompt")
+	otpClearCmd.MarkFlagsMutuallyExclusive("all", "expired")
 }
 
 func runOTPClear(cmd *cobra.Command, args []string) {
@@ -42,51 +57,64 @@ func runOTPClear(cmd *cobra.Command, args []string) {
 	}
 	defer storage.CloseDB(db)
 
-	// First expire codes, then count them
-	storage.ExpireOTPAlerts(db)
+	// Mark codes past their expiry as inactive so the count matches what gets deleted
+	if _, err := storage.ExpireOTPAlerts(db); err != nil {
+		fmt.Printf("❌ Error expiring codes: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Count expired (inactive) codes
-	otps, err := storage.GetRecentOTPAlerts(db, 1000)
+	total, inactive, err := storage.CountOTPAlerts(db)
 	if err != nil {
 		fmt.Printf("❌ Error counting codes: %v\n", err)
 		os.Exit(1)
 	}
 
-	count := 0
-	for _, otp := range otps {
-		if !otp.IsActive {
-			count++
-		}
+	count, what := inactive, "expired OTP code(s)"
+	if clearAllOTPs {
+		count, what = total, "OTP code(s)"
 	}
 
 	if count == 0 {
-		fmt.Println("✨ No expired OTP codes to clear")
+		if clearAllOTPs {
+			fmt.Println("✨ No OTP codes to clear")
+		} else {
+			fmt.Println("✨ No expired OTP codes to clear")
+		}
 		return
 	}
 
-	// Prompt for confirmation
-	fmt.Printf("Found %d expired OTP code(s)\n", count)
-	fmt.Print("Delete these codes? [y/N]: ")
+	// Prompt for confirmation unless --force is used
+	if !forceOTPClear {
+		fmt.Printf("Found %d %s\n", count, what)
+		if clearAllOTPs && total > inactive {
+			fmt.Printf("⚠️  Including %d code(s) that are still valid\n", total-inactive)
+		}
+		fmt.Print("Delete these codes? [y/N]: ")
 
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		fmt.Printf("❌ Error reading input: %v\n", err)
-		os.Exit(1)
-	}
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Printf("❌ Error reading input: %v\n", err)
+			os.Exit(1)
+		}
 
-	response = strings.TrimSpace(strings.ToLower(response))
-	if response != "y" && response != "yes" {
-		fmt.Println("Cancelled.")
-		return
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Cancelled.")
+			return
+		}
 	}
 
-	// Delete expired codes
-	deleted, err := storage.DeleteExpiredOTPAlerts(db)
+	var deleted int64
+	if clearAllOTPs {
+		deleted, err = storage.DeleteAllOTPAlerts(db)
+	} else {
+		deleted, err = storage.DeleteInactiveOTPAlerts(db)
+	}
 	if err != nil {
 		fmt.Printf("❌ Error deleting codes: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("🔐 Cleared %d expired OTP code(s)\n", deleted)
+	fmt.Printf("🔐 Cleared %d %s\n", deleted, what)
 }
@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/otp"
+	"github.com/datateamsix/email-sentinel/internal/state"
+)
+
+// clipboardHashEnv passes the copied code's hash to the background clear,
+// so the code itself never appears in a process listing
+const clipboardHashEnv = "EMAIL_SENTINEL_CLIPBOARD_HASH"
+
+var clearClipboardAfter time.Duration
+
+// otpClearClipboardCmd clears a copied code in the background for 'otp get' and 'otp latest --copy'
+var otpClearClipboardCmd = &cobra.Command{
+	Use:    "clear-clipboard",
+	Short:  "Clear a copied OTP code from the clipboard after a delay",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		time.Sleep(clearClipboardAfter)
+		otp.ClearIfHash(os.Getenv(clipboardHashEnv))
+	},
+}
+
+func init() {
+	otpCmd.AddCommand(otpClearClipboardCmd)
+
+	otpClearClipboardCmd.Flags().DurationVar(&clearClipboardAfter, "after", 0, "How long to wait before clearing")
+}
+
+// scheduleClipboardClear honors otp.clipboard.clear_after without blocking
+// A detached process clears the clipboard later, and only if it still holds code,
+// so the command (or the console a toast button opened) can exit right away.
+func scheduleClipboardClear(code string) {
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		return
+	}
+
+	clearAfter := otpClearAfter(appCfg)
+	if clearAfter <= 0 {
+		return
+	}
+
+	args := []string{"otp", "clear-clipboard", "--after", clearAfter.String()}
+	if err := state.StartDetached(args, clipboardHashEnv+"="+otp.CodeHash(code)); err != nil {
+		fmt.Printf("⚠️  Could not schedule clipboard clear: %v\n", err)
+		return
+	}
+	fmt.Printf("🧹 Clipboard will be cleared in %v\n", clearAfter)
+}
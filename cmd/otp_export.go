@@ -0,0 +1,181 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+var (
+	otpExportJSON   bool
+	otpExportLimit  int
+	otpExportOutput string
+)
+
+// otpExportColumns defines the CSV header order
+var otpExportColumns = []string{
+	"id", "received_at", "expires_at", "sender", "subject", "code", "confidence",
+	"source", "pattern", "filter_name", "gmail_link", "active", "copied_at",
+}
+
+// otpExportRecord is the exported representation of an OTP code
+type otpExportRecord struct {
+	ID         int64   `json:"id"`
+	ReceivedAt string  `json:"received_at"`
+	ExpiresAt  string  `json:"expires_at"`
+	Sender     string  `json:"sender"`
+	Subject    string  `json:"subject"`
+	Code       string  `json:"code"`
+	Confidence float64 `json:"confidence"`
+	Source     string  `json:"source"`
+	Pattern    string  `json:"pattern"`
+	FilterName string  `json:"filter_name"`
+	GmailLink  string  `json:"gmail_link"`
+	Active     bool    `json:"active"`
+	CopiedAt   string  `json:"copied_at"` // RFC 3339, empty if never copied
+}
+
+// otpExportCmd represents the otp export command
+var otpExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export captured OTP codes for auditing",
+	Long: `Export recently captured OTP codes, newest first.
+
+Useful for checking what was captured before purging the history with
+'email-sentinel otp clear --all'. The export contains the codes themselves,
+so store it carefully. Writes CSV to stdout by default.
+
+Examples:
+  email-sentinel otp export --json
+  email-sentinel otp export --limit 500 --output otp-codes.csv`,
+	Run: runOTPExport,
+}
+
+func init() {
+	otpCmd.AddCommand(otpExportCmd)
+	otpExportCmd.Flags().BoolVar(&otpExportJSON, "json", false, "Export as a JSON array instead of CSV")
+	otpExportCmd.Flags().IntVarP(&otpExportLimit, "limit", "l", 100, "Maximum number of codes to export")
+	otpExportCmd.Flags().StringVarP(&otpExportOutput, "output", "o", "", "Write to this file instead of stdout")
+}
+
+func runOTPExport(cmd *cobra.Command, args []string) {
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening database: %v\n", err)
+		fmt.Println("   Tip: Database may not exist. Start monitoring with 'email-sentinel start' first.")
+		os.Exit(1)
+	}
+	defer storage.CloseDB(db)
+
+	otps, err := storage.GetRecentOTPAlerts(db, otpExportLimit)
+	if err != nil {
+		fmt.Printf("❌ Error fetching OTP codes: %v\n", err)
+		os.Exit(1)
+	}
+
+	var out io.Writer = os.Stdout
+	if otpExportOutput != "" {
+		file, err := os.OpenFile(otpExportOutput, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			fmt.Printf("❌ Error creating %s: %v\n", otpExportOutput, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if otpExportJSON {
+		err = writeOTPsJSON(out, otps)
+	} else {
+		err = writeOTPsCSV(out, otps)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error exporting OTP codes: %v\n", err)
+		os.Exit(1)
+	}
+
+	if otpExportOutput != "" {
+		fmt.Printf("🔐 Exported %d OTP code(s) to %s\n", len(otps), otpExportOutput)
+	}
+}
+
+// toOTPExportRecord converts a stored OTP alert to its export form
+func toOTPExportRecord(otp storage.OTPAlert) otpExportRecord {
+	copiedAt := ""
+	if otp.CopiedAt != nil {
+		copiedAt = otp.CopiedAt.Format(time.RFC3339)
+	}
+
+	return otpExportRecord{
+		ID:         otp.ID,
+		ReceivedAt: otp.Timestamp.Format(time.RFC3339),
+		ExpiresAt:  otp.ExpiresAt.Format(time.RFC3339),
+		Sender:     otp.Sender,
+		Subject:    otp.Subject,
+		Code:       otp.OTPCode,
+		Confidence: otp.Confidence,
+		Source:     otp.Source,
+		Pattern:    otp.PatternName,
+		FilterName: otp.FilterName,
+		GmailLink:  otp.GmailLink,
+		Active:     otp.IsActive && time.Now().Before(otp.ExpiresAt),
+		CopiedAt:   copiedAt,
+	}
+}
+
+// writeOTPsCSV writes OTP codes as CSV (fields with commas/quotes are quoted)
+func writeOTPsCSV(w io.Writer, otps []storage.OTPAlert) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(otpExportColumns); err != nil {
+		return err
+	}
+
+	for _, otp := range otps {
+		rec := toOTPExportRecord(otp)
+		row := []string{
+			strconv.FormatInt(rec.ID, 10),
+			rec.ReceivedAt,
+			rec.ExpiresAt,
+			rec.Sender,
+			rec.Subject,
+			rec.Code,
+			strconv.FormatFloat(rec.Confidence, 'f', 2, 64),
+			rec.Source,
+			rec.Pattern,
+			rec.FilterName,
+			rec.GmailLink,
+			strconv.FormatBool(rec.Active),
+			rec.CopiedAt,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeOTPsJSON writes OTP codes as an indented JSON array ([] when empty)
+func writeOTPsJSON(w io.Writer, otps []storage.OTPAlert) error {
+	records := make([]otpExportRecord, 0, len(otps))
+	for _, otp := range otps {
+		records = append(records, toOTPExportRecord(otp))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
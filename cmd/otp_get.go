@@ -22,6 +22,7 @@ var otpGetCmd = &cobra.Command{
 
 This command retrieves the newest OTP code that hasn't expired yet
 and automatically copies it to your clipboard for easy pasting.
+The clipboard is cleared after otp.clipboard.clear_after (app-config.yaml).
 
 Examples:
   email-sentinel otp get`,
@@ -78,4 +79,9 @@ func runOTPGet(cmd *cobra.Command, args []string) {
 	// Display metadata
 	fmt.Printf("From: %s\n", otpAlert.Sender)
 	fmt.Printf("Expires %s\n", formatExpiry(otpAlert.ExpiresAt))
+
+	// Clear the code from the clipboard after otp.clipboard.clear_after
+	if err == nil {
+		scheduleClipboardClear(otpAlert.OTPCode)
+	}
 }
@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/otp"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+var otpLatestCopy bool
+
+// otpLatestCmd represents the otp latest command
+var otpLatestCmd = &cobra.Command{
+	Use:   "latest",
+	Short: "Print the newest active OTP code",
+	Long: `Print the newest active, non-expired OTP code.
+
+The code is printed on the first line on its own, followed by the sender
+and subject, so it is easy to use from scripts and hotkeys.
+
+With --copy the code is also copied to the clipboard. If otp.clipboard.clear_after
+is set in app-config.yaml, a background process clears the clipboard after that
+duration (unless you've copied something else in the meantime).
+
+Exits with status 1 if no active code exists.
+
+Examples:
+  email-sentinel otp latest
+  email-sentinel otp latest --copy
+
+  # Use in a script
+  if code=$(email-sentinel otp latest | head -n 1); then
+    echo "Got $code"
+  fi`,
+	Run: runOTPLatest,
+}
+
+func init() {
+	otpCmd.AddCommand(otpLatestCmd)
+
+	otpLatestCmd.Flags().BoolVarP(&otpLatestCopy, "copy", "c", false, "Copy the code to the clipboard")
+}
+
+func runOTPLatest(cmd *cobra.Command, args []string) {
+	// Open database
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+
+	otps, err := storage.GetActiveOTPAlerts(db)
+	if err != nil {
+		storage.CloseDB(db)
+		fmt.Printf("❌ Error fetching OTP: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(otps) == 0 {
+		storage.CloseDB(db)
+		fmt.Println("📭 No active OTP codes found")
+		os.Exit(1)
+	}
+
+	otpAlert := otps[0] // Most recent
+
+	fmt.Println(otpAlert.OTPCode)
+	fmt.Printf("From:    %s\n", otpAlert.Sender)
+	fmt.Printf("Subject: %s\n", otpAlert.Subject)
+	fmt.Printf("Expires: %s\n", formatExpiry(otpAlert.ExpiresAt))
+
+	if !otpLatestCopy {
+		storage.CloseDB(db)
+		return
+	}
+
+	if err := otp.CopyToClipboard(otpAlert.OTPCode); err != nil {
+		storage.CloseDB(db)
+		fmt.Printf("❌ Failed to copy to clipboard: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Copied to clipboard")
+
+	if err := storage.MarkOTPAsCopied(db, otpAlert.ID); err != nil {
+		// Non-fatal error, just log it
+		fmt.Printf("   Warning: Failed to mark as copied: %v\n", err)
+	}
+	storage.CloseDB(db)
+
+	scheduleClipboardClear(otpAlert.OTPCode)
+}
+
+// otpClearAfter returns otp.clipboard.clear_after as a duration (0 = never clear)
+func otpClearAfter(appCfg *appconfig.AppConfig) time.Duration {
+	if appCfg.OTP.Clipboard.ClearAfter == "" || appCfg.OTP.Clipboard.ClearAfter == "0" {
+		return 0
+	}
+
+	clearAfter, err := appCfg.OTP.Clipboard.GetClearAfterDuration()
+	if err != nil || clearAfter < 0 {
+		return 0
+	}
+	return clearAfter
+}
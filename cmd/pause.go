@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/state"
+)
+
+var pauseDuration time.Duration
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Temporarily pause email monitoring",
+	Long: `Pause email monitoring without stopping the running watcher.
+
+While paused, no emails are checked and no notifications are sent.
+Expired filters are still cleaned up. The watcher keeps its state, so
+monitoring picks up where it left off when resumed.
+
+Use --duration to resume automatically, or run 'email-sentinel resume'.
+You can also pause from the system tray menu.
+
+Examples:
+  email-sentinel pause
+  email-sentinel pause --duration 2h
+  email-sentinel pause --duration 30m`,
+	Run: runPause,
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+
+	pauseCmd.Flags().DurationVar(&pauseDuration, "duration", 0, "Resume automatically after this long (e.g. 30m, 2h; default: until resumed)")
+}
+
+func runPause(cmd *cobra.Command, args []string) {
+	if pauseDuration < 0 {
+		fmt.Println("❌ --duration cannot be negative")
+		os.Exit(1)
+	}
+
+	p, err := state.Pause(pauseDuration)
+	if err != nil {
+		fmt.Printf("❌ Error pausing monitoring: %v\n", err)
+		os.Exit(1)
+	}
+
+	if p.Until.IsZero() {
+		fmt.Println("⏸️  Monitoring paused until resumed")
+	} else {
+		fmt.Printf("⏸️  Monitoring paused until %s\n", p.Until.Format("Jan 2 15:04"))
+	}
+	fmt.Println("\nResume with: email-sentinel resume")
+}
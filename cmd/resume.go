@@ -0,0 +1,44 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/state"
+)
+
+// resumeCmd represents the resume command
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume paused email monitoring",
+	Long: `Resume email monitoring after 'email-sentinel pause'.
+
+The running watcher picks this up on its next polling cycle.
+
+Example:
+  email-sentinel resume`,
+	Run: runResume,
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+}
+
+func runResume(cmd *cobra.Command, args []string) {
+	if !state.IsPaused() {
+		fmt.Println("▶️  Monitoring is not paused")
+		return
+	}
+
+	if err := state.Resume(); err != nil {
+		fmt.Printf("❌ Error resuming monitoring: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("▶️  Monitoring resumed")
+}
@@ -4,12 +4,17 @@ Copyright © 2025 Datateamsix <research@dt6.io>
 package cmd
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -22,7 +27,10 @@ import (
 	"github.com/datateamsix/email-sentinel/internal/config"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/log"
+	"github.com/datateamsix/email-sentinel/internal/metrics"
 	"github.com/datateamsix/email-sentinel/internal/notify"
+	"github.com/datateamsix/email-sentinel/internal/otp"
 	"github.com/datateamsix/email-sentinel/internal/rules"
 	"github.com/datateamsix/email-sentinel/internal/state"
 	"github.com/datateamsix/email-sentinel/internal/storage"
@@ -34,6 +42,50 @@ var trayMode bool
 var cleanupInterval int // in minutes
 var aiSummaryEnabled bool
 var searchScope string // Gmail search scope (inbox, all, all-except-trash, spam-only)
+var dryRun bool
+var dryRunNoSave bool
+var debugLogging bool
+var verboseMatching bool // log every checked message and why each filter did or didn't match
+var intervalOverride int // seconds; overrides polling_interval for this run
+
+// minPollingInterval is the fastest allowed polling interval (seconds), to stay well within Gmail API quota
+const minPollingInterval = 10
+
+// maxPollJitterPct caps monitoring.poll_jitter_pct so a check is never delayed more than 1.5x
+const maxPollJitterPct = 50
+
+// defaultFetchLimit and maxFetchLimit bound monitoring.fetch_limit (Gmail returns at most 500 per list call)
+const (
+	defaultFetchLimit = 10
+	maxFetchLimit     = 500
+)
+
+// checkOptions controls side effects of a single email check
+// Passed down explicitly so the processing functions don't depend on flag globals
+type checkOptions struct {
+	DryRun          bool                       // Log matches instead of sending notifications
+	NoSave          bool                       // In dry-run mode, also skip saving alerts to the database
+	Labeler         *gmail.Client              // Applies per-filter Gmail labels and mark-read (nil = read-only mode)
+	Webhooks        []appconfig.WebhookConfig  // Extra HTTP endpoints that receive each alert
+	Senders         appconfig.MonitoringConfig // Sender blocklist/allowlist checked before anything else
+	FetchLimit      int64                      // Newest messages fetched per scope each poll
+	NotifyOnStartup bool                       // Alert on existing mail when nothing is seen yet, instead of baselining it
+	RichSnippet     bool                       // Replace Gmail's snippet with a longer preview from the body
+	MaxMessageAge   time.Duration              // Skip messages Gmail received longer ago than this (0 = no limit)
+	Digests         *notify.Digester           // Batches pushes for filters with a digest (nil in dry-run)
+	OTP             *otpOptions                // Extracts verification codes from every email (nil = OTP detection off)
+	FirstMatchWins  bool                       // Alert only for the first matching filter in config order
+	Async           *asyncWork                 // Tracks AI summary and webhook goroutines so shutdown can wait for them (nil = untracked)
+	Verbose         bool                       // Log each checked message and every filter's match reason
+}
+
+// otpOptions holds the OTP detector and what to do with the codes it finds
+type otpOptions struct {
+	Detector   *otp.Detector
+	AutoCopy   bool          // Copy each new code to the clipboard
+	ClearAfter time.Duration // Clear the clipboard after this long (0 = never)
+	MaxCodes   int           // Codes kept in history (0 = unlimited)
+}
 
 // startCmd represents the start command
 var startCmd = &cobra.Command{
@@ -62,8 +114,17 @@ Examples:
   # Override all filters to search only social category
   email-sentinel start --search social
 
-  # Run as background daemon
-  email-sentinel start --daemon`,
+  # Run as background daemon (stop with: email-sentinel stop)
+  email-sentinel start --daemon
+
+  # Preview which emails would match without sending any notifications
+  email-sentinel start --dry-run
+
+  # Check every 15 seconds for this run (e.g. while waiting for an OTP)
+  email-sentinel start --interval 15
+
+  # See why each email did or didn't match your filters
+  email-sentinel start --dry-run --verbose`,
 	Run: runStart,
 }
 
@@ -73,7 +134,12 @@ func init() {
 	startCmd.Flags().BoolVarP(&trayMode, "tray", "t", false, "Run with system tray icon")
 	startCmd.Flags().IntVar(&cleanupInterval, "cleanup-interval", 60, "Auto-cleanup interval in minutes (0=disabled, default=60)")
 	startCmd.Flags().BoolVar(&aiSummaryEnabled, "ai-summary", false, "Enable AI-powered email summaries")
-	startCmd.Flags().StringVar(&searchScope, "search", "", "Override filter scopes with global search: inbox, all, primary, social, promotions, updates, forums, all-except-trash")
+	startCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Log matches without sending notifications (alerts are still saved)")
+	startCmd.Flags().BoolVar(&dryRunNoSave, "dry-run-no-save", false, "With --dry-run, also skip saving alerts to history")
+	startCmd.Flags().BoolVar(&debugLogging, "debug", false, "Print debug messages (e.g. why an AI summary was skipped)")
+	startCmd.Flags().BoolVar(&verboseMatching, "verbose", false, "Log every checked email and why each filter did or didn't match (or set VERBOSE=1)")
+	startCmd.Flags().IntVar(&intervalOverride, "interval", 0, "Polling interval in seconds for this run, overriding the config (min 10)")
+	startCmd.Flags().StringVar(&searchScope, "search", "", "Override filter scopes with global search: inbox, all, primary, social, promotions, updates, forums, all-except-trash, spam-only (or combine with +)")
 }
 
 func runStart(cmd *cobra.Command, args []string) {
@@ -83,6 +149,12 @@ func runStart(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// --daemon re-launches this command detached; the re-launched copy runs the loop below
+	if daemonMode && !state.IsDaemonProcess() {
+		startDaemon()
+		return
+	}
+
 	// Load unified configuration
 	appCfg, err := appconfig.Load()
 	if err != nil {
@@ -90,6 +162,17 @@ func runStart(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Watcher logs: friendly lines in a terminal, key=value lines for files, pipes and daemons
+	logLevel := appCfg.Monitoring.LogLevel
+	if debugLogging {
+		logLevel = "debug"
+	}
+	if err := log.Setup(logLevel, appCfg.Monitoring.LogOutput); err != nil {
+		fmt.Printf("❌ Invalid logging configuration: %v\n", err)
+		os.Exit(1)
+	}
+	log.SetRedact(appCfg.Monitoring.RedactLogs)
+
 	// Load filter configuration (separate from app-config for now)
 	cfg, err := filter.LoadConfig()
 	if err != nil {
@@ -97,6 +180,20 @@ func runStart(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// --interval overrides the configured polling interval for this run only
+	pollingSeconds := cfg.PollingInterval
+	if cmd.Flags().Changed("interval") {
+		if intervalOverride < minPollingInterval {
+			fmt.Printf("❌ --interval must be at least %d seconds\n", minPollingInterval)
+			os.Exit(1)
+		}
+		pollingSeconds = intervalOverride
+		if pollingSeconds < 30 {
+			fmt.Printf("⚠️  Polling every %d seconds uses Gmail API quota quickly; use short intervals only temporarily\n", pollingSeconds)
+		}
+	}
+	pollingInterval := time.Duration(pollingSeconds) * time.Second
+
 	if len(cfg.Filters) == 0 {
 		fmt.Println("⚠️  No filters configured yet.")
 		fmt.Println("\n📝 You can add filters in several ways:")
@@ -120,7 +217,8 @@ func runStart(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	oauthConfig, err := gmail.LoadCredentials(credPath)
+	// Only request gmail.modify when the user has explicitly opted in
+	oauthConfig, err := gmail.LoadCredentialsWithModify(credPath, appCfg.Monitoring.Gmail.AllowModify)
 	if err != nil {
 		fmt.Printf("❌ Error loading credentials: %v\n", err)
 		os.Exit(1)
@@ -156,13 +254,41 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 	defer storage.CloseDB(db)
 
-	// Run automatic backup on startup to ensure we have a recent backup
-	storage.AutoBackupOnStartup(db)
+	// Run automatic backup on startup to ensure we have a recent backup (monitoring.backup)
+	if appCfg.Monitoring.Backup.Enabled {
+		backupOpts, err := backupOptions(appCfg)
+		if err != nil {
+			fmt.Printf("❌ Invalid monitoring.backup.directory: %v\n", err)
+			os.Exit(1)
+		}
+		storage.AutoBackupOnStartup(db, backupOpts)
+	} else {
+		log.Debug("Startup backup disabled (monitoring.backup.enabled: false)")
+	}
+
+	// Daily cleanup, quiet hours and weekend mode all run on the configured timezone
+	location, err := appCfg.Monitoring.GetLocation()
+	if err != nil {
+		fmt.Printf("❌ Invalid monitoring.timezone: %v\n", err)
+		os.Exit(1)
+	}
+
+	maxMessageAge, err := appCfg.Monitoring.GetMaxMessageAge()
+	if err != nil {
+		fmt.Printf("❌ Invalid monitoring.max_message_age: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Start daily cleanup scheduler (runs at 12:00 AM)
+	retentionDays := appCfg.Monitoring.Database.RetentionDays
+	if retentionDays > 0 {
+		fmt.Printf("🗄️  Alert retention: keeping %d day(s) of history\n", retentionDays)
+	} else {
+		fmt.Println("🗄️  Alert retention: alerts are wiped daily at midnight")
+	}
 	stopCleanup := make(chan struct{})
 	defer close(stopCleanup)
-	go storage.StartDailyCleanup(db, stopCleanup)
+	go storage.StartDailyCleanup(db, retentionDays, location, stopCleanup)
 
 	// Create priority rules from unified config
 	priorityRules := &rules.Rules{
@@ -170,11 +296,14 @@ func runStart(cmd *cobra.Command, args []string) {
 			UrgentKeywords: appCfg.Priority.UrgentKeywords,
 			VIPSenders:     appCfg.Priority.VIPSenders,
 			VIPDomains:     appCfg.Priority.VIPDomains,
+			VIPPatterns:    appCfg.Priority.VIPPatterns,
 		},
 		NotificationSettings: rules.NotificationSettings{
 			QuietHoursStart: appCfg.Notifications.QuietHours.Start,
 			QuietHoursEnd:   appCfg.Notifications.QuietHours.End,
 			WeekendMode:     appCfg.Notifications.WeekendMode,
+			AllowUrgent:     appCfg.Notifications.QuietHours.AllowUrgent,
+			Location:        location,
 		},
 	}
 
@@ -187,14 +316,52 @@ func runStart(cmd *cobra.Command, args []string) {
 		aiService, err = ai.NewService(aiConfig, db)
 		if err != nil {
 			fmt.Printf("⚠️  AI summary disabled: %v\n", err)
-			fmt.Println("   Tip: Set API key environment variable (GEMINI_API_KEY, ANTHROPIC_API_KEY, or OPENAI_API_KEY)")
+			if appCfg.AISummary.Provider == "ollama" {
+				fmt.Println("   Tip: Set ai_summary.providers.ollama.model and make sure Ollama is running")
+			} else {
+				fmt.Println("   Tip: Set API key environment variable (GEMINI_API_KEY, ANTHROPIC_API_KEY, or OPENAI_API_KEY)")
+			}
+		}
+	}
+
+	// Desktop toasts can be muted at runtime from the tray, which updates both configs
+	notify.SetDesktopEnabled(cfg.Notifications.Desktop && appCfg.Notifications.Desktop.Enabled)
+	notify.SetDesktopOptions(desktopOptions(appCfg))
+	mobileOpts, err := notify.MobileOptionsFromConfig(&appCfg.Notifications.Mobile)
+	if err != nil {
+		fmt.Printf("❌ Invalid notifications.mobile.server: %v\n", err)
+		os.Exit(1)
+	}
+	notify.SetMobileOptions(mobileOpts)
+
+	// Prometheus metrics endpoint (off by default, loopback unless configured otherwise)
+	if appCfg.Monitoring.Metrics.Enabled {
+		if server, err := startMetricsServer(appCfg.Monitoring.Metrics); err != nil {
+			fmt.Printf("⚠️  Metrics endpoint disabled: %v\n", err)
+		} else {
+			defer server.Close()
 		}
 	}
 
 	fmt.Println("✅ Email Sentinel Started")
-	fmt.Printf("   Monitoring %d filter(s)\n", len(cfg.Filters))
-	fmt.Printf("   Polling interval: %d seconds\n", cfg.PollingInterval)
-	if cfg.Notifications.Desktop {
+	if disabled := disabledFilterCount(cfg); disabled > 0 {
+		fmt.Printf("   Monitoring %d filter(s) (%d disabled)\n", len(cfg.Filters)-disabled, disabled)
+	} else {
+		fmt.Printf("   Monitoring %d filter(s)\n", len(cfg.Filters))
+	}
+	if pollingSeconds != cfg.PollingInterval {
+		fmt.Printf("   Polling interval: %d seconds (--interval override, config: %d)\n", pollingSeconds, cfg.PollingInterval)
+	} else {
+		fmt.Printf("   Polling interval: %d seconds\n", pollingSeconds)
+	}
+	pollJitterPct := appCfg.Monitoring.PollJitterPct
+	if pollJitterPct > 0 {
+		fmt.Printf("   Poll jitter: ±%d%%\n", min(pollJitterPct, maxPollJitterPct))
+	}
+	if appCfg.Monitoring.Timezone != "" {
+		fmt.Printf("   Timezone: %s\n", location)
+	}
+	if notify.DesktopEnabled() {
 		fmt.Println("   Desktop notifications: enabled")
 	}
 	if cfg.Notifications.Mobile.Enabled {
@@ -205,6 +372,11 @@ func runStart(cmd *cobra.Command, args []string) {
 		fmt.Printf("   AI provider: %s\n", appCfg.AISummary.Provider)
 	}
 
+	// Ctrl-C, SIGTERM or Quit in the tray cancels ctx: a check in progress stops
+	// between messages and the monitoring loop shuts down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Start system tray if requested
 	if trayMode {
 		fmt.Println("   System tray: enabled")
@@ -221,56 +393,158 @@ func runStart(cmd *cobra.Command, args []string) {
 			tray.Run(tray.Config{
 				DB:              db,
 				CleanupInterval: time.Duration(cleanupInterval) * time.Minute,
+				RetentionDays:   retentionDays,
+				OnDesktopToggle: saveDesktopNotificationSetting,
+				OTPClearAfter:   otpClearAfter(appCfg),
 			})
+			stop()
 		}()
 
 		// Give tray time to initialize
 		time.Sleep(2 * time.Second)
 	}
 
-	// Build Gmail search query from scope flag (if provided)
-	var gmailSearchQuery string
-	if searchScope != "" {
-		gmailSearchQuery = buildGmailSearchQuery(searchScope)
-		fmt.Printf("   Global search override: %s (query: '%s')\n", searchScope, gmailSearchQuery)
+	// The --search scope (if provided) replaces every filter's own scope
+	overrideScope := strings.ToLower(strings.TrimSpace(searchScope))
+	if overrideScope != "" {
+		if !filter.IsValidGmailScope(overrideScope) {
+			log.Warn("Unknown search scope, defaulting to 'inbox'", "scope", searchScope)
+			overrideScope = "inbox"
+		}
+		fmt.Printf("   Global search override: %s (query: '%s')\n", overrideScope, filter.BuildGmailSearchQuery(overrideScope))
 	} else {
 		fmt.Println("   Using per-filter Gmail scopes")
 	}
 
+	opts := checkOptions{
+		DryRun:          dryRun,
+		NoSave:          dryRun && dryRunNoSave,
+		Webhooks:        appCfg.Notifications.Webhooks,
+		Senders:         appCfg.Monitoring,
+		FetchLimit:      fetchLimit(appCfg.Monitoring.FetchLimit),
+		NotifyOnStartup: appCfg.Monitoring.NotifyOnStartup,
+		RichSnippet:     appCfg.Monitoring.RichSnippet,
+		MaxMessageAge:   maxMessageAge,
+		FirstMatchWins:  appCfg.Filters.FirstMatchWins,
+		Verbose:         verboseMatching || envEnabled("VERBOSE"),
+		Async:           newAsyncWork(),
+	}
+	if !dryRun {
+		opts.Digests = notify.NewDigester(sendDigest)
+	}
+	if opts.FetchLimit != defaultFetchLimit {
+		fmt.Printf("   Fetch limit: %d messages per scope\n", opts.FetchLimit)
+	}
+	if opts.MaxMessageAge > 0 {
+		fmt.Printf("   Max message age: %s\n", opts.MaxMessageAge)
+	}
+	if len(opts.Webhooks) > 0 {
+		fmt.Printf("   Webhooks: %d configured\n", len(opts.Webhooks))
+	}
+	if opts.RichSnippet {
+		fmt.Println("   Rich snippets: enabled")
+	}
+	if opts.FirstMatchWins {
+		fmt.Println("   Filter routing: first match wins")
+	}
+	if appCfg.OTP.Enabled {
+		if opts.OTP, err = newOTPOptions(appCfg); err != nil {
+			fmt.Printf("⚠️  OTP detection disabled: %v\n", err)
+		} else if opts.OTP.AutoCopy {
+			fmt.Println("   OTP detection: enabled (auto-copy to clipboard)")
+		} else {
+			fmt.Println("   OTP detection: enabled")
+		}
+	}
+	if blocked := len(appCfg.Monitoring.BlocklistSenders) + len(appCfg.Monitoring.BlocklistDomains); blocked > 0 {
+		fmt.Printf("   Blocklist: %d senders/domains ignored\n", blocked)
+	}
+	if allowed := len(appCfg.Monitoring.AllowlistSenders) + len(appCfg.Monitoring.AllowlistDomains); allowed > 0 {
+		fmt.Printf("   Allowlist: only %d senders/domains processed\n", allowed)
+	}
+	if appCfg.Monitoring.Gmail.AllowModify {
+		opts.Labeler = client
+		fmt.Println("   Gmail labels and mark-read: enabled (gmail.modify scope)")
+	} else if filtersModifyGmail(cfg) {
+		fmt.Println("   ⚠️  Some filters set apply_gmail_label or mark_read, but Gmail access is read-only")
+		fmt.Println("      Enable with: email-sentinel init --reauth --scopes modify")
+	}
+	if opts.DryRun {
+		fmt.Println("   🧪 Dry-run mode: matches are logged, no notifications will be sent")
+		if opts.NoSave {
+			fmt.Println("   🧪 Dry-run mode: alerts will not be saved to history")
+		}
+	}
+
+	// Warn up front if Gmail access is about to stop (or already has)
+	authLifetime, authWindow := authWarningDurations(appCfg.Monitoring.Gmail)
+	if warning, ok := state.CheckAuth(time.Now(), authLifetime, authWindow); ok {
+		fmt.Println()
+		fmt.Println("⚠️  ══════════ GMAIL AUTH WARNING ══════════")
+		fmt.Printf("   %s\n", warning.Message)
+		fmt.Println("   Emails may be missed until you re-authorize: email-sentinel init --reauth")
+		notifyAuthWarning(warning, time.Now())
+	}
+
 	fmt.Println("\n🔍 Watching for new emails... (Press Ctrl+C to stop)")
 	fmt.Println("")
 
-	// Setup signal handling for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// The pidfile lets 'email-sentinel status' and 'stop' find the daemon
+	if state.IsDaemonProcess() {
+		if err := state.WritePIDFile(); err != nil {
+			fmt.Printf("⚠️  Failed to write pidfile: %v\n", err)
+		}
+		defer state.RemovePIDFile()
+	}
 
 	// Start monitoring loop with circuit breaker
-	ticker := time.NewTicker(time.Duration(cfg.PollingInterval) * time.Second)
-	defer ticker.Stop()
+	// A timer re-armed every cycle (instead of a ticker) lets each wait carry its own jitter
+	pollTimer := time.NewTimer(jitteredInterval(pollingInterval, pollJitterPct))
+	defer pollTimer.Stop()
 
 	// Circuit breaker state
 	var (
 		failureCount    int
 		lastFailureTime time.Time
-		backoffDuration = time.Duration(cfg.PollingInterval) * time.Second
+		backoffDuration = pollingInterval
 	)
 
-	// Do initial check
-	if err := checkEmailsWithRecovery(client, cfg, seenMessages, db, priorityRules, aiService, gmailSearchQuery); err != nil {
-		failureCount++
-		lastFailureTime = time.Now()
+	// Runtime status is written to status.json so the dashboard can show API health
+	runtimeStatus := state.NewRuntimeStatus()
+	defer func() {
+		if err := runtimeStatus.MarkStopped(); err != nil {
+			log.Warn("Failed to update runtime status", "error", err)
+		}
+	}()
+
+	// Pausing (email-sentinel pause / tray) skips checks but keeps this loop running
+	paused := state.IsPaused()
+	if paused {
+		log.Info("Monitoring is paused (run: email-sentinel resume)", log.Icon("⏸️ "))
+		recordPausedStatus(runtimeStatus, time.Now().Add(backoffDuration))
+	} else {
+		// Do initial check
+		err = checkEmailsWithRecovery(ctx, client, cfg, seenMessages, db, priorityRules, aiService, overrideScope, opts)
+		if countsAsFailure(err, cfg) {
+			failureCount++
+			lastFailureTime = time.Now()
+		}
+		metrics.SetConsecutiveFailures(failureCount)
+		recordRuntimeStatus(runtimeStatus, err, time.Now().Add(backoffDuration))
 	}
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-pollTimer.C:
+			pollTimer.Reset(jitteredInterval(pollingInterval, pollJitterPct))
+
 			// Check for expired filters and clean them up
 			removed, err := filter.CleanupExpiredFilters()
 			if err != nil {
-				fmt.Printf("⚠️  Error checking for expired filters: %v\n", err)
+				log.Warn("Error checking for expired filters", "error", err)
 			} else if len(removed) > 0 {
 				for _, name := range removed {
-					fmt.Printf("🗑️  Filter '%s' expired and was automatically removed\n", name)
+					log.Info("Filter expired and was automatically removed", log.Icon("🗑️ "), "filter", name)
 					// Send notification about expired filter
 					notify.SendDesktopNotification(
 						"Filter Expired",
@@ -280,46 +554,89 @@ func runStart(cmd *cobra.Command, args []string) {
 				// Reload config since filters were removed
 				cfg, err = filter.LoadConfig()
 				if err != nil {
-					fmt.Printf("⚠️  Error reloading config after cleanup: %v\n", err)
+					log.Warn("Error reloading config after cleanup", "error", err)
 				}
 			}
 
 			// Check for expiring trials and send alerts
 			checkExpiringTrials(db)
 
+			// Mark OTP codes past their expiry as inactive (old codes are deleted by the daily cleanup)
+			if expired, err := storage.ExpireOTPAlerts(db); err != nil {
+				log.Warn("Error expiring OTP codes", "error", err)
+			} else if expired > 0 {
+				log.Info("OTP codes expired", log.Icon("🔐"), "count", expired)
+			}
+
+			// Remind about Gmail auth trouble recorded by the token refresh monitor
+			if warning, ok := state.CheckAuth(time.Now(), authLifetime, authWindow); ok {
+				notifyAuthWarning(warning, time.Now())
+			}
+
+			// Skip checks while paused; resumes automatically when a timed pause elapses
+			if state.IsPaused() {
+				if !paused {
+					log.Info("Monitoring paused", log.Icon("⏸️ "))
+					paused = true
+				}
+				recordPausedStatus(runtimeStatus, time.Now().Add(pollingInterval))
+				continue
+			}
+			if paused {
+				log.Info("Monitoring resumed", log.Icon("▶️ "))
+				paused = false
+			}
+
 			// Circuit breaker: implement exponential backoff on repeated failures
 			if failureCount > 0 && time.Since(lastFailureTime) < backoffDuration {
-				fmt.Printf("[%s] Backing off due to %d consecutive failures... waiting %v\n",
-					time.Now().Format("15:04:05"), failureCount, backoffDuration)
+				log.Info("Backing off due to consecutive failures", log.Icon("⏳"),
+					"failures", failureCount, "wait", backoffDuration)
+				runtimeStatus.NextCheck = lastFailureTime.Add(backoffDuration)
+				runtimeStatus.UpdatedAt = time.Now()
+				saveRuntimeStatus(runtimeStatus)
 				continue
 			}
 
 			// Attempt email check with recovery
-			if err := checkEmailsWithRecovery(client, cfg, seenMessages, db, priorityRules, aiService, gmailSearchQuery); err != nil {
+			err = checkEmailsWithRecovery(ctx, client, cfg, seenMessages, db, priorityRules, aiService, overrideScope, opts)
+			if countsAsFailure(err, cfg) {
 				failureCount++
 				lastFailureTime = time.Now()
 
-				// Exponential backoff: 45s, 90s, 180s, 360s (max 6 minutes)
-				backoffDuration = time.Duration(cfg.PollingInterval*(1<<uint(min(failureCount-1, 3)))) * time.Second
+				// Exponential backoff: 1x, 2x, 4x, 8x the polling interval (45s interval: max 6 minutes)
+				backoffDuration = pollingInterval * time.Duration(1<<uint(min(failureCount-1, 3)))
 
 				if failureCount >= 5 {
-					fmt.Printf("\n❌ CRITICAL: %d consecutive Gmail API failures\n", failureCount)
-					fmt.Printf("   Last error: %v\n", err)
-					fmt.Printf("   Backing off for %v before next attempt\n", backoffDuration)
-					fmt.Printf("   Check your network connection and Gmail API quota\n\n")
+					log.Error("CRITICAL: repeated Gmail API failures, check your network connection and Gmail API quota",
+						"failures", failureCount, "error", err, "backoff", backoffDuration)
 				}
-			} else {
+			} else if err == nil {
 				// Success - reset circuit breaker
 				if failureCount > 0 {
-					fmt.Printf("[%s] ✅ Gmail API recovered after %d failures\n",
-						time.Now().Format("15:04:05"), failureCount)
+					log.Info("Gmail API recovered", log.Icon("✅"), "failures", failureCount)
 					failureCount = 0
-					backoffDuration = time.Duration(cfg.PollingInterval) * time.Second
+					backoffDuration = pollingInterval
 				}
 			}
-
-		case <-sigChan:
-			fmt.Println("\n\n⏹️  Stopping Email Sentinel...")
+			metrics.SetConsecutiveFailures(failureCount)
+			recordRuntimeStatus(runtimeStatus, err, time.Now().Add(backoffDuration))
+
+		case <-ctx.Done():
+			// Restore default signal handling so a second Ctrl-C exits instead of waiting out the shutdown
+			stop()
+			log.Info("Stopping Email Sentinel...", log.Icon("⏹️ "))
+			seenMessages.Flush() // logs its own failures
+			if opts.Digests != nil && opts.Digests.Pending() > 0 {
+				log.Info("Sending pending digests", log.Icon("📬"), "emails", opts.Digests.Pending())
+				opts.Digests.FlushAll()
+			}
+			// Let in-flight AI summaries and webhooks finish before the database is closed
+			if running := opts.Async.Pending(); running > 0 {
+				log.Info("Waiting for AI summaries and webhooks to finish", log.Icon("🤖"), "tasks", running, "timeout", shutdownTimeout)
+			}
+			if dropped := opts.Async.Wait(shutdownTimeout); dropped > 0 {
+				log.Warn("Shutdown timed out, AI summaries and webhooks dropped", "tasks", dropped)
+			}
 			if trayMode {
 				tray.Quit()
 			}
@@ -328,6 +645,213 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 }
 
+// shutdownTimeout bounds how long stopping waits for in-flight AI summaries
+const shutdownTimeout = 10 * time.Second
+
+// asyncWork tracks background goroutines (AI summaries, webhooks) so shutdown can wait for them
+// Its context is only cancelled once the shutdown wait times out, so work that is
+// in flight at Ctrl-C gets the chance to finish and save its result.
+type asyncWork struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	pending int
+	closed  bool
+}
+
+// newAsyncWork creates an empty tracker
+func newAsyncWork() *asyncWork {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &asyncWork{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a tracked goroutine; returns false once shutdown has started
+// A nil tracker runs fn untracked.
+func (w *asyncWork) Go(fn func(ctx context.Context)) bool {
+	if w == nil {
+		go fn(context.Background())
+		return true
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return false
+	}
+	w.pending++
+	w.wg.Add(1)
+	w.mu.Unlock()
+
+	go func() {
+		defer func() {
+			w.mu.Lock()
+			w.pending--
+			w.mu.Unlock()
+			w.wg.Done()
+		}()
+		fn(w.ctx)
+	}()
+	return true
+}
+
+// Pending returns the number of goroutines still running
+func (w *asyncWork) Pending() int {
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pending
+}
+
+// Wait refuses new work and waits up to timeout for the goroutines in flight
+// Whatever is still running then is cancelled; returns how many were cut off.
+func (w *asyncWork) Wait(timeout time.Duration) int {
+	if w == nil {
+		return 0
+	}
+
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.cancel()
+		return 0
+	case <-time.After(timeout):
+	}
+
+	dropped := w.Pending()
+	w.cancel()
+
+	// Give cancelled work a moment to return before the database is closed
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+	return dropped
+}
+
+// daemonStartupWait is how long startDaemon waits to confirm the daemon didn't exit on startup
+const daemonStartupWait = 2 * time.Second
+
+// startDaemon re-executes 'start' in the background and reports where it logs
+func startDaemon() {
+	if pid := state.RunningDaemonPID(); pid > 0 {
+		fmt.Printf("⚠️  Email Sentinel is already running in the background (PID: %d)\n", pid)
+		fmt.Println("   Stop it with: email-sentinel stop")
+		os.Exit(1)
+	}
+
+	logPath, _ := state.DaemonLogPath()
+
+	// Config or credential errors make the daemon exit right away
+	pid, err := state.StartDaemon(os.Args[1:], daemonStartupWait)
+	if err != nil {
+		fmt.Printf("❌ Error starting daemon: %v\n", err)
+		fmt.Printf("   See the log for details: %s\n", logPath)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Email Sentinel started in the background (PID: %d)\n", pid)
+	fmt.Printf("   Log: %s\n", logPath)
+	fmt.Println("   Check:  email-sentinel status")
+	fmt.Println("   Stop:   email-sentinel stop")
+}
+
+// authNotifyInterval limits how often the same auth warning is sent as a desktop notification
+const authNotifyInterval = 12 * time.Hour
+
+// startMetricsServer serves Prometheus metrics at http://<addr>/metrics
+func startMetricsServer(metricsCfg appconfig.MetricsConfig) (*http.Server, error) {
+	addr, err := metricsCfg.GetAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := metrics.Serve(addr)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Serving Prometheus metrics", log.Icon("📈"), "url", "http://"+addr+"/metrics")
+	return server, nil
+}
+
+// desktopOptions returns the configured desktop notification duration and sound
+func desktopOptions(appCfg *appconfig.AppConfig) notify.DesktopOptions {
+	return notify.DesktopOptions{
+		Duration: time.Duration(appCfg.Notifications.Desktop.Duration) * time.Second,
+		Sound:    appCfg.Notifications.Desktop.Sound,
+	}
+}
+
+// authWarningDurations returns the configured auth lifetime and warning window
+// Invalid values are reported and fall back to no known expiry / 24h.
+func authWarningDurations(gmailCfg appconfig.GmailConfig) (time.Duration, time.Duration) {
+	lifetime, err := gmailCfg.GetTokenLifetime()
+	if err != nil {
+		fmt.Printf("⚠️  Invalid monitoring.gmail.token_lifetime %q, expiry warnings disabled\n", gmailCfg.TokenLifetime)
+		lifetime = 0
+	}
+
+	window, err := gmailCfg.GetAuthWarningWindow()
+	if err != nil {
+		fmt.Printf("⚠️  Invalid monitoring.gmail.auth_warning_window %q, using 24h\n", gmailCfg.AuthWarningWindow)
+		window = 24 * time.Hour
+	}
+
+	return lifetime, window
+}
+
+// notifyAuthWarning logs an auth warning and sends a desktop notification,
+// at most once per authNotifyInterval (tracked in auth.json across restarts)
+func notifyAuthWarning(warning *state.AuthWarning, now time.Time) {
+	auth, err := state.LoadAuthState()
+	if err == nil && now.Sub(auth.NotifiedAt) < authNotifyInterval {
+		return
+	}
+
+	log.Warn("Gmail auth needs attention, re-authenticate with: email-sentinel init --reauth", log.Icon("🔑"), "reason", warning.Message)
+	if notify.DesktopEnabled() {
+		if err := notify.SendDesktopNotification("Gmail auth expiring — run email-sentinel init", warning.Message); err != nil {
+			log.Warn("Desktop notification failed", "error", err)
+		}
+	}
+
+	if err := state.MarkAuthNotified(now); err != nil {
+		log.Warn("Failed to update auth state", "error", err)
+	}
+}
+
+// recordRuntimeStatus records the outcome of a check cycle in status.json
+func recordRuntimeStatus(status *state.RuntimeStatus, checkErr error, nextCheck time.Time) {
+	metrics.SetLastCheck(time.Now())
+	status.RecordCheck(checkErr, nextCheck)
+	saveRuntimeStatus(status)
+}
+
+// recordPausedStatus keeps status.json fresh while paused so the watcher still shows as running
+func recordPausedStatus(status *state.RuntimeStatus, nextCheck time.Time) {
+	status.NextCheck = nextCheck
+	status.UpdatedAt = time.Now()
+	saveRuntimeStatus(status)
+}
+
+// saveRuntimeStatus persists the status, logging (but not failing) on error
+func saveRuntimeStatus(status *state.RuntimeStatus) {
+	if err := status.Save(); err != nil {
+		log.Warn("Failed to update runtime status", "error", err)
+	}
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -336,42 +860,115 @@ func min(a, b int) int {
 	return b
 }
 
-// checkEmailsWithRecovery wraps checkEmails with panic recovery
-// buildGmailSearchQuery converts a search scope string to a Gmail search query
-func buildGmailSearchQuery(scope string) string {
-	switch strings.ToLower(scope) {
-	case "all":
-		return "" // Empty query = search everything
-	case "all-except-trash":
-		return "-in:trash"
-	case "spam-only":
-		return "in:spam"
-	case "promotions":
-		return "category:promotions"
-	case "social":
-		return "category:social"
-	case "updates":
-		return "category:updates"
-	case "forums":
-		return "category:forums"
-	case "inbox":
-		return "in:inbox"
-	default:
-		// Default to inbox if unknown scope
-		fmt.Printf("⚠️  Unknown search scope '%s', defaulting to 'inbox'\n", scope)
-		return "in:inbox"
+// jitteredInterval returns interval randomly adjusted by up to ±pct percent
+// Spreads checks out so many clients don't hit the Gmail API in lockstep. pct 0 disables jitter.
+func jitteredInterval(interval time.Duration, pct int) time.Duration {
+	if pct <= 0 {
+		return interval
+	}
+	if pct > maxPollJitterPct {
+		pct = maxPollJitterPct
 	}
+
+	spread := float64(interval) * float64(pct) / 100
+	return interval + time.Duration((rand.Float64()*2-1)*spread)
 }
 
-func checkEmailsWithRecovery(client *gmail.Client, cfg *filter.Config, seenMessages *state.SeenMessages, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service, searchQuery string) (err error) {
+// fetchLimit returns the configured messages-per-scope for each poll
+// 0 (unset) falls back to the default; values above the Gmail API maximum are capped
+func fetchLimit(configured int) int64 {
+	if configured <= 0 {
+		return defaultFetchLimit
+	}
+	if configured > maxFetchLimit {
+		return maxFetchLimit
+	}
+	return int64(configured)
+}
+
+// checkEmailsWithRecovery wraps checkEmails with panic recovery
+func checkEmailsWithRecovery(ctx context.Context, client gmail.MessageFetcher, cfg *filter.Config, seenMessages *state.SeenMessages, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service, overrideScope string, opts checkOptions) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic in checkEmails: %v", r)
-			fmt.Printf("\n❌ PANIC RECOVERED in email checking: %v\n", r)
+			log.Error("PANIC RECOVERED in email checking", "panic", r)
 		}
 	}()
 
-	return checkEmails(client, cfg, seenMessages, db, priorityRules, aiService, searchQuery)
+	return checkEmails(ctx, client, cfg, seenMessages, db, priorityRules, aiService, overrideScope, opts)
+}
+
+// newOTPOptions builds the OTP detector from the unified config
+func newOTPOptions(appCfg *appconfig.AppConfig) (*otpOptions, error) {
+	otpRules, err := createOTPRulesFromAppConfig(appCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	detector, err := otp.NewDetector(otpRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &otpOptions{
+		Detector:   detector,
+		AutoCopy:   appCfg.OTP.Clipboard.AutoCopy,
+		ClearAfter: otpClearAfter(appCfg),
+		MaxCodes:   appCfg.OTP.MaxCodes,
+	}, nil
+}
+
+// createOTPRulesFromAppConfig converts the unified OTP settings to detector rules
+// Unset values fall back to the otp package defaults
+func createOTPRulesFromAppConfig(appCfg *appconfig.AppConfig) (*otp.OTPRules, error) {
+	otpCfg := appCfg.OTP
+
+	otpRules := &otp.OTPRules{
+		Enabled:                 otpCfg.Enabled,
+		ConfidenceThreshold:     otpCfg.ConfidenceThreshold,
+		AutoCopy:                otpCfg.Clipboard.AutoCopy,
+		TrustedSenders:          otpCfg.TrustedSenders,
+		TrustedDomains:          otpCfg.TrustedDomains,
+		RequireTrustedSender:    otpCfg.RequireTrustedSender,
+		TriggerPhrases:          otpCfg.TriggerPhrases,
+		RequireTriggerProximity: otpCfg.RequireTriggerProximity,
+	}
+
+	if otpCfg.ExpiryDuration != "" {
+		expiry, err := otpCfg.GetOTPExpiryDuration()
+		if err != nil {
+			return nil, fmt.Errorf("invalid otp.expiry_duration: %w", err)
+		}
+		otpRules.ExpiryDuration = expiry
+	}
+
+	overrides, err := otpCfg.GetOTPExpiryOverrides()
+	if err != nil {
+		return nil, err
+	}
+	otpRules.ExpiryOverrides = overrides
+
+	for _, p := range otpCfg.CustomPatterns {
+		otpRules.CustomPatterns = append(otpRules.CustomPatterns, otp.CustomPattern{
+			Name:       p.Description,
+			Regex:      p.Pattern,
+			Confidence: customPatternConfidence(p.Confidence),
+		})
+	}
+
+	return otp.MergeWithDefaults(otpRules), nil
+}
+
+// customPatternConfidence maps a custom pattern's "high"/"medium"/"low" to a base confidence score
+func customPatternConfidence(level string) float64 {
+	switch strings.ToLower(level) {
+	case "high":
+		return 0.9
+	case "low":
+		return 0.5
+	default:
+		return 0.7
+	}
 }
 
 // createAIConfigFromAppConfig converts the unified AppConfig to the AI config format
@@ -399,77 +996,100 @@ func createAIConfigFromAppConfig(appCfg *appconfig.AppConfig) *ai.Config {
 					MaxTokens:   appCfg.AISummary.Providers.Gemini.MaxTokens,
 					Temperature: appCfg.AISummary.Providers.Gemini.Temperature,
 				},
+				Ollama: ai.OllamaConfig{
+					Endpoint:    appCfg.AISummary.Providers.Ollama.Endpoint,
+					Model:       appCfg.AISummary.Providers.Ollama.Model,
+					MaxTokens:   appCfg.AISummary.Providers.Ollama.MaxTokens,
+					Temperature: appCfg.AISummary.Providers.Ollama.Temperature,
+				},
 			},
 			Behavior: ai.BehaviorConfig{
-				EnableCache: appCfg.AISummary.Cache.Enabled,
+				EnableCache:  appCfg.AISummary.Cache.Enabled,
+				CacheTTL:     appCfg.AISummary.Cache.TTL,
+				PriorityOnly: appCfg.AISummary.PriorityOnly,
 				// Set defaults for fields not in new config
-				MaxSummaryLength:       500,
-				PriorityOnly:           false,
+				MaxSummaryLength:       appCfg.AISummary.GetMaxSummaryLength(),
 				TimeoutSeconds:         30,
 				RetryAttempts:          3,
 				IncludeInNotifications: true,
 				ShowAIIcon:             true,
 			},
-			RateLimit: ai.RateLimitConfig{
-				MaxPerHour: appCfg.AISummary.Providers.Gemini.RateLimit.RequestsPerMinute * 60,
-				MaxPerDay:  appCfg.AISummary.Providers.Gemini.RateLimit.RequestsPerDay,
-			},
+			RateLimit: providerRateLimit(appCfg),
 			Prompt: ai.PromptConfig{
 				System:       appCfg.AISummary.Prompt.System,
-				UserTemplate: "Summarize this email:\n\nFrom: {{.From}}\nSubject: {{.Subject}}\n\n{{.Body}}",
+				UserTemplate: "Summarize this email in at most {{.MaxLength}} characters:\n\nFrom: {{.Sender}}\nSubject: {{.Subject}}\n\n{{.Body}}",
+				Templates:    appCfg.AISummary.Prompt.Templates,
 			},
 		},
 	}
 }
 
-func checkEmails(client *gmail.Client, cfg *filter.Config, seenMessages *state.SeenMessages, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service, searchQuery string) error {
-	// Get all unique scopes from filters for optimized fetching
-	uniqueScopes, err := filter.GetAllUniqueScopes()
+// providerRateLimit returns the rate limits for the configured AI provider
+func providerRateLimit(appCfg *appconfig.AppConfig) ai.RateLimitConfig {
+	var limits appconfig.RateLimitConfig
+	switch appCfg.AISummary.Provider {
+	case "claude":
+		limits = appCfg.AISummary.Providers.Claude.RateLimit
+	case "openai":
+		limits = appCfg.AISummary.Providers.OpenAI.RateLimit
+	case "ollama":
+		limits = appCfg.AISummary.Providers.Ollama.RateLimit
+	default:
+		limits = appCfg.AISummary.Providers.Gemini.RateLimit
+	}
+
+	return ai.RateLimitConfig{
+		RequestsPerMinute: limits.RequestsPerMinute,
+		MaxPerDay:         limits.RequestsPerDay,
+		DailyTokenBudget:  appCfg.AISummary.DailyTokenBudget,
+	}
+}
+
+func checkEmails(ctx context.Context, client gmail.MessageFetcher, cfg *filter.Config, seenMessages *state.SeenMessages, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service, overrideScope string, opts checkOptions) error {
+	// One query per unique filter scope plus gmail_query; the --search
+	// override replaces every filter's scope
+	queries, err := filter.GetAllSearchQueries(overrideScope)
 	if err != nil {
-		fmt.Printf("⚠️  Error getting filter scopes: %v\n", err)
+		log.Warn("Error getting filter queries", "error", err)
 		return err
 	}
 
-	// If global search query is provided (via --search flag), use it
-	// Otherwise, fetch messages for each unique scope
-	var allMessages []*googlemail.Message
-	var fetchErr error
+	// Category scopes share one round trip; FetchedBy remembers which queries returned
+	// each message so gmail_query filters only match their own results
+	fetched, err := client.GetMessagesForScopes(opts.FetchLimit, queries)
+	if err != nil {
+		metrics.IncGmailAPIError()
+		return err
+	}
+	allMessages, fetchedBy := fetched.Messages, fetched.FetchedBy
 
-	if searchQuery != "" {
-		// Global scope override from command line flag
-		allMessages, fetchErr = client.GetRecentMessagesWithQuery(10, searchQuery)
-	} else {
-		// Fetch messages for each unique filter scope
-		messageMap := make(map[string]*googlemail.Message)
-		for _, scope := range uniqueScopes {
-			query := filter.BuildGmailSearchQuery(scope)
-			messages, err := client.GetRecentMessagesWithQuery(10, query)
-			if err != nil {
-				fmt.Printf("⚠️  Error fetching messages for scope '%s': %v\n", scope, err)
-				fetchErr = err
-				continue
-			}
+	// Save newly seen IDs once per check (also after a recovered panic)
+	defer seenMessages.Flush() // logs its own failures
 
-			// Deduplicate messages by ID
-			for _, msg := range messages {
-				messageMap[msg.Id] = msg
-			}
+	// First run (nothing seen yet): treat the current inbox as a baseline instead of alerting on old mail
+	if seenMessages.Count() == 0 && !opts.NotifyOnStartup && len(allMessages) > 0 {
+		ids := make([]string, 0, len(allMessages))
+		for _, msg := range allMessages {
+			ids = append(ids, msg.Id)
 		}
-
-		// Convert map to slice
-		allMessages = make([]*googlemail.Message, 0, len(messageMap))
-		for _, msg := range messageMap {
-			allMessages = append(allMessages, msg)
-		}
-	}
-
-	if fetchErr != nil {
-		return fetchErr
+		seenMessages.MarkMultipleSeen(ids)
+		log.Info("Baseline set: existing messages marked as seen, only new mail will alert", log.Icon("📌"), "messages", len(ids))
+		return nil
 	}
 
 	matchCount := 0
+	checkedCount := 0
+
+	// Cache bodies for this check so each message is only fetched/decoded once
+	bodyCache := make(map[string]string)
+
+	for i, msg := range allMessages {
+		// Stop between messages on shutdown; unseen ones are picked up by the next run
+		if ctx.Err() != nil {
+			log.Info("Shutting down, remaining messages left for the next run", "remaining", len(allMessages)-i)
+			break
+		}
 
-	for _, msg := range allMessages {
 		// Skip if already seen
 		if seenMessages.IsSeen(msg.Id) {
 			continue
@@ -479,170 +1099,719 @@ func checkEmails(client *gmail.Client, cfg *filter.Config, seenMessages *state.S
 		seenMessages.MarkSeen(msg.Id)
 
 		// Process this message
-		matched := processMessage(msg, cfg, db, priorityRules, aiService)
+		checkedCount++
+		body := getMessageBody(client, msg, bodyCache)
+		hasAttachment := messageHasAttachment(client, msg)
+		matched := processMessage(msg, body, hasAttachment, fetchedBy[msg.Id], cfg, db, priorityRules, aiService, overrideScope, opts)
 		if matched {
 			matchCount++
 		}
 	}
 
 	if matchCount == 0 {
-		fmt.Printf("[%s] Checked %d messages, no new matches\n",
-			time.Now().Format("15:04:05"), len(allMessages))
+		log.Info("Checked messages, no new matches", "checked", len(allMessages))
+	}
+
+	// Persist daily counters for the dashboard
+	metrics.AddEmailsChecked(checkedCount)
+	if err := storage.IncrementCheckStats(db, checkedCount, matchCount); err != nil {
+		log.Warn("Failed to update check stats", "error", err)
 	}
 
 	return nil
 }
 
+// messageHasAttachment reports whether a message has an attachment, for has_attachment filters
+// A failed lookup is logged and treated as no attachment.
+func messageHasAttachment(client gmail.MessageFetcher, msg *googlemail.Message) bool {
+	hasAttachment, err := client.MessageHasAttachment(msg)
+	if err != nil {
+		log.Warn("Could not check message attachments", "message_id", msg.Id, "error", err)
+	}
+	return hasAttachment
+}
+
+// getMessageBody returns the plain text body for a message, using the per-check cache
+// Messages are fetched in "full" format, so the payload is used directly when present
+func getMessageBody(client gmail.MessageFetcher, msg *googlemail.Message, bodyCache map[string]string) string {
+	if body, ok := bodyCache[msg.Id]; ok {
+		return body
+	}
+
+	body := gmail.ExtractBody(msg.Payload)
+	if body == "" && msg.Payload == nil {
+		fetched, err := client.GetMessageBody(msg.Id)
+		if err != nil {
+			log.Warn("Could not fetch message body", "message_id", msg.Id, "error", err)
+		} else {
+			body = fetched
+		}
+	}
+
+	bodyCache[msg.Id] = body
+	return body
+}
+
 // processMessage processes a single email message and handles all matched filters
-func processMessage(msg *googlemail.Message, cfg *filter.Config, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service) bool {
+// fetchedBy holds the Gmail queries that returned the message.
+func processMessage(msg *googlemail.Message, body string, hasAttachment bool, fetchedBy map[string]bool, cfg *filter.Config, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service, overrideScope string, opts checkOptions) bool {
 	// Parse message
 	email := gmail.ParseMessage(msg)
 
+	if opts.Verbose {
+		log.Info("Checking", append([]any{log.Icon("🔎")}, log.Email(email.From, email.Subject)...)...)
+	}
+
+	// Blocked (or non-allowlisted) senders skip account detection, filters, AI and alerts
+	if !opts.Senders.SenderAllowed(email.From) {
+		logVerbose(opts.Verbose, "Skipping message from blocked sender", "from", log.Address(email.From))
+		return false
+	}
+
+	// Old mail that resurfaced (moved back to the inbox, newly in a filter's scope) doesn't alert
+	if messageTooOld(email, opts.MaxMessageAge, time.Now()) {
+		logVerbose(opts.Verbose, "Skipping old message", "received", email.ReceivedAt.Format(time.RFC3339), "max_age", opts.MaxMessageAge)
+		return false
+	}
+
 	// Detect digital accounts (subscriptions, trials, etc.) - runs on ALL emails
-	detectAndSaveAccount(email, db)
+	detectAndSaveAccount(email, body, db, opts)
+
+	// Extract verification codes - also runs on ALL emails, matched or not
+	detectAndSaveOTP(email, body, db, cfg, priorityRules, opts)
 
 	// Check against all filters (with metadata including labels)
-	matchedFilters, err := filter.CheckAllFiltersWithMetadata(email.From, email.Subject)
+	matchedFilters, err := filter.CheckAllFiltersWithMetadata(email.From, email.Subject, body, hasAttachment)
 	if err != nil {
-		fmt.Printf("⚠️  Error checking filters: %v\n", err)
+		log.Warn("Error checking filters", "error", err)
 		return false
 	}
+	matchedFilters = filter.KeepFetched(matchedFilters, fetchedBy, overrideScope)
+	if opts.FirstMatchWins {
+		matchedFilters = filter.FirstMatch(matchedFilters)
+	}
+	if opts.Verbose {
+		explainMatches(cfg.Filters, email, body, hasAttachment, matchedFilters)
+	}
 
 	// If no matches, return early
 	if len(matchedFilters) == 0 {
 		return false
 	}
-
-	// Process each matched filter
-	for _, match := range matchedFilters {
-		processFilterMatch(msg, email, match, cfg, db, priorityRules, aiService)
+	for _, m := range matchedFilters {
+		metrics.IncMatch(m.Name)
 	}
 
+	// One alert and one notification per email, however many filters matched
+	processMatches(msg, email, body, matchedFilters, cfg, db, priorityRules, aiService, opts)
+
 	return true
 }
 
-// processFilterMatch handles a single filter match including notifications and storage
-func processFilterMatch(msg *googlemail.Message, email *gmail.EmailMessage, match filter.MatchResult, cfg *filter.Config, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service) {
+// logVerbose logs at info level in --verbose mode and at debug level otherwise
+func logVerbose(verbose bool, msg string, args ...any) {
+	if verbose {
+		log.Info(msg, args...)
+		return
+	}
+	log.Debug(msg, args...)
+}
+
+// explainMatches logs why each filter did or didn't match a message (--verbose)
+// A filter whose patterns matched but isn't in kept was dropped by its gmail_query or first_match_wins.
+func explainMatches(filters []filter.Filter, email *gmail.EmailMessage, body string, hasAttachment bool, kept []filter.MatchResult) {
+	alerted := make(map[string]bool, len(kept))
+	for _, m := range kept {
+		alerted[m.Name] = true
+	}
+
+	for _, f := range filters {
+		matched, reason := filter.MatchesFilterExplain(f, email.From, email.Subject, body, hasAttachment)
+		switch {
+		case matched && !alerted[f.Name] && f.GmailQuery != "":
+			matched, reason = false, reason+"; but its gmail_query didn't return this message"
+		case matched && !alerted[f.Name]:
+			matched, reason = false, reason+"; but an earlier filter matched first (first_match_wins)"
+		}
+		icon := "➖"
+		if matched {
+			icon = "✅"
+		}
+		log.Info("Filter", log.Icon(icon), "filter", f.Name, "matched", matched, "reason", reason)
+	}
+}
+
+// envEnabled reports whether an environment variable is set to a true value like "1" or "true"
+func envEnabled(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(name))) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// messageTooOld reports whether Gmail received email longer than maxAge before now
+// A zero maxAge or a message without an internal date is never too old.
+func messageTooOld(email *gmail.EmailMessage, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 || email.ReceivedAt.IsZero() {
+		return false
+	}
+	return now.Sub(email.ReceivedAt) > maxAge
+}
+
+// processMatches handles all filter matches for an email including notifications and storage
+// The email is saved and notified once, listing every matched filter
+func processMatches(msg *googlemail.Message, email *gmail.EmailMessage, body string, matches []filter.MatchResult, cfg *filter.Config, db *sql.DB, priorityRules *rules.Rules, aiService *ai.Service, opts checkOptions) {
+	alertName := matchedFilterNames(matches)
+	alertLabels := matchedFilterLabels(matches)
+
+	// The enriched snippet is what gets saved, notified and shown in the tray
+	if opts.RichSnippet {
+		email.Snippet = gmail.RichSnippet(email.Snippet, body)
+	}
+
 	// Log the match
-	labelStr := ""
-	if len(match.Labels) > 0 {
-		labelStr = fmt.Sprintf(" 🏷️ %s", strings.Join(match.Labels, ", "))
+	matchAttrs := append([]any{log.Icon("📧"), "filter", alertName}, log.Email(email.From, email.Subject)...)
+	if len(alertLabels) > 0 {
+		matchAttrs = append(matchAttrs, "labels", strings.Join(alertLabels, ","))
+	}
+	log.Info("MATCH", matchAttrs...)
+
+	// Evaluate priority using rules engine, then apply any filter's force_priority
+	// The override feeds the saved alert, so the tray icon and quiet-hours urgent bypass follow it too
+	priority := evaluateMessagePriority(email, body, priorityRules)
+	if forced, ok := filter.ForcedPriority(matches); ok && forced != priority {
+		log.Debug("Priority forced by filter", "filter", alertName, "rules_priority", priority, "priority", forced)
+		priority = forced
+	}
+
+	// Dry-run: log what would be sent and skip notifications, tray and AI
+	if opts.DryRun {
+		log.Info("[DRY-RUN] would notify", append([]any{log.Icon("🧪"), "filter", alertName}, log.Email(email.From, email.Subject)...)...)
+		if opts.Labeler != nil {
+			for _, label := range matchedGmailLabels(matches) {
+				log.Info("[DRY-RUN] would apply Gmail label", log.Icon("🧪"), "label", label)
+			}
+			if matchesMarkRead(matches) {
+				log.Info("[DRY-RUN] would mark as read", log.Icon("🧪"), "message_id", msg.Id)
+			}
+		}
+		if !opts.NoSave {
+			saveAlert(db, createAlert(msg, email, matches, priority))
+		}
+		return
+	}
+
+	// Save before notifying: an alert that's already in the database (e.g. the seen
+	// state was cleared) was notified when it first arrived, so nothing is sent again
+	alert := createAlert(msg, email, matches, priority)
+	if !saveAlert(db, alert) {
+		return
+	}
+
+	// Quiet hours and weekend mode only suppress the push - the alert is still saved to history
+	notifyAllowed := rules.ShouldNotify(priorityRules, time.Now(), priority)
+
+	// Filters with a digest hold normal-priority pushes for one summary per interval
+	// High and critical matches still notify right away
+	digested := false
+	if notifyAllowed && opts.Digests != nil && priority == storage.PriorityNormal {
+		if interval, ok := filter.DigestInterval(matches); ok {
+			topics, _ := groupMatchesByTopic(matches, cfg)
+			opts.Digests.Add(alertName, interval, topics, notify.DigestEntry{From: email.From, Subject: email.Subject})
+			log.Info("Notification held for digest (alert saved to history)", log.Icon("📥"), "filter", alertName, "digest", interval)
+			digested = true
+		}
 	}
-	fmt.Printf("📧 MATCH [%s]%s From: %s | Subject: %s\n",
-		match.Name, labelStr, email.From, email.Subject)
 
 	// Send notifications (desktop and mobile)
-	sendNotificationsForMatch(match, email, cfg)
+	if !notifyAllowed {
+		log.Info("Quiet hours/weekend mode: notification suppressed (alert saved to history)", log.Icon("🔕"))
+	} else if !digested {
+		sendNotificationsForMatches(matches, email, priority, cfg)
+	}
 
-	// Evaluate priority using rules engine
-	priority := evaluateMessagePriority(email, priorityRules)
+	notifyDesktopAndTray(*alert, notifyAllowed && !digested)
 
-	// Create and save alert
-	alert := createAlert(msg, email, match, priority)
-	saveAndNotifyAlert(db, alert, cfg)
+	// Post to webhooks (Slack, Discord, etc.) alongside desktop/mobile
+	if notifyAllowed {
+		sendWebhooksForAlert(opts.Async, opts.Webhooks, *alert)
+	}
+
+	// Apply the filters' Gmail labels and mark-read (only when gmail.modify is enabled)
+	if opts.Labeler != nil {
+		for _, label := range matchedGmailLabels(matches) {
+			applyGmailLabel(opts.Labeler, msg.Id, label)
+		}
+		if matchesMarkRead(matches) {
+			markGmailRead(opts.Labeler, msg.Id)
+		}
+	}
 
 	// Generate AI summary asynchronously if enabled
 	if aiService != nil {
-		generateAISummaryAsync(aiService, *alert)
+		generateAISummaryAsync(opts.Async, aiService, *alert, body)
 	}
 }
 
-// sendNotificationsForMatch sends mobile notifications for a matched filter
-// Desktop notifications are handled by saveAndNotifyAlert() to avoid duplicates
-func sendNotificationsForMatch(match filter.MatchResult, email *gmail.EmailMessage, cfg *filter.Config) {
-	// Send mobile notification with labels
-	if cfg.Notifications.Mobile.Enabled && cfg.Notifications.Mobile.NtfyTopic != "" {
+// matchedFilterNames joins the names of all matched filters for display and storage
+func matchedFilterNames(matches []filter.MatchResult) string {
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m.Name)
+	}
+	return strings.Join(names, storage.FilterNameSeparator)
+}
+
+// matchedFilterLabels returns the labels of all matched filters, without duplicates
+func matchedFilterLabels(matches []filter.MatchResult) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		for _, label := range m.Labels {
+			if !seen[strings.ToLower(label)] {
+				seen[strings.ToLower(label)] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+// matchedGmailLabels returns the distinct Gmail labels the matched filters apply
+func matchedGmailLabels(matches []filter.MatchResult) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		if m.ApplyGmailLabel != "" && !seen[m.ApplyGmailLabel] {
+			seen[m.ApplyGmailLabel] = true
+			labels = append(labels, m.ApplyGmailLabel)
+		}
+	}
+	return labels
+}
+
+// matchesMarkRead reports whether any matched filter wants the message marked as read
+func matchesMarkRead(matches []filter.MatchResult) bool {
+	for _, m := range matches {
+		if m.MarkRead {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeErrorWarned ensures the re-auth message for a check failing on scope is only printed once per run
+var scopeErrorWarned bool
+
+// countsAsFailure reports whether a failed check should trip the circuit breaker
+// A token missing a required scope fails every check until the user re-authorizes, so
+// backing off won't help; it gets a one-time message instead.
+func countsAsFailure(err error, cfg *filter.Config) bool {
+	if err == nil {
+		return false
+	}
+	if !gmail.IsInsufficientScopeError(err) {
+		return true
+	}
+
+	if !scopeErrorWarned {
+		scopeErrorWarned = true
+		reauth := "email-sentinel init --reauth"
+		if filtersModifyGmail(cfg) {
+			reauth += " --scopes modify"
+		}
+		log.Error("Gmail rejected the check: the saved token is missing a required permission. Retrying won't help, re-authorize with: "+reauth,
+			log.Icon("🔑"), "error", err)
+	}
+	return false
+}
+
+// modifyScopeWarned ensures the re-auth prompt is only printed once per run
+var modifyScopeWarned bool
+
+// warnReadOnlyToken prompts the user to re-authorize when the saved token lacks gmail.modify
+func warnReadOnlyToken(action string) {
+	if !modifyScopeWarned {
+		modifyScopeWarned = true
+		log.Warn("Cannot " + action + ": token was authorized read-only (re-run init to grant modify access: email-sentinel init --reauth --scopes modify)")
+	}
+}
+
+// applyGmailLabel adds a label to a matched message
+// If the saved token is still read-only, prompts the user to re-run init
+func applyGmailLabel(client *gmail.Client, messageID, labelName string) {
+	err := client.ApplyLabel(messageID, labelName)
+	if err == nil {
+		log.Info("Applied Gmail label", log.Icon("🏷️ "), "label", labelName)
+		return
+	}
+
+	if gmail.IsInsufficientScopeError(err) {
+		warnReadOnlyToken("apply Gmail labels")
+		return
+	}
+
+	log.Warn("Failed to apply Gmail label", "label", labelName, "error", err)
+}
+
+// markGmailRead marks a matched message as read
+// If the saved token is still read-only, prompts the user to re-run init
+func markGmailRead(client *gmail.Client, messageID string) {
+	err := client.MarkAsRead(messageID)
+	if err == nil {
+		log.Info("Marked as read in Gmail", log.Icon("👁️ "), "message_id", messageID)
+		return
+	}
+
+	if gmail.IsInsufficientScopeError(err) {
+		warnReadOnlyToken("mark messages as read")
+		return
+	}
+
+	log.Warn("Failed to mark message as read", "message_id", messageID, "error", err)
+}
+
+// disabledFilterCount returns the number of filters paused with 'filter disable'
+func disabledFilterCount(cfg *filter.Config) int {
+	count := 0
+	for _, f := range cfg.Filters {
+		if !f.IsEnabled() {
+			count++
+		}
+	}
+	return count
+}
+
+// filtersModifyGmail reports whether any filter wants a Gmail label applied or mail marked read
+func filtersModifyGmail(cfg *filter.Config) bool {
+	for _, f := range cfg.Filters {
+		if f.IsEnabled() && (f.ApplyGmailLabel != "" || f.MarkRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendWebhooksForAlert posts an alert to every configured webhook
+// Each webhook is posted in a tracked goroutine, so a slow endpoint doesn't delay the poll loop.
+func sendWebhooksForAlert(async *asyncWork, webhooks []appconfig.WebhookConfig, alert storage.Alert) {
+	for _, hook := range webhooks {
+		if hook.URL == "" {
+			continue
+		}
+		started := async.Go(func(ctx context.Context) {
+			if err := notify.SendWebhookWithTemplate(hook.URL, hook.Template, alert); err != nil {
+				log.Warn("Webhook failed", "webhook", hook.Name, "error", err)
+			}
+		})
+		if !started {
+			log.Warn("Shutting down, webhook skipped", "webhook", hook.Name, "message_id", alert.MessageID)
+		}
+	}
+}
+
+// sendNotificationsForMatches sends mobile notifications for the matched filters
+// Filters sharing an ntfy topic get a single push listing all of them; high-priority
+// emails are pushed at ntfy's max priority so they break through Do Not Disturb
+// Desktop notifications are handled by notifyDesktopAndTray() to avoid duplicates
+func sendNotificationsForMatches(matches []filter.MatchResult, email *gmail.EmailMessage, priority int, cfg *filter.Config) {
+	topics, byTopic := groupMatchesByTopic(matches, cfg)
+
+	// Send one mobile notification with labels per topic
+	for _, topic := range topics {
 		if err := notify.SendMobileEmailAlertWithLabels(
-			cfg.Notifications.Mobile.NtfyTopic,
-			match.Name,
-			match.Labels,
+			topic,
+			matchedFilterNames(byTopic[topic]),
+			matchedFilterLabels(byTopic[topic]),
 			email.From,
 			email.Subject,
+			priority,
 		); err != nil {
-			fmt.Printf("   ⚠️  Mobile notification failed: %v\n", err)
+			log.Warn("Mobile notification failed", "error", err)
+		}
+	}
+}
+
+// groupMatchesByTopic groups matches by ntfy topic, preferring each filter's own topic over the global one
+// Returns no topics when mobile notifications are disabled.
+func groupMatchesByTopic(matches []filter.MatchResult, cfg *filter.Config) ([]string, map[string][]filter.MatchResult) {
+	var topics []string
+	byTopic := make(map[string][]filter.MatchResult)
+	if !cfg.Notifications.Mobile.Enabled {
+		return topics, byTopic
+	}
+
+	for _, match := range matches {
+		topic := match.NtfyTopic
+		if topic == "" {
+			topic = cfg.Notifications.Mobile.NtfyTopic
+		}
+		if topic == "" {
+			continue
+		}
+		if _, ok := byTopic[topic]; !ok {
+			topics = append(topics, topic)
+		}
+		byTopic[topic] = append(byTopic[topic], match)
+	}
+	return topics, byTopic
+}
+
+// sendDigest sends one summary notification for a batch of held-back matches
+// Runs on the digest's timer, or on shutdown for digests still pending.
+func sendDigest(dg notify.Digest) {
+	title, message := dg.Summary()
+	log.Info("Sending digest", log.Icon("📬"), "filter", dg.Filter, "emails", len(dg.Entries))
+
+	if notify.DesktopEnabled() {
+		if err := notify.SendDesktopNotification(title, message); err != nil {
+			log.Warn("Digest desktop notification failed", "error", err)
+		}
+	}
+	for _, topic := range dg.Topics {
+		if err := notify.SendMobileNotification(topic, title, message); err != nil {
+			log.Warn("Digest mobile notification failed", "error", err)
 		}
 	}
 }
 
 // evaluateMessagePriority determines the priority level of a message
-func evaluateMessagePriority(email *gmail.EmailMessage, priorityRules *rules.Rules) int {
+func evaluateMessagePriority(email *gmail.EmailMessage, body string, priorityRules *rules.Rules) int {
 	msgMeta := rules.MessageMetadata{
 		Sender:  email.From,
 		Subject: email.Subject,
 		Snippet: email.Snippet,
-		Body:    "", // Body not available in snippet API call
+		Body:    body,
 	}
 	return rules.EvaluatePriorityRules(priorityRules, msgMeta)
 }
 
-// createAlert creates an Alert struct from message data
-func createAlert(msg *googlemail.Message, email *gmail.EmailMessage, match filter.MatchResult, priority int) *storage.Alert {
+// createAlert creates an Alert struct from message data and every filter it matched
+func createAlert(msg *googlemail.Message, email *gmail.EmailMessage, matches []filter.MatchResult, priority int) *storage.Alert {
 	return &storage.Alert{
-		Timestamp:    time.Now(),
+		Timestamp:    receivedDate(email), // History sorts and cleans up by when the email arrived, not when we saw it
 		Sender:       email.From,
 		Subject:      email.Subject,
 		Snippet:      email.Snippet,
 		Labels:       strings.Join(msg.LabelIds, ","),
 		MessageID:    msg.Id,
 		GmailLink:    gmail.BuildGmailLink(msg.Id),
-		FilterName:   match.Name,
-		FilterLabels: match.Labels,
+		FilterName:   matchedFilterNames(matches),
+		FilterLabels: matchedFilterLabels(matches),
 		Priority:     priority,
 	}
 }
 
-// saveAndNotifyAlert saves an alert to the database and sends system notifications
-func saveAndNotifyAlert(db *sql.DB, alert *storage.Alert, cfg *filter.Config) {
+// saveAlert saves an alert to the database
+// Returns false if the alert was already saved, so the caller doesn't notify it twice.
+// Other failures still return true: losing the history entry shouldn't also lose the notification.
+func saveAlert(db *sql.DB, alert *storage.Alert) bool {
 	// Save alert with retry logic to prevent data loss
-	if err := storage.InsertAlertWithRetry(db, alert); err != nil {
+	if err := storage.InsertAlertWithRetry(db, alert); storage.IsDuplicateAlert(err) {
+		log.Debug("Alert already saved, not notifying again", "message_id", alert.MessageID)
+		return false
+	} else if err != nil {
 		// Critical: Even retry and fallback failed
-		fmt.Printf("   ❌ CRITICAL: Failed to save alert (retry + fallback failed): %v\n", err)
+		log.Error("CRITICAL: Failed to save alert (retry + fallback failed)", "error", err)
 	}
+	return true
+}
 
+// notifyDesktopAndTray sends the desktop notification and updates the system tray
+// notifyAllowed only controls the desktop notification
+func notifyDesktopAndTray(alert storage.Alert, notifyAllowed bool) {
 	// Send desktop notification (Windows toast or Unix notification) if enabled
 	// This provides a rich, platform-specific notification with AI summaries
-	if cfg.Notifications.Desktop {
-		if err := notify.SendAlertNotification(*alert); err != nil {
-			fmt.Printf("   ⚠️  Desktop notification failed: %v\n", err)
+	if notify.DesktopEnabled() && notifyAllowed {
+		if err := notify.SendAlertNotification(alert); err != nil {
+			log.Warn("Desktop notification failed", "error", err)
 		}
 	}
 
 	// Update system tray if enabled
 	if trayMode {
-		tray.UpdateTrayOnNewAlert(*alert)
+		tray.UpdateTrayOnNewAlert(alert)
 	}
 }
 
+// saveDesktopNotificationSetting persists a tray toggle of desktop notifications
+// Both configs are updated since the watcher requires both to enable toasts
+func saveDesktopNotificationSetting(enabled bool) error {
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load app config: %w", err)
+	}
+	appCfg.Notifications.Desktop.Enabled = enabled
+	if err := appconfig.Save(appCfg); err != nil {
+		return fmt.Errorf("failed to save app config: %w", err)
+	}
+
+	cfg, err := filter.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.Notifications.Desktop = enabled
+	if err := filter.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	return nil
+}
+
 // generateAISummaryAsync generates an AI summary in a separate goroutine with panic recovery
-func generateAISummaryAsync(aiService *ai.Service, alert storage.Alert) {
-	go func(alertCopy storage.Alert) {
+// The goroutine is tracked by async so shutdown waits for it to save its summary.
+func generateAISummaryAsync(async *asyncWork, aiService *ai.Service, alert storage.Alert, body string) {
+	if !aiService.ShouldSummarize(alert.Priority) {
+		log.Debug("Skipping AI summary (priority-only mode)", "message_id", alert.MessageID)
+		return
+	}
+
+	alertCopy := alert
+	started := async.Go(func(ctx context.Context) {
 		defer func() {
 			if r := recover(); r != nil {
-				fmt.Printf("   ❌ PANIC in AI summary goroutine: %v\n", r)
-				fmt.Printf("      Alert: %s from %s\n", alertCopy.Subject, alertCopy.Sender)
+				log.Error("PANIC in AI summary goroutine", append([]any{"panic", r}, log.Email(alertCopy.Sender, alertCopy.Subject)...)...)
 			}
 		}()
 
 		summary, err := aiService.GenerateSummary(
+			ctx,
 			alertCopy.MessageID,
 			alertCopy.Sender,
 			alertCopy.Subject,
-			"", // body not available in snippet API
+			body,
 			alertCopy.Snippet,
+			alertCopy.FilterLabels,
 			alertCopy.Priority,
 		)
 		if err != nil {
-			fmt.Printf("   ⚠️  AI summary failed: %v\n", err)
+			log.Warn("AI summary failed", "error", err)
 			return
 		}
 		if summary != nil {
-			fmt.Printf("   🤖 AI: %s\n", summary.Summary)
+			metrics.AddAITokens(summary.TokensUsed)
+			summaryAttrs := []any{log.Icon("🤖"), "message_id", alertCopy.MessageID}
+			if !log.Redacting() {
+				summaryAttrs = append(summaryAttrs, "summary", summary.Summary)
+			}
+			log.Info("AI summary", summaryAttrs...)
+		}
+	})
+	if !started {
+		log.Warn("Shutting down, AI summary skipped", "message_id", alert.MessageID)
+	}
+}
+
+// detectAndSaveOTP extracts a verification code from an email and saves it for 'otp list/get'
+// A confident code is optionally copied to the clipboard and gets its own notification
+func detectAndSaveOTP(email *gmail.EmailMessage, body string, db *sql.DB, cfg *filter.Config, priorityRules *rules.Rules, opts checkOptions) {
+	if opts.OTP == nil {
+		return
+	}
+
+	result := opts.OTP.Detector.Detect(otp.DetectionContext{
+		Subject: email.Subject,
+		Body:    body,
+		Snippet: email.Snippet,
+		Sender:  email.From,
+	})
+	if result == nil {
+		return
+	}
+
+	// The code itself is never logged
+	log.Info("OTP DETECTED", log.Icon("🔐"),
+		"from", log.Address(email.From),
+		"confidence", fmt.Sprintf("%.2f", result.Confidence),
+		"expires_in", time.Until(result.ExpiresAt).Round(time.Second))
+
+	if opts.NoSave {
+		return
+	}
+
+	code := &storage.OTPAlert{
+		Timestamp:   time.Now(),
+		ExpiresAt:   result.ExpiresAt,
+		Sender:      email.From,
+		Subject:     email.Subject,
+		OTPCode:     result.Code,
+		Confidence:  result.Confidence,
+		Source:      result.Source,
+		PatternName: result.Pattern,
+		MessageID:   email.ID,
+		GmailLink:   gmail.BuildGmailLink(email.ID),
+		IsActive:    true,
+	}
+	if err := storage.InsertOTPAlert(db, code); err != nil {
+		log.Warn("Failed to save OTP code", "error", err)
+		return
+	}
+
+	// Keep the history to max_codes
+	if trimmed, err := storage.TrimOTPAlerts(db, opts.OTP.MaxCodes); err != nil {
+		log.Warn("Failed to trim OTP history", "error", err)
+	} else if trimmed > 0 {
+		log.Debug("Trimmed OTP history", "deleted", trimmed, "max_codes", opts.OTP.MaxCodes)
+	}
+
+	// Dry-run: saved for inspection, but no clipboard or notification
+	if opts.DryRun {
+		log.Info("[DRY-RUN] would notify OTP code", log.Icon("🧪"), "from", log.Address(email.From))
+		return
+	}
+
+	copied := false
+	if opts.OTP.AutoCopy && otp.AutoCopy(result.Code, opts.OTP.ClearAfter) {
+		copied = true
+		if err := storage.MarkOTPAsCopied(db, code.ID); err != nil {
+			log.Warn("Failed to mark OTP code as copied", "error", err)
 		}
-	}(alert)
+	}
+
+	// Codes are time-sensitive, so they notify like a high-priority alert
+	if !rules.ShouldNotify(priorityRules, time.Now(), storage.PriorityHigh) {
+		log.Info("Quiet hours/weekend mode: OTP notification suppressed (code saved)", log.Icon("🔕"))
+		return
+	}
+	sendOTPNotification(email, result, copied, cfg)
+}
+
+// sendOTPNotification sends the dedicated notification for a detected code
+// The desktop notification shows the code; the ntfy push only says one arrived,
+// since ntfy topics can be read by anyone who knows the topic name.
+func sendOTPNotification(email *gmail.EmailMessage, result *otp.OTPResult, copied bool, cfg *filter.Config) {
+	expires := fmt.Sprintf("Expires at %s", result.ExpiresAt.Format("15:04"))
+
+	if notify.DesktopEnabled() {
+		message := fmt.Sprintf("From: %s\n%s", email.From, expires)
+		if copied {
+			message += " (copied to clipboard)"
+		}
+		if err := notify.SendOTPNotification("🔐 Verification code: "+result.Code, message, gmail.BuildGmailLink(email.ID)); err != nil {
+			log.Warn("OTP desktop notification failed", "error", err)
+		}
+	}
+
+	if cfg.Notifications.Mobile.Enabled && cfg.Notifications.Mobile.NtfyTopic != "" {
+		message := fmt.Sprintf("From: %s\n%s\nRun 'email-sentinel otp get' to copy it", email.From, expires)
+		if err := notify.SendMobileNotification(cfg.Notifications.Mobile.NtfyTopic, "🔐 Verification code received", message); err != nil {
+			log.Warn("OTP mobile notification failed", "error", err)
+		}
+	}
+}
+
+// receivedDate returns when Gmail received email, or now if the API didn't say
+func receivedDate(email *gmail.EmailMessage) time.Time {
+	if email.ReceivedAt.IsZero() {
+		return time.Now()
+	}
+	return email.ReceivedAt
 }
 
 // detectAndSaveAccount detects and saves digital account information from emails
-func detectAndSaveAccount(email *gmail.EmailMessage, db *sql.DB) {
+// In dry-run mode detections are only logged: accounts drive trial and renewal reminders.
+func detectAndSaveAccount(email *gmail.EmailMessage, body string, db *sql.DB, opts checkOptions) {
 	// Load app config to get account settings
 	appCfg, err := appconfig.Load()
 	if err != nil || !appCfg.Accounts.Enabled {
@@ -658,16 +1827,17 @@ func detectAndSaveAccount(email *gmail.EmailMessage, db *sql.DB) {
 
 	// Create detector
 	detector := accounts.NewDetector(accountCfg.MinConfidence, accountCfg.Categories)
+	detector.SetDetectTypes(accountCfg.DetectTypes)
 
 	// Create detection context
 	ctx := accounts.DetectionContext{
 		Subject:      email.Subject,
-		Body:         "",          // Body not available in snippet API
+		Body:         body,
 		Snippet:      email.Snippet,
 		Sender:       email.From,
-		ToEmail:      "",          // We'll try to extract this
-		ReceivedDate: time.Now(),  // Use current time as we don't have exact received date
-		MessageID:    email.ID,    // Use Gmail message ID
+		ToEmail:      "",                  // We'll try to extract this
+		ReceivedDate: receivedDate(email), // When Gmail received it, so trial end dates count from there
+		MessageID:    email.ID,            // Use Gmail message ID
 	}
 
 	// Attempt to extract recipient email from snippet
@@ -688,6 +1858,17 @@ func detectAndSaveAccount(email *gmail.EmailMessage, db *sql.DB) {
 		return
 	}
 
+	if opts.DryRun {
+		log.Info("[DRY-RUN] would save account", log.Icon("🧪"), "service", result.ServiceName, "type", result.AccountType)
+		return
+	}
+
+	// Cancellation emails update the existing account instead of adding a new one
+	if result.AccountType == "cancellation" {
+		markAccountCancelled(db, result)
+		return
+	}
+
 	// Convert to storage model
 	now := time.Now()
 	account := &storage.Account{
@@ -696,6 +1877,7 @@ func detectAndSaveAccount(email *gmail.EmailMessage, db *sql.DB) {
 		AccountType:    result.AccountType,
 		Status:         "active",
 		PriceMonthly:   result.PriceMonthly,
+		Currency:       result.Currency,
 		TrialEndDate:   result.TrialEndDate,
 		GmailMessageID: result.GmailMessageID,
 		DetectedAt:     now,
@@ -709,7 +1891,7 @@ func detectAndSaveAccount(email *gmail.EmailMessage, db *sql.DB) {
 	if err := storage.InsertAccount(db, account); err != nil {
 		// Only log if it's not a duplicate
 		if !strings.Contains(err.Error(), "UNIQUE") {
-			fmt.Printf("   ⚠️  Failed to save account: %v\n", err)
+			log.Warn("Failed to save account", "error", err)
 		}
 		return
 	}
@@ -722,23 +1904,49 @@ func detectAndSaveAccount(email *gmail.EmailMessage, db *sql.DB) {
 		typeIcon = "🎁"
 	}
 
-	fmt.Printf("   %s ACCOUNT DETECTED: %s (%s) | Email: %s\n",
-		typeIcon,
-		account.ServiceName,
-		account.AccountType,
-		account.EmailAddress,
-	)
+	accountAttrs := []any{log.Icon(typeIcon),
+		"service", account.ServiceName,
+		"type", account.AccountType,
+		"email", log.Address(account.EmailAddress),
+	}
 
 	if account.TrialEndDate != nil {
 		daysUntil := time.Until(*account.TrialEndDate).Hours() / 24
 		if daysUntil > 0 {
-			fmt.Printf("      Trial expires in %d days\n", int(daysUntil)+1)
+			accountAttrs = append(accountAttrs, "trial_days_left", int(daysUntil)+1)
 		}
 	}
 
 	if account.PriceMonthly > 0 {
-		fmt.Printf("      Price: $%.2f/month\n", account.PriceMonthly)
+		accountAttrs = append(accountAttrs, "price_monthly", accounts.FormatPrice(account.PriceMonthly, account.Currency))
+	}
+
+	log.Info("ACCOUNT DETECTED", accountAttrs...)
+}
+
+// markAccountCancelled flips a tracked account to "cancelled" when a cancellation email arrives
+func markAccountCancelled(db *sql.DB, result *accounts.DetectionResult) {
+	existing, err := storage.GetAccountByServiceAndEmail(db, result.ServiceName, result.EmailAddress)
+	if err != nil {
+		log.Warn("Failed to look up account", "error", err)
+		return
+	}
+
+	if existing == nil {
+		// Nothing tracked for this service - no need to record a cancelled account
+		return
 	}
+
+	if existing.Status == "cancelled" {
+		return
+	}
+
+	if err := storage.UpdateAccountStatus(db, existing.ServiceName, existing.EmailAddress, "cancelled"); err != nil {
+		log.Warn("Failed to update account status", "error", err)
+		return
+	}
+
+	log.Info("ACCOUNT CANCELLED", log.Icon("❌"), "service", existing.ServiceName, "email", log.Address(existing.EmailAddress))
 }
 
 // extractRecipientFromEmail attempts to extract the recipient email address
@@ -755,7 +1963,8 @@ func extractRecipientFromEmail(email *gmail.EmailMessage) string {
 
 	for _, patternStr := range patterns {
 		if matches := regexp.MustCompile(patternStr).FindStringSubmatch(text); len(matches) > 1 {
-			return matches[1]
+			// Canonical form, so signups via an alias (name+service@gmail.com) group under one address
+			return gmail.NormalizeAddress(matches[1])
 		}
 	}
 
@@ -771,6 +1980,13 @@ func checkExpiringTrials(db *sql.DB) {
 		return
 	}
 
+	// Mark trials whose end date has passed as expired
+	if expired, err := storage.ExpirePastTrials(db, time.Now()); err != nil {
+		log.Warn("Failed to expire past trials", "error", err)
+	} else if expired > 0 {
+		log.Info("Marked trials as expired", log.Icon("📅"), "count", expired)
+	}
+
 	// Get all active trials
 	trials, err := storage.GetActiveTrials(db)
 	if err != nil {
@@ -778,7 +1994,7 @@ func checkExpiringTrials(db *sql.DB) {
 		return
 	}
 
-	// Check each trial against alert thresholds
+	// Alert once per threshold: the tightest threshold the trial is inside, unless already sent
 	for _, trial := range trials {
 		if trial.TrialEndDate == nil {
 			continue
@@ -791,14 +2007,44 @@ func checkExpiringTrials(db *sql.DB) {
 			continue
 		}
 
-		// Check against each alert threshold
-		for _, alert := range appCfg.Accounts.TrialAlerts {
-			if daysUntil <= float64(alert.DaysBefore) && daysUntil > float64(alert.DaysBefore-1) {
-				// Should send alert
-				sendTrialExpirationAlert(trial, alert.Urgency, int(daysUntil)+1)
-			}
+		threshold, ok := trialAlertThreshold(daysUntil, appCfg.Accounts.TrialAlerts)
+		if !ok {
+			continue
+		}
+
+		alertType := fmt.Sprintf("trial_expiring_%dd", threshold.DaysBefore)
+		sent, err := storage.HasAccountAlert(db, trial.ID, alertType, *trial.TrialEndDate)
+		if err != nil {
+			log.Warn("Failed to check sent trial alerts", "service", trial.ServiceName, "error", err)
+			continue
+		}
+		if sent {
+			continue
+		}
+
+		sendTrialExpirationAlert(trial, threshold.Urgency, int(math.Ceil(daysUntil)))
+
+		// Record even if the notification failed, so a broken notifier doesn't alert every poll
+		if err := storage.RecordAccountAlert(db, trial.ID, alertType, *trial.TrialEndDate); err != nil {
+			log.Warn("Failed to record trial alert", "service", trial.ServiceName, "error", err)
+		}
+	}
+}
+
+// trialAlertThreshold returns the tightest configured threshold a trial ending in daysUntil days falls within
+// A trial first seen with 2 days left gets the 3-day alert, not also a late 7-day one.
+func trialAlertThreshold(daysUntil float64, alerts []appconfig.TrialAlert) (appconfig.TrialAlert, bool) {
+	var best appconfig.TrialAlert
+	found := false
+	for _, alert := range alerts {
+		if daysUntil > float64(alert.DaysBefore) {
+			continue
+		}
+		if !found || alert.DaysBefore < best.DaysBefore {
+			best, found = alert, true
 		}
 	}
+	return best, found
 }
 
 // sendTrialExpirationAlert sends a notification for an expiring trial
@@ -824,11 +2070,11 @@ func sendTrialExpirationAlert(trial storage.Account, urgency string, daysUntil i
 	}
 
 	if trial.PriceMonthly > 0 {
-		message += fmt.Sprintf(" ($%.2f/month)", trial.PriceMonthly)
+		message += fmt.Sprintf(" (%s/month)", accounts.FormatPrice(trial.PriceMonthly, trial.Currency))
 	}
 
 	// Log to console
-	fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), message)
+	log.Info(title, log.Icon(icon), "service", trial.ServiceName, "days_left", daysUntil, "price_monthly", trial.PriceMonthly, "currency", trial.Currency)
 
 	// Send desktop notification
 	if err := notify.SendDesktopNotification(title, message); err != nil {
@@ -0,0 +1,485 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	googlemail "google.golang.org/api/gmail/v1"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/filter"
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/state"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+// fakeFetcher is a gmail.MessageFetcher that returns canned messages instead of calling Gmail
+type fakeFetcher struct {
+	messages    []*googlemail.Message
+	bodies      map[string]string // message ID -> body returned by GetMessageBody
+	attachments map[string]bool   // message IDs that have an attachment
+	err         error             // returned by GetMessagesForScopes
+
+	queries [][]string // queries passed to each GetMessagesForScopes call
+}
+
+func (f *fakeFetcher) GetMessagesForScopes(maxResults int64, queries []string) (*gmail.ScopeMessages, error) {
+	f.queries = append(f.queries, queries)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	fetchedBy := make(map[string]map[string]bool, len(f.messages))
+	for _, msg := range f.messages {
+		fetchedBy[msg.Id] = make(map[string]bool, len(queries))
+		for _, q := range queries {
+			fetchedBy[msg.Id][q] = true
+		}
+	}
+	return &gmail.ScopeMessages{Messages: f.messages, FetchedBy: fetchedBy, Calls: len(queries)}, nil
+}
+
+func (f *fakeFetcher) GetMessageBody(messageID string) (string, error) {
+	return f.bodies[messageID], nil
+}
+
+func (f *fakeFetcher) MessageHasAttachment(msg *googlemail.Message) (bool, error) {
+	return f.attachments[msg.Id], nil
+}
+
+// testMessage builds a full-format Gmail message with a plain text body
+func testMessage(id, from, subject, body string) *googlemail.Message {
+	return &googlemail.Message{
+		Id:      id,
+		Snippet: subject,
+		Payload: &googlemail.MessagePart{
+			MimeType: "text/plain",
+			Headers: []*googlemail.MessagePartHeader{
+				{Name: "From", Value: from},
+				{Name: "Subject", Value: subject},
+			},
+			Body: &googlemail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(body))},
+		},
+	}
+}
+
+// setupPipeline points the config directory at a temp dir holding filters
+// and returns an empty seen-message state and alert database there.
+func setupPipeline(t *testing.T, filters ...filter.Filter) (*state.SeenMessages, *sql.DB) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("APPDATA", home)
+
+	cfg := filter.DefaultConfig()
+	cfg.Filters = filters
+	if err := filter.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	if err := appconfig.Save(appconfig.DefaultConfig()); err != nil {
+		t.Fatalf("appconfig.Save() error = %v", err)
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	t.Cleanup(func() { storage.CloseDB(db) })
+
+	seen, err := state.NewSeenMessages()
+	if err != nil {
+		t.Fatalf("NewSeenMessages() error = %v", err)
+	}
+
+	return seen, db
+}
+
+// runCheck runs one dry-run check (alerts saved, nothing notified) against fetcher
+func runCheck(t *testing.T, fetcher gmail.MessageFetcher, seen *state.SeenMessages, db *sql.DB, opts checkOptions) error {
+	t.Helper()
+	return runCheckContext(context.Background(), t, fetcher, seen, db, opts)
+}
+
+// runCheckContext is runCheck with a context that may already be cancelled
+func runCheckContext(ctx context.Context, t *testing.T, fetcher gmail.MessageFetcher, seen *state.SeenMessages, db *sql.DB, opts checkOptions) error {
+	t.Helper()
+
+	cfg, err := filter.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	opts.DryRun = true
+	opts.FetchLimit = 10
+	return checkEmailsWithRecovery(ctx, fetcher, cfg, seen, db, nil, nil, "", opts)
+}
+
+// alertFilters returns the filter name of each saved alert, keyed by message ID
+func alertFilters(t *testing.T, db *sql.DB) map[string]string {
+	t.Helper()
+
+	alerts, err := storage.GetRecentAlerts(db, 100)
+	if err != nil {
+		t.Fatalf("GetRecentAlerts() error = %v", err)
+	}
+
+	byID := make(map[string]string, len(alerts))
+	for _, a := range alerts {
+		byID[a.MessageID] = a.FilterName
+	}
+	return byID
+}
+
+func TestCheckEmailsMatches(t *testing.T) {
+	attachment := true
+	seen, db := setupPipeline(t,
+		filter.Filter{Name: "Boss", From: []string{"boss@company.com"}, Match: "any"},
+		filter.Filter{Name: "Invoices", Body: []string{"invoice"}, Match: "any"},
+		filter.Filter{Name: "Contracts", Subject: []string{"contract"}, Match: "any", HasAttachment: &attachment},
+	)
+
+	fetcher := &fakeFetcher{
+		messages: []*googlemail.Message{
+			testMessage("m1", "Boss <boss@company.com>", "Quick question", "Can you call me?"),
+			testMessage("m2", "billing@vendor.com", "Your receipt", "Invoice #42 is attached"),
+			testMessage("m3", "legal@vendor.com", "Signed contract", "See attached"),
+			testMessage("m4", "legal@vendor.com", "Contract draft", "Link to the draft inside"),
+			testMessage("m5", "news@example.com", "Weekly digest", "Nothing important"),
+		},
+		attachments: map[string]bool{"m3": true},
+	}
+
+	if err := runCheck(t, fetcher, seen, db, checkOptions{NotifyOnStartup: true}); err != nil {
+		t.Fatalf("checkEmails() error = %v", err)
+	}
+
+	got := alertFilters(t, db)
+	want := map[string]string{"m1": "Boss", "m2": "Invoices", "m3": "Contracts"}
+	if len(got) != len(want) {
+		t.Errorf("saved alerts = %v, want %v", got, want)
+	}
+	for id, name := range want {
+		if got[id] != name {
+			t.Errorf("alert for %s filter = %q, want %q", id, got[id], name)
+		}
+	}
+
+	for _, id := range []string{"m1", "m2", "m3", "m4", "m5"} {
+		if !seen.IsSeen(id) {
+			t.Errorf("message %s not marked as seen", id)
+		}
+	}
+}
+
+func TestCheckEmailsSkipsSeenMessages(t *testing.T) {
+	seen, db := setupPipeline(t, filter.Filter{Name: "Boss", From: []string{"boss@company.com"}, Match: "any"})
+
+	fetcher := &fakeFetcher{
+		messages: []*googlemail.Message{testMessage("m1", "boss@company.com", "First", "")},
+	}
+	opts := checkOptions{NotifyOnStartup: true}
+
+	if err := runCheck(t, fetcher, seen, db, opts); err != nil {
+		t.Fatalf("first check error = %v", err)
+	}
+
+	// Clearing the database shows whether the second check reprocesses m1
+	if _, err := db.Exec("DELETE FROM alerts"); err != nil {
+		t.Fatalf("failed to clear alerts: %v", err)
+	}
+
+	fetcher.messages = append(fetcher.messages, testMessage("m2", "boss@company.com", "Second", ""))
+	if err := runCheck(t, fetcher, seen, db, opts); err != nil {
+		t.Fatalf("second check error = %v", err)
+	}
+
+	got := alertFilters(t, db)
+	if _, ok := got["m1"]; ok {
+		t.Errorf("already seen message m1 was processed again")
+	}
+	if got["m2"] != "Boss" {
+		t.Errorf("new message m2 not alerted, alerts = %v", got)
+	}
+	if len(fetcher.queries) != 2 {
+		t.Errorf("GetMessagesForScopes calls = %d, want 2", len(fetcher.queries))
+	}
+}
+
+func TestCheckEmailsMaxMessageAge(t *testing.T) {
+	seen, db := setupPipeline(t, filter.Filter{Name: "Boss", From: []string{"boss@company.com"}, Match: "any"})
+
+	recent := testMessage("recent", "boss@company.com", "Today", "")
+	recent.InternalDate = time.Now().Add(-time.Hour).UnixMilli()
+	old := testMessage("old", "boss@company.com", "Last month", "")
+	old.InternalDate = time.Now().Add(-30 * 24 * time.Hour).UnixMilli()
+	undated := testMessage("undated", "boss@company.com", "No date", "")
+
+	fetcher := &fakeFetcher{messages: []*googlemail.Message{recent, old, undated}}
+	opts := checkOptions{NotifyOnStartup: true, MaxMessageAge: 24 * time.Hour}
+	if err := runCheck(t, fetcher, seen, db, opts); err != nil {
+		t.Fatalf("checkEmails() error = %v", err)
+	}
+
+	got := alertFilters(t, db)
+	if _, ok := got["old"]; ok {
+		t.Errorf("message older than max_message_age was alerted")
+	}
+	for _, id := range []string{"recent", "undated"} {
+		if got[id] != "Boss" {
+			t.Errorf("message %s not alerted, alerts = %v", id, got)
+		}
+	}
+	if !seen.IsSeen("old") {
+		t.Errorf("skipped old message not marked as seen")
+	}
+}
+
+// TestDetectAndSaveAccountDryRun tests that dry-run logs account detections without saving them
+func TestDetectAndSaveAccountDryRun(t *testing.T) {
+	_, db := setupPipeline(t)
+
+	appCfg := appconfig.DefaultConfig()
+	appCfg.Accounts.Enabled = true
+	if err := appconfig.Save(appCfg); err != nil {
+		t.Fatalf("appconfig.Save() error = %v", err)
+	}
+
+	body := "Welcome to Spotify Premium free trial. You'll be charged $10.99/month after your trial ends on 03/31/2025."
+	email := gmail.ParseMessage(testMessage("trial-1", "no-reply@spotify.com", "Your free trial has started", body))
+
+	for _, tt := range []struct {
+		opts checkOptions
+		want int
+	}{
+		{checkOptions{DryRun: true}, 0},
+		{checkOptions{}, 1},
+	} {
+		detectAndSaveAccount(email, body, db, tt.opts)
+
+		saved, err := storage.GetAllAccounts(db)
+		if err != nil {
+			t.Fatalf("GetAllAccounts() error = %v", err)
+		}
+		if len(saved) != tt.want {
+			t.Errorf("DryRun=%v: saved %d account(s), want %d", tt.opts.DryRun, len(saved), tt.want)
+		}
+	}
+}
+
+func TestCreateAlertTimestamp(t *testing.T) {
+	received := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	msg := testMessage("m1", "boss@company.com", "Quick question", "")
+	msg.InternalDate = received.UnixMilli()
+	alert := createAlert(msg, gmail.ParseMessage(msg), nil, 0)
+	if !alert.Timestamp.Equal(received) {
+		t.Errorf("Timestamp = %v, want the received date %v", alert.Timestamp, received)
+	}
+
+	// Without an internal date the alert is stamped with the time it was processed
+	msg.InternalDate = 0
+	before := time.Now()
+	alert = createAlert(msg, gmail.ParseMessage(msg), nil, 0)
+	if alert.Timestamp.Before(before) {
+		t.Errorf("Timestamp = %v, want now (after %v)", alert.Timestamp, before)
+	}
+}
+
+func TestSaveAlertDuplicate(t *testing.T) {
+	_, db := setupPipeline(t)
+
+	msg := testMessage("m1", "boss@company.com", "Quick question", "")
+	if !saveAlert(db, createAlert(msg, gmail.ParseMessage(msg), nil, 0)) {
+		t.Fatal("saveAlert() = false for a new alert, want true")
+	}
+
+	// Seen state cleared: the same message comes round again and must not notify twice
+	if saveAlert(db, createAlert(msg, gmail.ParseMessage(msg), nil, 0)) {
+		t.Error("saveAlert() = true for an alert already saved, want false")
+	}
+}
+
+func TestCheckEmailsBaseline(t *testing.T) {
+	seen, db := setupPipeline(t, filter.Filter{Name: "Boss", From: []string{"boss@company.com"}, Match: "any"})
+
+	fetcher := &fakeFetcher{
+		messages: []*googlemail.Message{
+			testMessage("old1", "boss@company.com", "Last week", ""),
+			testMessage("old2", "news@example.com", "Newsletter", ""),
+		},
+	}
+
+	// Nothing seen yet: existing mail becomes the baseline without alerting
+	if err := runCheck(t, fetcher, seen, db, checkOptions{}); err != nil {
+		t.Fatalf("checkEmails() error = %v", err)
+	}
+	if got := alertFilters(t, db); len(got) != 0 {
+		t.Errorf("baseline check saved alerts %v, want none", got)
+	}
+	if seen.Count() != 2 {
+		t.Errorf("seen count = %d, want 2", seen.Count())
+	}
+
+	fetcher.messages = append([]*googlemail.Message{testMessage("new1", "boss@company.com", "Today", "")}, fetcher.messages...)
+	if err := runCheck(t, fetcher, seen, db, checkOptions{}); err != nil {
+		t.Fatalf("checkEmails() error = %v", err)
+	}
+	got := alertFilters(t, db)
+	if len(got) != 1 || got["new1"] != "Boss" {
+		t.Errorf("alerts after baseline = %v, want only new1", got)
+	}
+}
+
+func TestCheckEmailsFetchError(t *testing.T) {
+	seen, db := setupPipeline(t, filter.Filter{Name: "Boss", From: []string{"boss@company.com"}, Match: "any"})
+
+	fetchErr := errors.New("quota exceeded")
+	fetcher := &fakeFetcher{err: fetchErr}
+
+	if err := runCheck(t, fetcher, seen, db, checkOptions{NotifyOnStartup: true}); !errors.Is(err, fetchErr) {
+		t.Errorf("checkEmails() error = %v, want %v", err, fetchErr)
+	}
+	if seen.Count() != 0 {
+		t.Errorf("seen count = %d after a failed fetch, want 0", seen.Count())
+	}
+}
+
+func TestGetMessageBodyFetchesMissingPayload(t *testing.T) {
+	fetcher := &fakeFetcher{bodies: map[string]string{"m1": "fetched body"}}
+	cache := make(map[string]string)
+
+	if got := getMessageBody(fetcher, &googlemail.Message{Id: "m1"}, cache); got != "fetched body" {
+		t.Errorf("getMessageBody() = %q, want the fetched body", got)
+	}
+
+	// Cached for the rest of the check
+	fetcher.bodies["m1"] = "changed"
+	if got := getMessageBody(fetcher, &googlemail.Message{Id: "m1"}, cache); got != "fetched body" {
+		t.Errorf("getMessageBody() second call = %q, want cached body", got)
+	}
+
+	// Full-format messages use their own payload
+	msg := testMessage("m2", "a@b.com", "Hi", "inline body")
+	if got := getMessageBody(fetcher, msg, cache); got != "inline body" {
+		t.Errorf("getMessageBody() = %q, want the payload body", got)
+	}
+}
+
+func TestCheckEmailsStopsOnShutdown(t *testing.T) {
+	seen, db := setupPipeline(t, filter.Filter{Name: "Boss", From: []string{"boss@company.com"}, Match: "any"})
+
+	fetcher := &fakeFetcher{
+		messages: []*googlemail.Message{testMessage("m1", "boss@company.com", "Hello", "")},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runCheckContext(ctx, t, fetcher, seen, db, checkOptions{NotifyOnStartup: true}); err != nil {
+		t.Fatalf("checkEmails() error = %v", err)
+	}
+
+	// Left unseen so the next run processes it
+	if seen.IsSeen("m1") {
+		t.Error("message marked as seen after shutdown was requested")
+	}
+	if got := alertFilters(t, db); len(got) != 0 {
+		t.Errorf("alerts saved after shutdown was requested: %v", got)
+	}
+}
+
+func TestAsyncWorkWait(t *testing.T) {
+	t.Run("waits for work in flight", func(t *testing.T) {
+		w := newAsyncWork()
+		finished := make(chan struct{})
+		w.Go(func(ctx context.Context) {
+			time.Sleep(20 * time.Millisecond)
+			close(finished)
+		})
+
+		if dropped := w.Wait(time.Second); dropped != 0 {
+			t.Errorf("Wait() dropped = %d, want 0", dropped)
+		}
+		select {
+		case <-finished:
+		default:
+			t.Error("Wait() returned before the work finished")
+		}
+		if w.Go(func(ctx context.Context) {}) {
+			t.Error("Go() accepted work after Wait()")
+		}
+	})
+
+	t.Run("cancels work after the timeout", func(t *testing.T) {
+		w := newAsyncWork()
+		cancelled := make(chan struct{})
+		w.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			close(cancelled)
+		})
+
+		if dropped := w.Wait(10 * time.Millisecond); dropped != 1 {
+			t.Errorf("Wait() dropped = %d, want 1", dropped)
+		}
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Error("work was not cancelled")
+		}
+	})
+
+	t.Run("nil tracker", func(t *testing.T) {
+		var w *asyncWork
+		ran := make(chan struct{})
+		if !w.Go(func(ctx context.Context) { close(ran) }) {
+			t.Fatal("Go() on nil tracker = false, want true")
+		}
+		<-ran
+		if dropped := w.Wait(time.Millisecond); dropped != 0 {
+			t.Errorf("Wait() on nil tracker = %d, want 0", dropped)
+		}
+	})
+}
+
+// TestSendWebhooksForAlertAsync tests that a slow webhook doesn't block the caller and is waited for at shutdown
+func TestSendWebhooksForAlertAsync(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		received <- struct{}{}
+	}))
+	defer server.Close()
+
+	hooks := []appconfig.WebhookConfig{{Name: "slack", URL: server.URL}, {Name: "unset"}, {Name: "discord", URL: server.URL}}
+	async := newAsyncWork()
+
+	returned := make(chan struct{})
+	go func() {
+		sendWebhooksForAlert(async, hooks, storage.Alert{MessageID: "m1", Subject: "Hi"})
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("sendWebhooksForAlert() blocked on a slow webhook")
+	}
+
+	if pending := async.Pending(); pending != 2 {
+		t.Errorf("Pending() = %d, want 2 webhooks in flight", pending)
+	}
+
+	close(release)
+	if dropped := async.Wait(5 * time.Second); dropped != 0 {
+		t.Errorf("Wait() dropped = %d, want 0", dropped)
+	}
+	if len(received) != 2 {
+		t.Errorf("webhooks received = %d, want 2", len(received))
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+var (
+	statsDays  int
+	statsSince string
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize alert history",
+	Long: `Show a summary of alerts over a date range.
+
+Displays total alerts, counts per filter, the top 10 senders,
+and a breakdown by priority.
+
+Stats can only cover alerts that are still in the database. By default
+alerts are wiped daily at midnight; set monitoring.database.retention_days
+in app-config.yaml to keep more history.
+
+Examples:
+  # Last 7 days (default)
+  email-sentinel stats
+
+  # Last 30 days
+  email-sentinel stats --days 30
+
+  # Everything since a date
+  email-sentinel stats --since 2025-01-01`,
+	Run: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().IntVar(&statsDays, "days", 7, "Number of days to include")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Include alerts since this date (YYYY-MM-DD)")
+}
+
+func runStats(cmd *cobra.Command, args []string) {
+	end := time.Now()
+	var start time.Time
+
+	if statsSince != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", statsSince, time.Local)
+		if err != nil {
+			fmt.Printf("❌ Invalid --since date '%s' (expected YYYY-MM-DD)\n", statsSince)
+			os.Exit(1)
+		}
+		start = parsed
+	} else {
+		if statsDays <= 0 {
+			fmt.Println("❌ --days must be positive")
+			os.Exit(1)
+		}
+		midnight := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+		start = midnight.AddDate(0, 0, -(statsDays - 1))
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		fmt.Printf("❌ Error opening alert database: %v\n", err)
+		os.Exit(1)
+	}
+	defer storage.CloseDB(db)
+
+	alerts, err := storage.GetAlertsBetween(db, start, end)
+	if err != nil {
+		fmt.Printf("❌ Error fetching alerts: %v\n", err)
+		os.Exit(1)
+	}
+
+	stats := storage.ComputeAlertStats(alerts)
+
+	// Warn when the requested range reaches past what retention keeps
+	if appCfg, err := appconfig.Load(); err == nil {
+		cutoff := storage.RetentionCutoff(end, appCfg.Monitoring.Database.RetentionDays)
+		if start.Before(cutoff) {
+			fmt.Printf("⚠️  Alerts before %s have been removed by cleanup (retention_days: %d)\n\n",
+				cutoff.Format("2006-01-02"), appCfg.Monitoring.Database.RetentionDays)
+		}
+	}
+
+	ui.PrintSection(fmt.Sprintf("Alert Stats: %s → %s", start.Format("2006-01-02"), end.Format("2006-01-02")))
+	ui.PrintKeyValue("Total alerts", strconv.Itoa(stats.Total))
+	fmt.Println()
+
+	if stats.Total == 0 {
+		ui.PrintInfo("No alerts in this range")
+		return
+	}
+
+	ui.PrintSubsection("By Filter")
+	ui.PrintTable([]string{"Filter", "Alerts"}, countRows(stats.ByFilter))
+	fmt.Println()
+
+	ui.PrintSubsection("Top Senders")
+	ui.PrintTable([]string{"Sender", "Alerts"}, countRows(storage.TopN(stats.BySender, 10)))
+	fmt.Println()
+
+	ui.PrintSubsection("By Priority")
+	ui.PrintTable([]string{"Priority", "Alerts"}, [][]string{
+		{"Critical", strconv.Itoa(stats.ByPriority[storage.PriorityCritical])},
+		{"High", strconv.Itoa(stats.ByPriority[storage.PriorityHigh])},
+		{"Normal", strconv.Itoa(stats.ByPriority[storage.PriorityNormal])},
+	})
+	fmt.Println()
+}
+
+// countRows converts count entries into table rows
+func countRows(entries []storage.CountEntry) [][]string {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, []string{entry.Key, strconv.Itoa(entry.Count)})
+	}
+	return rows
+}
@@ -4,13 +4,20 @@ Copyright © 2025 Datateamsix <research@dt6.io>
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/datateamsix/email-sentinel/internal/config"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/state"
+	"github.com/datateamsix/email-sentinel/internal/ui"
 )
 
 // statusCmd represents the status command
@@ -20,25 +27,97 @@ var statusCmd = &cobra.Command{
 	Long: `Display the current status of email-sentinel configuration.
 
 Shows:
+- Watcher status (including a background daemon) and Gmail API health
 - Authentication status
 - Number of configured filters
 - Configuration settings
 - Notification settings
 
-Example:
-  email-sentinel status`,
+For scripts, shell prompts and cron jobs, --oneline prints a single line of
+key=value pairs and --json prints the full dashboard data:
+  running=true filters=3 alerts_today=5 auth=valid next_check=12s paused=false failures=0
+
+Examples:
+  email-sentinel status
+  email-sentinel status --oneline
+  email-sentinel status --json | jq .running`,
 	Run: runStatus,
 }
 
+var (
+	statusOneLine bool
+	statusJSON    bool
+)
+
 func init() {
 	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().BoolVar(&statusOneLine, "oneline", false, "Print a single line of key=value pairs")
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the status as JSON")
+	statusCmd.MarkFlagsMutuallyExclusive("oneline", "json")
 }
 
 func runStatus(cmd *cobra.Command, args []string) {
+	if statusOneLine || statusJSON {
+		data, err := ui.GatherDashboardData()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error gathering status: %v\n", err)
+			os.Exit(1)
+		}
+
+		if statusJSON {
+			if err := writeStatusJSON(os.Stdout, data); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error writing JSON: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		fmt.Println(formatStatusOneLine(data, time.Now()))
+		return
+	}
+
 	fmt.Println("📊 Email Sentinel Status")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println("")
 
+	// Watcher status (written by 'email-sentinel start'), plus the daemon's pidfile
+	status, err := state.LoadRuntimeStatus()
+	if err != nil {
+		status = nil
+	}
+	daemonPID := state.RunningDaemonPID()
+	if daemonPID > 0 || status.IsRunning(time.Now()) {
+		if daemonPID > 0 {
+			fmt.Printf("🟢 Watcher: Running in background (PID: %d)\n", daemonPID)
+			if logPath, err := state.DaemonLogPath(); err == nil {
+				fmt.Printf("   Log: %s\n", logPath)
+			}
+		} else {
+			fmt.Printf("🟢 Watcher: Running (PID: %d)\n", status.PID)
+		}
+		if pause, err := state.LoadPauseState(); err == nil && pause.IsActive(time.Now()) {
+			if pause.Until.IsZero() {
+				fmt.Println("   ⏸️  Monitoring paused (run: email-sentinel resume)")
+			} else {
+				fmt.Printf("   ⏸️  Monitoring paused until %s\n", pause.Until.Format("15:04"))
+			}
+		}
+		if status.IsRunning(time.Now()) {
+			if !status.LastCheck.IsZero() {
+				fmt.Printf("   Last check: %s\n", status.LastCheck.Format("15:04:05"))
+			}
+			if status.ConsecutiveFailures > 0 {
+				fmt.Printf("   ⚠️  Gmail API: %d consecutive failure(s)\n", status.ConsecutiveFailures)
+				fmt.Printf("   Last error: %s\n", status.LastError)
+			} else {
+				fmt.Println("   Gmail API: healthy")
+			}
+		}
+	} else {
+		fmt.Println("⚪ Watcher: Stopped")
+		fmt.Println("   Run: email-sentinel start (or start --daemon to run in the background)")
+	}
+	fmt.Println("")
+
 	// Check authentication
 	if gmail.TokenExists() {
 		fmt.Println("✅ Authentication: Configured")
@@ -90,3 +169,44 @@ func runStatus(cmd *cobra.Command, args []string) {
 	configPath, _ := config.ConfigPath()
 	fmt.Printf("📁 Config File: %s\n", configPath)
 }
+
+// formatStatusOneLine renders the status as space-separated key=value pairs
+// Keys are stable so the line can be parsed with cut/awk; next_check is "-" when unknown.
+func formatStatusOneLine(data *ui.DashboardData, now time.Time) string {
+	auth := "missing"
+	switch {
+	case data.TokenExists && !data.AuthValid:
+		auth = "expired"
+	case data.TokenExists && data.AuthWarning != "":
+		auth = "warning"
+	case data.TokenExists:
+		auth = "valid"
+	}
+
+	nextCheck := "-"
+	if data.IsRunning && !data.NextCheck.IsZero() {
+		wait := data.NextCheck.Sub(now).Round(time.Second)
+		if wait < 0 {
+			wait = 0
+		}
+		nextCheck = wait.String()
+	}
+
+	fields := []string{
+		fmt.Sprintf("running=%t", data.IsRunning),
+		fmt.Sprintf("filters=%d", data.FilterCount-data.DisabledFilters),
+		fmt.Sprintf("alerts_today=%d", data.NotificationsSent),
+		"auth=" + auth,
+		"next_check=" + nextCheck,
+		fmt.Sprintf("paused=%t", data.Paused),
+		fmt.Sprintf("failures=%d", data.ConsecutiveFailures),
+	}
+	return strings.Join(fields, " ")
+}
+
+// writeStatusJSON writes the dashboard data as indented JSON
+func writeStatusJSON(w io.Writer, data *ui.DashboardData) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
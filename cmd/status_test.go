@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/ui"
+)
+
+// TestFormatStatusOneLine pins the --oneline format scripts and status bars parse
+func TestFormatStatusOneLine(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		data ui.DashboardData
+		want string
+	}{
+		{
+			name: "Stopped without a token",
+			data: ui.DashboardData{},
+			want: "running=false filters=0 alerts_today=0 auth=missing next_check=- paused=false failures=0",
+		},
+		{
+			name: "Running",
+			data: ui.DashboardData{IsRunning: true, NextCheck: now.Add(42 * time.Second), TokenExists: true, AuthValid: true, FilterCount: 5, DisabledFilters: 2, NotificationsSent: 7},
+			want: "running=true filters=3 alerts_today=7 auth=valid next_check=42s paused=false failures=0",
+		},
+		{
+			name: "Overdue check and auth warning",
+			data: ui.DashboardData{IsRunning: true, NextCheck: now.Add(-time.Minute), TokenExists: true, AuthValid: true, AuthWarning: "refresh failed", ConsecutiveFailures: 3},
+			want: "running=true filters=0 alerts_today=0 auth=warning next_check=0s paused=false failures=3",
+		},
+		{
+			name: "Paused with an expired token",
+			data: ui.DashboardData{Paused: true, TokenExists: true, NextCheck: now.Add(time.Minute)},
+			want: "running=false filters=0 alerts_today=0 auth=expired next_check=- paused=true failures=0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatStatusOneLine(&tt.data, now); got != tt.want {
+				t.Errorf("formatStatusOneLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriteStatusJSON pins the --json field names
+func TestWriteStatusJSON(t *testing.T) {
+	data := &ui.DashboardData{
+		IsRunning:   true,
+		PID:         1234,
+		Uptime:      time.Hour,
+		LastError:   "quota exceeded",
+		Email:       "me@example.com",
+		AuthWarning: "expires soon",
+		NtfyTopic:   "alerts",
+		Filters:     []ui.FilterSummary{{Name: "Boss", Summary: "from boss", Disabled: true}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeStatusJSON(&buf, data); err != nil {
+		t.Fatalf("writeStatusJSON() error = %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	var got []string
+	for name := range fields {
+		got = append(got, name)
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"alerts_today", "auth_valid", "auth_warning", "consecutive_failures", "desktop_enabled",
+		"disabled_filters", "email", "emails_checked_today", "filter_count", "filters",
+		"has_state_info", "last_check", "last_error", "last_run", "matches_today",
+		"mobile_enabled", "next_check", "ntfy_topic", "paused", "paused_until", "pid",
+		"polling_interval", "running", "token_exists", "token_expiry", "uptime_ns",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON fields = %q, want %q", got, want)
+	}
+
+	var filters []map[string]any
+	if err := json.Unmarshal(fields["filters"], &filters); err != nil {
+		t.Fatalf("json.Unmarshal(filters) error = %v", err)
+	}
+	wantFilter := map[string]any{"name": "Boss", "summary": "from boss", "disabled": true}
+	if len(filters) != 1 || !reflect.DeepEqual(filters[0], wantFilter) {
+		t.Errorf("filters = %v, want [%v]", filters, wantFilter)
+	}
+}
@@ -5,25 +5,31 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/datateamsix/email-sentinel/internal/state"
 )
 
+// stopTimeout is how long to wait for the daemon to shut down after signalling it
+const stopTimeout = 10 * time.Second
+
 // stopCmd represents the stop command
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the email-sentinel daemon",
 	Long: `Stop the email-sentinel background daemon if it's running.
 
-Note: Currently, daemon mode requires manual process management.
-If you started email-sentinel in the foreground, use Ctrl+C to stop it.
+The daemon is found through the pidfile written by 'email-sentinel start --daemon'.
+On Linux and macOS it is asked to shut down gracefully (SIGTERM); on Windows
+the process is terminated.
 
-For true daemon mode, consider using a process manager like:
-- Windows: nssm (Non-Sucking Service Manager)
-- macOS: launchd
-- Linux: systemd
+If you started email-sentinel in the foreground, use Ctrl+C to stop it.
 
 Example:
+  email-sentinel start --daemon
   email-sentinel stop`,
 	Run: runStop,
 }
@@ -33,13 +39,33 @@ func init() {
 }
 
 func runStop(cmd *cobra.Command, args []string) {
-	fmt.Println("⏹️  Stop Command")
-	fmt.Println("")
-	fmt.Println("Currently, daemon management is not fully implemented.")
-	fmt.Println("")
-	fmt.Println("To stop email-sentinel:")
-	fmt.Println("  • If running in foreground: Press Ctrl+C")
-	fmt.Println("  • If running as daemon: Manually kill the process")
-	fmt.Println("")
-	fmt.Println("Future versions will include full daemon management.")
+	pid := state.RunningDaemonPID()
+	if pid == 0 {
+		fmt.Println("⚪ No background daemon is running")
+		if status, err := state.LoadRuntimeStatus(); err == nil && status.IsRunning(time.Now()) {
+			fmt.Printf("   A watcher is running in the foreground (PID: %d) - press Ctrl+C in its terminal\n", status.PID)
+		}
+		return
+	}
+
+	if err := state.StopProcess(pid); err != nil {
+		fmt.Printf("❌ Error stopping daemon (PID: %d): %v\n", pid, err)
+		os.Exit(1)
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for state.ProcessAlive(pid) {
+		if time.Now().After(deadline) {
+			fmt.Printf("⚠️  Daemon (PID: %d) is still shutting down after %v\n", pid, stopTimeout)
+			os.Exit(1)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	// A terminated (not gracefully stopped) daemon can't clean up its own pidfile
+	if path, err := state.PIDPath(); err == nil {
+		os.Remove(path)
+	}
+
+	fmt.Printf("⏹️  Email Sentinel stopped (PID: %d)\n", pid)
 }
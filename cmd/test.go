@@ -6,11 +6,15 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/notify"
+	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
 // testCmd represents the test command
@@ -25,12 +29,14 @@ properly before you start monitoring.
 Subcommands:
   desktop     Test desktop notification
   mobile      Test mobile notification (requires ntfy_topic configured)
+  webhook     Test webhook notifications (notifications.webhooks in app-config.yaml)
   toast       Test Windows toast notification (Windows only)
   filter      Test if an email would match a filter
 
 Examples:
   email-sentinel test desktop
   email-sentinel test mobile
+  email-sentinel test webhook
   email-sentinel test toast
   email-sentinel test toast --priority  (test high-priority notification)
   email-sentinel test filter "Job Alerts" "from:linkedin.com" "subject:interview"`,
@@ -94,13 +100,38 @@ Example:
 
 var testPriority bool
 
+var testWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Short: "Send a test webhook notification",
+	Long: `Send a sample alert to the webhooks configured in app-config.yaml.
+
+Without a name every configured webhook is tested. Use --url to test an
+endpoint that isn't in the config yet.
+
+Requires:
+- At least one entry under notifications.webhooks in app-config.yaml
+  (or the --url flag)
+
+Examples:
+  email-sentinel test webhook
+  email-sentinel test webhook slack
+  email-sentinel test webhook --url "https://example.com/hook"`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runTestWebhook,
+}
+
+var testWebhookURL string
+
 func init() {
 	rootCmd.AddCommand(testCmd)
 	testCmd.AddCommand(testDesktopCmd)
 	testCmd.AddCommand(testMobileCmd)
+	testCmd.AddCommand(testWebhookCmd)
 	testCmd.AddCommand(testToastCmd)
 	testCmd.AddCommand(testFilterCmd)
 
+	testWebhookCmd.Flags().StringVar(&testWebhookURL, "url", "", "Test this URL instead of the configured webhooks")
+
 	// Add priority flag to toast test
 	testToastCmd.Flags().BoolVarP(&testPriority, "priority", "p", false, "Test high-priority notification")
 }
@@ -108,6 +139,7 @@ func init() {
 func runTestDesktop(cmd *cobra.Command, args []string) {
 	fmt.Println("🔔 Sending test desktop notification...")
 	fmt.Println("")
+	useConfiguredDesktopOptions()
 
 	err := notify.SendDesktopNotification(
 		"Email Sentinel Test",
@@ -170,6 +202,20 @@ func runTestMobile(cmd *cobra.Command, args []string) {
 	fmt.Printf("Sending to topic: %s\n", cfg.Notifications.Mobile.NtfyTopic)
 	fmt.Println("")
 
+	// Send to the configured server and priority, like a real alert
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading app-config.yaml: %v\n", err)
+		os.Exit(1)
+	}
+	opts, err := notify.MobileOptionsFromConfig(&appCfg.Notifications.Mobile)
+	if err != nil {
+		fmt.Printf("❌ Invalid notifications.mobile.server: %v\n", err)
+		os.Exit(1)
+	}
+	notify.SetMobileOptions(opts)
+	server := opts.Server
+
 	err = notify.SendMobileNotification(
 		cfg.Notifications.Mobile.NtfyTopic,
 		"Email Sentinel Test",
@@ -182,13 +228,13 @@ func runTestMobile(cmd *cobra.Command, args []string) {
 		fmt.Println("Troubleshooting:")
 		fmt.Println("  1. Verify ntfy app is installed on your phone")
 		fmt.Println("  2. Check you're subscribed to topic:", cfg.Notifications.Mobile.NtfyTopic)
-		fmt.Println("  3. Test manually: https://ntfy.sh/" + cfg.Notifications.Mobile.NtfyTopic)
+		fmt.Println("  3. Test manually:", server+"/"+cfg.Notifications.Mobile.NtfyTopic)
 		os.Exit(1)
 	}
 
 	fmt.Println("✅ Test notification sent!")
 	fmt.Println("")
-	fmt.Println("Check your phone for a notification from ntfy.sh")
+	fmt.Println("Check your phone for a notification from ntfy")
 	fmt.Println("")
 	fmt.Println("If you didn't receive it:")
 	fmt.Println("  • Open ntfy app and verify subscription to:", cfg.Notifications.Mobile.NtfyTopic)
@@ -196,9 +242,92 @@ func runTestMobile(cmd *cobra.Command, args []string) {
 	fmt.Println("  • Try a different topic name (must be unique)")
 }
 
+// useConfiguredDesktopOptions applies the configured duration and sound so the test looks like a real alert
+// If app-config.yaml can't be read, the notification is sent short and silent.
+func useConfiguredDesktopOptions() {
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		return
+	}
+
+	opts := desktopOptions(appCfg)
+	notify.SetDesktopOptions(opts)
+
+	sound := "off"
+	if opts.Sound {
+		sound = "on"
+	}
+	fmt.Printf("   Duration: %ds, sound: %s (notifications.desktop in app-config.yaml)\n", appCfg.Notifications.Desktop.Duration, sound)
+	fmt.Println("")
+}
+
+func runTestWebhook(cmd *cobra.Command, args []string) {
+	fmt.Println("🌐 Sending test webhook notification...")
+	fmt.Println("")
+
+	var webhooks []appconfig.WebhookConfig
+	if testWebhookURL != "" {
+		webhooks = []appconfig.WebhookConfig{{Name: "command line", URL: testWebhookURL}}
+	} else {
+		appCfg, err := appconfig.Load()
+		if err != nil {
+			fmt.Printf("❌ Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, hook := range appCfg.Notifications.Webhooks {
+			if len(args) == 0 || strings.EqualFold(hook.Name, args[0]) {
+				webhooks = append(webhooks, hook)
+			}
+		}
+	}
+
+	if len(webhooks) == 0 {
+		if len(args) > 0 {
+			fmt.Printf("❌ No webhook named '%s' configured\n", args[0])
+		} else {
+			fmt.Println("❌ No webhooks configured")
+		}
+		fmt.Println("\nAdd one under notifications.webhooks in app-config.yaml,")
+		fmt.Println("or test a URL directly: email-sentinel test webhook --url <url>")
+		os.Exit(1)
+	}
+
+	alert := storage.Alert{
+		Timestamp:    time.Now(),
+		Sender:       "test@email-sentinel.local",
+		Subject:      "Email Sentinel Test",
+		Snippet:      "If you can see this, webhook notifications are working! ✅",
+		GmailLink:    "https://mail.google.com/mail/u/0/#inbox",
+		FilterName:   "Test Filter",
+		FilterLabels: []string{"test"},
+		Priority:     0,
+	}
+
+	failed := 0
+	for _, hook := range webhooks {
+		if err := notify.SendWebhookWithTemplate(hook.URL, hook.Template, alert); err != nil {
+			fmt.Printf("❌ %s: %v\n", hook.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ %s: delivered\n", hook.Name)
+	}
+
+	if failed > 0 {
+		fmt.Println("")
+		fmt.Println("Troubleshooting:")
+		fmt.Println("  1. Check the webhook URL is correct and still active")
+		fmt.Println("  2. Custom templates must produce valid JSON - quote values with {{json .Field}}")
+		fmt.Println("  3. Slack expects a \"text\" field, Discord expects \"content\"")
+		os.Exit(1)
+	}
+}
+
 func runTestToast(cmd *cobra.Command, args []string) {
 	fmt.Println("🪟 Sending test Windows toast notification...")
 	fmt.Println("")
+	useConfiguredDesktopOptions()
 
 	var err error
 	if testPriority {
@@ -289,4 +418,9 @@ func runTestFilter(cmd *cobra.Command, args []string) {
 		}
 		fmt.Printf("  Match mode: %s\n", targetFilter.Match)
 	}
+
+	if targetFilter.HasAttachment != nil {
+		fmt.Println("")
+		fmt.Printf("ℹ️  Attachment condition (%s) isn't checked for a simulated email\n", attachmentDesc(*targetFilter.HasAttachment))
+	}
 }
@@ -0,0 +1,111 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package accounts
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+)
+
+// maxCancelURLLength bounds the URLs we are willing to hand to the OS
+const maxCancelURLLength = 2048
+
+// knownManagePages are account/subscription pages for common services, keyed by lowercase name
+// Used when no cancellation link was captured from an email.
+var knownManagePages = map[string]string{
+	"netflix":         "https://www.netflix.com/cancelplan",
+	"spotify":         "https://www.spotify.com/account/subscription/",
+	"hulu":            "https://secure.hulu.com/account",
+	"disney+":         "https://www.disneyplus.com/account",
+	"youtube premium": "https://www.youtube.com/paid_memberships",
+	"apple music":     "https://apps.apple.com/account/subscriptions",
+	"icloud":          "https://apps.apple.com/account/subscriptions",
+	"adobe":           "https://account.adobe.com/plans",
+	"microsoft 365":   "https://account.microsoft.com/services",
+	"onedrive":        "https://account.microsoft.com/services",
+	"github":          "https://github.com/settings/billing",
+	"dropbox":         "https://www.dropbox.com/account/plan",
+	"grammarly":       "https://account.grammarly.com/subscription",
+	"zoom":            "https://zoom.us/billing",
+	"amazon prime":    "https://www.amazon.com/mc",
+}
+
+// ValidateCancelURL checks that a captured cancellation URL is safe to open
+// The URL must be https, name a public host (not an IP address or localhost)
+// and carry no credentials or whitespace.
+func ValidateCancelURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("empty URL")
+	}
+	if len(rawURL) > maxCancelURLLength {
+		return fmt.Errorf("URL is longer than %d characters", maxCancelURLLength)
+	}
+	if strings.ContainsAny(rawURL, " \t\r\n\"<>`") {
+		return fmt.Errorf("URL contains whitespace or quote characters")
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("only https links are opened (got %q)", parsed.Scheme)
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("URL contains credentials")
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return fmt.Errorf("URL has no host")
+	}
+	if net.ParseIP(host) != nil {
+		return fmt.Errorf("URL points to an IP address, not a website")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") {
+		return fmt.Errorf("URL points to a local host")
+	}
+	if !strings.Contains(host, ".") {
+		return fmt.Errorf("host %q is not a public domain", host)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if !isHostLabel(label) {
+			return fmt.Errorf("host %q is not a valid domain name", host)
+		}
+	}
+
+	return nil
+}
+
+// isHostLabel reports whether s is a valid DNS label (letters, digits and inner hyphens)
+func isHostLabel(s string) bool {
+	if s == "" || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// ManagePageFor returns a known account management page for a service, if there is one
+func ManagePageFor(serviceName string) (string, bool) {
+	page, ok := knownManagePages[strings.ToLower(strings.TrimSpace(serviceName))]
+	return page, ok
+}
+
+// OpenCancelURL opens a validated cancellation URL in the default browser
+func OpenCancelURL(rawURL string) error {
+	if err := ValidateCancelURL(rawURL); err != nil {
+		return fmt.Errorf("blocked cancellation link %q: %w", rawURL, err)
+	}
+
+	return gmail.OpenURL(rawURL)
+}
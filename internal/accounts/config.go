@@ -15,6 +15,7 @@ func LoadConfigFromAppConfig(appCfg *appconfig.AppConfig) *AccountConfig {
 
 	cfg := &AccountConfig{
 		Enabled:            appCfg.Accounts.Enabled,
+		DetectTypes:        appCfg.Accounts.DetectTypes,
 		MinConfidence:      appCfg.Accounts.Detection.MinConfidence,
 		Categories:         appCfg.Accounts.Categories,
 		DetectionKeywords:  appCfg.Accounts.Detection.Keywords,
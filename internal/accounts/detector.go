@@ -15,8 +15,12 @@ type Detector struct {
 	patterns      []DetectionPattern
 	minConfidence float64
 	categories    map[string][]string
+	detectTypes   map[string]bool // nil = detect every account type
 }
 
+// AccountTypes lists the account types detection patterns produce
+var AccountTypes = []string{"trial", "paid", "free", "cancellation"}
+
 // NewDetector creates a new account detector
 func NewDetector(minConfidence float64, categories map[string][]string) *Detector {
 	return &Detector{
@@ -26,6 +30,22 @@ func NewDetector(minConfidence float64, categories map[string][]string) *Detecto
 	}
 }
 
+// SetDetectTypes limits detection to the given account types (e.g. "trial", "paid")
+// An empty list detects every type.
+func (d *Detector) SetDetectTypes(types []string) {
+	d.detectTypes = nil
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if d.detectTypes == nil {
+			d.detectTypes = make(map[string]bool)
+		}
+		d.detectTypes[t] = true
+	}
+}
+
 // DetectAccount analyzes an email and attempts to detect account information
 func (d *Detector) DetectAccount(ctx DetectionContext) (*DetectionResult, error) {
 	// Combine all text for analysis
@@ -33,6 +53,9 @@ func (d *Detector) DetectAccount(ctx DetectionContext) (*DetectionResult, error)
 
 	// Try each pattern
 	for _, pattern := range d.patterns {
+		if d.detectTypes != nil && !d.detectTypes[pattern.Type] {
+			continue // Type turned off in accounts.detect_types
+		}
 		if d.matchesPattern(fullText, pattern) {
 			result := d.extractAccountInfo(ctx, pattern, fullText)
 			if result != nil && result.Confidence >= d.minConfidence {
@@ -81,8 +104,9 @@ func (d *Detector) extractAccountInfo(ctx DetectionContext, pattern DetectionPat
 
 	// Extract price if pattern has price regex
 	if pattern.PriceRegex != nil {
-		if price := d.extractPrice(fullText, pattern.PriceRegex); price > 0 {
-			result.PriceMonthly = price
+		if price, ok := d.extractPrice(fullText, pattern.PriceRegex); ok {
+			result.PriceMonthly = price.Amount
+			result.Currency = price.Currency
 		}
 	}
 
@@ -125,25 +149,25 @@ func (d *Detector) extractAccountInfo(ctx DetectionContext, pattern DetectionPat
 	return result
 }
 
-// extractPrice attempts to extract a price from text using the pattern
-func (d *Detector) extractPrice(text string, priceRegex *regexp.Regexp) float64 {
+// extractPrice attempts to extract a price and its currency from text using the pattern
+func (d *Detector) extractPrice(text string, priceRegex *regexp.Regexp) (PriceInfo, bool) {
 	// Try pattern-specific regex first
 	if matches := priceRegex.FindStringSubmatch(text); len(matches) > 1 {
-		if price, err := strconv.ParseFloat(matches[1], 64); err == nil {
-			return price
+		if price, ok := ParsePrice(matches[1]); ok {
+			return price, true
 		}
 	}
 
 	// Try common price patterns
 	for _, pattern := range PricePatterns {
 		if matches := pattern.FindStringSubmatch(text); len(matches) > 1 {
-			if price, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				return price
+			if price, ok := ParsePrice(matches[1]); ok {
+				return price, true
 			}
 		}
 	}
 
-	return 0
+	return PriceInfo{}, false
 }
 
 // extractDate attempts to extract a date from text
@@ -152,27 +176,9 @@ func (d *Detector) extractDate(text string, dateRegex *regexp.Regexp, baseDate t
 	if matches := dateRegex.FindStringSubmatch(text); len(matches) > 1 {
 		dateStr := matches[1]
 
-		// Try parsing as "in N days"
-		if strings.Contains(toLower(text), "in") && strings.Contains(toLower(dateStr), "day") {
-			// Extract number
-			var days int
-			for i := 0; i < len(dateStr); i++ {
-				if dateStr[i] >= '0' && dateStr[i] <= '9' {
-					numStr := ""
-					for i < len(dateStr) && dateStr[i] >= '0' && dateStr[i] <= '9' {
-						numStr += string(dateStr[i])
-						i++
-					}
-					if n, err := strconv.Atoi(numStr); err == nil {
-						days = n
-						break
-					}
-				}
-			}
-			if days > 0 {
-				date := baseDate.Add(time.Duration(days) * 24 * time.Hour)
-				return &date
-			}
+		// Try parsing as "in N days", counted from when the email arrived
+		if date := relativeDate(dateStr, baseDate); date != nil {
+			return date
 		}
 
 		// Try common date formats
@@ -193,6 +199,32 @@ func (d *Detector) extractDate(text string, dateRegex *regexp.Regexp, baseDate t
 	return nil
 }
 
+// relativeDateRegex matches the "N days" / "N hours" captured from phrases like "ends in 7 days"
+var relativeDateRegex = regexp.MustCompile(`(?i)^(\d{1,2})\s+(day|hour)s?$`)
+
+// relativeDate resolves "N days" or "N hours" against baseDate (now if unknown)
+func relativeDate(dateStr string, baseDate time.Time) *time.Time {
+	matches := relativeDateRegex.FindStringSubmatch(strings.TrimSpace(dateStr))
+	if matches == nil {
+		return nil
+	}
+
+	n, err := strconv.Atoi(matches[1])
+	if err != nil || n <= 0 {
+		return nil
+	}
+	if baseDate.IsZero() {
+		baseDate = time.Now()
+	}
+
+	unit := 24 * time.Hour
+	if strings.EqualFold(matches[2], "hour") {
+		unit = time.Hour
+	}
+	date := baseDate.Add(time.Duration(n) * unit)
+	return &date
+}
+
 // parseDate attempts to parse a date string in various formats
 func parseDate(dateStr string) *time.Time {
 	formats := []string{
@@ -0,0 +1,135 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package accounts
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDetectTypes tests that accounts.detect_types turns whole account types off
+func TestDetectTypes(t *testing.T) {
+	received := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	freeSignup := DetectionContext{
+		Subject:      "Welcome to Notion!",
+		Body:         "Welcome to Notion! Your account created successfully.",
+		Sender:       "team@notion.so",
+		ReceivedDate: received,
+		MessageID:    "free-1",
+	}
+	trialStart := DetectionContext{
+		Subject:      "Your free trial has started",
+		Body:         "Welcome to Spotify Premium free trial. You'll be charged $10.99/month after your trial ends on 03/31/2025.",
+		Sender:       "no-reply@spotify.com",
+		ReceivedDate: received,
+		MessageID:    "trial-1",
+	}
+
+	tests := []struct {
+		name     string
+		types    []string
+		ctx      DetectionContext
+		wantType string // "" = not detected
+	}{
+		{"All types - free signup detected", nil, freeSignup, "free"},
+		{"Paid and trial only - free signup ignored", []string{"trial", "paid"}, freeSignup, ""},
+		{"Paid and trial only - trial detected", []string{"trial", "paid"}, trialStart, "trial"},
+		{"Case and spacing ignored", []string{" Free "}, freeSignup, "free"},
+		{"Blank entries mean all types", []string{""}, freeSignup, "free"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewDetector(0.7, nil)
+			detector.SetDetectTypes(tt.types)
+
+			result, err := detector.DetectAccount(tt.ctx)
+			if err != nil {
+				t.Fatalf("DetectAccount() error = %v", err)
+			}
+
+			if tt.wantType == "" {
+				if result != nil {
+					t.Errorf("DetectAccount() = %s account for %q, want none", result.AccountType, result.ServiceName)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatalf("DetectAccount() = nil, want a %s account", tt.wantType)
+			}
+			if result.AccountType != tt.wantType {
+				t.Errorf("AccountType = %s, want %s", result.AccountType, tt.wantType)
+			}
+		})
+	}
+}
+
+// TestTrialEndDate tests that relative trial dates count from when the email was received
+func TestTrialEndDate(t *testing.T) {
+	received := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		body     string
+		received time.Time
+		expected time.Time
+	}{
+		{
+			name:     "Days from the received date",
+			body:     "Your Spotify Premium trial ends in 7 days. You'll be charged $10.99/month.",
+			received: received,
+			expected: received.AddDate(0, 0, 7),
+		},
+		{
+			name:     "Hours from the received date",
+			body:     "Your Spotify Premium trial expires in 12 hours.",
+			received: received,
+			expected: received.Add(12 * time.Hour),
+		},
+		{
+			name:     "Single day",
+			body:     "Your Spotify Premium trial ends in 1 day.",
+			received: received,
+			expected: received.AddDate(0, 0, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewDetector(0.7, nil)
+			result, err := detector.DetectAccount(DetectionContext{
+				Subject:      "Your trial is ending soon",
+				Body:         tt.body,
+				Sender:       "no-reply@spotify.com",
+				ReceivedDate: tt.received,
+				MessageID:    "trial-ending-1",
+			})
+			if err != nil {
+				t.Fatalf("DetectAccount() error = %v", err)
+			}
+			if result == nil || result.TrialEndDate == nil {
+				t.Fatalf("DetectAccount() = %+v, want a trial end date", result)
+			}
+			if !result.TrialEndDate.Equal(tt.expected) {
+				t.Errorf("TrialEndDate = %v, want %v", result.TrialEndDate, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRelativeDateFallsBackToNow tests that an unknown received date counts from now
+func TestRelativeDateFallsBackToNow(t *testing.T) {
+	before := time.Now()
+	date := relativeDate("3 days", time.Time{})
+	if date == nil {
+		t.Fatal("relativeDate() = nil, want a date")
+	}
+	if date.Before(before.AddDate(0, 0, 3)) || date.After(time.Now().AddDate(0, 0, 3)) {
+		t.Errorf("relativeDate() = %v, want 3 days from now", date)
+	}
+	if relativeDate("March 15, 2025", before) != nil {
+		t.Error("relativeDate() parsed an absolute date")
+	}
+}
@@ -17,7 +17,7 @@ func GetDefaultPatterns() []DetectionPattern {
 			Type:         "trial",
 			Keywords:     []string{"free trial", "trial period", "trial started", "trial membership", "start your trial", "trial begins"},
 			ServiceRegex: regexp.MustCompile(`(?i)(?:welcome to|thanks for joining|you.?re now a member of|trial for)\s+([A-Z][A-Za-z0-9\s]+?)(?:\s+(?:premium|plus|pro|free trial))?(?:\.|!|,)`),
-			PriceRegex:   regexp.MustCompile(`(?i)\$(\d+(?:\.\d{2})?)\s*(?:per|/)\s*(?:month|mo)`),
+			PriceRegex:   regexp.MustCompile(`(?i)(` + pricedAmount + `)` + perMonth),
 			DateRegex:    regexp.MustCompile(`(?i)(?:trial\s+)?(?:ends?|expires?)\s+(?:on\s+)?(\d{1,2}[-/]\d{1,2}[-/]\d{2,4}|\w+\s+\d{1,2},?\s+\d{4})`),
 			Confidence:   0.85,
 		},
@@ -26,8 +26,8 @@ func GetDefaultPatterns() []DetectionPattern {
 			Type:         "trial",
 			Keywords:     []string{"trial expires", "trial ends", "trial ending soon", "last chance", "trial will expire"},
 			ServiceRegex: regexp.MustCompile(`(?i)(?:your|the)\s+([A-Z][A-Za-z0-9\s]+?)\s+(?:trial|free trial|membership)`),
-			PriceRegex:   regexp.MustCompile(`(?i)\$(\d+(?:\.\d{2})?)\s*(?:per|/)\s*(?:month|mo)`),
-			DateRegex:    regexp.MustCompile(`(?i)(?:on|in)\s+(\d{1,2})\s+(?:day|hour)`),
+			PriceRegex:   regexp.MustCompile(`(?i)(` + pricedAmount + `)` + perMonth),
+			DateRegex:    regexp.MustCompile(`(?i)(?:on|in)\s+(\d{1,2}\s+(?:day|hour)s?)`),
 			Confidence:   0.90,
 		},
 
@@ -37,7 +37,7 @@ func GetDefaultPatterns() []DetectionPattern {
 			Type:         "paid",
 			Keywords:     []string{"subscription renewed", "payment successful", "subscription confirmed", "payment processed", "billing successful"},
 			ServiceRegex: regexp.MustCompile(`(?i)(?:for|your)\s+([A-Z][A-Za-z0-9\s]+?)\s+(?:subscription|membership|plan)`),
-			PriceRegex:   regexp.MustCompile(`(?i)(?:total|amount|charged|paid):\s*\$(\d+(?:\.\d{2})?)`),
+			PriceRegex:   regexp.MustCompile(`(?i)(?:total|amount|charged|paid|betrag|gesamt):\s*(` + pricedAmount + `)`),
 			Confidence:   0.90,
 		},
 		{
@@ -45,7 +45,7 @@ func GetDefaultPatterns() []DetectionPattern {
 			Type:         "paid",
 			Keywords:     []string{"monthly subscription", "annual subscription", "recurring payment", "auto-renew", "automatic renewal"},
 			ServiceRegex: regexp.MustCompile(`(?i)([A-Z][A-Za-z0-9\s]+?)\s+(?:subscription|membership|plan)`),
-			PriceRegex:   regexp.MustCompile(`(?i)\$(\d+(?:\.\d{2})?)`),
+			PriceRegex:   regexp.MustCompile(`(?i)(` + pricedAmount + `)`),
 			Confidence:   0.85,
 		},
 
@@ -73,10 +73,9 @@ func GetDefaultPatterns() []DetectionPattern {
 var (
 	// PricePatterns contains various price extraction patterns
 	PricePatterns = []*regexp.Regexp{
-		regexp.MustCompile(`\$(\d+(?:\.\d{2})?)`),                                    // $9.99
-		regexp.MustCompile(`USD\s*(\d+(?:\.\d{2})?)`),                                 // USD 9.99
-		regexp.MustCompile(`(?i)(?:price|cost|total|amount):\s*\$?(\d+(?:\.\d{2})?)`), // Price: $9.99
-		regexp.MustCompile(`(?i)(\d+(?:\.\d{2})?)\s*(?:per|/)\s*(?:month|mo)`),       // 9.99 per month
+		regexp.MustCompile(`(?i)(` + pricedAmount + `)`),                                                          // $9.99, USD 9.99, €12,00
+		regexp.MustCompile(`(?i)(?:price|cost|total|amount|preis):\s*(` + pricedAmount + `|` + priceAmount + `)`), // Price: 9.99
+		regexp.MustCompile(`(?i)(` + priceAmount + `)` + perMonth),                                                // 9.99 per month
 	}
 
 	// DatePatterns contains various date extraction patterns
@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package accounts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is assumed for prices without a currency symbol or code
+const DefaultCurrency = "USD"
+
+// currencySymbols maps price symbols to ISO 4217 codes
+// Multi-character symbols come first so "US$" isn't read as a bare "$".
+var currencySymbols = []struct {
+	symbol string
+	code   string
+}{
+	{"US$", "USD"},
+	{"C$", "CAD"},
+	{"A$", "AUD"},
+	{"$", "USD"},
+	{"€", "EUR"},
+	{"£", "GBP"},
+	{"₹", "INR"},
+	{"¥", "JPY"},
+}
+
+// Regex fragments for prices in email text
+const (
+	currencySymbol = `(?:US\$|C\$|A\$|\$|€|£|₹|¥)`
+	currencyCode   = `\b(?:USD|EUR|GBP|INR|JPY|CAD|AUD|CHF)\b`
+	priceAmount    = `\d+(?:[.,]\d{3})*(?:[.,]\d{1,2})?`
+
+	// pricedAmount is an amount with a currency symbol or code on either side:
+	// $9.99, £9.99, €12,00, 12,00 €, USD 9.99, 9.99 EUR
+	pricedAmount = `(?:(?:` + currencySymbol + `|` + currencyCode + `)\s*` + priceAmount +
+		`|` + priceAmount + `\s*(?:` + currencySymbol + `|` + currencyCode + `))`
+
+	// perMonth matches "/month", "per month", "a month", "/mo" and "pro Monat"
+	perMonth = `\s*(?:per|/|a|pro|im)\s*(?:month|mo|monat)`
+)
+
+var (
+	codeRegex   = regexp.MustCompile(`(?i)` + currencyCode)
+	amountRegex = regexp.MustCompile(priceAmount)
+)
+
+// ParsePrice reads the amount and currency from a matched price like "£9.99" or "12,00 €"
+// Both decimal points and decimal commas are understood; a price without a
+// currency is assumed to be DefaultCurrency.
+func ParsePrice(s string) (PriceInfo, bool) {
+	raw := amountRegex.FindString(s)
+	if raw == "" {
+		return PriceInfo{}, false
+	}
+
+	amount, err := parseAmount(raw)
+	if err != nil || amount <= 0 {
+		return PriceInfo{}, false
+	}
+
+	return PriceInfo{Amount: amount, Currency: parseCurrency(s), Period: "monthly"}, true
+}
+
+// parseCurrency returns the ISO code for the symbol or code in s
+func parseCurrency(s string) string {
+	if code := codeRegex.FindString(s); code != "" {
+		return strings.ToUpper(code)
+	}
+	for _, cs := range currencySymbols {
+		if strings.Contains(s, cs.symbol) {
+			return cs.code
+		}
+	}
+	return DefaultCurrency
+}
+
+// parseAmount parses "9.99", "12,00", "1,299.00" and "1.234,56"
+// The last separator is the decimal mark when one or two digits follow it;
+// every other separator groups thousands.
+func parseAmount(raw string) (float64, error) {
+	decimals := ""
+	if i := strings.LastIndexAny(raw, ".,"); i >= 0 && len(raw)-i-1 <= 2 {
+		raw, decimals = raw[:i], raw[i+1:]
+	}
+
+	raw = strings.NewReplacer(".", "", ",", "").Replace(raw)
+	if decimals != "" {
+		raw += "." + decimals
+	}
+
+	return strconv.ParseFloat(raw, 64)
+}
+
+// FormatPrice renders an amount with its currency symbol, e.g. "$9.99" or "€12.00"
+// Currencies without a known symbol are shown by code ("CHF 15.00").
+func FormatPrice(amount float64, currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	for _, cs := range currencySymbols {
+		// "US$" is only recognized when parsing; USD is shown as "$"
+		if cs.code != currency || cs.symbol == "US$" {
+			continue
+		}
+		return fmt.Sprintf("%s%.2f", cs.symbol, amount)
+	}
+
+	return fmt.Sprintf("%s %.2f", currency, amount)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package accounts
+
+import (
+	"testing"
+	"time"
+)
+
+// TestExtractPrice tests that prices keep their currency
+func TestExtractPrice(t *testing.T) {
+	trialPrice := GetDefaultPatterns()[0].PriceRegex
+
+	tests := []struct {
+		name         string
+		text         string
+		wantAmount   float64
+		wantCurrency string
+		wantOK       bool
+	}{
+		{"Dollars", "then $10.99/month after your trial", 10.99, "USD", true},
+		{"Pounds", "then £9.99/month after your trial", 9.99, "GBP", true},
+		{"Euros with decimal comma", "danach €12,00 pro Monat", 12.00, "EUR", true},
+		{"Euro symbol after amount", "danach 12,00 € pro Monat", 12.00, "EUR", true},
+		{"ISO code", "then 15.00 CHF per month", 15.00, "CHF", true},
+		{"Code before amount", "Total: EUR 1.234,56", 1234.56, "EUR", true},
+		{"Thousands separator", "Amount: $1,299.00", 1299.00, "USD", true},
+		{"Bare amount assumes default", "Price: 7.50", 7.50, DefaultCurrency, true},
+		{"No price", "Your trial has started", 0, "", false},
+	}
+
+	d := NewDetector(0.7, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := d.extractPrice(tt.text, trialPrice)
+			if ok != tt.wantOK {
+				t.Fatalf("extractPrice(%q) ok = %v, want %v", tt.text, ok, tt.wantOK)
+			}
+			if price.Amount != tt.wantAmount || price.Currency != tt.wantCurrency {
+				t.Errorf("extractPrice(%q) = %.2f %s, want %.2f %s", tt.text, price.Amount, price.Currency, tt.wantAmount, tt.wantCurrency)
+			}
+		})
+	}
+}
+
+// TestDetectAccountCurrency tests that a detected account carries its price currency
+func TestDetectAccountCurrency(t *testing.T) {
+	ctx := DetectionContext{
+		Subject:      "Your free trial has started",
+		Body:         "Welcome to Spotify Premium free trial. You'll be charged £9.99/month after your trial ends on 03/31/2025.",
+		Sender:       "no-reply@spotify.com",
+		ReceivedDate: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		MessageID:    "trial-gbp",
+	}
+
+	result, err := NewDetector(0.7, nil).DetectAccount(ctx)
+	if err != nil {
+		t.Fatalf("DetectAccount() error = %v", err)
+	}
+	if result == nil {
+		t.Fatal("DetectAccount() = nil, want a trial account")
+	}
+	if result.PriceMonthly != 9.99 || result.Currency != "GBP" {
+		t.Errorf("price = %.2f %s, want 9.99 GBP", result.PriceMonthly, result.Currency)
+	}
+}
+
+// TestFormatPrice tests currency display
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		{9.99, "USD", "$9.99"},
+		{12, "eur", "€12.00"},
+		{9.99, "GBP", "£9.99"},
+		{15, "CHF", "CHF 15.00"},
+		{5, "", "$5.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatPrice(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("FormatPrice(%g, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
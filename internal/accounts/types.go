@@ -14,6 +14,7 @@ type DetectionResult struct {
 	EmailAddress   string     // Email address used for the account
 	AccountType    string     // "trial", "paid", "free"
 	PriceMonthly   float64    // Monthly price (normalized)
+	Currency       string     // ISO 4217 code of PriceMonthly (e.g., "USD", "EUR")
 	TrialEndDate   *time.Time // When trial expires (if applicable)
 	CancelURL      string     // Cancellation URL (if detected)
 	Category       string     // Service category (streaming, software, cloud, productivity)
@@ -47,6 +48,7 @@ type DetectionContext struct {
 // AccountConfig represents the configuration for account detection
 type AccountConfig struct {
 	Enabled            bool          // Enable/disable account detection
+	DetectTypes        []string      // Account types to detect (empty = all)
 	MinConfidence      float64       // Minimum confidence threshold (0.0 to 1.0)
 	TrialAlerts        []TrialAlert  // Trial expiration alerts configuration
 	Categories         map[string][]string // Service categories
@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// checkTimeout bounds a provider health check; it only fetches model metadata
+const checkTimeout = 15 * time.Second
+
+// Check verifies the API key and model by fetching the model's metadata
+// No tokens are used.
+func (p *ClaudeProvider) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.anthropic.com/v1/models/"+url.PathEscape(p.model), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	_, err = doCheckRequest(req)
+	return err
+}
+
+// Check verifies the API key and model by fetching the model's metadata
+// No tokens are used.
+func (p *OpenAIProvider) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.openai.com/v1/models/"+url.PathEscape(p.model), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	_, err = doCheckRequest(req)
+	return err
+}
+
+// Check verifies the API key and model by fetching the model's metadata
+// No tokens are used.
+func (p *GeminiProvider) Check(ctx context.Context) error {
+	endpoint := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s?key=%s",
+		url.PathEscape(p.model), url.QueryEscape(p.apiKey))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	_, err = doCheckRequest(req)
+	return err
+}
+
+// Check verifies the Ollama server is reachable and the model has been pulled
+func (p *OllamaProvider) Check(ctx context.Context) error {
+	tagsURL := ollamaTagsURL(p.endpoint)
+	req, err := http.NewRequestWithContext(ctx, "GET", tagsURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, err := doCheckRequest(req)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			return err
+		}
+		return fmt.Errorf("%w (is Ollama running at %s?)", err, p.endpoint)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	for _, m := range tags.Models {
+		if ollamaModelMatches(m.Name, p.model) {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %q not found on the Ollama server (run: ollama pull %s)", p.model, p.model)
+}
+
+// doCheckRequest sends a health-check request and returns the body of a 200 response
+// Any other status becomes an APIError with the body sanitized
+func doCheckRequest(req *http.Request) ([]byte, error) {
+	client := &http.Client{Timeout: checkTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		// Drop the URL from the error; the Gemini key is in its query string
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: sanitizeAPIError(string(body))}
+	}
+	return body, nil
+}
+
+// ollamaTagsURL returns the model-list URL of the Ollama server behind a generate endpoint
+func ollamaTagsURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(endpoint, "/api/generate") + "/api/tags"
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/generate") + "/api/tags"
+	u.RawQuery = ""
+	return u.String()
+}
+
+// ollamaModelMatches reports whether an installed model name satisfies the configured one
+// "llama3.2" matches "llama3.2:latest", since Ollama adds the tag when none is given
+func ollamaModelMatches(installed, configured string) bool {
+	if installed == configured {
+		return true
+	}
+	return !strings.Contains(configured, ":") && installed == configured+":latest"
+}
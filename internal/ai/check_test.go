@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOllamaTagsURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{DefaultOllamaEndpoint, "http://localhost:11434/api/tags"},
+		{"http://gpu-box:11434/api/generate/", "http://gpu-box:11434/api/tags"},
+		{"https://ollama.example.com/proxy/api/generate", "https://ollama.example.com/proxy/api/tags"},
+		{"http://gpu-box:11434", "http://gpu-box:11434/api/tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			if got := ollamaTagsURL(tt.endpoint); got != tt.want {
+				t.Errorf("ollamaTagsURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOllamaCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      string
+		status     int
+		body       string
+		wantErr    string
+		wantStatus int
+	}{
+		{"exact match", "llama3.2:3b", http.StatusOK, `{"models":[{"name":"llama3.2:3b"}]}`, "", 0},
+		{"implicit latest tag", "llama3.2", http.StatusOK, `{"models":[{"name":"llama3.2:latest"}]}`, "", 0},
+		{"model not pulled", "mistral", http.StatusOK, `{"models":[{"name":"llama3.2:latest"}]}`, "ollama pull mistral", 0},
+		{"server error", "llama3.2", http.StatusInternalServerError, `{"error":"boom"}`, "boom", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/tags" {
+					t.Errorf("request path = %q, want /api/tags", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			p := &OllamaProvider{endpoint: srv.URL + "/api/generate", model: tt.model}
+			err := p.Check(context.Background())
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Check() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+
+			var apiErr *APIError
+			if tt.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus) {
+				t.Errorf("Check() error = %v, want APIError with status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -18,7 +18,7 @@ type Config struct {
 // AISummaryConfig holds all AI summarization settings
 type AISummaryConfig struct {
 	Enabled  bool              `yaml:"enabled"`
-	Provider string            `yaml:"provider"` // "claude", "openai", "gemini"
+	Provider string            `yaml:"provider"` // "claude", "openai", "gemini", "ollama"
 	API      APIConfig         `yaml:"api"`
 	Behavior BehaviorConfig    `yaml:"behavior"`
 	RateLimit RateLimitConfig  `yaml:"rate_limit"`
@@ -30,6 +30,7 @@ type APIConfig struct {
 	Claude ClaudeConfig `yaml:"claude"`
 	OpenAI OpenAIConfig `yaml:"openai"`
 	Gemini GeminiConfig `yaml:"gemini"`
+	Ollama OllamaConfig `yaml:"ollama"`
 }
 
 // ClaudeConfig holds Claude (Anthropic) API settings
@@ -56,27 +57,39 @@ type GeminiConfig struct {
 	Temperature float64 `yaml:"temperature"`
 }
 
+// OllamaConfig holds settings for a local Ollama server (no API key needed)
+type OllamaConfig struct {
+	Endpoint    string  `yaml:"endpoint"` // defaults to http://localhost:11434/api/generate
+	Model       string  `yaml:"model"`
+	MaxTokens   int     `yaml:"max_tokens"`
+	Temperature float64 `yaml:"temperature"`
+}
+
 // BehaviorConfig controls summary generation behavior
 type BehaviorConfig struct {
-	MaxSummaryLength       int  `yaml:"max_summary_length"`
-	PriorityOnly           bool `yaml:"priority_only"`
-	EnableCache            bool `yaml:"enable_cache"`
-	TimeoutSeconds         int  `yaml:"timeout_seconds"`
-	RetryAttempts          int  `yaml:"retry_attempts"`
-	IncludeInNotifications bool `yaml:"include_in_notifications"`
-	ShowAIIcon             bool `yaml:"show_ai_icon"`
+	MaxSummaryLength       int    `yaml:"max_summary_length"`
+	PriorityOnly           bool   `yaml:"priority_only"`
+	EnableCache            bool   `yaml:"enable_cache"`
+	CacheTTL               string `yaml:"cache_ttl"` // e.g. "24h"; older content-hash matches are ignored ("" = no expiry)
+	TimeoutSeconds         int    `yaml:"timeout_seconds"`
+	RetryAttempts          int    `yaml:"retry_attempts"`
+	IncludeInNotifications bool   `yaml:"include_in_notifications"`
+	ShowAIIcon             bool   `yaml:"show_ai_icon"`
 }
 
 // RateLimitConfig controls API usage limits
 type RateLimitConfig struct {
-	MaxPerHour int `yaml:"max_per_hour"`
-	MaxPerDay  int `yaml:"max_per_day"`
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	MaxPerHour        int `yaml:"max_per_hour"`
+	MaxPerDay         int `yaml:"max_per_day"`
+	DailyTokenBudget  int `yaml:"daily_token_budget"` // tokens per day for this provider (0 = unlimited)
 }
 
 // PromptConfig holds customizable prompts
 type PromptConfig struct {
-	System       string `yaml:"system"`
-	UserTemplate string `yaml:"user_template"`
+	System       string            `yaml:"system"`
+	UserTemplate string            `yaml:"user_template"`
+	Templates    map[string]string `yaml:"templates"` // category -> extra instructions (see SelectTemplate)
 }
 
 // LoadConfig loads AI configuration from ai-config.yaml
@@ -114,6 +127,21 @@ func LoadConfig() (*Config, error) {
 	return &cfg, nil
 }
 
+// APIKeyEnvVar returns the environment variable holding the API key for a provider
+// Returns "" for providers that don't need a key (e.g. ollama)
+func APIKeyEnvVar(provider string) string {
+	switch strings.ToLower(provider) {
+	case "claude":
+		return "ANTHROPIC_API_KEY"
+	case "openai":
+		return "OPENAI_API_KEY"
+	case "gemini":
+		return "GEMINI_API_KEY"
+	default:
+		return ""
+	}
+}
+
 // loadAPIKeysFromEnv loads API keys from environment variables
 func (c *Config) loadAPIKeysFromEnv() {
 	// Claude API key
@@ -145,8 +173,16 @@ func (c *Config) Validate() error {
 	}
 
 	provider := strings.ToLower(c.AISummary.Provider)
-	if provider != "claude" && provider != "openai" && provider != "gemini" {
-		return fmt.Errorf("invalid provider: %s (must be: claude, openai, or gemini)", c.AISummary.Provider)
+	if provider != "claude" && provider != "openai" && provider != "gemini" && provider != "ollama" {
+		return fmt.Errorf("invalid provider: %s (must be: claude, openai, gemini, or ollama)", c.AISummary.Provider)
+	}
+
+	// Local models don't need an API key
+	if provider == "ollama" {
+		if c.AISummary.API.Ollama.Model == "" {
+			return fmt.Errorf("ollama model not specified")
+		}
+		return nil
 	}
 
 	// Check if API key is set for the selected provider
@@ -170,7 +206,7 @@ func (c *Config) Validate() error {
 	}
 
 	if apiKey == "" {
-		return fmt.Errorf("API key not set for provider: %s", provider)
+		return fmt.Errorf("API key not set for provider: %s (set %s)", provider, APIKeyEnvVar(provider))
 	}
 
 	return nil
@@ -185,6 +221,8 @@ func (c *Config) GetProviderConfig() interface{} {
 		return c.AISummary.API.OpenAI
 	case "gemini":
 		return c.AISummary.API.Gemini
+	case "ollama":
+		return c.AISummary.API.Ollama
 	default:
 		return nil
 	}
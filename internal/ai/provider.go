@@ -4,14 +4,46 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"regexp"
 	"strings"
 	"time"
 )
 
+// APIError is a non-200 response from an AI provider
+type APIError struct {
+	StatusCode int
+	Message    string // Sanitized response body
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// isRetryableError reports whether a provider error is worth retrying
+// Rate limits (429), server errors (5xx) and network timeouts are transient
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
 // sanitizeAPIError removes potential API keys and sensitive data from error messages
 // This prevents accidental exposure of credentials in logs or terminal output
 func sanitizeAPIError(errorBody string) string {
@@ -44,6 +76,8 @@ func sanitizeAPIError(errorBody string) string {
 // Provider defines the interface for AI providers
 type Provider interface {
 	GenerateSummary(ctx context.Context, req SummaryRequest) (*SummaryResponse, int, error)
+	// Check makes a minimal call to confirm the credentials and model work
+	Check(ctx context.Context) error
 	Name() string
 }
 
@@ -79,6 +113,19 @@ func NewProvider(cfg *Config) (Provider, error) {
 			prompt:      cfg.AISummary.Prompt,
 		}, nil
 
+	case "ollama":
+		endpoint := cfg.AISummary.API.Ollama.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultOllamaEndpoint
+		}
+		return &OllamaProvider{
+			endpoint:    endpoint,
+			model:       cfg.AISummary.API.Ollama.Model,
+			maxTokens:   cfg.AISummary.API.Ollama.MaxTokens,
+			temperature: cfg.AISummary.API.Ollama.Temperature,
+			prompt:      cfg.AISummary.Prompt,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
@@ -145,7 +192,7 @@ func (p *ClaudeProvider) GenerateSummary(ctx context.Context, req SummaryRequest
 
 	if resp.StatusCode != http.StatusOK {
 		sanitized := sanitizeAPIError(string(bodyBytes))
-		return nil, 0, fmt.Errorf("API error (status %d): %s", resp.StatusCode, sanitized)
+		return nil, 0, &APIError{StatusCode: resp.StatusCode, Message: sanitized}
 	}
 
 	// Parse response
@@ -190,7 +237,7 @@ func (p *ClaudeProvider) buildPrompt(req SummaryRequest) string {
 	}
 	template = strings.ReplaceAll(template, "{{.Body}}", body)
 
-	return template
+	return appendGuidance(template, req.Guidance)
 }
 
 // ====================================
@@ -250,7 +297,7 @@ func (p *OpenAIProvider) GenerateSummary(ctx context.Context, req SummaryRequest
 
 	if resp.StatusCode != http.StatusOK {
 		sanitized := sanitizeAPIError(string(bodyBytes))
-		return nil, 0, fmt.Errorf("API error (status %d): %s", resp.StatusCode, sanitized)
+		return nil, 0, &APIError{StatusCode: resp.StatusCode, Message: sanitized}
 	}
 
 	var openaiResp struct {
@@ -292,7 +339,7 @@ func (p *OpenAIProvider) buildPrompt(req SummaryRequest) string {
 	}
 	template = strings.ReplaceAll(template, "{{.Body}}", body)
 
-	return template
+	return appendGuidance(template, req.Guidance)
 }
 
 // ====================================
@@ -359,7 +406,7 @@ func (p *GeminiProvider) GenerateSummary(ctx context.Context, req SummaryRequest
 
 	if resp.StatusCode != http.StatusOK {
 		sanitized := sanitizeAPIError(string(bodyBytes))
-		return nil, 0, fmt.Errorf("API error (status %d): %s", resp.StatusCode, sanitized)
+		return nil, 0, &APIError{StatusCode: resp.StatusCode, Message: sanitized}
 	}
 
 	var geminiResp struct {
@@ -403,5 +450,190 @@ func (p *GeminiProvider) buildPrompt(req SummaryRequest) string {
 	}
 	template = strings.ReplaceAll(template, "{{.Body}}", body)
 
-	return template
+	return appendGuidance(template, req.Guidance)
+}
+
+// ====================================
+// Ollama (Local LLM) Provider
+// ====================================
+
+// DefaultOllamaEndpoint is the generate API of a local Ollama server
+const DefaultOllamaEndpoint = "http://localhost:11434/api/generate"
+
+type OllamaProvider struct {
+	endpoint    string
+	model       string
+	maxTokens   int
+	temperature float64
+	prompt      PromptConfig
+}
+
+func (p *OllamaProvider) Name() string {
+	return "ollama"
+}
+
+func (p *OllamaProvider) GenerateSummary(ctx context.Context, req SummaryRequest) (*SummaryResponse, int, error) {
+	userPrompt := p.buildPrompt(req)
+
+	options := map[string]interface{}{
+		"temperature": p.temperature,
+	}
+	if p.maxTokens > 0 {
+		options["num_predict"] = p.maxTokens
+	}
+
+	payload := map[string]interface{}{
+		"model":   p.model,
+		"system":  p.prompt.System,
+		"prompt":  userPrompt,
+		"stream":  false,
+		"format":  "json",
+		"options": options,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Local models can be slow on CPU-only machines
+	client := &http.Client{Timeout: 120 * time.Second}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, 0, fmt.Errorf("API request failed (is Ollama running at %s?): %w", p.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		sanitized := sanitizeAPIError(string(bodyBytes))
+		return nil, 0, &APIError{StatusCode: resp.StatusCode, Message: sanitized}
+	}
+
+	text, tokens, err := parseOllamaResponse(bodyBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Local models often wrap the JSON in prose or code fences
+	jsonText, ok := extractJSONObject(text)
+	if !ok {
+		return nil, 0, fmt.Errorf("no JSON object in response")
+	}
+
+	var summary SummaryResponse
+	if err := json.Unmarshal([]byte(jsonText), &summary); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse summary JSON: %w", err)
+	}
+
+	return &summary, tokens, nil
+}
+
+func (p *OllamaProvider) buildPrompt(req SummaryRequest) string {
+	template := p.prompt.UserTemplate
+	template = strings.ReplaceAll(template, "{{.MaxLength}}", fmt.Sprintf("%d", req.MaxLength))
+	template = strings.ReplaceAll(template, "{{.Sender}}", req.Sender)
+	template = strings.ReplaceAll(template, "{{.Subject}}", req.Subject)
+
+	body := req.Body
+	if body == "" {
+		body = req.Snippet
+	}
+	template = strings.ReplaceAll(template, "{{.Body}}", body)
+
+	return appendGuidance(template, req.Guidance)
+}
+
+// parseOllamaResponse returns the generated text and token count from an Ollama reply
+// Handles both a single JSON object (stream: false) and newline-delimited stream chunks
+func parseOllamaResponse(body []byte) (string, int, error) {
+	type chunk struct {
+		Response        string `json:"response"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
+		Error           string `json:"error"`
+	}
+
+	var text strings.Builder
+	tokens := 0
+	parsed := 0
+
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var c chunk
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return "", 0, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if c.Error != "" {
+			return "", 0, fmt.Errorf("ollama error: %s", c.Error)
+		}
+
+		text.WriteString(c.Response)
+		tokens += c.PromptEvalCount + c.EvalCount
+		parsed++
+	}
+
+	if parsed == 0 || strings.TrimSpace(text.String()) == "" {
+		return "", 0, fmt.Errorf("no content in response")
+	}
+
+	return text.String(), tokens, nil
+}
+
+// extractJSONObject returns the first balanced {...} block in text
+// Braces inside JSON strings are ignored
+func extractJSONObject(text string) (string, bool) {
+	start := strings.Index(text, "{")
+	if start < 0 {
+		return "", false
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := start; i < len(text); i++ {
+		ch := text[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return text[start : i+1], true
+			}
+		}
+	}
+
+	return "", false
 }
@@ -0,0 +1,68 @@
+package ai
+
+import "testing"
+
+func TestExtractJSONObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"plain object", `{"summary":"hi"}`, `{"summary":"hi"}`, true},
+		{"wrapped in prose", "Here you go:\n```json\n{\"summary\":\"hi\"}\n```\nDone.", `{"summary":"hi"}`, true},
+		{"nested object", `x {"a":{"b":1},"c":2} y {"d":3}`, `{"a":{"b":1},"c":2}`, true},
+		{"braces inside strings", `{"summary":"use } and { freely","q":"\"}"}`, `{"summary":"use } and { freely","q":"\"}"}`, true},
+		{"no object", "no json here", "", false},
+		{"unterminated", `{"summary":"hi"`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := extractJSONObject(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("extractJSONObject() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseOllamaResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantText   string
+		wantTokens int
+		wantErr    bool
+	}{
+		{
+			name:       "single response",
+			body:       `{"response":"{\"summary\":\"hi\"}","done":true,"prompt_eval_count":10,"eval_count":5}`,
+			wantText:   `{"summary":"hi"}`,
+			wantTokens: 15,
+		},
+		{
+			name:       "streamed chunks",
+			body:       "{\"response\":\"{\\\"summ\",\"done\":false}\n{\"response\":\"ary\\\":1}\",\"done\":true,\"prompt_eval_count\":3,\"eval_count\":4}\n",
+			wantText:   `{"summary":1}`,
+			wantTokens: 7,
+		},
+		{name: "error reply", body: `{"error":"model not found"}`, wantErr: true},
+		{name: "empty", body: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, tokens, err := parseOllamaResponse([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOllamaResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if text != tt.wantText || tokens != tt.wantTokens {
+				t.Errorf("parseOllamaResponse() = %q, %d; want %q, %d", text, tokens, tt.wantText, tt.wantTokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter enforces provider request budgets
+// Requests per minute use a token bucket (bursts up to the per-minute limit, then
+// refills continuously); hourly and daily limits are simple fixed-window counters.
+type RateLimiter struct {
+	tokens      float64
+	lastRefill  time.Time
+	hourlyCount int
+	dailyCount  int
+	hourReset   time.Time
+	dayReset    time.Time
+	mu          sync.Mutex
+}
+
+// Usage is a snapshot of rate limit consumption (limits of 0 mean unlimited)
+type Usage struct {
+	MinuteLimit     int
+	MinuteRemaining int
+	HourlyUsed      int
+	HourlyLimit     int
+	DailyUsed       int
+	DailyLimit      int
+	DayResetsAt     time.Time
+}
+
+// DailyRemaining returns how many requests are left today (-1 = unlimited)
+func (u Usage) DailyRemaining() int {
+	if u.DailyLimit <= 0 {
+		return -1
+	}
+	if remaining := u.DailyLimit - u.DailyUsed; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// NewRateLimiter creates a rate limiter starting with a full bucket
+func NewRateLimiter(limits RateLimitConfig, now time.Time) *RateLimiter {
+	return &RateLimiter{
+		tokens:     float64(limits.RequestsPerMinute),
+		lastRefill: now,
+		hourReset:  now.Add(1 * time.Hour),
+		dayReset:   now.Add(24 * time.Hour),
+	}
+}
+
+// Allow reports whether a request may be made now and, if so, consumes budget for it
+func (rl *RateLimiter) Allow(limits RateLimitConfig, now time.Time) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.advance(limits, now)
+
+	// Check limits (0 means unlimited)
+	if limits.RequestsPerMinute > 0 && rl.tokens < 1 {
+		return false
+	}
+	if limits.MaxPerHour > 0 && rl.hourlyCount >= limits.MaxPerHour {
+		return false
+	}
+	if limits.MaxPerDay > 0 && rl.dailyCount >= limits.MaxPerDay {
+		return false
+	}
+
+	if limits.RequestsPerMinute > 0 {
+		rl.tokens--
+	}
+	rl.hourlyCount++
+	rl.dailyCount++
+
+	return true
+}
+
+// Usage returns the current consumption against limits
+func (rl *RateLimiter) Usage(limits RateLimitConfig, now time.Time) Usage {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.advance(limits, now)
+
+	return Usage{
+		MinuteLimit:     limits.RequestsPerMinute,
+		MinuteRemaining: int(rl.tokens),
+		HourlyUsed:      rl.hourlyCount,
+		HourlyLimit:     limits.MaxPerHour,
+		DailyUsed:       rl.dailyCount,
+		DailyLimit:      limits.MaxPerDay,
+		DayResetsAt:     rl.dayReset,
+	}
+}
+
+// advance refills the token bucket and resets expired windows
+// Caller must hold rl.mu
+func (rl *RateLimiter) advance(limits RateLimitConfig, now time.Time) {
+	if limits.RequestsPerMinute > 0 {
+		capacity := float64(limits.RequestsPerMinute)
+		if elapsed := now.Sub(rl.lastRefill); elapsed > 0 {
+			rl.tokens += elapsed.Minutes() * capacity
+		}
+		if rl.tokens > capacity {
+			rl.tokens = capacity
+		}
+	}
+	rl.lastRefill = now
+
+	if now.After(rl.hourReset) {
+		rl.hourlyCount = 0
+		rl.hourReset = now.Add(1 * time.Hour)
+	}
+	if now.After(rl.dayReset) {
+		rl.dailyCount = 0
+		rl.dayReset = now.Add(24 * time.Hour)
+	}
+}
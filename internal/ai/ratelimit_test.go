@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	limits := RateLimitConfig{RequestsPerMinute: 3}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(limits, now)
+
+	// Full bucket allows a burst up to the per-minute limit
+	for i := 0; i < 3; i++ {
+		if !rl.Allow(limits, now) {
+			t.Fatalf("Allow() request %d = false, want true", i+1)
+		}
+	}
+	if rl.Allow(limits, now) {
+		t.Error("Allow() after burst = true, want false")
+	}
+
+	// One token refills every 20s at 3/minute
+	if rl.Allow(limits, now.Add(10*time.Second)) {
+		t.Error("Allow() after 10s = true, want false")
+	}
+	if !rl.Allow(limits, now.Add(21*time.Second)) {
+		t.Error("Allow() after 21s = false, want true")
+	}
+
+	// Bucket never exceeds capacity
+	usage := rl.Usage(limits, now.Add(time.Hour))
+	if usage.MinuteRemaining != 3 {
+		t.Errorf("MinuteRemaining after idle hour = %d, want 3", usage.MinuteRemaining)
+	}
+}
+
+func TestRateLimiter_DailyLimit(t *testing.T) {
+	limits := RateLimitConfig{MaxPerDay: 2}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(limits, now)
+
+	rl.Allow(limits, now)
+	rl.Allow(limits, now)
+	if rl.Allow(limits, now.Add(time.Hour)) {
+		t.Error("Allow() past daily limit = true, want false")
+	}
+
+	usage := rl.Usage(limits, now.Add(time.Hour))
+	if usage.DailyUsed != 2 || usage.DailyRemaining() != 0 {
+		t.Errorf("Usage() = %+v, want 2 used and 0 remaining", usage)
+	}
+
+	// Window resets after 24 hours
+	if !rl.Allow(limits, now.Add(25*time.Hour)) {
+		t.Error("Allow() after daily reset = false, want true")
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Nil", err: nil, expected: false},
+		{name: "Rate limited", err: &APIError{StatusCode: 429}, expected: true},
+		{name: "Server error", err: &APIError{StatusCode: 503}, expected: true},
+		{name: "Wrapped server error", err: fmt.Errorf("request: %w", &APIError{StatusCode: 500}), expected: true},
+		{name: "Bad request", err: &APIError{StatusCode: 400}, expected: false},
+		{name: "Unauthorized", err: &APIError{StatusCode: 401}, expected: false},
+		{name: "Context deadline", err: context.DeadlineExceeded, expected: true},
+		{name: "Parse error", err: fmt.Errorf("failed to parse response"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.expected {
+				t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package ai
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
-	"log"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
+	"github.com/datateamsix/email-sentinel/internal/log"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
@@ -20,15 +24,6 @@ type Service struct {
 	mu          sync.Mutex
 }
 
-// RateLimiter tracks API usage to enforce rate limits
-type RateLimiter struct {
-	hourlyCount int
-	dailyCount  int
-	hourReset   time.Time
-	dayReset    time.Time
-	mu          sync.Mutex
-}
-
 // NewService creates a new AI summary service
 func NewService(cfg *Config, db *sql.DB) (*Service, error) {
 	if !cfg.AISummary.Enabled {
@@ -40,26 +35,24 @@ func NewService(cfg *Config, db *sql.DB) (*Service, error) {
 		return nil, fmt.Errorf("failed to create provider: %w", err)
 	}
 
-	now := time.Now()
 	return &Service{
-		provider: provider,
-		config:   cfg,
-		db:       db,
-		rateLimiter: &RateLimiter{
-			hourReset: now.Add(1 * time.Hour),
-			dayReset:  now.Add(24 * time.Hour),
-		},
+		provider:    provider,
+		config:      cfg,
+		db:          db,
+		rateLimiter: NewRateLimiter(cfg.AISummary.RateLimit, time.Now()),
 	}, nil
 }
 
 // GenerateSummary generates an AI summary for an email
-// Returns cached summary if available, otherwise calls the AI provider
-func (s *Service) GenerateSummary(messageID, sender, subject, body, snippet string, priority int) (*storage.EmailSummary, error) {
+// Returns cached summary if available, otherwise calls the AI provider.
+// labels are the matched filters' labels, used to pick a prompt template.
+// Cancelling ctx aborts the provider request and any retry backoff.
+func (s *Service) GenerateSummary(ctx context.Context, messageID, sender, subject, body, snippet string, labels []string, priority int) (*storage.EmailSummary, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// Check if we should skip based on priority
-	if s.config.AISummary.Behavior.PriorityOnly && priority != 1 {
+	if !s.ShouldSummarize(priority) {
 		return nil, nil // Skip non-priority emails
 	}
 
@@ -67,20 +60,37 @@ func (s *Service) GenerateSummary(messageID, sender, subject, body, snippet stri
 	if s.config.AISummary.Behavior.EnableCache {
 		cached, err := storage.GetAISummaryByMessageID(s.db, messageID)
 		if err != nil {
-			log.Printf("⚠️  Error checking cache: %v", err)
+			log.Warn("Error checking AI summary cache", "error", err)
 		} else if cached != nil {
-			log.Printf("🤖 Using cached AI summary for message %s", messageID)
+			log.Info("Using cached AI summary", log.Icon("🤖"), "message_id", messageID)
 			return cached, nil
 		}
 	}
 
-	// Check rate limits
-	if !s.rateLimiter.CanProceed(s.config.AISummary.RateLimit) {
-		return nil, fmt.Errorf("rate limit exceeded")
+	// Identical content (e.g. repeated notification emails) can reuse an earlier summary
+	contentHash := ContentHash(sender, subject, body)
+	if s.config.AISummary.Behavior.EnableCache {
+		if cached := s.lookupContentCache(contentHash); cached != nil {
+			log.Info("Using cached AI summary for identical content", append([]any{log.Icon("🤖")}, log.Email(sender, subject)...)...)
+			return s.saveCachedCopy(cached, messageID), nil
+		}
+	}
+
+	if used, budget, exhausted := s.tokenBudgetExhausted(time.Now()); exhausted {
+		log.Warn("Daily AI token budget exhausted, skipping summary", log.Icon("💸"), "used", used, "budget", budget, "message_id", messageID)
+		return nil, nil
+	}
+
+	// Check rate limits - skip rather than queue so a burst of matches can't
+	// exhaust the provider quota
+	limits := s.config.AISummary.RateLimit
+	if !s.rateLimiter.Allow(limits, time.Now()) {
+		log.Warn("AI rate limit reached, skipping summary", append([]any{log.Icon("⏳")}, log.Email(sender, subject)...)...)
+		return nil, nil
 	}
 
 	// Generate summary
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.config.AISummary.Behavior.TimeoutSeconds)*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.AISummary.Behavior.TimeoutSeconds)*time.Second)
 	defer cancel()
 
 	req := SummaryRequest{
@@ -91,34 +101,51 @@ func (s *Service) GenerateSummary(messageID, sender, subject, body, snippet stri
 		MaxLength: s.config.AISummary.Behavior.MaxSummaryLength,
 	}
 
-	log.Printf("🤖 Generating AI summary for: %s", subject)
+	// Category-specific guidance, e.g. the "meeting" template for calendar alerts
+	templates := s.config.AISummary.Prompt.Templates
+	if key := SelectTemplate(templates, labels, subject, body); key != "" {
+		req.Guidance = templates[key]
+		log.Debug("Using prompt template", log.Icon("🤖"), "template", key)
+	}
+
+	log.Info("Generating AI summary", append([]any{log.Icon("🤖")}, log.Email(sender, subject)...)...)
 
 	var resp *SummaryResponse
 	var tokens int
 	var err error
 
-	// Retry logic
+	// Retry transient errors (429, 5xx, timeouts) with exponential backoff: 1s, 2s, 4s...
 	maxRetries := s.config.AISummary.Behavior.RetryAttempts
+	attempts := 0
 	for attempt := 0; attempt <= maxRetries; attempt++ {
+		attempts++
 		resp, tokens, err = s.provider.GenerateSummary(ctx, req)
-		if err == nil {
+		if err == nil || !isRetryableError(err) || attempt == maxRetries {
 			break
 		}
 
-		if attempt < maxRetries {
-			log.Printf("⚠️  AI API error (attempt %d/%d): %v", attempt+1, maxRetries+1, err)
-			time.Sleep(time.Duration(attempt+1) * time.Second) // Exponential backoff
+		backoff := time.Duration(1<<uint(attempt)) * time.Second
+		log.Warn("AI API error, retrying", "attempt", attempt+1, "attempts", maxRetries+1, "backoff", backoff, "error", err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out after %d attempts: %w", attempts, err)
+		}
+
+		// Each retry is another request against the provider quota
+		if !s.rateLimiter.Allow(limits, time.Now()) {
+			log.Warn("AI rate limit reached, skipping summary", append([]any{log.Icon("⏳")}, log.Email(sender, subject)...)...)
+			return nil, nil
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed after %d attempts: %w", maxRetries+1, err)
+		return nil, fmt.Errorf("failed after %d attempts: %w", attempts, err)
 	}
 
-	// Truncate summary if too long
-	if len(resp.Summary) > s.config.AISummary.Behavior.MaxSummaryLength {
-		resp.Summary = resp.Summary[:s.config.AISummary.Behavior.MaxSummaryLength-3] + "..."
-	}
+	// Providers don't always respect the requested length, so enforce it here
+	resp.Summary = truncateSummary(resp.Summary, s.config.AISummary.Behavior.MaxSummaryLength)
 
 	// Save to database
 	summary := &storage.EmailSummary{
@@ -130,20 +157,91 @@ func (s *Service) GenerateSummary(messageID, sender, subject, body, snippet stri
 		Model:       s.getModelName(),
 		GeneratedAt: time.Now(),
 		TokensUsed:  tokens,
+		ContentHash: contentHash,
 	}
 
 	if err := storage.InsertAISummary(s.db, summary); err != nil {
-		log.Printf("⚠️  Failed to save AI summary: %v", err)
+		log.Warn("Failed to save AI summary", "error", err)
 		// Don't fail - we still return the summary
 	}
 
-	// Update rate limiter
-	s.rateLimiter.Increment()
-
-	log.Printf("✅ AI summary generated (%d tokens)", tokens)
+	log.Info("AI summary generated", log.Icon("✅"), "tokens", tokens)
 	return summary, nil
 }
 
+// tokenBudgetExhausted reports whether the active provider has used up today's token budget
+// Days start at local midnight; a budget of 0 means unlimited
+func (s *Service) tokenBudgetExhausted(now time.Time) (used, budget int, exhausted bool) {
+	budget = s.config.AISummary.RateLimit.DailyTokenBudget
+	if budget <= 0 {
+		return 0, budget, false
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	used, err := storage.GetTokenUsageSince(s.db, s.provider.Name(), midnight)
+	if err != nil {
+		log.Warn("Error checking AI token budget", "error", err)
+		return 0, budget, false
+	}
+
+	return used, budget, used >= budget
+}
+
+// lookupContentCache returns a cached summary for identical content within the cache TTL
+func (s *Service) lookupContentCache(contentHash string) *storage.EmailSummary {
+	var notBefore time.Time
+	if ttl := s.config.AISummary.Behavior.CacheTTL; ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			log.Warn("Invalid AI cache TTL", "ttl", ttl, "error", err)
+		} else if d > 0 {
+			notBefore = time.Now().Add(-d)
+		}
+	}
+
+	cached, err := storage.GetAISummaryByContentHash(s.db, contentHash, notBefore)
+	if err != nil {
+		log.Warn("Error checking AI summary cache", "error", err)
+		return nil
+	}
+	return cached
+}
+
+// saveCachedCopy stores a cached summary under a new message ID so the alert can display it
+// The copy records zero tokens used since no API call was made
+func (s *Service) saveCachedCopy(cached *storage.EmailSummary, messageID string) *storage.EmailSummary {
+	summary := *cached
+	summary.ID = 0
+	summary.MessageID = messageID
+	summary.TokensUsed = 0
+
+	if err := storage.InsertAISummary(s.db, &summary); err != nil {
+		log.Warn("Failed to save AI summary", "error", err)
+	}
+
+	return &summary
+}
+
+// ContentHash returns a SHA-256 hash of the normalized sender, subject and body
+// Case and whitespace differences are ignored so re-sent notifications hash the same
+func ContentHash(sender, subject, body string) string {
+	normalize := func(s string) string {
+		return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	}
+
+	h := sha256.New()
+	for _, part := range []string{sender, subject, body} {
+		h.Write([]byte(normalize(part)))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Usage returns the current rate limit consumption (e.g. for an `ai status` command)
+func (s *Service) Usage() Usage {
+	return s.rateLimiter.Usage(s.config.AISummary.RateLimit, time.Now())
+}
+
 // getModelName returns the model name for the current provider
 func (s *Service) getModelName() string {
 	switch s.provider.Name() {
@@ -153,51 +251,48 @@ func (s *Service) getModelName() string {
 		return s.config.AISummary.API.OpenAI.Model
 	case "gemini":
 		return s.config.AISummary.API.Gemini.Model
+	case "ollama":
+		return s.config.AISummary.API.Ollama.Model
 	default:
 		return "unknown"
 	}
 }
 
-// CanProceed checks if we can make another API call within rate limits
-func (rl *RateLimiter) CanProceed(limits RateLimitConfig) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// ShouldSummarize reports whether an alert with the given priority should get a summary
+func (s *Service) ShouldSummarize(priority int) bool {
+	return ShouldSummarize(s.config.AISummary.Behavior, priority)
+}
 
-	now := time.Now()
+// ShouldSummarize applies the behavior settings to an alert priority
+// In priority-only mode only high (1) and critical (2) alerts are summarized
+func ShouldSummarize(behavior BehaviorConfig, priority int) bool {
+	return !behavior.PriorityOnly || priority >= storage.PriorityHigh
+}
 
-	// Reset counters if time windows have passed
-	if now.After(rl.hourReset) {
-		rl.hourlyCount = 0
-		rl.hourReset = now.Add(1 * time.Hour)
+// truncateSummary shortens a summary to at most maxLen characters
+// It cuts after the last complete sentence that keeps at least half the limit,
+// otherwise at a word boundary with "...", so summaries never stop mid-word.
+func truncateSummary(summary string, maxLen int) string {
+	runes := []rune(summary)
+	if maxLen <= 0 || len(runes) <= maxLen {
+		return summary
 	}
-	if now.After(rl.dayReset) {
-		rl.dailyCount = 0
-		rl.dayReset = now.Add(24 * time.Hour)
+
+	for i := maxLen - 1; i >= maxLen/2; i-- {
+		if strings.ContainsRune(".!?", runes[i]) && unicode.IsSpace(runes[i+1]) {
+			return string(runes[:i+1])
+		}
 	}
 
-	// Check limits (0 means unlimited)
-	if limits.MaxPerHour > 0 && rl.hourlyCount >= limits.MaxPerHour {
-		return false
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
 	}
-	if limits.MaxPerDay > 0 && rl.dailyCount >= limits.MaxPerDay {
-		return false
+	cut := runes[:maxLen-3]
+	for i := len(cut) - 1; i >= len(cut)/2; i-- {
+		if unicode.IsSpace(cut[i]) {
+			cut = cut[:i]
+			break
+		}
 	}
-
-	return true
-}
-
-// Increment increments the rate limit counters
-func (rl *RateLimiter) Increment() {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	rl.hourlyCount++
-	rl.dailyCount++
-}
-
-// GetStats returns current rate limit stats
-func (rl *RateLimiter) GetStats() (hourly, daily int) {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-	return rl.hourlyCount, rl.dailyCount
+	return strings.TrimRight(string(cut), " ,;:-") + "..."
 }
@@ -0,0 +1,193 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+func TestShouldSummarize(t *testing.T) {
+	tests := []struct {
+		name         string
+		priorityOnly bool
+		priority     int
+		want         bool
+	}{
+		{"all alerts, high priority", false, 1, true},
+		{"all alerts, normal priority", false, 0, true},
+		{"priority-only, high priority", true, 1, true},
+		{"priority-only, normal priority", true, 0, false},
+		{"priority-only, critical priority", true, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShouldSummarize(BehaviorConfig{PriorityOnly: tt.priorityOnly}, tt.priority)
+			if got != tt.want {
+				t.Errorf("ShouldSummarize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	base := ContentHash("Service <no-reply@example.com>", "Your trial ends soon", "Your trial ends in 3 days.")
+
+	if got := ContentHash("service <NO-REPLY@example.com>", "Your  trial ends soon ", "Your trial\nends in 3 days."); got != base {
+		t.Errorf("ContentHash() should ignore case and whitespace differences")
+	}
+	if got := ContentHash("Service <no-reply@example.com>", "Your trial ends soon", "Your trial ends in 2 days."); got == base {
+		t.Errorf("ContentHash() should differ when the body differs")
+	}
+	if got := ContentHash("Service <no-reply@example.com>Your trial", " ends soon", "Your trial ends in 3 days."); got == base {
+		t.Errorf("ContentHash() should not let fields run together")
+	}
+}
+
+func TestTruncateSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		maxLen  int
+		want    string
+	}{
+		{"fits", "Short summary.", 50, "Short summary."},
+		{"exact length", "Exactly.", 8, "Exactly."},
+		{"no limit", "Anything goes here.", 0, "Anything goes here."},
+		{"cuts at sentence", "First sentence here. Second one is long.", 30, "First sentence here."},
+		{"keeps last sentence that fits", "One. Two is here. Three goes past the limit.", 25, "One. Two is here."},
+		{"sentence too short falls back to words", "Ok. then a long run of words without any stop", 30, "Ok. then a long run of..."},
+		{"word boundary", "a long run of words without any sentence stop", 20, "a long run of..."},
+		{"decimal is not a sentence end", "Pay $4.99 by Friday or lose access", 16, "Pay $4.99 by..."},
+		{"counts characters not bytes", "Café déjà vu. Encore une fois.", 15, "Café déjà vu."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateSummary(tt.summary, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("truncateSummary(%q, %d) = %q, want %q", tt.summary, tt.maxLen, got, tt.want)
+			}
+			if tt.maxLen > 0 && utf8.RuneCountInString(got) > tt.maxLen {
+				t.Errorf("truncateSummary() = %d characters, limit %d", utf8.RuneCountInString(got), tt.maxLen)
+			}
+		})
+	}
+}
+
+// longSummaryProvider returns a summary longer than the requested MaxLength
+type longSummaryProvider struct {
+	summary string
+	got     SummaryRequest
+}
+
+func (p *longSummaryProvider) GenerateSummary(ctx context.Context, req SummaryRequest) (*SummaryResponse, int, error) {
+	p.got = req
+	return &SummaryResponse{Summary: p.summary}, 42, nil
+}
+
+func (p *longSummaryProvider) Check(ctx context.Context) error { return nil }
+
+func (p *longSummaryProvider) Name() string { return "fake" }
+
+func TestGenerateSummaryEnforcesMaxLength(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("APPDATA", home)
+
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer storage.CloseDB(db)
+
+	provider := &longSummaryProvider{
+		summary: "The invoice is due Friday. Payment can be made online. " + strings.Repeat("Extra detail nobody asked for. ", 20),
+	}
+	cfg := &Config{AISummary: AISummaryConfig{
+		Enabled: true,
+		Behavior: BehaviorConfig{
+			MaxSummaryLength: 60,
+			TimeoutSeconds:   5,
+		},
+	}}
+	s := &Service{provider: provider, config: cfg, db: db, rateLimiter: NewRateLimiter(cfg.AISummary.RateLimit, time.Now())}
+
+	summary, err := s.GenerateSummary(context.Background(), "msg-1", "billing@example.com", "Invoice", "body", "", nil, 0)
+	if err != nil {
+		t.Fatalf("GenerateSummary() error = %v", err)
+	}
+
+	want := "The invoice is due Friday. Payment can be made online."
+	if summary.Summary != want {
+		t.Errorf("Summary = %q, want %q", summary.Summary, want)
+	}
+	if provider.got.MaxLength != 60 {
+		t.Errorf("provider request MaxLength = %d, want 60", provider.got.MaxLength)
+	}
+
+	// The truncated version is what gets stored
+	stored, err := storage.GetAISummaryByMessageID(db, "msg-1")
+	if err != nil || stored == nil {
+		t.Fatalf("GetAISummaryByMessageID() = %v, %v", stored, err)
+	}
+	if stored.Summary != want {
+		t.Errorf("stored Summary = %q, want %q", stored.Summary, want)
+	}
+}
+
+// TestGenerateSummaryRedactsLogs tests that monitoring.redact_logs keeps subjects and senders out of the log
+func TestGenerateSummaryRedactsLogs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("APPDATA", home)
+
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer storage.CloseDB(db)
+
+	logPath := filepath.Join(home, "sentinel.log")
+	if err := log.Setup("debug", logPath); err != nil {
+		t.Fatalf("log.Setup() error = %v", err)
+	}
+	log.SetRedact(true)
+	t.Cleanup(func() {
+		log.SetRedact(false)
+		log.Setup("info", "stderr")
+	})
+
+	cfg := &Config{AISummary: AISummaryConfig{
+		Enabled:  true,
+		Behavior: BehaviorConfig{TimeoutSeconds: 5, EnableCache: true},
+	}}
+	s := &Service{provider: &longSummaryProvider{summary: "Pay by Friday."}, config: cfg, db: db, rateLimiter: NewRateLimiter(cfg.AISummary.RateLimit, time.Now())}
+
+	// The second email has identical content, so it's served from the content cache
+	for _, id := range []string{"msg-1", "msg-2"} {
+		if _, err := s.GenerateSummary(context.Background(), id, "billing@example.com", "Secret invoice 4471", "body", "", nil, 0); err != nil {
+			t.Fatalf("GenerateSummary(%s) error = %v", id, err)
+		}
+	}
+
+	out, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if !strings.Contains(string(out), "Generating AI summary") {
+		t.Fatalf("log = %q, want the summary lines", out)
+	}
+	for _, secret := range []string{"Secret invoice 4471", "billing@example.com"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("redacted log contains %q:\n%s", secret, out)
+		}
+	}
+}
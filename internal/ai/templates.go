@@ -0,0 +1,82 @@
+package ai
+
+import "strings"
+
+// DefaultTemplate is the prompt.templates key used when no category matches
+const DefaultTemplate = "default"
+
+// categoryLabels maps filter labels to the built-in template categories
+// A label that is itself a template key (e.g. a custom "invoice" template) is used as-is.
+var categoryLabels = map[string]string{
+	"calendar":      "meeting",
+	"meetings":      "meeting",
+	"events":        "meeting",
+	"interview":     "meeting",
+	"interviews":    "meeting",
+	"tasks":         "task",
+	"todo":          "task",
+	"action":        "task",
+	"deadlines":     "task",
+	"notifications": "notification",
+	"alerts":        "notification",
+	"updates":       "notification",
+	"status":        "notification",
+}
+
+// categoryKeywords picks a category from the subject and body when no label decides it
+// Checked in this order, so a meeting invite that mentions a deadline is still a meeting.
+var categoryKeywords = []struct {
+	category string
+	keywords []string
+}{
+	{"meeting", []string{"invitation:", "meeting", "calendar", "agenda", "webinar", "zoom.us", "meet.google.com", "teams.microsoft.com"}},
+	{"task", []string{"action required", "deadline", "due date", "due by", "assigned to you", "to-do", "todo", "deliverable"}},
+	{"notification", []string{"notification", "has been updated", "has changed", "status update", "your account", "alert:"}},
+}
+
+// SelectTemplate returns the prompt.templates key for an email, or "" if none applies
+// Filter labels are checked first (a label naming a template, then categoryLabels),
+// then keywords in the subject and body. Falls back to the "default" template if set.
+func SelectTemplate(templates map[string]string, labels []string, subject, body string) string {
+	if len(templates) == 0 {
+		return ""
+	}
+
+	for _, label := range labels {
+		key := strings.ToLower(strings.TrimSpace(label))
+		if _, ok := templates[key]; ok && key != DefaultTemplate {
+			return key
+		}
+		if category, ok := categoryLabels[key]; ok {
+			if _, ok := templates[category]; ok {
+				return category
+			}
+		}
+	}
+
+	text := strings.ToLower(subject + "\n" + body)
+	for _, c := range categoryKeywords {
+		if _, ok := templates[c.category]; !ok {
+			continue
+		}
+		for _, kw := range c.keywords {
+			if strings.Contains(text, kw) {
+				return c.category
+			}
+		}
+	}
+
+	if _, ok := templates[DefaultTemplate]; ok {
+		return DefaultTemplate
+	}
+	return ""
+}
+
+// appendGuidance adds category-specific instructions to a built prompt
+func appendGuidance(prompt, guidance string) string {
+	guidance = strings.TrimSpace(guidance)
+	if guidance == "" {
+		return prompt
+	}
+	return prompt + "\n\n" + guidance
+}
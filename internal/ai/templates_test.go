@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectTemplate(t *testing.T) {
+	builtIn := map[string]string{
+		"meeting":      "Focus on time, participants, and agenda items.",
+		"task":         "Extract deadlines, deliverables, and dependencies.",
+		"notification": "Identify what changed and why it matters.",
+	}
+	withDefault := map[string]string{
+		"meeting": "Focus on time, participants, and agenda items.",
+		"invoice": "List the amount, due date and payee.",
+		"default": "Keep it short.",
+	}
+
+	tests := []struct {
+		name      string
+		templates map[string]string
+		labels    []string
+		subject   string
+		body      string
+		want      string
+	}{
+		{name: "calendar label uses meeting", templates: builtIn, labels: []string{"calendar"}, subject: "Quarterly sync", want: "meeting"},
+		{name: "label case ignored", templates: builtIn, labels: []string{" Calendar "}, subject: "Sync", want: "meeting"},
+		{name: "label naming a template", templates: builtIn, labels: []string{"work", "task"}, subject: "Hello", want: "task"},
+		{name: "first deciding label wins", templates: builtIn, labels: []string{"alerts", "calendar"}, subject: "Hello", want: "notification"},
+		{name: "label beats keywords", templates: builtIn, labels: []string{"todo"}, subject: "Meeting notes", want: "task"},
+		{name: "subject keyword", templates: builtIn, subject: "Invitation: Design review @ Tue 3pm", want: "meeting"},
+		{name: "body keyword", templates: builtIn, subject: "Project X", body: "Action required: sign by Friday", want: "task"},
+		{name: "meeting checked before task", templates: builtIn, subject: "Meeting about the deadline", want: "meeting"},
+		{name: "no match without default", templates: builtIn, labels: []string{"work"}, subject: "Lunch?", want: ""},
+		{name: "custom template by label", templates: withDefault, labels: []string{"invoice"}, subject: "Your bill", want: "invoice"},
+		{name: "category missing from templates", templates: withDefault, labels: []string{"todo"}, subject: "Your bill", want: "default"},
+		{name: "default label is not a category", templates: withDefault, labels: []string{"default"}, subject: "Agenda for Monday", want: "meeting"},
+		{name: "falls back to default", templates: withDefault, subject: "Lunch?", want: "default"},
+		{name: "no templates", templates: nil, labels: []string{"calendar"}, subject: "Meeting", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectTemplate(tt.templates, tt.labels, tt.subject, tt.body); got != tt.want {
+				t.Errorf("SelectTemplate(%v, %q) = %q, want %q", tt.labels, tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPromptSubstitutesMaxLength(t *testing.T) {
+	p := &GeminiProvider{prompt: PromptConfig{UserTemplate: "In at most {{.MaxLength}} characters, from {{.Sender}}: {{.Body}}"}}
+
+	got := p.buildPrompt(SummaryRequest{Sender: "a@b.com", Body: "Hi", MaxLength: 300})
+	if want := "In at most 300 characters, from a@b.com: Hi"; got != want {
+		t.Errorf("buildPrompt() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildPromptAppendsGuidance(t *testing.T) {
+	p := &ClaudeProvider{prompt: PromptConfig{UserTemplate: "Subject: {{.Subject}}\n\n{{.Body}}"}}
+
+	got := p.buildPrompt(SummaryRequest{Subject: "Sync", Body: "See you at 3", Guidance: "Focus on time."})
+	if want := "Subject: Sync\n\nSee you at 3\n\nFocus on time."; got != want {
+		t.Errorf("buildPrompt() = %q, want %q", got, want)
+	}
+
+	got = p.buildPrompt(SummaryRequest{Subject: "Sync", Body: "See you at 3"})
+	if strings.HasSuffix(got, "\n\n") {
+		t.Errorf("buildPrompt() without guidance = %q, want no trailing blank lines", got)
+	}
+}
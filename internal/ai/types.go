@@ -22,6 +22,7 @@ type SummaryRequest struct {
 	Body    string
 	Snippet string
 	MaxLength int
+	Guidance  string // Extra instructions from the email's prompt template (may be empty)
 }
 
 // SummaryResponse represents the AI provider's response
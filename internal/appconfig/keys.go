@@ -0,0 +1,242 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package appconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KeyValue is a single dotted config key and its current value
+type KeyValue struct {
+	Key   string
+	Value string
+	Type  string // "int", "bool", "string", "float", "list"
+}
+
+// ListKeys returns every settable dotted key (e.g. "notifications.mobile.topic")
+// with its current value, sorted by key
+func ListKeys(cfg *AppConfig) []KeyValue {
+	var result []KeyValue
+	walkKeys(reflect.ValueOf(cfg).Elem(), "", func(key string, v reflect.Value) {
+		result = append(result, KeyValue{
+			Key:   key,
+			Value: formatValue(v),
+			Type:  typeName(v),
+		})
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+// GetValue returns the current value of a dotted key as a string
+func GetValue(cfg *AppConfig, key string) (string, error) {
+	v, err := lookupKey(cfg, key)
+	if err != nil {
+		return "", err
+	}
+	return formatValue(v), nil
+}
+
+// SetValue parses value according to the key's type and stores it in cfg
+// Lists are given as comma-separated values
+func SetValue(cfg *AppConfig, key, value string) error {
+	v, err := lookupKey(cfg, key)
+	if err != nil {
+		return err
+	}
+
+	value = strings.TrimSpace(value)
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s expects true or false, got '%s'", key, value)
+		}
+		v.SetBool(b)
+
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s expects an integer, got '%s'", key, value)
+		}
+		v.SetInt(int64(n))
+
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s expects a number, got '%s'", key, value)
+		}
+		v.SetFloat(f)
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%s cannot be set from the command line", key)
+		}
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+
+	default:
+		return fmt.Errorf("%s cannot be set from the command line", key)
+	}
+
+	return nil
+}
+
+// lookupKey resolves a dotted key to a settable leaf value
+func lookupKey(cfg *AppConfig, key string) (reflect.Value, error) {
+	v := reflect.ValueOf(cfg).Elem()
+
+	for _, part := range strings.Split(strings.ToLower(strings.TrimSpace(key)), ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, unknownKeyError(key)
+		}
+
+		field, ok := fieldByYAMLName(v, part)
+		if !ok {
+			return reflect.Value{}, unknownKeyError(key)
+		}
+		v = field
+	}
+
+	if !isLeaf(v) {
+		return reflect.Value{}, unknownKeyError(key)
+	}
+
+	return v, nil
+}
+
+// unknownKeyError builds an error listing the top-level sections
+func unknownKeyError(key string) error {
+	sections := []string{}
+	t := reflect.TypeOf(AppConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		sections = append(sections, yamlName(t.Field(i)))
+	}
+	return fmt.Errorf("unknown config key '%s' (keys start with one of: %s; run 'config list' to see all keys)",
+		key, strings.Join(sections, ", "))
+}
+
+// walkKeys calls fn for every leaf field reachable from v
+func walkKeys(v reflect.Value, prefix string, fn func(key string, v reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := yamlName(t.Field(i))
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			walkKeys(field, key, fn)
+			continue
+		}
+		if isLeaf(field) {
+			fn(key, field)
+		}
+	}
+}
+
+// fieldByYAMLName finds a struct field by its yaml tag name
+func fieldByYAMLName(v reflect.Value, name string) (reflect.Value, bool) {
+	field, ok := structFieldByYAMLName(v.Type(), name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return v.FieldByIndex(field.Index), true
+}
+
+// structFieldByYAMLName finds a struct type's field by its yaml tag name
+func structFieldByYAMLName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlName(t.Field(i)) == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// yamlName returns the yaml key for a struct field
+func yamlName(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	if idx := strings.Index(tag, ","); idx != -1 {
+		tag = tag[:idx]
+	}
+	return tag
+}
+
+// isLeaf reports whether a value can be read and written as a single key
+func isLeaf(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+// formatValue renders a leaf value as a string
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = v.Index(i).String()
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
+
+// typeName returns a user-facing type name for a leaf value
+func typeName(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int:
+		return "int"
+	case reflect.Float64:
+		return "float"
+	case reflect.Slice:
+		return "list"
+	default:
+		return "string"
+	}
+}
+
+// parseBool accepts the same spellings as the legacy config command
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean")
+	}
+}
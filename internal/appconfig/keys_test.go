@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package appconfig
+
+import (
+	"testing"
+)
+
+// TestSetAndGetValue tests dotted key access on the unified config
+func TestSetAndGetValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "String", key: "notifications.mobile.topic", value: "my-topic", expected: "my-topic"},
+		{name: "Int", key: "monitoring.polling_interval", value: "60", expected: "60"},
+		{name: "Bool", key: "ai_summary.enabled", value: "yes", expected: "true"},
+		{name: "Float", key: "accounts.detection.min_confidence", value: "0.8", expected: "0.8"},
+		{name: "List", key: "priority.vip_domains", value: "a.com, b.io", expected: "a.com,b.io"},
+		{name: "Case insensitive key", key: "Notifications.Desktop.Enabled", value: "false", expected: "false"},
+		{name: "Invalid int", key: "monitoring.polling_interval", value: "soon", wantErr: true},
+		{name: "Invalid bool", key: "ai_summary.enabled", value: "maybe", wantErr: true},
+		{name: "Unknown key", key: "notifications.pager.enabled", value: "true", wantErr: true},
+		{name: "Section is not a key", key: "notifications.mobile", value: "true", wantErr: true},
+		{name: "Map is not settable", key: "accounts.categories", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+
+			err := SetValue(cfg, tt.key, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SetValue(%s, %s) expected error", tt.key, tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetValue(%s, %s) error = %v", tt.key, tt.value, err)
+			}
+
+			got, err := GetValue(cfg, tt.key)
+			if err != nil {
+				t.Fatalf("GetValue(%s) error = %v", tt.key, err)
+			}
+			if got != tt.expected {
+				t.Errorf("GetValue(%s) = %q, want %q", tt.key, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestListKeys tests that nested keys are flattened
+func TestListKeys(t *testing.T) {
+	keys := ListKeys(DefaultConfig())
+
+	found := make(map[string]bool)
+	for _, kv := range keys {
+		found[kv.Key] = true
+	}
+
+	for _, key := range []string{"monitoring.polling_interval", "notifications.mobile.topic", "ai_summary.providers.gemini.model"} {
+		if !found[key] {
+			t.Errorf("ListKeys() missing %s", key)
+		}
+	}
+}
@@ -70,10 +70,20 @@ func loadUnifiedConfig() (*AppConfig, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse app-config.yaml: %w", err)
 	}
+	upgradeSchema(&cfg)
 
 	return &cfg, nil
 }
 
+// upgradeSchema fills in settings added since the file's schema_version was written
+// The upgraded values are persisted the next time the config is saved.
+func upgradeSchema(cfg *AppConfig) {
+	if cfg.SchemaVersion < 2 {
+		// Startup backups ran unconditionally before monitoring.backup existed
+		cfg.Monitoring.Backup.Enabled = true
+	}
+}
+
 // Save saves the app configuration to app-config.yaml
 func Save(cfg *AppConfig) error {
 	configPath, err := ConfigPath()
@@ -86,6 +96,9 @@ func Save(cfg *AppConfig) error {
 		return err
 	}
 
+	// Stamp the layout this build writes
+	cfg.SchemaVersion = CurrentSchemaVersion
+
 	// Marshal to YAML
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
@@ -202,7 +215,9 @@ func migrateAIConfig(path string, appConfig *AppConfig) error {
 				} `yaml:"gemini"`
 			} `yaml:"api"`
 			Behavior struct {
-				EnableCache bool `yaml:"enable_cache"`
+				EnableCache      bool `yaml:"enable_cache"`
+				PriorityOnly     bool `yaml:"priority_only"`
+				MaxSummaryLength int  `yaml:"max_summary_length"`
 			} `yaml:"behavior"`
 			RateLimit struct {
 				MaxPerHour int `yaml:"max_per_hour"`
@@ -246,6 +261,10 @@ func migrateAIConfig(path string, appConfig *AppConfig) error {
 
 	// Migrate cache settings
 	appConfig.AISummary.Cache.Enabled = oldConfig.AISummary.Behavior.EnableCache
+	appConfig.AISummary.PriorityOnly = oldConfig.AISummary.Behavior.PriorityOnly
+	if oldConfig.AISummary.Behavior.MaxSummaryLength > 0 {
+		appConfig.AISummary.MaxSummaryLength = oldConfig.AISummary.Behavior.MaxSummaryLength
+	}
 
 	// Migrate rate limits (convert from hour/day to per-minute/per-day)
 	if oldConfig.AISummary.RateLimit.MaxPerHour > 0 {
@@ -328,16 +347,22 @@ func migrateOTPRules(path string, appConfig *AppConfig) error {
 	}
 
 	var oldOTPRules struct {
-		Enabled           bool   `yaml:"enabled"`
-		ExpiryDuration    string `yaml:"expiry_duration"`
-		AutoCopy          bool   `yaml:"auto_copy_to_clipboard"`
-		AutoClearDuration string `yaml:"clipboard_auto_clear"`
+		Enabled           bool              `yaml:"enabled"`
+		ExpiryDuration    string            `yaml:"expiry_duration"`
+		ExpiryOverrides   map[string]string `yaml:"expiry_overrides"`
+		Threshold         float64           `yaml:"confidence_threshold"`
+		AutoCopy          bool              `yaml:"auto_copy_to_clipboard"`
+		AutoClearDuration string            `yaml:"clipboard_auto_clear"`
 		CustomPatterns    []struct {
 			Name       string  `yaml:"name"`
 			Regex      string  `yaml:"regex"`
 			Confidence float64 `yaml:"confidence"`
 		} `yaml:"custom_patterns"`
-		TrustedSenders []string `yaml:"trusted_otp_senders"`
+		TrustedSenders          []string `yaml:"trusted_otp_senders"`
+		TrustedDomains          []string `yaml:"trusted_otp_domains"`
+		RequireTrustedSender    bool     `yaml:"require_trusted_sender"`
+		TriggerPhrases          []string `yaml:"trigger_phrases"`
+		RequireTriggerProximity int      `yaml:"require_trigger_proximity"`
 	}
 
 	if err := yaml.Unmarshal(data, &oldOTPRules); err != nil {
@@ -350,6 +375,12 @@ func migrateOTPRules(path string, appConfig *AppConfig) error {
 	if oldOTPRules.ExpiryDuration != "" {
 		appConfig.OTP.ExpiryDuration = oldOTPRules.ExpiryDuration
 	}
+	if len(oldOTPRules.ExpiryOverrides) > 0 {
+		appConfig.OTP.ExpiryOverrides = oldOTPRules.ExpiryOverrides
+	}
+	if oldOTPRules.Threshold > 0 {
+		appConfig.OTP.ConfidenceThreshold = oldOTPRules.Threshold
+	}
 
 	// Migrate clipboard settings
 	appConfig.OTP.Clipboard.AutoCopy = oldOTPRules.AutoCopy
@@ -380,6 +411,16 @@ func migrateOTPRules(path string, appConfig *AppConfig) error {
 	if len(oldOTPRules.TrustedSenders) > 0 {
 		appConfig.OTP.TrustedSenders = oldOTPRules.TrustedSenders
 	}
+	if len(oldOTPRules.TrustedDomains) > 0 {
+		appConfig.OTP.TrustedDomains = oldOTPRules.TrustedDomains
+	}
+	appConfig.OTP.RequireTrustedSender = oldOTPRules.RequireTrustedSender
+
+	// Migrate trigger phrase settings
+	if len(oldOTPRules.TriggerPhrases) > 0 {
+		appConfig.OTP.TriggerPhrases = oldOTPRules.TriggerPhrases
+	}
+	appConfig.OTP.RequireTriggerProximity = oldOTPRules.RequireTriggerProximity
 
 	return nil
 }
@@ -387,16 +428,34 @@ func migrateOTPRules(path string, appConfig *AppConfig) error {
 // DefaultConfig returns a new AppConfig with sensible defaults
 func DefaultConfig() *AppConfig {
 	return &AppConfig{
+		SchemaVersion: CurrentSchemaVersion,
 		Monitoring: MonitoringConfig{
 			PollingInterval: 45,
+			FetchLimit:      10,
+			LogLevel:        "info",
+			LogOutput:       "stdout",
 			Database: DatabaseConfig{
 				WALMode:         true,
 				CleanupInterval: "1h",
+				RetentionDays:   0,
+			},
+			Backup: BackupConfig{
+				Enabled:  true,
+				KeepLast: 5,
+			},
+			Metrics: MetricsConfig{
+				Enabled: false,
+				Addr:    DefaultMetricsAddr,
+			},
+			Gmail: GmailConfig{
+				AllowModify:       false,
+				AuthWarningWindow: "24h",
 			},
 		},
 		AISummary: AISummaryConfig{
-			Enabled:  false,
-			Provider: "gemini",
+			Enabled:          false,
+			Provider:         "gemini",
+			MaxSummaryLength: DefaultMaxSummaryLength,
 			Providers: AIProvidersConfig{
 				Gemini: GeminiProviderConfig{
 					Model:       "gemini-2.0-flash-exp",
@@ -428,6 +487,12 @@ func DefaultConfig() *AppConfig {
 						RequestsPerDay:    10000,
 					},
 				},
+				Ollama: OllamaProviderConfig{
+					Model:       "llama3.2",
+					Endpoint:    "http://localhost:11434/api/generate",
+					MaxTokens:   1000,
+					Temperature: 0.3,
+				},
 			},
 			Cache: CacheConfig{
 				Enabled: true,
@@ -467,9 +532,10 @@ func DefaultConfig() *AppConfig {
 			},
 		},
 		OTP: OTPConfig{
-			Enabled:        true,
-			ExpiryDuration: "5m",
-			MaxCodes:       50,
+			Enabled:             true,
+			ExpiryDuration:      "5m",
+			ConfidenceThreshold: 0.7,
+			MaxCodes:            50,
 			TrustedSenders: []string{
 				"noreply@accountprotection.microsoft.com",
 				"account-security-noreply@accountprotection.microsoft.com",
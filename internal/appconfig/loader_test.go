@@ -202,3 +202,31 @@ func TestSaveAndLoad(t *testing.T) {
 
 	t.Log("✅ Save and load test successful!")
 }
+
+// TestUpgradeSchema tests that settings added in later schema versions get their defaults
+func TestUpgradeSchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		wantBackups bool
+	}{
+		{"unversioned file", "monitoring:\n  polling_interval: 60\n", true},
+		{"version 1 file", "schema_version: 1\nmonitoring:\n  database:\n    wal_mode: true\n", true},
+		{"version 2 keeps disabled", "schema_version: 2\nmonitoring:\n  backup:\n    enabled: false\n", false},
+		{"version 2 enabled", "schema_version: 2\nmonitoring:\n  backup:\n    enabled: true\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg AppConfig
+			if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+				t.Fatalf("Failed to unmarshal config: %v", err)
+			}
+			upgradeSchema(&cfg)
+
+			if cfg.Monitoring.Backup.Enabled != tt.wantBackups {
+				t.Errorf("Backup.Enabled = %v, want %v", cfg.Monitoring.Backup.Enabled, tt.wantBackups)
+			}
+		})
+	}
+}
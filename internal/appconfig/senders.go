@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package appconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+)
+
+// SenderAllowed reports whether mail from the given From header should be processed at all
+// Blocklisted senders are always skipped; when an allowlist is set only listed senders pass
+func (m *MonitoringConfig) SenderAllowed(from string) bool {
+	if senderMatches(from, m.BlocklistSenders, m.BlocklistDomains) {
+		return false
+	}
+
+	if len(m.AllowlistSenders) == 0 && len(m.AllowlistDomains) == 0 {
+		return true
+	}
+
+	return senderMatches(from, m.AllowlistSenders, m.AllowlistDomains)
+}
+
+// AddToBlocklist adds a sender address or domain to the blocklist
+// Returns false if the entry was already listed
+func (m *MonitoringConfig) AddToBlocklist(entry string) (bool, error) {
+	value, isAddress, err := normalizeSenderEntry(entry)
+	if err != nil {
+		return false, err
+	}
+
+	list := &m.BlocklistDomains
+	if isAddress {
+		list = &m.BlocklistSenders
+	}
+
+	for _, existing := range *list {
+		if strings.EqualFold(strings.TrimSpace(existing), value) {
+			return false, nil
+		}
+	}
+
+	*list = append(*list, value)
+	return true, nil
+}
+
+// RemoveFromBlocklist removes a sender address or domain from the blocklist
+// Returns false if the entry wasn't listed
+func (m *MonitoringConfig) RemoveFromBlocklist(entry string) bool {
+	value, isAddress, err := normalizeSenderEntry(entry)
+	if err != nil {
+		return false
+	}
+
+	list := &m.BlocklistDomains
+	if isAddress {
+		list = &m.BlocklistSenders
+	}
+
+	found := false
+	kept := []string{}
+	for _, existing := range *list {
+		if strings.EqualFold(strings.TrimSpace(existing), value) {
+			found = true
+			continue
+		}
+		kept = append(kept, existing)
+	}
+
+	*list = kept
+	return found
+}
+
+// senderMatches reports whether a From header matches any address or domain
+// Addresses match exactly; domains also match their subdomains. Case-insensitive.
+func senderMatches(from string, addresses, domains []string) bool {
+	address := strings.ToLower(gmail.GetFromAddress(from))
+	if address == "" {
+		return false
+	}
+
+	for _, a := range addresses {
+		if strings.ToLower(strings.TrimSpace(a)) == address {
+			return true
+		}
+	}
+
+	domain := strings.ToLower(gmail.GetFromDomain(from))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if d == "" {
+			continue
+		}
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeSenderEntry lowercases a blocklist entry and reports whether it's an address
+// "user@example.com" and "Name <user@example.com>" are addresses; "example.com" and "@example.com" are domains
+func normalizeSenderEntry(entry string) (string, bool, error) {
+	value := strings.ToLower(gmail.GetFromAddress(entry))
+	value = strings.TrimPrefix(value, "@")
+
+	if value == "" {
+		return "", false, fmt.Errorf("sender or domain is required")
+	}
+	if strings.ContainsAny(value, " \t") {
+		return "", false, fmt.Errorf("invalid sender or domain: %s", entry)
+	}
+
+	return value, strings.Contains(value, "@"), nil
+}
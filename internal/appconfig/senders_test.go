@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package appconfig
+
+import (
+	"testing"
+)
+
+// TestSenderAllowed tests blocklist and allowlist matching
+func TestSenderAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      MonitoringConfig
+		from     string
+		expected bool
+	}{
+		{name: "No lists", cfg: MonitoringConfig{}, from: "a@example.com", expected: true},
+		{
+			name:     "Blocked address",
+			cfg:      MonitoringConfig{BlocklistSenders: []string{"News@Example.com"}},
+			from:     "Example News <news@example.com>",
+			expected: false,
+		},
+		{
+			name:     "Other address at blocked sender's domain",
+			cfg:      MonitoringConfig{BlocklistSenders: []string{"news@example.com"}},
+			from:     "support@example.com",
+			expected: true,
+		},
+		{
+			name:     "Blocked domain",
+			cfg:      MonitoringConfig{BlocklistDomains: []string{"EXAMPLE.com"}},
+			from:     "Deals <deals@example.com>",
+			expected: false,
+		},
+		{
+			name:     "Blocked subdomain",
+			cfg:      MonitoringConfig{BlocklistDomains: []string{"@example.com"}},
+			from:     "deals@mail.example.com",
+			expected: false,
+		},
+		{
+			name:     "Similar domain is not blocked",
+			cfg:      MonitoringConfig{BlocklistDomains: []string{"example.com"}},
+			from:     "deals@notexample.com",
+			expected: true,
+		},
+		{
+			name:     "Allowlist match",
+			cfg:      MonitoringConfig{AllowlistDomains: []string{"work.com"}},
+			from:     "Boss <boss@work.com>",
+			expected: true,
+		},
+		{
+			name:     "Allowlist miss",
+			cfg:      MonitoringConfig{AllowlistSenders: []string{"boss@work.com"}},
+			from:     "someone@else.com",
+			expected: false,
+		},
+		{
+			name: "Blocklist wins over allowlist",
+			cfg: MonitoringConfig{
+				AllowlistDomains: []string{"work.com"},
+				BlocklistSenders: []string{"noreply@work.com"},
+			},
+			from:     "noreply@work.com",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.SenderAllowed(tt.from); got != tt.expected {
+				t.Errorf("SenderAllowed(%q) = %v, expected %v", tt.from, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestBlocklistAddRemove tests that entries land in the right list and aren't duplicated
+func TestBlocklistAddRemove(t *testing.T) {
+	var cfg MonitoringConfig
+
+	for _, entry := range []string{"News@Example.com", "@spam.io", "Promo <promo@shop.com>"} {
+		added, err := cfg.AddToBlocklist(entry)
+		if err != nil || !added {
+			t.Fatalf("AddToBlocklist(%q) = %v, %v", entry, added, err)
+		}
+	}
+
+	if added, _ := cfg.AddToBlocklist("news@example.com"); added {
+		t.Error("AddToBlocklist() added a duplicate address")
+	}
+	if _, err := cfg.AddToBlocklist("   "); err == nil {
+		t.Error("AddToBlocklist() accepted an empty entry")
+	}
+
+	if len(cfg.BlocklistSenders) != 2 || cfg.BlocklistSenders[0] != "news@example.com" || cfg.BlocklistSenders[1] != "promo@shop.com" {
+		t.Errorf("BlocklistSenders = %v", cfg.BlocklistSenders)
+	}
+	if len(cfg.BlocklistDomains) != 1 || cfg.BlocklistDomains[0] != "spam.io" {
+		t.Errorf("BlocklistDomains = %v", cfg.BlocklistDomains)
+	}
+
+	if !cfg.RemoveFromBlocklist("SPAM.IO") {
+		t.Error("RemoveFromBlocklist() did not find domain")
+	}
+	if cfg.RemoveFromBlocklist("spam.io") {
+		t.Error("RemoveFromBlocklist() removed a domain twice")
+	}
+	if len(cfg.BlocklistDomains) != 0 {
+		t.Errorf("BlocklistDomains = %v, expected empty", cfg.BlocklistDomains)
+	}
+}
@@ -4,13 +4,25 @@ Copyright © 2025 Datateamsix <research@dt6.io>
 package appconfig
 
 import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
+// CurrentSchemaVersion is the app-config.yaml layout this build writes
+// Bump it when a change needs a migration, so older files can be detected in Load.
+const CurrentSchemaVersion = 2
+
 // AppConfig represents the unified application configuration
 // This replaces the previous separate configs (ai-config.yaml, rules.yaml, otp_rules.yaml)
 type AppConfig struct {
+	SchemaVersion int                 `yaml:"schema_version"` // 0 = written before versioning
 	Monitoring    MonitoringConfig    `yaml:"monitoring"`
+	Filters       FiltersConfig       `yaml:"filters"`
 	AISummary     AISummaryConfig     `yaml:"ai_summary"`
 	Priority      PriorityConfig      `yaml:"priority"`
 	OTP           OTPConfig           `yaml:"otp"`
@@ -20,14 +32,65 @@ type AppConfig struct {
 
 // MonitoringConfig holds email monitoring settings
 type MonitoringConfig struct {
-	PollingInterval int              `yaml:"polling_interval"` // seconds
-	Database        DatabaseConfig   `yaml:"database"`
+	PollingInterval  int              `yaml:"polling_interval"`  // seconds
+	PollJitterPct    int              `yaml:"poll_jitter_pct"`   // randomize each wait by ±N% (0 = fixed interval)
+	FetchLimit       int              `yaml:"fetch_limit"`       // messages fetched per filter scope each poll (default 10, max 500)
+	MaxMessageAge    string           `yaml:"max_message_age"`   // skip messages Gmail received longer ago than this, like "24h" (empty = no limit)
+	NotifyOnStartup  bool             `yaml:"notify_on_startup"` // alert on existing mail the first time (default: baseline it silently)
+	RichSnippet      bool             `yaml:"rich_snippet"`      // use the start of the email body as the alert snippet when it says more than Gmail's
+	Timezone         string           `yaml:"timezone"`          // IANA name like "America/New_York", empty = system local time
+	LogLevel         string           `yaml:"log_level"`         // "debug", "info", "warn", "error"
+	LogOutput        string           `yaml:"log_output"`        // "stdout", "stderr" or a file path
+	RedactLogs       bool             `yaml:"redact_logs"`       // hash senders and omit subjects in logs (the database keeps full data)
+	Database         DatabaseConfig   `yaml:"database"`
+	Backup           BackupConfig     `yaml:"backup"`
+	Gmail            GmailConfig      `yaml:"gmail"`
+	Metrics          MetricsConfig    `yaml:"metrics"`
+	BlocklistSenders []string         `yaml:"blocklist_senders"` // addresses that are never processed
+	BlocklistDomains []string         `yaml:"blocklist_domains"` // domains (and subdomains) that are never processed
+	AllowlistSenders []string         `yaml:"allowlist_senders"` // if any allowlist is set, only matching senders are processed
+	AllowlistDomains []string         `yaml:"allowlist_domains"`
 }
 
 // DatabaseConfig holds database settings
 type DatabaseConfig struct {
 	WALMode         bool   `yaml:"wal_mode"`
 	CleanupInterval string `yaml:"cleanup_interval"` // duration string like "1h", "0" to disable
+	RetentionDays   int    `yaml:"retention_days"`   // days of alert history to keep, 0 = wipe daily at midnight
+}
+
+// BackupConfig controls the database backup made each time monitoring starts
+type BackupConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	KeepLast  int    `yaml:"keep_last"` // newest backups to keep, older ones are deleted (0 = default 5)
+	Directory string `yaml:"directory"` // empty = backups/ in the config directory
+}
+
+// MetricsConfig controls the optional Prometheus metrics endpoint of the watcher
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"` // host:port to listen on (default "127.0.0.1:9477")
+}
+
+// GmailConfig holds Gmail API access settings
+type GmailConfig struct {
+	// AllowModify requests the gmail.modify scope so filters can apply labels and mark mail read
+	// Off by default - email-sentinel stays read-only unless explicitly enabled
+	AllowModify bool `yaml:"allow_modify"`
+
+	// TokenLifetime is how long an authorization from 'init' stays valid, e.g. "168h"
+	// for OAuth apps in Google's "Testing" status. Empty = no known expiry.
+	TokenLifetime string `yaml:"token_lifetime"`
+
+	// AuthWarningWindow is how long before that expiry to start warning (default "24h")
+	AuthWarningWindow string `yaml:"auth_warning_window"`
+}
+
+// FiltersConfig holds settings that apply to all filters (the filters themselves live in config.yaml)
+type FiltersConfig struct {
+	// FirstMatchWins alerts only for the first matching filter, in the order shown by
+	// 'filter list' (change it with 'filter move'). Default: every matching filter fires.
+	FirstMatchWins bool `yaml:"first_match_wins"`
 }
 
 // ==============================================================================
@@ -36,11 +99,25 @@ type DatabaseConfig struct {
 
 // AISummaryConfig holds AI-powered email summary settings
 type AISummaryConfig struct {
-	Enabled   bool                       `yaml:"enabled"`
-	Provider  string                     `yaml:"provider"` // "gemini", "claude", "openai"
-	Providers AIProvidersConfig          `yaml:"providers"`
-	Cache     CacheConfig                `yaml:"cache"`
-	Prompt    PromptConfig               `yaml:"prompt"`
+	Enabled          bool              `yaml:"enabled"`
+	Provider         string            `yaml:"provider"`           // "gemini", "claude", "openai", "ollama"
+	PriorityOnly     bool              `yaml:"priority_only"`      // only summarize high and critical (priority 1-2) alerts
+	DailyTokenBudget int               `yaml:"daily_token_budget"` // max tokens per day for the active provider (0 = unlimited)
+	MaxSummaryLength int               `yaml:"max_summary_length"` // characters kept from each summary (0 = default 500)
+	Providers        AIProvidersConfig `yaml:"providers"`
+	Cache            CacheConfig       `yaml:"cache"`
+	Prompt           PromptConfig      `yaml:"prompt"`
+}
+
+// DefaultMaxSummaryLength is the summary length limit when max_summary_length is unset
+const DefaultMaxSummaryLength = 500
+
+// GetMaxSummaryLength returns the summary length limit in characters
+func (a *AISummaryConfig) GetMaxSummaryLength() int {
+	if a.MaxSummaryLength <= 0 {
+		return DefaultMaxSummaryLength
+	}
+	return a.MaxSummaryLength
 }
 
 // AIProvidersConfig holds settings for all AI providers
@@ -48,6 +125,7 @@ type AIProvidersConfig struct {
 	Gemini GeminiProviderConfig `yaml:"gemini"`
 	Claude ClaudeProviderConfig `yaml:"claude"`
 	OpenAI OpenAIProviderConfig `yaml:"openai"`
+	Ollama OllamaProviderConfig `yaml:"ollama"`
 }
 
 // GeminiProviderConfig holds Google Gemini settings
@@ -77,6 +155,15 @@ type OpenAIProviderConfig struct {
 	RateLimit   RateLimitConfig `yaml:"rate_limit"`
 }
 
+// OllamaProviderConfig holds settings for a local Ollama server (no API key needed)
+type OllamaProviderConfig struct {
+	Model       string          `yaml:"model"`
+	Endpoint    string          `yaml:"endpoint"`
+	MaxTokens   int             `yaml:"max_tokens"`
+	Temperature float64         `yaml:"temperature"`
+	RateLimit   RateLimitConfig `yaml:"rate_limit"`
+}
+
 // RateLimitConfig controls API usage limits
 type RateLimitConfig struct {
 	RequestsPerMinute int `yaml:"requests_per_minute"`
@@ -103,8 +190,9 @@ type PromptConfig struct {
 // PriorityConfig defines rules for marking emails as high priority
 type PriorityConfig struct {
 	UrgentKeywords []string `yaml:"urgent_keywords"`
-	VIPSenders     []string `yaml:"vip_senders"`
+	VIPSenders     []string `yaml:"vip_senders"` // addresses, or globs like "*@board.company.com"
 	VIPDomains     []string `yaml:"vip_domains"`
+	VIPPatterns    []string `yaml:"vip_patterns"` // regular expressions matched against the sender address
 }
 
 // ==============================================================================
@@ -113,14 +201,18 @@ type PriorityConfig struct {
 
 // OTPConfig holds OTP/2FA detection settings
 type OTPConfig struct {
-	Enabled          bool             `yaml:"enabled"`
-	ExpiryDuration   string           `yaml:"expiry_duration"`   // duration string like "5m"
-	MaxCodes         int              `yaml:"max_codes"`
-	TrustedSenders   []string         `yaml:"trusted_senders"`
-	TrustedDomains   []string         `yaml:"trusted_domains"`
-	CustomPatterns   []CustomPattern  `yaml:"custom_patterns"`
-	TriggerPhrases   []string         `yaml:"trigger_phrases"`
-	Clipboard        ClipboardConfig  `yaml:"clipboard"`
+	Enabled                 bool              `yaml:"enabled"`
+	ExpiryDuration          string            `yaml:"expiry_duration"`      // duration string like "5m"
+	ExpiryOverrides         map[string]string `yaml:"expiry_overrides"`     // sender address or domain -> duration like "15m"
+	ConfidenceThreshold     float64           `yaml:"confidence_threshold"` // minimum confidence to accept a code (0 = default 0.7)
+	MaxCodes                int               `yaml:"max_codes"`            // codes kept in history, oldest deleted first (0 = unlimited)
+	TrustedSenders          []string          `yaml:"trusted_senders"`
+	TrustedDomains          []string          `yaml:"trusted_domains"`
+	RequireTrustedSender    bool              `yaml:"require_trusted_sender"` // only accept codes from trusted senders/domains
+	CustomPatterns          []CustomPattern   `yaml:"custom_patterns"`
+	TriggerPhrases          []string          `yaml:"trigger_phrases"`
+	RequireTriggerProximity int               `yaml:"require_trigger_proximity"` // chars; a trigger phrase must be this close to the code (0 = off)
+	Clipboard               ClipboardConfig   `yaml:"clipboard"`
 }
 
 // CustomPattern represents a custom OTP detection pattern
@@ -143,6 +235,7 @@ type ClipboardConfig struct {
 // AccountsConfig holds digital account tracking settings
 type AccountsConfig struct {
 	Enabled      bool                       `yaml:"enabled"`
+	DetectTypes  []string                   `yaml:"detect_types"` // account types to detect: trial, paid, free, cancellation (empty = all)
 	TrialAlerts  []TrialAlert               `yaml:"trial_alerts"`
 	Detection    AccountDetectionConfig     `yaml:"detection"`
 	Categories   map[string][]string        `yaml:"categories"`
@@ -170,6 +263,14 @@ type NotificationsConfig struct {
 	Mobile      MobileNotifConfig  `yaml:"mobile"`
 	QuietHours  QuietHoursConfig   `yaml:"quiet_hours"`
 	WeekendMode string             `yaml:"weekend_mode"` // "normal", "quiet", "disabled"
+	Webhooks    []WebhookConfig    `yaml:"webhooks"`
+}
+
+// WebhookConfig is a generic HTTP endpoint (Slack, Discord, etc.) that receives alerts
+type WebhookConfig struct {
+	Name     string `yaml:"name"`
+	URL      string `yaml:"url"`
+	Template string `yaml:"template,omitempty"` // Go template producing JSON; empty = default payload
 }
 
 // DesktopNotifConfig controls desktop notifications
@@ -183,15 +284,16 @@ type DesktopNotifConfig struct {
 type MobileNotifConfig struct {
 	Enabled  bool   `yaml:"enabled"`
 	Topic    string `yaml:"topic"`
-	Server   string `yaml:"server"`
+	Server   string `yaml:"server"`   // ntfy server URL (empty = https://ntfy.sh)
 	Priority int    `yaml:"priority"` // 1-5
+	Auth     string `yaml:"auth"`     // access token, or "user:password" for basic auth (empty = $NTFY_AUTH, or none)
 }
 
 // QuietHoursConfig defines quiet hours settings
 type QuietHoursConfig struct {
 	Start       string `yaml:"start"`        // "HH:MM" format
 	End         string `yaml:"end"`          // "HH:MM" format
-	AllowUrgent bool   `yaml:"allow_urgent"` // Allow priority emails during quiet hours
+	AllowUrgent bool   `yaml:"allow_urgent"` // Allow critical (priority 2) emails during quiet hours
 }
 
 // ==============================================================================
@@ -206,11 +308,133 @@ func (m *MonitoringConfig) GetCleanupInterval() (time.Duration, error) {
 	return time.ParseDuration(m.Database.CleanupInterval)
 }
 
+// GetMaxMessageAge returns the oldest message age worth alerting on (0 = no limit)
+func (m *MonitoringConfig) GetMaxMessageAge() (time.Duration, error) {
+	age := strings.TrimSpace(m.MaxMessageAge)
+	if age == "" || age == "0" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(age)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a duration like \"24h\"", m.MaxMessageAge)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("'%s' must not be negative", m.MaxMessageAge)
+	}
+	return d, nil
+}
+
+// GetLocation returns the configured timezone, or time.Local when unset
+// Daily cleanup, quiet hours and weekend mode are evaluated in this zone
+func (m *MonitoringConfig) GetLocation() (*time.Location, error) {
+	if strings.TrimSpace(m.Timezone) == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(strings.TrimSpace(m.Timezone))
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone '%s': expected an IANA name like \"America/New_York\", \"Europe/London\" or \"UTC\"", m.Timezone)
+	}
+	return loc, nil
+}
+
+// GetDirectory returns the backup directory with a leading "~" expanded ("" = default)
+func (b *BackupConfig) GetDirectory() (string, error) {
+	dir := strings.TrimSpace(b.Directory)
+	if dir != "~" && !strings.HasPrefix(dir, "~/") && !strings.HasPrefix(dir, `~\`) {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ~ in backup directory: %w", err)
+	}
+	return filepath.Join(home, dir[1:]), nil
+}
+
+// DefaultMetricsAddr is where the metrics endpoint listens when no addr is set
+// Loopback only, so the endpoint isn't reachable from other machines by default.
+const DefaultMetricsAddr = "127.0.0.1:9477"
+
+// GetAddr returns the metrics listen address, validated as host:port
+func (m *MetricsConfig) GetAddr() (string, error) {
+	addr := strings.TrimSpace(m.Addr)
+	if addr == "" {
+		return DefaultMetricsAddr, nil
+	}
+	if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+		return "", fmt.Errorf("'%s' is not a host:port address like \"%s\"", m.Addr, DefaultMetricsAddr)
+	}
+	return addr, nil
+}
+
+// DefaultNtfyServer is where mobile notifications go when no server is set
+const DefaultNtfyServer = "https://ntfy.sh"
+
+// NtfyAuthEnvVar holds ntfy credentials when notifications.mobile.auth is empty
+// Keeps a token or password out of app-config.yaml.
+const NtfyAuthEnvVar = "NTFY_AUTH"
+
+// GetServer returns the ntfy server URL without a trailing slash, validated as http(s)
+func (m *MobileNotifConfig) GetServer() (string, error) {
+	server := strings.TrimRight(strings.TrimSpace(m.Server), "/")
+	if server == "" {
+		return DefaultNtfyServer, nil
+	}
+
+	parsed, err := url.Parse(server)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("'%s' is not an http(s) URL like \"%s\"", m.Server, DefaultNtfyServer)
+	}
+	if parsed.User != nil {
+		return "", fmt.Errorf("'%s' contains credentials; set notifications.mobile.auth instead", parsed.Redacted())
+	}
+	return server, nil
+}
+
+// GetAuth returns the ntfy credentials, falling back to the NTFY_AUTH environment variable ("" = none)
+func (m *MobileNotifConfig) GetAuth() string {
+	if auth := strings.TrimSpace(m.Auth); auth != "" {
+		return auth
+	}
+	return strings.TrimSpace(os.Getenv(NtfyAuthEnvVar))
+}
+
+// GetTokenLifetime returns the authorization lifetime (0 = no known expiry)
+func (g *GmailConfig) GetTokenLifetime() (time.Duration, error) {
+	if g.TokenLifetime == "" || g.TokenLifetime == "0" {
+		return 0, nil
+	}
+	return time.ParseDuration(g.TokenLifetime)
+}
+
+// GetAuthWarningWindow returns how long before auth expiry to warn (default 24h)
+func (g *GmailConfig) GetAuthWarningWindow() (time.Duration, error) {
+	if g.AuthWarningWindow == "" {
+		return 24 * time.Hour, nil
+	}
+	return time.ParseDuration(g.AuthWarningWindow)
+}
+
 // GetOTPExpiryDuration returns the OTP expiry as a time.Duration
 func (o *OTPConfig) GetOTPExpiryDuration() (time.Duration, error) {
 	return time.ParseDuration(o.ExpiryDuration)
 }
 
+// GetOTPExpiryOverrides returns the per-sender OTP expiries as time.Durations
+func (o *OTPConfig) GetOTPExpiryOverrides() (map[string]time.Duration, error) {
+	overrides := make(map[string]time.Duration, len(o.ExpiryOverrides))
+	for sender, value := range o.ExpiryOverrides {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry override for %s: %w", sender, err)
+		}
+		overrides[sender] = d
+	}
+	return overrides, nil
+}
+
 // GetClearAfterDuration returns the clipboard clear duration as time.Duration
 func (c *ClipboardConfig) GetClearAfterDuration() (time.Duration, error) {
 	return time.ParseDuration(c.ClearAfter)
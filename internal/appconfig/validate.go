@@ -0,0 +1,293 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package appconfig
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a problem found while validating app-config.yaml
+type Issue struct {
+	Key     string // dotted key, e.g. "monitoring.polling_interval"
+	Line    int    // line in app-config.yaml (0 if unknown)
+	Message string
+}
+
+// String renders the issue as "key (line N): message"
+func (i Issue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", i.Key, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Key, i.Message)
+}
+
+// LoadStrict reads app-config.yaml like Load, but also reports unknown keys and
+// out-of-range values instead of silently ignoring them
+// A YAML syntax error is returned as an error; everything else is an Issue.
+func LoadStrict() (*AppConfig, []Issue, error) {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read app-config.yaml: %w", err)
+	}
+
+	return parseStrict(data)
+}
+
+// parseStrict parses app-config.yaml content and validates it
+func parseStrict(data []byte) (*AppConfig, []Issue, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse app-config.yaml: %w", err)
+	}
+
+	var cfg AppConfig
+	if err := root.Decode(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse app-config.yaml: %w", err)
+	}
+
+	var issues []Issue
+	if len(root.Content) > 0 {
+		issues = unknownKeys(root.Content[0], reflect.TypeOf(cfg), "")
+	}
+	issues = append(issues, cfg.Validate()...)
+
+	return &cfg, issues, nil
+}
+
+// unknownKeys reports mapping keys in node that don't map to a field of t
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix string) []Issue {
+	var issues []Issue
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return unknownKeys(node, t.Elem(), prefix)
+	case reflect.Slice:
+		if node.Kind == yaml.SequenceNode {
+			for i, item := range node.Content {
+				issues = append(issues, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))...)
+			}
+		}
+		return issues
+	case reflect.Map:
+		// Map keys are user-defined (categories, templates); check the values only
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				issues = append(issues, unknownKeys(node.Content[i+1], t.Elem(), joinKey(prefix, node.Content[i].Value))...)
+			}
+		}
+		return issues
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := joinKey(prefix, keyNode.Value)
+
+		field, ok := structFieldByYAMLName(t, keyNode.Value)
+		if !ok {
+			issues = append(issues, Issue{Key: key, Line: keyNode.Line, Message: "unknown key (ignored)"})
+			continue
+		}
+		issues = append(issues, unknownKeys(valueNode, field.Type, key)...)
+	}
+
+	return issues
+}
+
+// joinKey appends a key to a dotted prefix
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// Validate checks values that parse but can't work as intended
+// Zero values mean "use the default" for most settings, so they are accepted.
+func (c *AppConfig) Validate() []Issue {
+	var issues []Issue
+	add := func(key, format string, args ...any) {
+		issues = append(issues, Issue{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.SchemaVersion > CurrentSchemaVersion {
+		add("schema_version", "%d is newer than this version of email-sentinel understands (%d)", c.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	// Monitoring
+	m := c.Monitoring
+	if m.PollingInterval != 0 && m.PollingInterval < 10 {
+		add("monitoring.polling_interval", "%d is below the 10 second minimum (Gmail API quota)", m.PollingInterval)
+	}
+	if m.PollJitterPct < 0 || m.PollJitterPct > 50 {
+		add("monitoring.poll_jitter_pct", "%d must be between 0 and 50", m.PollJitterPct)
+	}
+	if m.FetchLimit < 0 || m.FetchLimit > 500 {
+		add("monitoring.fetch_limit", "%d must be between 1 and 500 (0 = default)", m.FetchLimit)
+	}
+	if _, err := m.GetMaxMessageAge(); err != nil {
+		add("monitoring.max_message_age", "%v", err)
+	}
+	if !oneOf(m.LogLevel, "", "debug", "info", "warn", "error") {
+		add("monitoring.log_level", "'%s' must be debug, info, warn or error", m.LogLevel)
+	}
+	if _, err := m.GetLocation(); err != nil {
+		add("monitoring.timezone", "%v", err)
+	}
+	if _, err := m.GetCleanupInterval(); err != nil {
+		add("monitoring.database.cleanup_interval", "'%s' is not a duration like \"1h\"", m.Database.CleanupInterval)
+	}
+	if m.Database.RetentionDays < 0 {
+		add("monitoring.database.retention_days", "%d must not be negative", m.Database.RetentionDays)
+	}
+	if m.Backup.KeepLast < 0 {
+		add("monitoring.backup.keep_last", "%d must not be negative (0 = default)", m.Backup.KeepLast)
+	}
+	if _, err := m.Backup.GetDirectory(); err != nil {
+		add("monitoring.backup.directory", "%v", err)
+	}
+	if _, err := m.Metrics.GetAddr(); err != nil {
+		add("monitoring.metrics.addr", "%v", err)
+	}
+	if _, err := m.Gmail.GetTokenLifetime(); err != nil {
+		add("monitoring.gmail.token_lifetime", "'%s' is not a duration like \"168h\"", m.Gmail.TokenLifetime)
+	}
+	if _, err := m.Gmail.GetAuthWarningWindow(); err != nil {
+		add("monitoring.gmail.auth_warning_window", "'%s' is not a duration like \"24h\"", m.Gmail.AuthWarningWindow)
+	}
+
+	// AI summaries
+	ai := c.AISummary
+	if !oneOf(ai.Provider, "", "gemini", "claude", "openai", "ollama") {
+		add("ai_summary.provider", "'%s' must be gemini, claude, openai or ollama", ai.Provider)
+	}
+	if ai.DailyTokenBudget < 0 {
+		add("ai_summary.daily_token_budget", "%d must not be negative (0 = unlimited)", ai.DailyTokenBudget)
+	}
+	if ai.MaxSummaryLength < 0 {
+		add("ai_summary.max_summary_length", "%d must not be negative (0 = default %d)", ai.MaxSummaryLength, DefaultMaxSummaryLength)
+	}
+	providers := []struct {
+		name        string
+		maxTokens   int
+		temperature float64
+	}{
+		{"gemini", ai.Providers.Gemini.MaxTokens, ai.Providers.Gemini.Temperature},
+		{"claude", ai.Providers.Claude.MaxTokens, ai.Providers.Claude.Temperature},
+		{"openai", ai.Providers.OpenAI.MaxTokens, ai.Providers.OpenAI.Temperature},
+		{"ollama", ai.Providers.Ollama.MaxTokens, ai.Providers.Ollama.Temperature},
+	}
+	for _, p := range providers {
+		if p.maxTokens < 0 {
+			add("ai_summary.providers."+p.name+".max_tokens", "%d must not be negative", p.maxTokens)
+		}
+		if p.temperature < 0 || p.temperature > 2 {
+			add("ai_summary.providers."+p.name+".temperature", "%g must be between 0 and 2", p.temperature)
+		}
+	}
+	if ai.Cache.TTL != "" {
+		if _, err := ai.Cache.GetCacheTTL(); err != nil {
+			add("ai_summary.cache.ttl", "'%s' is not a duration like \"24h\"", ai.Cache.TTL)
+		}
+	}
+
+	// Priority
+	for _, pattern := range c.Priority.VIPPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			add("priority.vip_patterns", "'%s' is not a valid regular expression: %v", pattern, err)
+		}
+	}
+
+	// OTP
+	if c.OTP.ExpiryDuration != "" {
+		if _, err := c.OTP.GetOTPExpiryDuration(); err != nil {
+			add("otp.expiry_duration", "'%s' is not a duration like \"5m\"", c.OTP.ExpiryDuration)
+		}
+	}
+	if c.OTP.ConfidenceThreshold < 0 || c.OTP.ConfidenceThreshold > 1 {
+		add("otp.confidence_threshold", "%g must be between 0 and 1", c.OTP.ConfidenceThreshold)
+	}
+	if c.OTP.MaxCodes < 0 {
+		add("otp.max_codes", "%d must not be negative", c.OTP.MaxCodes)
+	}
+	for sender, value := range c.OTP.ExpiryOverrides {
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			add("otp.expiry_overrides."+sender, "'%s' is not a positive duration like \"15m\"", value)
+		}
+	}
+	if c.OTP.Clipboard.ClearAfter != "" {
+		if _, err := c.OTP.Clipboard.GetClearAfterDuration(); err != nil {
+			add("otp.clipboard.clear_after", "'%s' is not a duration like \"30s\"", c.OTP.Clipboard.ClearAfter)
+		}
+	}
+
+	// Accounts
+	for _, t := range c.Accounts.DetectTypes {
+		if !oneOf(t, "trial", "paid", "free", "cancellation") {
+			add("accounts.detect_types", "'%s' must be trial, paid, free or cancellation", t)
+		}
+	}
+	if mc := c.Accounts.Detection.MinConfidence; mc < 0 || mc > 1 {
+		add("accounts.detection.min_confidence", "%g must be between 0 and 1", mc)
+	}
+
+	// Notifications
+	n := c.Notifications
+	if n.Mobile.Priority != 0 && (n.Mobile.Priority < 1 || n.Mobile.Priority > 5) {
+		add("notifications.mobile.priority", "%d must be between 1 and 5", n.Mobile.Priority)
+	}
+	if _, err := n.Mobile.GetServer(); err != nil {
+		add("notifications.mobile.server", "%v", err)
+	}
+	if !oneOf(n.WeekendMode, "", "normal", "quiet", "disabled") {
+		add("notifications.weekend_mode", "'%s' must be normal, quiet or disabled", n.WeekendMode)
+	}
+	for _, qh := range []struct{ key, value string }{
+		{"notifications.quiet_hours.start", n.QuietHours.Start},
+		{"notifications.quiet_hours.end", n.QuietHours.End},
+	} {
+		if qh.value == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", strings.TrimSpace(qh.value)); err != nil {
+			add(qh.key, "'%s' is not a time like \"22:00\"", qh.value)
+		}
+	}
+	for i, hook := range n.Webhooks {
+		if hook.URL != "" && !strings.HasPrefix(hook.URL, "http://") && !strings.HasPrefix(hook.URL, "https://") {
+			add(fmt.Sprintf("notifications.webhooks[%d].url", i), "'%s' must start with http:// or https://", hook.URL)
+		}
+	}
+
+	return issues
+}
+
+// oneOf reports whether value (case-insensitive) is one of the allowed values
+func oneOf(value string, allowed ...string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package appconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseStrict tests unknown key detection and range checks
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		wantIssues []string // "key (line N)" prefixes, in order
+	}{
+		{
+			name: "Valid config",
+			yaml: `schema_version: 1
+monitoring:
+  polling_interval: 45
+ai_summary:
+  provider: claude
+  providers:
+    claude:
+      temperature: 0.3
+accounts:
+  categories:
+    streaming: [netflix]
+`,
+		},
+		{
+			name: "Typo'd keys",
+			yaml: `monitoring:
+  poling_interval: 30
+notifications:
+  quiet_hours:
+    strat: "22:00"
+`,
+			wantIssues: []string{"monitoring.poling_interval (line 2)", "notifications.quiet_hours.strat (line 5)"},
+		},
+		{
+			name: "Unknown key inside a list item",
+			yaml: `notifications:
+  webhooks:
+    - name: slack
+      urll: https://hooks.example.com
+`,
+			wantIssues: []string{"notifications.webhooks[0].urll (line 4)"},
+		},
+		{
+			name: "Out-of-range values",
+			yaml: `monitoring:
+  polling_interval: 5
+ai_summary:
+  providers:
+    openai:
+      temperature: 2.5
+notifications:
+  weekend_mode: off
+`,
+			wantIssues: []string{"monitoring.polling_interval", "ai_summary.providers.openai.temperature", "notifications.weekend_mode"},
+		},
+		{
+			name: "Bad OTP expiry override",
+			yaml: `otp:
+  expiry_overrides:
+    github.com: 15m
+    mybank.com: soon
+`,
+			wantIssues: []string{"otp.expiry_overrides.mybank.com"},
+		},
+		{
+			name: "Bad VIP pattern",
+			yaml: `priority:
+  vip_patterns:
+    - '^ops-.*@company\.com$'
+    - '(unclosed'
+`,
+			wantIssues: []string{"priority.vip_patterns"},
+		},
+		{
+			name:       "Negative summary length",
+			yaml:       "ai_summary:\n  max_summary_length: -1\n",
+			wantIssues: []string{"ai_summary.max_summary_length"},
+		},
+		{
+			name:       "Newer schema",
+			yaml:       "schema_version: 99\n",
+			wantIssues: []string{"schema_version"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, issues, err := parseStrict([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("parseStrict() error = %v", err)
+			}
+			if len(issues) != len(tt.wantIssues) {
+				t.Fatalf("parseStrict() issues = %v, want %d", issues, len(tt.wantIssues))
+			}
+			for i, want := range tt.wantIssues {
+				if !strings.HasPrefix(issues[i].String(), want+":") {
+					t.Errorf("issue %d = %q, want prefix %q", i, issues[i], want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseStrictSyntaxError tests that malformed YAML is an error, not an issue
+func TestParseStrictSyntaxError(t *testing.T) {
+	if _, _, err := parseStrict([]byte("monitoring:\n  polling_interval: [45\n")); err == nil {
+		t.Error("parseStrict() with broken YAML should return an error")
+	}
+}
+
+// TestDefaultConfigValid tests that the defaults pass validation
+func TestDefaultConfigValid(t *testing.T) {
+	if issues := DefaultConfig().Validate(); len(issues) > 0 {
+		t.Errorf("DefaultConfig().Validate() = %v, want no issues", issues)
+	}
+}
@@ -3,8 +3,11 @@ package filter
 import (
 	"fmt"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/datateamsix/email-sentinel/internal/config"
+	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
 // LoadConfig loads the config or returns default
@@ -34,6 +37,13 @@ func AddFilter(f Filter) error {
 		return err
 	}
 
+	if err := ValidateName(f.Name); err != nil {
+		return err
+	}
+	if err := ValidatePatterns(f); err != nil {
+		return err
+	}
+
 	// Check for duplicate name
 	for _, existing := range cfg.Filters {
 		if strings.EqualFold(existing.Name, f.Name) {
@@ -56,6 +66,13 @@ func UpdateFilter(index int, updated Filter) error {
 		return fmt.Errorf("filter index out of range")
 	}
 
+	if err := ValidateName(updated.Name); err != nil {
+		return err
+	}
+	if err := ValidatePatterns(updated); err != nil {
+		return err
+	}
+
 	// Check for duplicate name (excluding current filter)
 	for i, existing := range cfg.Filters {
 		if i != index && strings.EqualFold(existing.Name, updated.Name) {
@@ -67,6 +84,19 @@ func UpdateFilter(index int, updated Filter) error {
 	return SaveConfig(cfg)
 }
 
+// ValidateName rejects an empty filter name or one containing storage.FilterNameSeparator
+// Alerts store every matched filter name joined by the separator, so such a name
+// would be split into two filters in history, stats and renames.
+func ValidateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("filter name is empty")
+	}
+	if strings.Contains(name, storage.FilterNameSeparator) {
+		return fmt.Errorf("filter name '%s' must not contain '%s'", name, storage.FilterNameSeparator)
+	}
+	return nil
+}
+
 // RemoveFilter removes a filter by name
 func RemoveFilter(name string) error {
 	cfg, err := LoadConfig()
@@ -92,6 +122,117 @@ func RemoveFilter(name string) error {
 	return SaveConfig(cfg)
 }
 
+// RenameFilter renames a filter, rejecting a name another filter already uses
+// The old name is matched case-insensitively; returns the name as it was stored
+// and the new name as it is now stored.
+func RenameFilter(oldName, newName string) (string, string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", "", err
+	}
+
+	previous, stored, err := renameFilter(cfg.Filters, oldName, newName)
+	if err != nil {
+		return "", "", err
+	}
+
+	return previous, stored, SaveConfig(cfg)
+}
+
+// renameFilter sets the named filter's Name after checking the new one is valid and free
+func renameFilter(filters []Filter, oldName, newName string) (string, string, error) {
+	newName = strings.TrimSpace(newName)
+	if err := ValidateName(newName); err != nil {
+		return "", "", err
+	}
+
+	index := -1
+	for i := range filters {
+		if strings.EqualFold(filters[i].Name, oldName) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return "", "", fmt.Errorf("filter '%s' not found", oldName)
+	}
+
+	// Changing only the case of a filter's own name is allowed
+	for i, existing := range filters {
+		if i != index && strings.EqualFold(existing.Name, newName) {
+			return "", "", fmt.Errorf("filter '%s' already exists", existing.Name)
+		}
+	}
+
+	previous := filters[index].Name
+	if previous == newName {
+		return "", "", fmt.Errorf("filter is already named '%s'", previous)
+	}
+
+	filters[index].Name = newName
+	return previous, newName, nil
+}
+
+// SetFilterEnabled enables or disables a filter by name without removing it
+func SetFilterEnabled(name string, enabled bool) error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	for i := range cfg.Filters {
+		if strings.EqualFold(cfg.Filters[i].Name, name) {
+			if enabled {
+				cfg.Filters[i].Enabled = nil // enabled is the default, keep the YAML clean
+			} else {
+				disabled := false
+				cfg.Filters[i].Enabled = &disabled
+			}
+			return SaveConfig(cfg)
+		}
+	}
+
+	return fmt.Errorf("filter '%s' not found", name)
+}
+
+// MoveFilter moves a filter one position up (offset -1) or down (offset +1) in config order
+// Order only matters with first_match_wins routing. Returns the filter's new index.
+func MoveFilter(name string, offset int) (int, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return 0, err
+	}
+
+	index, err := moveFilter(cfg.Filters, name, offset)
+	if err != nil {
+		return 0, err
+	}
+
+	return index, SaveConfig(cfg)
+}
+
+// moveFilter swaps the named filter with its neighbour offset positions away
+func moveFilter(filters []Filter, name string, offset int) (int, error) {
+	for i := range filters {
+		if !strings.EqualFold(filters[i].Name, name) {
+			continue
+		}
+
+		target := i + offset
+		if target < 0 {
+			return 0, fmt.Errorf("filter '%s' is already first", filters[i].Name)
+		}
+		if target >= len(filters) {
+			return 0, fmt.Errorf("filter '%s' is already last", filters[i].Name)
+		}
+
+		filters[i], filters[target] = filters[target], filters[i]
+		return target, nil
+	}
+
+	return 0, fmt.Errorf("filter '%s' not found", name)
+}
+
 // ListFilters returns all filters
 func ListFilters() ([]Filter, error) {
 	cfg, err := LoadConfig()
@@ -103,61 +244,117 @@ func ListFilters() ([]Filter, error) {
 
 // MatchesFilter checks if an email matches a given filter
 func MatchesFilter(f Filter, fromAddress string, subject string) bool {
+	return MatchesFilterWithBody(f, fromAddress, subject, "")
+}
+
+// MatchesFilterWithBody checks if an email matches a given filter, including body patterns
+func MatchesFilterWithBody(f Filter, fromAddress string, subject string, body string) bool {
 	fromAddress = strings.ToLower(fromAddress)
 	subject = strings.ToLower(subject)
+	body = strings.ToLower(body)
 
-	fromMatched := false
-	subjectMatched := false
+	// Each pattern group only participates when it has patterns
+	type condition struct {
+		patterns []string
+		text     string
+		match    func(f Filter, text string, pattern string) bool
+	}
+	conditions := []condition{
+		{f.From, fromAddress, matchFromPattern},
+		{f.Subject, subject, matchPattern},
+		{f.Body, body, matchPattern},
+	}
 
-	// Check from patterns
-	if len(f.From) == 0 {
-		fromMatched = true // No from filter means auto-match
-	} else {
-		for _, pattern := range f.From {
-			if strings.Contains(fromAddress, strings.ToLower(pattern)) {
-				fromMatched = true
+	active := 0
+	matched := 0
+	for _, c := range conditions {
+		if len(c.patterns) == 0 {
+			continue // No patterns means this group doesn't participate
+		}
+		active++
+		for _, pattern := range c.patterns {
+			if c.match(f, c.text, pattern) {
+				matched++
 				break
 			}
 		}
 	}
 
-	// Check subject patterns
-	if len(f.Subject) == 0 {
-		subjectMatched = true // No subject filter means auto-match
-	} else {
-		for _, pattern := range f.Subject {
-			if strings.Contains(subject, strings.ToLower(pattern)) {
-				subjectMatched = true
-				break
-			}
-		}
+	if active == 0 {
+		return false
 	}
 
 	// Apply match mode
 	if f.Match == "all" {
-		// AND logic - both must match (considering empty patterns)
-		if len(f.From) > 0 && len(f.Subject) > 0 {
-			return fromMatched && subjectMatched
-		}
-		// If only one type of filter exists, just check that one
-		if len(f.From) > 0 {
-			return fromMatched
+		// AND logic - every group with patterns must match
+		return matched == active
+	}
+
+	// "any" (OR) logic - either can match
+	return matched > 0
+}
+
+// MatchesFilterExplain is MatchesEmail plus a human-readable reason
+// It names the pattern that hit in each group, or the group that failed under "all" mode.
+// Disabled filters are reported too. Slower than MatchesEmail (it formats every group),
+// so it's only used for --verbose output.
+func MatchesFilterExplain(f Filter, fromAddress string, subject string, body string, hasAttachment bool) (bool, string) {
+	if !f.IsEnabled() {
+		return false, "filter is disabled"
+	}
+	if !MatchesAttachment(f, hasAttachment) {
+		if *f.HasAttachment {
+			return false, "has_attachment: email has no attachment"
 		}
-		if len(f.Subject) > 0 {
-			return subjectMatched
+		return false, "has_attachment: false, but email has an attachment"
+	}
+	if !hasPatterns(f) {
+		if f.GmailQuery != "" || f.HasAttachment != nil {
+			return true, "no patterns: matches whatever its gmail_query/has_attachment lets through"
 		}
-		return false
+		return false, "filter has no patterns"
 	}
 
-	// "any" (OR) logic - either can match
-	if len(f.From) > 0 && fromMatched {
-		return true
+	groups := []struct {
+		name     string
+		patterns []string
+		text     string
+		match    func(f Filter, text string, pattern string) bool
+	}{
+		{"from", f.From, strings.ToLower(fromAddress), matchFromPattern},
+		{"subject", f.Subject, strings.ToLower(subject), matchPattern},
+		{"body", f.Body, strings.ToLower(body), matchPattern},
 	}
-	if len(f.Subject) > 0 && subjectMatched {
-		return true
+
+	var hits, misses []string
+	for _, g := range groups {
+		if len(g.patterns) == 0 {
+			continue
+		}
+		hit := ""
+		for _, pattern := range g.patterns {
+			if g.match(f, g.text, pattern) {
+				hit = pattern
+				break
+			}
+		}
+		if hit != "" {
+			hits = append(hits, fmt.Sprintf("%s matched %q", g.name, hit))
+		} else {
+			misses = append(misses, fmt.Sprintf("%s matched none of %q", g.name, g.patterns))
+		}
 	}
 
-	return false
+	if f.Match == "all" {
+		if len(misses) > 0 {
+			return false, strings.Join(misses, "; ") + " (match: all)"
+		}
+		return true, strings.Join(hits, "; ") + " (match: all)"
+	}
+	if len(hits) > 0 {
+		return true, strings.Join(hits, "; ")
+	}
+	return false, strings.Join(misses, "; ")
 }
 
 // CheckAllFilters checks an email against all filters and returns matching filter names
@@ -169,6 +366,9 @@ func CheckAllFilters(fromAddress string, subject string) ([]string, error) {
 
 	var matchedFilters []string
 	for _, f := range filters {
+		if !f.IsEnabled() {
+			continue
+		}
 		if MatchesFilter(f, fromAddress, subject) {
 			matchedFilters = append(matchedFilters, f.Name)
 		}
@@ -177,8 +377,25 @@ func CheckAllFilters(fromAddress string, subject string) ([]string, error) {
 	return matchedFilters, nil
 }
 
+// MatchesAttachment checks a filter's has_attachment condition, which is ANDed with its patterns
+func MatchesAttachment(f Filter, hasAttachment bool) bool {
+	return f.HasAttachment == nil || *f.HasAttachment == hasAttachment
+}
+
+// MatchesEmail reports whether an email matches a filter's patterns and has_attachment condition
+// A filter with only a gmail_query or has_attachment matches whatever reaches it.
+func MatchesEmail(f Filter, fromAddress string, subject string, body string, hasAttachment bool) bool {
+	if !MatchesAttachment(f, hasAttachment) {
+		return false
+	}
+	if !hasPatterns(f) {
+		return f.GmailQuery != "" || f.HasAttachment != nil
+	}
+	return MatchesFilterWithBody(f, fromAddress, subject, body)
+}
+
 // CheckAllFiltersWithMetadata checks an email against all filters and returns detailed match results
-func CheckAllFiltersWithMetadata(fromAddress string, subject string) ([]MatchResult, error) {
+func CheckAllFiltersWithMetadata(fromAddress string, subject string, body string, hasAttachment bool) ([]MatchResult, error) {
 	filters, err := ListFilters()
 	if err != nil {
 		return nil, err
@@ -186,15 +403,24 @@ func CheckAllFiltersWithMetadata(fromAddress string, subject string) ([]MatchRes
 
 	var matchedFilters []MatchResult
 	for _, f := range filters {
-		if MatchesFilter(f, fromAddress, subject) {
+		if !f.IsEnabled() {
+			continue
+		}
+		if MatchesEmail(f, fromAddress, subject, body, hasAttachment) {
 			scope := f.GmailScope
 			if scope == "" {
 				scope = "inbox" // Default scope
 			}
 			matchedFilters = append(matchedFilters, MatchResult{
-				Name:       f.Name,
-				Labels:     f.Labels,
-				GmailScope: scope,
+				Name:            f.Name,
+				Labels:          f.Labels,
+				GmailScope:      scope,
+				GmailQuery:      f.GmailQuery,
+				NtfyTopic:       f.NtfyTopic,
+				ApplyGmailLabel: f.ApplyGmailLabel,
+				MarkRead:        f.MarkRead,
+				Digest:          f.Digest,
+				ForcePriority:   f.ForcePriority,
 			})
 		}
 	}
@@ -202,7 +428,129 @@ func CheckAllFiltersWithMetadata(fromAddress string, subject string) ([]MatchRes
 	return matchedFilters, nil
 }
 
+// hasPatterns reports whether a filter has any from, subject or body patterns
+func hasPatterns(f Filter) bool {
+	return len(f.From) > 0 || len(f.Subject) > 0 || len(f.Body) > 0
+}
+
+// KeepFetched drops gmail_query matches for messages their query didn't return
+// Gmail evaluates the operators server-side, so a message fetched only by another
+// filter's query hasn't been checked against them. fetchedBy holds the queries that
+// returned the message; override is the global --search scope, if any.
+func KeepFetched(matches []MatchResult, fetchedBy map[string]bool, override string) []MatchResult {
+	kept := matches[:0]
+	for _, m := range matches {
+		if m.GmailQuery == "" || fetchedBy[SearchQuery(m.GmailScope, m.GmailQuery, override)] {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// FirstMatch keeps only the first match in config order (filters.first_match_wins)
+// Apply it after KeepFetched, so a gmail_query filter that didn't fetch the message
+// can't shadow the filters below it.
+func FirstMatch(matches []MatchResult) []MatchResult {
+	if len(matches) > 1 {
+		return matches[:1]
+	}
+	return matches
+}
+
+// ForcedPriority returns the priority forced by the matched filters, if any
+// When filters disagree the highest forced priority wins, so an important match is never silenced.
+func ForcedPriority(matches []MatchResult) (int, bool) {
+	forced, ok := 0, false
+	for _, m := range matches {
+		if m.ForcePriority != nil && (!ok || *m.ForcePriority > forced) {
+			forced, ok = *m.ForcePriority, true
+		}
+	}
+	return forced, ok
+}
+
+// ValidateForcePriority checks that a filter's priority override is 0, 1 or 2
+func ValidateForcePriority(priority *int) error {
+	if priority != nil && (*priority < 0 || *priority > 2) {
+		return fmt.Errorf("invalid force_priority %d (use 0 for normal, 1 for high or 2 for critical)", *priority)
+	}
+	return nil
+}
+
+// Digest intervals outside this range are rejected
+const (
+	MinDigestInterval = time.Minute
+	MaxDigestInterval = 24 * time.Hour
+)
+
+// DigestInterval returns how long to batch notifications for an email's matches
+// An email is only batched when every matched filter has a digest; the shortest one
+// wins so no filter waits longer than it asked for. ok is false for immediate notification.
+func DigestInterval(matches []MatchResult) (time.Duration, bool) {
+	var interval time.Duration
+	for _, m := range matches {
+		d, err := time.ParseDuration(strings.TrimSpace(m.Digest))
+		if m.Digest == "" || err != nil || d <= 0 {
+			return 0, false
+		}
+		if interval == 0 || d < interval {
+			interval = d
+		}
+	}
+	return interval, interval > 0
+}
+
+// ValidateDigest checks that a filter's digest is a duration between one minute and a day
+func ValidateDigest(digest string) error {
+	digest = strings.TrimSpace(digest)
+	if digest == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(digest)
+	if err != nil {
+		return fmt.Errorf("invalid digest '%s' (use a duration like 5m or 1h)", digest)
+	}
+	if d < MinDigestInterval || d > MaxDigestInterval {
+		return fmt.Errorf("invalid digest '%s' (must be between %v and %v)", digest, MinDigestInterval, MaxDigestInterval)
+	}
+	return nil
+}
+
+// GmailScopes are the documented single scopes; combine them with "+" (e.g. "primary+social")
+var GmailScopes = []string{
+	"inbox", "all", "primary", "social", "promotions",
+	"updates", "forums", "all-except-trash", "spam-only",
+}
+
+// IsValidGmailScope reports whether scope is a documented scope or a "+" combination of them
+func IsValidGmailScope(scope string) bool {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	if scope == "" {
+		return false
+	}
+
+	for _, part := range strings.Split(scope, "+") {
+		if !isSingleGmailScope(strings.TrimSpace(part)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSingleGmailScope reports whether scope is one of GmailScopes
+func isSingleGmailScope(scope string) bool {
+	for _, valid := range GmailScopes {
+		if scope == valid {
+			return true
+		}
+	}
+	return false
+}
+
 // BuildGmailSearchQuery converts a Gmail scope to a search query string
+// This is the one place scopes are translated, for filters and the start --search override alike.
+// An empty scope means inbox; an unknown one falls back to inbox (check IsValidGmailScope first to warn).
 func BuildGmailSearchQuery(scope string) string {
 	scope = strings.ToLower(strings.TrimSpace(scope))
 	if scope == "" {
@@ -215,6 +563,9 @@ func BuildGmailSearchQuery(scope string) string {
 		queries := make([]string, 0, len(categories))
 		for _, cat := range categories {
 			cat = strings.TrimSpace(cat)
+			if cat == "all" {
+				return "" // Everything already covers the other parts
+			}
 			if query := buildSingleScopeQuery(cat); query != "" {
 				queries = append(queries, fmt.Sprintf("(%s)", query))
 			}
@@ -256,26 +607,85 @@ func buildSingleScopeQuery(scope string) string {
 	}
 }
 
-// GetAllUniqueScopes returns all unique Gmail scopes from all filters
-func GetAllUniqueScopes() ([]string, error) {
+// SearchQuery returns the Gmail query that fetches messages for a filter
+// The gmail_query is ANDed with the scope query; a non-empty override scope (the
+// start --search flag) replaces the filter's own scope.
+func SearchQuery(scope, gmailQuery, override string) string {
+	if override != "" {
+		scope = override
+	}
+	scopeQuery := BuildGmailSearchQuery(scope)
+
+	gmailQuery = strings.Join(strings.Fields(gmailQuery), " ")
+	switch {
+	case gmailQuery == "":
+		return scopeQuery
+	case scopeQuery == "":
+		return fmt.Sprintf("(%s)", gmailQuery)
+	default:
+		// Parentheses keep an OR on either side from escaping the AND
+		return fmt.Sprintf("(%s) (%s)", scopeQuery, gmailQuery)
+	}
+}
+
+// ValidateGmailQuery checks that a gmail_query is a single line with balanced quotes and parentheses
+// Gmail doesn't report syntax errors, so an unbalanced query would silently match the wrong messages.
+func ValidateGmailQuery(query string) error {
+	depth := 0
+	inQuote := false
+	for _, r := range query {
+		switch {
+		case unicode.IsControl(r):
+			return fmt.Errorf("invalid gmail_query '%s': must be a single line without control characters", query)
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+			continue
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("invalid gmail_query '%s': unmatched ')'", query)
+			}
+		}
+	}
+
+	if inQuote {
+		return fmt.Errorf("invalid gmail_query '%s': unterminated quote", query)
+	}
+	if depth > 0 {
+		return fmt.Errorf("invalid gmail_query '%s': unmatched '('", query)
+	}
+	return nil
+}
+
+// GetAllSearchQueries returns the unique Gmail queries needed to fetch messages for all enabled filters
+// A non-empty override scope (the start --search flag) replaces every filter's scope.
+func GetAllSearchQueries(override string) ([]string, error) {
 	filters, err := ListFilters()
 	if err != nil {
 		return nil, err
 	}
 
-	scopeMap := make(map[string]bool)
-	for _, f := range filters {
-		scope := f.GmailScope
-		if scope == "" {
-			scope = "inbox"
-		}
-		scopeMap[scope] = true
+	queryMap := make(map[string]bool)
+	queries := []string{}
+	if override != "" {
+		query := BuildGmailSearchQuery(override)
+		queryMap[query] = true
+		queries = append(queries, query)
 	}
 
-	scopes := make([]string, 0, len(scopeMap))
-	for scope := range scopeMap {
-		scopes = append(scopes, scope)
+	for _, f := range filters {
+		if !f.IsEnabled() {
+			continue
+		}
+		query := SearchQuery(f.GmailScope, f.GmailQuery, override)
+		if !queryMap[query] {
+			queryMap[query] = true
+			queries = append(queries, query)
+		}
 	}
 
-	return scopes, nil
+	return queries, nil
 }
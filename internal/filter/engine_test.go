@@ -0,0 +1,598 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestBuildGmailSearchQuery tests every documented scope and combined scopes
+func TestBuildGmailSearchQuery(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  string
+	}{
+		{"inbox", "in:inbox"},
+		{"all", ""},
+		{"primary", "category:primary"},
+		{"social", "category:social"},
+		{"promotions", "category:promotions"},
+		{"updates", "category:updates"},
+		{"forums", "category:forums"},
+		{"all-except-trash", "-in:trash"},
+		{"spam-only", "in:spam"},
+		{"", "in:inbox"},
+		{" Spam-Only ", "in:spam"},
+		{"primary+social", "(category:primary) OR (category:social)"},
+		{"inbox+spam-only", "(in:inbox) OR (in:spam)"},
+		{"social+all", ""},
+		{"bogus", "in:inbox"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			if got := BuildGmailSearchQuery(tt.scope); got != tt.want {
+				t.Errorf("BuildGmailSearchQuery(%q) = %q, want %q", tt.scope, got, tt.want)
+			}
+		})
+	}
+
+	// Every documented scope must be accepted and translated
+	for _, scope := range GmailScopes {
+		if !IsValidGmailScope(scope) {
+			t.Errorf("IsValidGmailScope(%q) = false, want true", scope)
+		}
+	}
+}
+
+// TestIsValidGmailScope tests scope validation, including combined scopes
+func TestIsValidGmailScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{"spam-only", true},
+		{"ALL", true},
+		{"primary+social", true},
+		{"primary + updates", true},
+		{"", false},
+		{"spam", false},
+		{"primary+bogus", false},
+		{"primary+", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			if got := IsValidGmailScope(tt.scope); got != tt.want {
+				t.Errorf("IsValidGmailScope(%q) = %v, want %v", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSearchQueryOverride tests that a --search scope replaces the filter's scope
+func TestSearchQueryOverride(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      string
+		gmailQuery string
+		override   string
+		want       string
+	}{
+		{"filter scope", "spam-only", "", "", "in:spam"},
+		{"override scope", "inbox", "", "spam-only", "in:spam"},
+		{"override all", "inbox", "", "all", ""},
+		{"override all with query", "inbox", "has:attachment", "all", "(has:attachment)"},
+		{"query ANDed with scope", "promotions", "from:shop.com", "", "(category:promotions) (from:shop.com)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchQuery(tt.scope, tt.gmailQuery, tt.override); got != tt.want {
+				t.Errorf("SearchQuery(%q, %q, %q) = %q, want %q", tt.scope, tt.gmailQuery, tt.override, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateGmailQuery tests that unbalanced or multi-line gmail_query values are rejected
+func TestValidateGmailQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"operators", "from:shop.com has:attachment", false},
+		{"nested parentheses", "(from:a.com OR (from:b.com -label:x))", false},
+		{"parenthesis inside quotes", `subject:"Re: (draft"`, false},
+		{"unmatched open", "(from:a.com", true},
+		{"unmatched close", "from:a.com)", true},
+		{"close before open", ")from:a.com(", true},
+		{"unterminated quote", `subject:"invoice`, true},
+		{"newline", "from:a.com\nfrom:b.com", true},
+		{"tab", "from:a.com\tis:unread", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateGmailQuery(tt.query); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateGmailQuery(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestKeepFetched tests that gmail_query matches are kept only when their own query fetched the message
+func TestKeepFetched(t *testing.T) {
+	matches := []MatchResult{
+		{Name: "Boss", GmailScope: "inbox"},
+		{Name: "Receipts", GmailScope: "inbox", GmailQuery: "has:attachment"},
+		{Name: "Shop", GmailScope: "promotions", GmailQuery: "from:shop.com"},
+	}
+
+	tests := []struct {
+		name      string
+		fetchedBy []string
+		override  string
+		want      []string
+	}{
+		{"only the scope query", []string{"in:inbox"}, "", []string{"Boss"}},
+		{"a filter's own query", []string{"in:inbox", "(in:inbox) (has:attachment)"}, "", []string{"Boss", "Receipts"}},
+		{"query under another scope", []string{"(in:inbox) (from:shop.com)"}, "", []string{"Boss"}},
+		{"override scope", []string{"(in:spam) (from:shop.com)"}, "spam-only", []string{"Boss", "Shop"}},
+		{"nothing fetched", nil, "", []string{"Boss"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetchedBy := make(map[string]bool)
+			for _, q := range tt.fetchedBy {
+				fetchedBy[q] = true
+			}
+
+			// KeepFetched filters in place, so each case gets its own copy
+			input := append([]MatchResult(nil), matches...)
+			var got []string
+			for _, m := range KeepFetched(input, fetchedBy, tt.override) {
+				got = append(got, m.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KeepFetched() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetAllSearchQueries tests that each enabled filter's query is fetched once
+func TestGetAllSearchQueries(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("APPDATA", home)
+
+	disabled := false
+	cfg := DefaultConfig()
+	cfg.Filters = []Filter{
+		{Name: "Boss", GmailScope: "inbox"},
+		{Name: "Work", GmailScope: "inbox"},
+		{Name: "Receipts", GmailScope: "inbox", GmailQuery: "has:attachment"},
+		{Name: "Deals", GmailScope: "promotions"},
+		{Name: "Paused", GmailScope: "spam-only", Enabled: &disabled},
+	}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		override string
+		want     []string
+	}{
+		{"filter scopes", "", []string{"in:inbox", "(in:inbox) (has:attachment)", "category:promotions"}},
+		{"override replaces scopes", "spam-only", []string{"in:spam", "(in:spam) (has:attachment)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAllSearchQueries(tt.override)
+			if err != nil {
+				t.Fatalf("GetAllSearchQueries() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAllSearchQueries(%q) = %q, want %q", tt.override, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMoveFilter tests reordering filters for first-match routing
+func TestMoveFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		offset    int
+		wantIndex int
+		wantOrder []string
+		wantErr   bool
+	}{
+		{"move up", "Work", -1, 0, []string{"Work", "Boss", "Other"}, false},
+		{"move down", "boss", 1, 1, []string{"Work", "Boss", "Other"}, false},
+		{"first can't move up", "Boss", -1, 0, nil, true},
+		{"last can't move down", "Other", 1, 0, nil, true},
+		{"unknown filter", "Nope", 1, 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := []Filter{{Name: "Boss"}, {Name: "Work"}, {Name: "Other"}}
+
+			index, err := moveFilter(filters, tt.filter, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("moveFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var order []string
+			for _, f := range filters {
+				order = append(order, f.Name)
+			}
+			if index != tt.wantIndex || !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("moveFilter() = %d, order %q; want %d, order %q", index, order, tt.wantIndex, tt.wantOrder)
+			}
+		})
+	}
+}
+
+// TestValidateName tests that names alerts would split on the filter name separator are rejected
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"plain name", "Work Email", false},
+		{"comma without space", "Jobs,Remote", false},
+		{"empty", "", true},
+		{"only spaces", "   ", true},
+		{"contains separator", "Bank, Jobs", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateName(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRenameFilter tests renaming by case-insensitive name and the collision and empty-name checks
+func TestRenameFilter(t *testing.T) {
+	tests := []struct {
+		name         string
+		oldName      string
+		newName      string
+		wantPrevious string
+		wantNames    []string
+		wantErr      bool
+	}{
+		{"rename", "Work", "Work Email", "Work", []string{"Boss", "Work Email", "Other"}, false},
+		{"old name is case-insensitive", "boss", " Manager ", "Boss", []string{"Manager", "Work", "Other"}, false},
+		{"change case only", "Work", "WORK", "Work", []string{"Boss", "WORK", "Other"}, false},
+		{"collides with another filter", "Work", "other", "", nil, true},
+		{"same name", "Work", "Work", "", nil, true},
+		{"empty name", "Work", "  ", "", nil, true},
+		{"contains separator", "Work", "Work, Email", "", nil, true},
+		{"unknown filter", "Nope", "New", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := []Filter{{Name: "Boss"}, {Name: "Work"}, {Name: "Other"}}
+
+			previous, stored, err := renameFilter(filters, tt.oldName, tt.newName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renameFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var names []string
+			for _, f := range filters {
+				names = append(names, f.Name)
+			}
+			if previous != tt.wantPrevious || !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("renameFilter() = %q, names %q; want %q, names %q", previous, names, tt.wantPrevious, tt.wantNames)
+			}
+			if stored != strings.TrimSpace(tt.newName) {
+				t.Errorf("renameFilter() stored = %q, want %q", stored, strings.TrimSpace(tt.newName))
+			}
+		})
+	}
+}
+
+// TestFirstMatch tests that first-match routing keeps the earliest match after scope checks
+func TestFirstMatch(t *testing.T) {
+	matches := []MatchResult{
+		{Name: "Receipts", GmailScope: "inbox", GmailQuery: "has:attachment"},
+		{Name: "Boss", GmailScope: "inbox"},
+		{Name: "Work", GmailScope: "inbox"},
+	}
+
+	// The message wasn't fetched by the Receipts query, so Boss is the first real match
+	kept := FirstMatch(KeepFetched(matches, map[string]bool{"in:inbox": true}, ""))
+	if len(kept) != 1 || kept[0].Name != "Boss" {
+		t.Errorf("FirstMatch() = %+v, want only Boss", kept)
+	}
+
+	if got := FirstMatch(nil); len(got) != 0 {
+		t.Errorf("FirstMatch(nil) = %+v, want no matches", got)
+	}
+}
+
+// TestForcedPriority tests that the highest forced priority wins when matched filters disagree
+func TestForcedPriority(t *testing.T) {
+	normal, high, critical := 0, 1, 2
+
+	tests := []struct {
+		name    string
+		matches []MatchResult
+		want    int
+		wantOK  bool
+	}{
+		{"no matches", nil, 0, false},
+		{"no overrides", []MatchResult{{Name: "A"}, {Name: "B"}}, 0, false},
+		{"single override", []MatchResult{{Name: "A"}, {Name: "B", ForcePriority: &high}}, 1, true},
+		{"forced normal", []MatchResult{{Name: "A", ForcePriority: &normal}}, 0, true},
+		{"conflict highest wins", []MatchResult{{Name: "A", ForcePriority: &high}, {Name: "B", ForcePriority: &critical}, {Name: "C", ForcePriority: &normal}}, 2, true},
+		{"conflict order doesn't matter", []MatchResult{{Name: "A", ForcePriority: &normal}, {Name: "B", ForcePriority: &high}}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ForcedPriority(tt.matches)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ForcedPriority() = %d, %v; want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestValidateForcePriority tests that only 0, 1 and 2 are accepted as force_priority
+func TestValidateForcePriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority *int
+		wantErr  bool
+	}{
+		{"unset", nil, false},
+		{"normal", intPtr(0), false},
+		{"high", intPtr(1), false},
+		{"critical", intPtr(2), false},
+		{"negative", intPtr(-1), true},
+		{"too high", intPtr(3), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateForcePriority(tt.priority); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateForcePriority() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// TestMatchesAttachment tests the has_attachment condition
+func TestMatchesAttachment(t *testing.T) {
+	required, excluded := true, false
+
+	tests := []struct {
+		name          string
+		condition     *bool
+		hasAttachment bool
+		want          bool
+	}{
+		{"unset, with attachment", nil, true, true},
+		{"unset, without attachment", nil, false, true},
+		{"required, with attachment", &required, true, true},
+		{"required, without attachment", &required, false, false},
+		{"excluded, with attachment", &excluded, true, false},
+		{"excluded, without attachment", &excluded, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{Name: "Invoices", Subject: []string{"invoice"}, HasAttachment: tt.condition}
+			if got := MatchesAttachment(f, tt.hasAttachment); got != tt.want {
+				t.Errorf("MatchesAttachment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMatchesFilterFromAliases tests that full-address from patterns match plus and Gmail dot aliases
+func TestMatchesFilterFromAliases(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		matchType string
+		from      string
+		want      bool
+	}{
+		{"plus alias", "johndoe@gmail.com", "", "john.doe+news@gmail.com", true},
+		{"dots in pattern", "john.doe@gmail.com", "", "John Doe <johndoe@gmail.com>", true},
+		{"googlemail", "johndoe@gmail.com", "", "john.doe@googlemail.com", true},
+		{"plus alias outside Gmail", "jane@company.com", "", "Jane <jane+billing@company.com>", true},
+		{"dots matter outside Gmail", "jane.doe@company.com", "", "janedoe@company.com", false},
+		{"different Gmail user", "johndoe@gmail.com", "", "john.doe2@gmail.com", false},
+		{"domain pattern unchanged", "gmail.com", "", "john.doe+news@gmail.com", true},
+		{"display name pattern unchanged", "John Doe", "", "John Doe <johndoe@gmail.com>", true},
+		{"regex isn't normalized", `^johndoe@gmail\.com$`, MatchTypeRegex, "john.doe@gmail.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{Name: "Sender", From: []string{tt.pattern}, Match: "any", MatchType: tt.matchType}
+			if got := MatchesFilter(f, tt.from, ""); got != tt.want {
+				t.Errorf("MatchesFilter(%q, %q) = %v, want %v", tt.pattern, tt.from, got, tt.want)
+			}
+			if got, reason := MatchesFilterExplain(f, tt.from, "", "", false); got != tt.want {
+				t.Errorf("MatchesFilterExplain(%q, %q) = %v (%s), want %v", tt.pattern, tt.from, got, reason, tt.want)
+			}
+		})
+	}
+}
+
+// TestMatchesFilterExplain tests match reasons and that they agree with MatchesEmail
+func TestMatchesFilterExplain(t *testing.T) {
+	required, disabled := true, false
+
+	tests := []struct {
+		name          string
+		filter        Filter
+		hasAttachment bool
+		want          bool
+		wantReason    string
+	}{
+		{
+			name:       "any: from hit",
+			filter:     Filter{From: []string{"boss@company.com"}, Subject: []string{"urgent"}, Match: "any"},
+			want:       true,
+			wantReason: `from matched "boss@company.com"`,
+		},
+		{
+			name:       "any: nothing hit",
+			filter:     Filter{From: []string{"ceo@company.com"}, Subject: []string{"urgent", "asap"}, Match: "any"},
+			wantReason: `from matched none of ["ceo@company.com"]; subject matched none of ["urgent" "asap"]`,
+		},
+		{
+			name:       "all: failing group named",
+			filter:     Filter{From: []string{"company.com"}, Body: []string{"invoice"}, Match: "all"},
+			wantReason: `body matched none of ["invoice"] (match: all)`,
+		},
+		{
+			name:       "all: every group hit",
+			filter:     Filter{From: []string{"company.com"}, Subject: []string{"quarterly"}, Match: "all"},
+			want:       true,
+			wantReason: `from matched "company.com"; subject matched "quarterly" (match: all)`,
+		},
+		{
+			name:       "regex pattern",
+			filter:     Filter{Subject: []string{`report\s+q\d`}, Match: "any", MatchType: MatchTypeRegex},
+			want:       true,
+			wantReason: `subject matched "report\\s+q\\d"`,
+		},
+		{
+			name:       "attachment required but missing",
+			filter:     Filter{Subject: []string{"quarterly"}, HasAttachment: &required},
+			wantReason: "has_attachment: email has no attachment",
+		},
+		{
+			name:          "query-only filter",
+			filter:        Filter{GmailQuery: "larger:5M", HasAttachment: &required},
+			hasAttachment: true,
+			want:          true,
+			wantReason:    "no patterns: matches whatever its gmail_query/has_attachment lets through",
+		},
+		{
+			name:       "disabled",
+			filter:     Filter{From: []string{"boss@company.com"}, Enabled: &disabled},
+			wantReason: "filter is disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, subject, body := "Boss <boss@company.com>", "Quarterly Report Q3", "Numbers attached."
+
+			got, reason := MatchesFilterExplain(tt.filter, from, subject, body, tt.hasAttachment)
+			if got != tt.want || reason != tt.wantReason {
+				t.Errorf("MatchesFilterExplain() = %v, %q, want %v, %q", got, reason, tt.want, tt.wantReason)
+			}
+			if tt.filter.IsEnabled() && got != MatchesEmail(tt.filter, from, subject, body, tt.hasAttachment) {
+				t.Errorf("MatchesFilterExplain() = %v, but MatchesEmail() disagrees", got)
+			}
+		})
+	}
+}
+
+// TestMatchesFilterRegex tests contains and regex match types against the same patterns
+func TestMatchesFilterRegex(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		matchType string
+		from      string
+		want      bool
+	}{
+		{"contains substring", "greenhouse.io", MatchTypeContains, "jobs-42@greenhouse.io", true},
+		{"contains treats pattern literally", "jobs-[0-9]+@greenhouse.io", MatchTypeContains, "jobs-42@greenhouse.io", false},
+		{"regex match", "jobs-[0-9]+@greenhouse.io", MatchTypeRegex, "Greenhouse <jobs-42@greenhouse.io>", true},
+		{"regex is case-insensitive", "jobs-[0-9]+@greenhouse.io", MatchTypeRegex, "JOBS-7@GREENHOUSE.IO", true},
+		{"regex no match", "jobs-[0-9]+@greenhouse.io", MatchTypeRegex, "jobs-team@greenhouse.io", false},
+		{"invalid regex never matches", "jobs-[0-9+", MatchTypeRegex, "jobs-[0-9+@greenhouse.io", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{Name: "Jobs", From: []string{tt.pattern}, Match: "any", MatchType: tt.matchType}
+			if got := MatchesFilter(f, tt.from, ""); got != tt.want {
+				t.Errorf("MatchesFilter(%q, %q) = %v, want %v", tt.pattern, tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidatePatternsRegex tests that regex filters with a pattern that doesn't compile are rejected
+func TestValidatePatternsRegex(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{"valid regex", Filter{From: []string{"jobs-[0-9]+@greenhouse.io"}, MatchType: MatchTypeRegex}, false},
+		{"invalid from regex", Filter{From: []string{"jobs-[0-9+"}, MatchType: MatchTypeRegex}, true},
+		{"invalid subject regex", Filter{Subject: []string{"(offer"}, MatchType: MatchTypeRegex}, true},
+		{"invalid body regex", Filter{Body: []string{"*interview"}, MatchType: MatchTypeRegex}, true},
+		{"contains isn't compiled", Filter{From: []string{"jobs-[0-9+"}, MatchType: MatchTypeContains}, false},
+		{"unknown match type", Filter{From: []string{"jobs"}, MatchType: "glob"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePatterns(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePatterns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCompilePatternCache tests that a pattern is compiled once and then served from the cache
+func TestCompilePatternCache(t *testing.T) {
+	first, err := CompilePattern("jobs-[0-9]+@greenhouse.io")
+	if err != nil {
+		t.Fatalf("CompilePattern() error = %v", err)
+	}
+	second, err := CompilePattern("jobs-[0-9]+@greenhouse.io")
+	if err != nil {
+		t.Fatalf("CompilePattern() error = %v", err)
+	}
+	if first != second {
+		t.Error("CompilePattern() returned a new *regexp.Regexp for a cached pattern")
+	}
+
+	if _, err := CompilePattern("jobs-[0-9+"); err == nil {
+		t.Error("CompilePattern() expected an error for an invalid pattern")
+	}
+	if _, err := CompilePattern("jobs-[0-9+"); err == nil {
+		t.Error("CompilePattern() expected the cached error for an invalid pattern")
+	}
+}
@@ -0,0 +1,107 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Overlap relations between a new filter's pattern and an existing filter's
+const (
+	OverlapDuplicate = "duplicate" // same pattern
+	OverlapBroader   = "broader"   // the existing pattern already matches everything the new one does
+	OverlapNarrower  = "narrower"  // the new pattern also matches everything the existing one does
+)
+
+// Overlap is an existing filter whose patterns cover, or are covered by, a new filter's
+type Overlap struct {
+	Filter   string // existing filter's name
+	Relation string // OverlapDuplicate, OverlapBroader or OverlapNarrower
+	Field    string // "from", "subject" or "body"
+	Existing string // existing filter's pattern
+	New      string // new filter's pattern
+	MatchAll bool   // either filter uses match: all, so its other patterns may still tell them apart
+}
+
+// String describes the overlap for a warning, e.g.
+// `filter 'LinkedIn' already matches: its from pattern "linkedin.com" covers "jobs.linkedin.com"`
+func (o Overlap) String() string {
+	if o.MatchAll {
+		return fmt.Sprintf("filter '%s' has a related %s pattern %q (new: %q), but with match: all their other patterns may still tell them apart", o.Filter, o.Field, o.Existing, o.New)
+	}
+
+	switch o.Relation {
+	case OverlapDuplicate:
+		return fmt.Sprintf("filter '%s' has the same %s pattern %q", o.Filter, o.Field, o.Existing)
+	case OverlapBroader:
+		return fmt.Sprintf("filter '%s' already matches: its %s pattern %q covers %q", o.Filter, o.Field, o.Existing, o.New)
+	default:
+		return fmt.Sprintf("filter '%s' is covered: the new %s pattern %q also matches its %q", o.Filter, o.Field, o.New, o.Existing)
+	}
+}
+
+// DetectOverlap compares a new filter with the enabled filters that search the same scope
+// It's a heuristic for "contains" patterns: one pattern containing another means every email
+// matching the longer one also matches the shorter one. Regex patterns only count when identical.
+// Under match: all one field's patterns don't decide a match, so such overlaps are marked MatchAll.
+// Returns at most one overlap per existing filter, the strongest (duplicate, then broader).
+func DetectOverlap(existing []Filter, f Filter) []Overlap {
+	scope := SearchQuery(f.GmailScope, f.GmailQuery, "")
+
+	var overlaps []Overlap
+	for _, e := range existing {
+		if !e.IsEnabled() || strings.EqualFold(e.Name, f.Name) || SearchQuery(e.GmailScope, e.GmailQuery, "") != scope {
+			continue
+		}
+		if o, ok := strongestOverlap(e, f); ok {
+			o.MatchAll = e.Match == "all" || f.Match == "all"
+			overlaps = append(overlaps, o)
+		}
+	}
+	return overlaps
+}
+
+// strongestOverlap finds the closest relation between any pair of patterns in the same field
+func strongestOverlap(existing, f Filter) (Overlap, bool) {
+	substrings := !isRegexFilter(existing) && !isRegexFilter(f)
+	fields := []struct {
+		name            string
+		existing, added []string
+	}{
+		{"from", existing.From, f.From},
+		{"subject", existing.Subject, f.Subject},
+		{"body", existing.Body, f.Body},
+	}
+
+	rank := map[string]int{OverlapDuplicate: 3, OverlapBroader: 2, OverlapNarrower: 1}
+	var best Overlap
+	for _, field := range fields {
+		for _, ep := range field.existing {
+			for _, np := range field.added {
+				relation := patternRelation(ep, np, substrings)
+				if rank[relation] > rank[best.Relation] {
+					best = Overlap{Filter: existing.Name, Relation: relation, Field: field.name, Existing: ep, New: np}
+				}
+			}
+		}
+	}
+	return best, best.Relation != ""
+}
+
+// patternRelation compares two patterns case-insensitively ("" = unrelated)
+func patternRelation(existing, added string, substrings bool) string {
+	e := strings.ToLower(strings.TrimSpace(existing))
+	n := strings.ToLower(strings.TrimSpace(added))
+	switch {
+	case e == "" || n == "":
+		return ""
+	case e == n:
+		return OverlapDuplicate
+	case !substrings:
+		return ""
+	case strings.Contains(n, e):
+		return OverlapBroader
+	case strings.Contains(e, n):
+		return OverlapNarrower
+	}
+	return ""
+}
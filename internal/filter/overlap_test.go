@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDetectOverlap tests the subset/superset heuristic between a new filter and existing ones
+func TestDetectOverlap(t *testing.T) {
+	paused := false
+	existing := []Filter{
+		{Name: "LinkedIn", From: []string{"linkedin.com"}, Match: "any"},
+		{Name: "Jobs", From: []string{"jobs.example.com"}, Subject: []string{"Interview"}, Match: "any"},
+		{Name: "Spam LinkedIn", From: []string{"linkedin.com"}, GmailScope: "spam-only"},
+		{Name: "Regex", From: []string{`.*@bank\.com`}, MatchType: MatchTypeRegex},
+		{Name: "Invoices", From: []string{"billing.acme.com"}, Subject: []string{"invoice"}, Match: "all"},
+		{Name: "Paused", From: []string{"news.example.org"}, Enabled: &paused},
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []Overlap
+	}{
+		{
+			name:   "Existing pattern is broader",
+			filter: Filter{Name: "LinkedIn Jobs", From: []string{"jobs.linkedin.com"}},
+			expected: []Overlap{
+				{Filter: "LinkedIn", Relation: OverlapBroader, Field: "from", Existing: "linkedin.com", New: "jobs.linkedin.com"},
+			},
+		},
+		{
+			name:   "New pattern is broader",
+			filter: Filter{Name: "Example", From: []string{"example.com"}},
+			expected: []Overlap{
+				{Filter: "Jobs", Relation: OverlapNarrower, Field: "from", Existing: "jobs.example.com", New: "example.com"},
+			},
+		},
+		{
+			name:   "Duplicate wins over a looser relation, case-insensitive",
+			filter: Filter{Name: "Interviews", From: []string{"example.com"}, Subject: []string{"interview"}, GmailScope: "inbox"},
+			expected: []Overlap{
+				{Filter: "Jobs", Relation: OverlapDuplicate, Field: "subject", Existing: "Interview", New: "interview"},
+			},
+		},
+		{
+			name:     "Different fields don't overlap",
+			filter:   Filter{Name: "About LinkedIn", Subject: []string{"linkedin.com"}},
+			expected: nil,
+		},
+		{
+			name:   "Only filters in the same scope",
+			filter: Filter{Name: "Spam", From: []string{"linkedin.com"}, GmailScope: "spam-only"},
+			expected: []Overlap{
+				{Filter: "Spam LinkedIn", Relation: OverlapDuplicate, Field: "from", Existing: "linkedin.com", New: "linkedin.com"},
+			},
+		},
+		{
+			name:     "Regex patterns aren't compared as substrings",
+			filter:   Filter{Name: "Bank", From: []string{`bank\.com`}, MatchType: MatchTypeRegex},
+			expected: nil,
+		},
+		{
+			name:   "Existing match all filter",
+			filter: Filter{Name: "Acme", From: []string{"acme.com"}, Match: "any"},
+			expected: []Overlap{
+				{Filter: "Invoices", Relation: OverlapNarrower, Field: "from", Existing: "billing.acme.com", New: "acme.com", MatchAll: true},
+			},
+		},
+		{
+			name:   "New match all filter",
+			filter: Filter{Name: "LinkedIn Recruiters", From: []string{"linkedin.com"}, Subject: []string{"recruiter"}, Match: "all"},
+			expected: []Overlap{
+				{Filter: "LinkedIn", Relation: OverlapDuplicate, Field: "from", Existing: "linkedin.com", New: "linkedin.com", MatchAll: true},
+			},
+		},
+		{
+			name:     "Disabled filters are skipped",
+			filter:   Filter{Name: "Newsletter", From: []string{"news.example.org"}},
+			expected: nil,
+		},
+		{
+			name:     "A filter doesn't overlap itself",
+			filter:   Filter{Name: "linkedin", From: []string{"linkedin.com"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectOverlap(existing, tt.filter)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("DetectOverlap() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestOverlapString tests the warning text for each relation
+func TestOverlapString(t *testing.T) {
+	tests := []struct {
+		overlap  Overlap
+		expected string
+	}{
+		{
+			Overlap{Filter: "LinkedIn", Relation: OverlapBroader, Field: "from", Existing: "linkedin.com", New: "jobs.linkedin.com"},
+			`filter 'LinkedIn' already matches: its from pattern "linkedin.com" covers "jobs.linkedin.com"`,
+		},
+		{
+			Overlap{Filter: "Jobs", Relation: OverlapNarrower, Field: "from", Existing: "jobs.example.com", New: "example.com"},
+			`filter 'Jobs' is covered: the new from pattern "example.com" also matches its "jobs.example.com"`,
+		},
+		{
+			Overlap{Filter: "Jobs", Relation: OverlapDuplicate, Field: "subject", Existing: "Interview", New: "interview"},
+			`filter 'Jobs' has the same subject pattern "Interview"`,
+		},
+		{
+			Overlap{Filter: "Invoices", Relation: OverlapNarrower, Field: "from", Existing: "billing.acme.com", New: "acme.com", MatchAll: true},
+			`filter 'Invoices' has a related from pattern "billing.acme.com" (new: "acme.com"), but with match: all their other patterns may still tell them apart`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.overlap.Filter+" "+tt.overlap.Relation, func(t *testing.T) {
+			if got := tt.overlap.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+)
+
+// Match types supported by filters
+const (
+	MatchTypeContains = "contains"
+	MatchTypeRegex    = "regex"
+)
+
+// cachedPattern is a compiled pattern, or the error compiling it returned
+type cachedPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+var (
+	regexCache   = make(map[string]cachedPattern)
+	regexCacheMu sync.RWMutex
+)
+
+// CompilePattern returns a cached case-insensitive regex for the pattern,
+// compiling and caching it (or its error) on first use
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	regexCacheMu.RLock()
+	cached, ok := regexCache[pattern]
+	regexCacheMu.RUnlock()
+	if ok {
+		return cached.re, cached.err
+	}
+
+	re, err := regexp.Compile("(?i)" + pattern)
+
+	regexCacheMu.Lock()
+	regexCache[pattern] = cachedPattern{re: re, err: err}
+	regexCacheMu.Unlock()
+
+	return re, err
+}
+
+// isRegexFilter reports whether the filter uses regex matching
+func isRegexFilter(f Filter) bool {
+	return strings.EqualFold(f.MatchType, MatchTypeRegex)
+}
+
+// matchPattern checks a single pattern against text using the filter's match type
+func matchPattern(f Filter, text string, pattern string) bool {
+	if !isRegexFilter(f) {
+		return strings.Contains(strings.ToLower(text), strings.ToLower(pattern))
+	}
+
+	re, err := CompilePattern(pattern)
+	if err != nil {
+		// Invalid patterns are rejected when the filter is added, so this
+		// only happens for hand-edited configs
+		return false
+	}
+	return re.MatchString(text)
+}
+
+// matchFromPattern checks a from pattern against the sender
+// Besides the usual match, a pattern that is a full address matches any alias of
+// it, e.g. "johndoe@gmail.com" matches "John Doe <john.doe+news@gmail.com>".
+func matchFromPattern(f Filter, from string, pattern string) bool {
+	if matchPattern(f, from, pattern) {
+		return true
+	}
+	if isRegexFilter(f) || !isFullAddress(pattern) {
+		return false
+	}
+	return gmail.NormalizeAddress(pattern) == gmail.NormalizeAddress(from)
+}
+
+// isFullAddress reports whether a pattern is a whole address rather than a domain or fragment
+func isFullAddress(pattern string) bool {
+	local, domain, ok := strings.Cut(strings.TrimSpace(pattern), "@")
+	return ok && local != "" && strings.Contains(domain, ".")
+}
+
+// ValidateMatchType checks that the match type is supported
+func ValidateMatchType(matchType string) error {
+	switch strings.ToLower(matchType) {
+	case "", MatchTypeContains, MatchTypeRegex:
+		return nil
+	default:
+		return fmt.Errorf("invalid match type '%s' (expected '%s' or '%s')", matchType, MatchTypeContains, MatchTypeRegex)
+	}
+}
+
+// ValidatePatterns ensures all regex patterns in a filter compile
+// It also rejects an unknown match type, priority override, malformed gmail_query or digest.
+func ValidatePatterns(f Filter) error {
+	if err := ValidateMatchType(f.MatchType); err != nil {
+		return err
+	}
+	if err := ValidateForcePriority(f.ForcePriority); err != nil {
+		return err
+	}
+	if err := ValidateGmailQuery(f.GmailQuery); err != nil {
+		return err
+	}
+	if err := ValidateDigest(f.Digest); err != nil {
+		return err
+	}
+
+	if !isRegexFilter(f) {
+		return nil
+	}
+
+	for _, pattern := range f.From {
+		if _, err := CompilePattern(pattern); err != nil {
+			return fmt.Errorf("invalid from regex '%s': %w", pattern, err)
+		}
+	}
+	for _, pattern := range f.Subject {
+		if _, err := CompilePattern(pattern); err != nil {
+			return fmt.Errorf("invalid subject regex '%s': %w", pattern, err)
+		}
+	}
+	for _, pattern := range f.Body {
+		if _, err := CompilePattern(pattern); err != nil {
+			return fmt.Errorf("invalid body regex '%s': %w", pattern, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ImportResult summarizes the outcome of an import
+type ImportResult struct {
+	Imported int
+	Skipped  []string // Names of filters skipped as duplicates
+}
+
+// ExportFilters writes all configured filters to w as indented JSON
+func ExportFilters(w io.Writer) error {
+	filters, err := ListFilters()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(filters); err != nil {
+		return fmt.Errorf("failed to encode filters: %w", err)
+	}
+
+	return nil
+}
+
+// ImportFilters reads filters from JSON and adds them to the config
+// In replace mode the existing filters are cleared first; otherwise filters
+// whose names already exist are skipped and reported in the result
+func ImportFilters(r io.Reader, replace bool) (*ImportResult, error) {
+	var imported []Filter
+	if err := json.NewDecoder(r).Decode(&imported); err != nil {
+		return nil, fmt.Errorf("failed to parse filters: %w", err)
+	}
+
+	// Validate everything before touching the config
+	for _, f := range imported {
+		if strings.TrimSpace(f.Name) == "" {
+			return nil, fmt.Errorf("filter with empty name in import")
+		}
+		if err := ValidateName(f.Name); err != nil {
+			return nil, err
+		}
+		if err := ValidatePatterns(f); err != nil {
+			return nil, fmt.Errorf("filter '%s': %w", f.Name, err)
+		}
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if replace {
+		cfg.Filters = []Filter{}
+	}
+
+	result := &ImportResult{}
+	for _, f := range imported {
+		if hasFilterNamed(cfg.Filters, f.Name) {
+			result.Skipped = append(result.Skipped, f.Name)
+			continue
+		}
+		cfg.Filters = append(cfg.Filters, f)
+		result.Imported++
+	}
+
+	if err := SaveConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// hasFilterNamed reports whether a filter with the given name exists (case-insensitive)
+func hasFilterNamed(filters []Filter, name string) bool {
+	for _, f := range filters {
+		if strings.EqualFold(f.Name, name) {
+			return true
+		}
+	}
+	return false
+}
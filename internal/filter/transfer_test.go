@@ -0,0 +1,154 @@
+package filter
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setupFilters points the config directory at a temp dir and saves the given filters
+func setupFilters(t *testing.T, filters []Filter) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("APPDATA", home)
+
+	cfg := DefaultConfig()
+	cfg.Filters = filters
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+}
+
+// filterNames returns the names of the configured filters, in order
+func filterNames(t *testing.T) []string {
+	t.Helper()
+	filters, err := ListFilters()
+	if err != nil {
+		t.Fatalf("ListFilters() error = %v", err)
+	}
+	names := make([]string, 0, len(filters))
+	for _, f := range filters {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// TestExportImportRoundTrip tests that exported filters import unchanged into an empty config
+func TestExportImportRoundTrip(t *testing.T) {
+	original := []Filter{
+		{Name: "Work", From: []string{"boss@company.com"}, Match: "any", Labels: []string{"work"}},
+		{Name: "Jobs", From: []string{"jobs-[0-9]+@greenhouse.io"}, Subject: []string{"interview"}, Match: "all", MatchType: MatchTypeRegex},
+	}
+	setupFilters(t, original)
+	want, err := ListFilters()
+	if err != nil {
+		t.Fatalf("ListFilters() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportFilters(&buf); err != nil {
+		t.Fatalf("ExportFilters() error = %v", err)
+	}
+
+	setupFilters(t, nil)
+	result, err := ImportFilters(&buf, false)
+	if err != nil {
+		t.Fatalf("ImportFilters() error = %v", err)
+	}
+	if result.Imported != len(original) || len(result.Skipped) != 0 {
+		t.Errorf("ImportFilters() = %+v, want %d imported and none skipped", result, len(original))
+	}
+
+	got, err := ListFilters()
+	if err != nil {
+		t.Fatalf("ListFilters() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filters after round trip = %+v, want %+v", got, want)
+	}
+}
+
+// TestImportFilters tests merge and replace imports and that invalid imports save nothing
+func TestImportFilters(t *testing.T) {
+	existing := []Filter{
+		{Name: "Work", From: []string{"boss@company.com"}},
+		{Name: "Bank", From: []string{"alerts@bank.com"}},
+	}
+
+	tests := []struct {
+		name         string
+		input        string
+		replace      bool
+		wantErr      bool
+		wantImported int
+		wantSkipped  []string
+		wantNames    []string
+	}{
+		{
+			name:         "Merge skips duplicate names case-insensitively",
+			input:        `[{"name": "work", "from": ["ceo@company.com"]}, {"name": "Deals", "from": ["shop.com"]}]`,
+			wantImported: 1,
+			wantSkipped:  []string{"work"},
+			wantNames:    []string{"Work", "Bank", "Deals"},
+		},
+		{
+			name:         "Merge skips duplicates within the import",
+			input:        `[{"name": "Deals", "from": ["shop.com"]}, {"name": "DEALS", "from": ["store.com"]}]`,
+			wantImported: 1,
+			wantSkipped:  []string{"DEALS"},
+			wantNames:    []string{"Work", "Bank", "Deals"},
+		},
+		{
+			name:         "Replace clears existing filters first",
+			input:        `[{"name": "work", "from": ["ceo@company.com"]}, {"name": "Deals", "from": ["shop.com"]}]`,
+			replace:      true,
+			wantImported: 2,
+			wantNames:    []string{"work", "Deals"},
+		},
+		{
+			name:      "Invalid regex saves nothing",
+			input:     `[{"name": "Deals", "from": ["shop.com"]}, {"name": "Jobs", "from": ["jobs-[0-9+"], "match_type": "regex"}]`,
+			replace:   true,
+			wantErr:   true,
+			wantNames: []string{"Work", "Bank"},
+		},
+		{
+			name:      "Empty name saves nothing",
+			input:     `[{"name": "Deals", "from": ["shop.com"]}, {"name": " ", "from": ["x.com"]}]`,
+			wantErr:   true,
+			wantNames: []string{"Work", "Bank"},
+		},
+		{
+			name:      "Malformed JSON saves nothing",
+			input:     `[{"name": "Deals"`,
+			replace:   true,
+			wantErr:   true,
+			wantNames: []string{"Work", "Bank"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupFilters(t, existing)
+
+			result, err := ImportFilters(strings.NewReader(tt.input), tt.replace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImportFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if result.Imported != tt.wantImported {
+					t.Errorf("Imported = %d, want %d", result.Imported, tt.wantImported)
+				}
+				if !reflect.DeepEqual(result.Skipped, tt.wantSkipped) {
+					t.Errorf("Skipped = %q, want %q", result.Skipped, tt.wantSkipped)
+				}
+			}
+
+			if got := filterNames(t); !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("filters = %q, want %q", got, tt.wantNames)
+			}
+		})
+	}
+}
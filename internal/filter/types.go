@@ -4,20 +4,41 @@ import "time"
 
 // Filter represents an email filter rule
 type Filter struct {
-	Name       string     `yaml:"name"`
-	From       []string   `yaml:"from"`
-	Subject    []string   `yaml:"subject"`
-	Match      string     `yaml:"match"`      // "any" or "all"
-	Labels     []string   `yaml:"labels,omitempty"`     // Categories like "work", "personal", etc.
-	GmailScope string     `yaml:"gmail_scope,omitempty"` // Gmail scope: "inbox", "all", "primary", "social", "promotions", "updates", "forums", etc.
-	ExpiresAt  *time.Time `yaml:"expires_at,omitempty"` // Expiration date (nil = never expires)
+	Name            string     `yaml:"name" json:"name"`
+	From            []string   `yaml:"from" json:"from,omitempty"`
+	Subject         []string   `yaml:"subject" json:"subject,omitempty"`
+	Body            []string   `yaml:"body,omitempty" json:"body,omitempty"`                           // Patterns matched against the message body text
+	Match           string     `yaml:"match" json:"match"`                                             // "any" or "all"
+	MatchType       string     `yaml:"match_type,omitempty" json:"match_type,omitempty"`               // "contains" (default) or "regex"
+	Labels          []string   `yaml:"labels,omitempty" json:"labels,omitempty"`                       // Categories like "work", "personal", etc.
+	GmailScope      string     `yaml:"gmail_scope,omitempty" json:"gmail_scope,omitempty"`             // Gmail scope: "inbox", "all", "primary", "social", "promotions", "updates", "forums", etc.
+	GmailQuery      string     `yaml:"gmail_query,omitempty" json:"gmail_query,omitempty"`             // Raw Gmail search operators ANDed with the scope, e.g. "has:attachment larger:5M"
+	NtfyTopic       string     `yaml:"ntfy_topic,omitempty" json:"ntfy_topic,omitempty"`               // Per-filter ntfy topic (empty = use global topic)
+	ApplyGmailLabel string     `yaml:"apply_gmail_label,omitempty" json:"apply_gmail_label,omitempty"` // Gmail label to add on match (requires monitoring.gmail.allow_modify)
+	MarkRead        bool       `yaml:"mark_read,omitempty" json:"mark_read,omitempty"`                 // Mark matched messages as read (requires monitoring.gmail.allow_modify)
+	Digest          string     `yaml:"digest,omitempty" json:"digest,omitempty"`                       // Batch notifications into one summary per interval, e.g. "5m" (empty = notify each match)
+	ExpiresAt       *time.Time `yaml:"expires_at,omitempty" json:"expires_at,omitempty"`               // Expiration date (nil = never expires)
+	Enabled         *bool      `yaml:"enabled,omitempty" json:"enabled,omitempty"`                     // false = paused (nil = enabled, for older configs)
+	ForcePriority   *int       `yaml:"force_priority,omitempty" json:"force_priority,omitempty"`       // 0 = normal, 1 = high, 2 = critical (nil = use priority rules)
+	HasAttachment   *bool      `yaml:"has_attachment,omitempty" json:"has_attachment,omitempty"`       // true = only emails with attachments, false = only without (nil = either)
+}
+
+// IsEnabled reports whether the filter is active (a missing enabled key means enabled)
+func (f Filter) IsEnabled() bool {
+	return f.Enabled == nil || *f.Enabled
 }
 
 // MatchResult represents a matched filter with its metadata
 type MatchResult struct {
-	Name       string
-	Labels     []string
-	GmailScope string
+	Name            string
+	Labels          []string
+	GmailScope      string
+	GmailQuery      string
+	NtfyTopic       string
+	ApplyGmailLabel string
+	MarkRead        bool
+	Digest          string
+	ForcePriority   *int
 }
 
 // Config represents the application configuration
@@ -0,0 +1,51 @@
+package gmail
+
+import (
+	"fmt"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// HasAttachment reports whether a message payload contains at least one attachment
+// A part counts when it has a filename and its data is stored separately (body.attachmentId),
+// so the text and HTML bodies and inline parts without a filename are ignored.
+func HasAttachment(payload *gmail.MessagePart) bool {
+	if payload == nil {
+		return false
+	}
+
+	if payload.Filename != "" && payload.Body != nil && payload.Body.AttachmentId != "" {
+		return true
+	}
+
+	for _, part := range payload.Parts {
+		if HasAttachment(part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MessageHasAttachment reports whether a message has an attachment
+// Messages from GetRecentMessagesWithQuery already carry their payload; anything
+// else (e.g. a bare ID from messages.list) is fetched in "full" format first.
+func (c *Client) MessageHasAttachment(msg *gmail.Message) (bool, error) {
+	if msg.Payload != nil {
+		return HasAttachment(msg.Payload), nil
+	}
+
+	// Refresh token if needed before making API call
+	if err := c.RefreshTokenIfNeeded(); err != nil {
+		return false, err
+	}
+
+	full, err := c.service.Users.Messages.Get("me", msg.Id).
+		Format("full").
+		Do()
+	if err != nil {
+		return false, fmt.Errorf("unable to retrieve message %s: %w", msg.Id, err)
+	}
+
+	return HasAttachment(full.Payload), nil
+}
@@ -0,0 +1,71 @@
+package gmail
+
+import (
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// TestHasAttachment tests attachment detection on synthetic MIME trees
+func TestHasAttachment(t *testing.T) {
+	textPart := &gmail.MessagePart{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "SGVsbG8"}}
+	htmlPart := &gmail.MessagePart{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: "PHA-SGk8L3A-"}}
+	pdfPart := &gmail.MessagePart{
+		MimeType: "application/pdf",
+		Filename: "invoice.pdf",
+		Body:     &gmail.MessagePartBody{AttachmentId: "ANGjdJ8", Size: 52311},
+	}
+
+	tests := []struct {
+		name    string
+		payload *gmail.MessagePart
+		want    bool
+	}{
+		{"nil payload", nil, false},
+		{"plain text only", textPart, false},
+		{
+			name:    "alternative bodies",
+			payload: &gmail.MessagePart{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{textPart, htmlPart}},
+			want:    false,
+		},
+		{
+			name:    "mixed with pdf",
+			payload: &gmail.MessagePart{MimeType: "multipart/mixed", Parts: []*gmail.MessagePart{textPart, pdfPart}},
+			want:    true,
+		},
+		{
+			name: "attachment nested under alternative",
+			payload: &gmail.MessagePart{
+				MimeType: "multipart/mixed",
+				Parts: []*gmail.MessagePart{
+					{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{textPart, htmlPart}},
+					{MimeType: "multipart/related", Parts: []*gmail.MessagePart{pdfPart}},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "inline image without filename",
+			payload: &gmail.MessagePart{MimeType: "multipart/related", Parts: []*gmail.MessagePart{
+				htmlPart,
+				{MimeType: "image/png", Body: &gmail.MessagePartBody{AttachmentId: "ANGjdJ9"}},
+			}},
+			want: false,
+		},
+		{
+			name: "filename without attachment data",
+			payload: &gmail.MessagePart{MimeType: "multipart/mixed", Parts: []*gmail.MessagePart{
+				{MimeType: "text/plain", Filename: "notes.txt", Body: &gmail.MessagePartBody{Data: "SGk"}},
+			}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasAttachment(tt.payload); got != tt.want {
+				t.Errorf("HasAttachment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,23 +4,41 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/gmail/v1"
 
 	"github.com/datateamsix/email-sentinel/internal/config"
+	"github.com/datateamsix/email-sentinel/internal/log"
+	"github.com/datateamsix/email-sentinel/internal/state"
 )
 
 // LoadCredentials reads the OAuth credentials from credentials.json
+// The resulting config requests read-only access
 func LoadCredentials(credPath string) (*oauth2.Config, error) {
+	return LoadCredentialsWithModify(credPath, false)
+}
+
+// LoadCredentialsWithModify reads the OAuth credentials and requests the
+// gmail.modify scope when allowModify is true (needed to apply labels and mark mail read)
+func LoadCredentialsWithModify(credPath string, allowModify bool) (*oauth2.Config, error) {
 	data, err := os.ReadFile(credPath)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read credentials file: %w", err)
 	}
 
-	config, err := google.ConfigFromJSON(data, gmail.GmailReadonlyScope)
+	scope := gmail.GmailReadonlyScope
+	if allowModify {
+		scope = gmail.GmailModifyScope
+	}
+
+	config, err := google.ConfigFromJSON(data, scope)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse credentials: %w", err)
 	}
@@ -28,10 +46,26 @@ func LoadCredentials(credPath string) (*oauth2.Config, error) {
 	return config, nil
 }
 
+// revokeURL is Google's OAuth token revocation endpoint (a variable so tests can point it elsewhere)
+var revokeURL = "https://oauth2.googleapis.com/revoke"
+
+// authCodeOptions returns the options for the authorization URL
+// Offline access is always requested so a refresh token is issued. forceConsent adds
+// prompt=consent, which makes Google show the consent screen again and issue a fresh
+// refresh token for the current scopes (otherwise a changed scope may not be granted).
+func authCodeOptions(forceConsent bool) []oauth2.AuthCodeOption {
+	opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOffline}
+	if forceConsent {
+		opts = append(opts, oauth2.SetAuthURLParam("prompt", "consent"))
+	}
+	return opts
+}
+
 // GetTokenFromWeb starts the OAuth flow and returns a token
-func GetTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
+// Set forceConsent when re-authorizing so Google asks for consent again.
+func GetTokenFromWeb(config *oauth2.Config, forceConsent bool) (*oauth2.Token, error) {
 	// Generate auth URL
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	authURL := config.AuthCodeURL("state-token", authCodeOptions(forceConsent)...)
 
 	fmt.Println("")
 	fmt.Println("🔐 Gmail Authorization Required")
@@ -57,6 +91,11 @@ func GetTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
 		return nil, fmt.Errorf("unable to exchange code for token: %w", err)
 	}
 
+	// Start the clock for refresh-token expiry warnings
+	if err := state.RecordAuthorized(); err != nil {
+		log.Warn("Failed to record authorization time", "error", err)
+	}
+
 	return token, nil
 }
 
@@ -87,6 +126,45 @@ func SaveToken(token *oauth2.Token) error {
 	return nil
 }
 
+// DeleteToken removes the saved token (a missing token is not an error)
+func DeleteToken() error {
+	tokenPath, err := config.TokenPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to delete token file: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeToken asks Google to revoke the authorization behind a token
+// Revoking the refresh token also invalidates its access tokens and removes the app's grant.
+func RevokeToken(token *oauth2.Token) error {
+	value := token.RefreshToken
+	if value == "" {
+		value = token.AccessToken
+	}
+	if value == "" {
+		return fmt.Errorf("token has nothing to revoke")
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Post(revokeURL, "application/x-www-form-urlencoded", strings.NewReader(url.Values{"token": {value}}.Encode()))
+	if err != nil {
+		return fmt.Errorf("unable to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to revoke token: %s", resp.Status)
+	}
+
+	return nil
+}
+
 // LoadToken loads a previously saved token
 func LoadToken() (*oauth2.Token, error) {
 	tokenPath, err := config.TokenPath()
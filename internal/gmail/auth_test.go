@@ -0,0 +1,83 @@
+package gmail
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestAuthCodeOptions(t *testing.T) {
+	config := &oauth2.Config{ClientID: "id", Endpoint: oauth2.Endpoint{AuthURL: "https://accounts.example.com/auth"}}
+
+	tests := []struct {
+		name         string
+		forceConsent bool
+		wantPrompt   string
+	}{
+		{"First authorization", false, ""},
+		{"Re-authorization", true, "consent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := url.Parse(config.AuthCodeURL("state", authCodeOptions(tt.forceConsent)...))
+			if err != nil {
+				t.Fatalf("invalid auth URL: %v", err)
+			}
+			query := authURL.Query()
+			if got := query.Get("access_type"); got != "offline" {
+				t.Errorf("access_type = %q, want offline", got)
+			}
+			if got := query.Get("prompt"); got != tt.wantPrompt {
+				t.Errorf("prompt = %q, want %q", got, tt.wantPrompt)
+			}
+		})
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	var revoked string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		revoked = r.PostForm.Get("token")
+		if revoked == "expired" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	original := revokeURL
+	revokeURL = server.URL
+	defer func() { revokeURL = original }()
+
+	tests := []struct {
+		name        string
+		token       *oauth2.Token
+		wantRevoked string
+		wantErr     bool
+	}{
+		{"Refresh token preferred", &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}, "refresh", false},
+		{"Access token fallback", &oauth2.Token{AccessToken: "access"}, "access", false},
+		{"Rejected by Google", &oauth2.Token{RefreshToken: "expired"}, "expired", true},
+		{"Empty token", &oauth2.Token{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked = ""
+			err := RevokeToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RevokeToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("revoked %q, want %q", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package gmail
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// MaxBodySize caps the decoded body size to keep memory and matching cost bounded
+const MaxBodySize = 100 * 1024 // 100KB
+
+// GetMessageBody fetches a message and returns its plain text body
+// Prefers text/plain parts and falls back to text/html with tags stripped
+func (c *Client) GetMessageBody(messageID string) (string, error) {
+	// Refresh token if needed before making API call
+	if err := c.RefreshTokenIfNeeded(); err != nil {
+		return "", err
+	}
+
+	msg, err := c.service.Users.Messages.Get("me", messageID).
+		Format("full").
+		Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve message %s: %w", messageID, err)
+	}
+
+	return ExtractBody(msg.Payload), nil
+}
+
+// ExtractBody walks the MIME parts of a message payload and returns the body text
+func ExtractBody(payload *gmail.MessagePart) string {
+	if payload == nil {
+		return ""
+	}
+
+	if body := findPart(payload, "text/plain"); body != "" {
+		return truncateBody(body)
+	}
+
+	if body := findPart(payload, "text/html"); body != "" {
+		return truncateBody(HTMLToText(body))
+	}
+
+	return ""
+}
+
+// findPart returns the decoded data of the first part matching mimeType
+// Attachments are skipped even if they have a text mime type
+func findPart(part *gmail.MessagePart, mimeType string) string {
+	if part == nil {
+		return ""
+	}
+
+	if strings.EqualFold(part.MimeType, mimeType) && part.Filename == "" && part.Body != nil && part.Body.Data != "" {
+		if decoded, err := decodeBase64URL(part.Body.Data); err == nil {
+			return decoded
+		}
+	}
+
+	for _, child := range part.Parts {
+		if body := findPart(child, mimeType); body != "" {
+			return body
+		}
+	}
+
+	return ""
+}
+
+// decodeBase64URL decodes Gmail's base64url encoded body data (padding optional)
+func decodeBase64URL(data string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(data, "="))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// truncateBody caps the body at MaxBodySize without splitting a UTF-8 rune
+func truncateBody(s string) string {
+	if len(s) <= MaxBodySize {
+		return s
+	}
+
+	cut := MaxBodySize
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,115 @@
+package gmail
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// bodyPart builds a MIME part with base64url data the way the Gmail API returns it
+func bodyPart(mimeType, filename, data string) *gmail.MessagePart {
+	return &gmail.MessagePart{
+		MimeType: mimeType,
+		Filename: filename,
+		Body:     &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(data))},
+	}
+}
+
+// TestExtractBody tests which MIME part becomes the body on hand-built message trees
+func TestExtractBody(t *testing.T) {
+	plain := bodyPart("text/plain", "", "Plain body")
+	html := bodyPart("text/html", "", "<p>HTML <b>body</b></p>")
+
+	tests := []struct {
+		name    string
+		payload *gmail.MessagePart
+		want    string
+	}{
+		{"nil payload", nil, ""},
+		{"single plain part", plain, "Plain body"},
+		{
+			name:    "plain preferred over html",
+			payload: &gmail.MessagePart{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{html, plain}},
+			want:    "Plain body",
+		},
+		{
+			name:    "html fallback",
+			payload: &gmail.MessagePart{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{html}},
+			want:    "HTML body",
+		},
+		{
+			name: "nested multipart",
+			payload: &gmail.MessagePart{MimeType: "multipart/mixed", Parts: []*gmail.MessagePart{
+				{MimeType: "multipart/related", Parts: []*gmail.MessagePart{
+					{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{html, plain}},
+				}},
+				bodyPart("application/pdf", "invoice.pdf", "%PDF-1.4"),
+			}},
+			want: "Plain body",
+		},
+		{
+			name: "text attachment skipped",
+			payload: &gmail.MessagePart{MimeType: "multipart/mixed", Parts: []*gmail.MessagePart{
+				bodyPart("text/plain", "notes.txt", "Attached notes"),
+				html,
+			}},
+			want: "HTML body",
+		},
+		{
+			name:    "only attachments",
+			payload: &gmail.MessagePart{MimeType: "multipart/mixed", Parts: []*gmail.MessagePart{bodyPart("text/plain", "notes.txt", "Attached notes")}},
+			want:    "",
+		},
+		{
+			name:    "unpadded data",
+			payload: &gmail.MessagePart{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte("No padding!"))}},
+			want:    "No padding!",
+		},
+		{
+			name: "undecodable part skipped",
+			payload: &gmail.MessagePart{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{
+				{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "not*base64"}},
+				html,
+			}},
+			want: "HTML body",
+		},
+		{
+			name:    "case-insensitive mime type",
+			payload: bodyPart("TEXT/PLAIN", "", "Upper case"),
+			want:    "Upper case",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.TrimSpace(ExtractBody(tt.payload)); got != tt.want {
+				t.Errorf("ExtractBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractBodyTruncation tests that long bodies are cut at MaxBodySize on a rune boundary
+func TestExtractBodyTruncation(t *testing.T) {
+	// "é" is two bytes, so MaxBodySize falls in the middle of one
+	long := "x" + strings.Repeat("é", MaxBodySize)
+
+	got := ExtractBody(bodyPart("text/plain", "", long))
+	if len(got) > MaxBodySize {
+		t.Errorf("len(ExtractBody()) = %d, want at most %d", len(got), MaxBodySize)
+	}
+	if len(got) < MaxBodySize-utf8.UTFMax {
+		t.Errorf("len(ExtractBody()) = %d, want close to %d", len(got), MaxBodySize)
+	}
+	if !utf8.ValidString(got) {
+		t.Error("ExtractBody() split a UTF-8 rune")
+	}
+
+	short := "Short body"
+	if got := ExtractBody(bodyPart("text/plain", "", short)); got != short {
+		t.Errorf("ExtractBody() = %q, want %q", got, short)
+	}
+}
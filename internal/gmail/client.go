@@ -3,12 +3,16 @@ package gmail
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
 	"google.golang.org/api/gmail/v1"
 	"google.golang.org/api/option"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
+	"github.com/datateamsix/email-sentinel/internal/state"
 )
 
 // Client wraps the Gmail API service with auto-refreshing tokens
@@ -17,6 +21,16 @@ type Client struct {
 	token       *oauth2.Token
 	oauthConfig *oauth2.Config
 	tokenMu     sync.RWMutex
+	labelIDs    map[string]string // lowercase label name -> label ID
+	labelMu     sync.Mutex
+}
+
+// MessageFetcher is the read side of the Gmail API that the monitoring pipeline depends on
+// *Client implements it; tests substitute a fake that returns canned messages.
+type MessageFetcher interface {
+	GetMessagesForScopes(maxResults int64, queries []string) (*ScopeMessages, error)
+	GetMessageBody(messageID string) (string, error)
+	MessageHasAttachment(msg *gmail.Message) (bool, error)
 }
 
 // NewClient creates a new Gmail API client using the provided OAuth token
@@ -56,14 +70,9 @@ func (c *Client) monitorTokenRefresh(tokenSource oauth2.TokenSource) {
 		newToken, err := tokenSource.Token()
 		if err != nil {
 			// CRITICAL: Token refresh failed - alert user immediately
-			fmt.Println("")
-			fmt.Println("❌ CRITICAL: OAuth token refresh failed!")
-			fmt.Printf("   Error: %v\n", err)
-			fmt.Println("")
-			fmt.Println("   This usually means your Gmail authentication has expired.")
-			fmt.Println("   Please re-authenticate with:")
-			fmt.Println("   email-sentinel init")
-			fmt.Println("")
+			log.Error("CRITICAL: OAuth token refresh failed! Gmail authentication has probably expired, re-authenticate with: email-sentinel init",
+				"error", err)
+			recordRefreshFailure(err)
 			// Continue monitoring, will retry next cycle (5 minutes)
 			continue
 		}
@@ -81,8 +90,9 @@ func (c *Client) monitorTokenRefresh(tokenSource oauth2.TokenSource) {
 
 			if err := SaveToken(newToken); err != nil {
 				// Log error but continue - not fatal
-				fmt.Printf("⚠️  Warning: Failed to save refreshed token: %v\n", err)
+				log.Warn("Failed to save refreshed token", "error", err)
 			}
+			recordRefreshSuccess()
 		}
 	}
 }
@@ -102,8 +112,10 @@ func (c *Client) RefreshTokenIfNeeded() error {
 
 	newToken, err := tokenSource.Token()
 	if err != nil {
+		recordRefreshFailure(err)
 		return fmt.Errorf("failed to refresh token: %w", err)
 	}
+	recordRefreshSuccess()
 
 	c.tokenMu.Lock()
 	c.token = newToken
@@ -114,10 +126,24 @@ func (c *Client) RefreshTokenIfNeeded() error {
 		return fmt.Errorf("failed to save refreshed token: %w", err)
 	}
 
-	fmt.Println("✅ OAuth token refreshed successfully")
+	log.Info("OAuth token refreshed successfully", log.Icon("✅"))
 	return nil
 }
 
+// recordRefreshFailure persists a failed refresh so 'start' and the dashboard can warn about it
+func recordRefreshFailure(refreshErr error) {
+	if err := state.RecordRefreshFailure(refreshErr); err != nil {
+		log.Warn("Failed to record token refresh failure", "error", err)
+	}
+}
+
+// recordRefreshSuccess clears a persisted refresh failure
+func recordRefreshSuccess() {
+	if err := state.RecordRefreshSuccess(); err != nil {
+		log.Warn("Failed to update auth state", "error", err)
+	}
+}
+
 // GetRecentMessages fetches recent messages from the inbox with retry logic
 // maxResults specifies the maximum number of messages to retrieve
 // Defaults to searching only the inbox (in:inbox)
@@ -150,8 +176,7 @@ func (c *Client) GetRecentMessagesWithQuery(maxResults int64, searchQuery string
 		// Exponential backoff
 		if attempt < maxRetries-1 {
 			delay := baseDelay * time.Duration(1<<uint(attempt))
-			fmt.Printf("⚠️  Gmail API error (attempt %d/%d): %v\n", attempt+1, maxRetries, err)
-			fmt.Printf("   Retrying in %v...\n", delay)
+			log.Warn("Gmail API error, retrying", "attempt", attempt+1, "max_attempts", maxRetries, "retry_in", delay, "error", err)
 			time.Sleep(delay)
 		}
 	}
@@ -178,7 +203,7 @@ func (c *Client) getRecentMessagesOnce(maxResults int64, searchQuery string) ([]
 
 	response, err := listCall.Do()
 	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve messages: %w", err)
+		return nil, wrapScopeError(fmt.Errorf("unable to retrieve messages: %w", err))
 	}
 
 	if len(response.Messages) == 0 {
@@ -193,7 +218,7 @@ func (c *Client) getRecentMessagesOnce(maxResults int64, searchQuery string) ([]
 			Do()
 		if err != nil {
 			// Log error but continue with other messages
-			fmt.Printf("⚠️  Warning: Could not fetch message %s: %v\n", msg.Id, err)
+			log.Warn("Could not fetch message", "message_id", msg.Id, "error", err)
 			continue
 		}
 		messages = append(messages, fullMsg)
@@ -208,6 +233,11 @@ func isRetryableError(err error) bool {
 		return false
 	}
 
+	// A token missing a scope fails the same way every time
+	if IsInsufficientScopeError(err) {
+		return false
+	}
+
 	errStr := err.Error()
 
 	// Network errors
@@ -233,19 +263,7 @@ func isRetryableError(err error) bool {
 
 // contains checks if a string contains a substring (case-insensitive)
 func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr ||
-		len(s) > len(substr) && (s[:len(substr)] == substr ||
-			s[len(s)-len(substr):] == substr ||
-			len(s) > len(substr)*2 && indexOf(s, substr) >= 0))
-}
-
-func indexOf(s, substr string) int {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return i
-		}
-	}
-	return -1
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
 
 // GetMessagesAfter fetches messages received after a specific message ID
@@ -289,7 +307,7 @@ func (c *Client) GetMessagesAfter(afterMessageID string, maxResults int64) ([]*g
 			Format("full").
 			Do()
 		if err != nil {
-			fmt.Printf("Warning: Could not fetch message %s: %v\n", msg.Id, err)
+			log.Warn("Could not fetch message", "message_id", msg.Id, "error", err)
 			continue
 		}
 		messages = append(messages, fullMsg)
@@ -308,7 +326,7 @@ func (c *Client) MarkAsRead(messageID string) error {
 
 	_, err := c.service.Users.Messages.Modify(user, messageID, modifyRequest).Do()
 	if err != nil {
-		return fmt.Errorf("unable to mark message as read: %w", err)
+		return wrapScopeError(fmt.Errorf("unable to mark message as read: %w", err))
 	}
 
 	return nil
@@ -0,0 +1,80 @@
+package gmail
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "Nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "Rate limit exceeded",
+			err:      errors.New("googleapi: Error 429: Rate Limit Exceeded, rateLimitExceeded"),
+			expected: true,
+		},
+		{
+			name:     "Timeout in the middle of the message",
+			err:      errors.New("Get \"https://gmail.googleapis.com/gmail/v1/users/me/messages\": read tcp 192.168.1.5:52344->142.250.72.10:443: i/o timeout"),
+			expected: true,
+		},
+		{
+			name:     "Service unavailable",
+			err:      errors.New("googleapi: got HTTP response code 503 Service Unavailable with body: "),
+			expected: true,
+		},
+		{
+			name:     "Backend error",
+			err:      errors.New("googleapi: Error 500: Backend Error, backendError"),
+			expected: true,
+		},
+		{
+			name:     "Quota exceeded (mixed case)",
+			err:      errors.New("googleapi: Error 403: Quota Exceeded for quota metric 'Queries'"),
+			expected: true,
+		},
+		{
+			name:     "Connection refused",
+			err:      errors.New("dial tcp 127.0.0.1:443: connect: connection refused"),
+			expected: true,
+		},
+		{
+			name:     "Not found is not retryable",
+			err:      errors.New("googleapi: Error 404: Requested entity was not found., notFound"),
+			expected: false,
+		},
+		{
+			name:     "Invalid credentials is not retryable",
+			err:      errors.New("googleapi: Error 401: Invalid Credentials, authError"),
+			expected: false,
+		},
+		{
+			name: "Insufficient scope is not retryable",
+			err: fmt.Errorf("unable to retrieve messages: %w", &googleapi.Error{
+				Code:    403,
+				Message: "Request had insufficient authentication scopes. (network request to gmail.googleapis.com)",
+			}),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isRetryableError(tt.err)
+			if result != tt.expected {
+				t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, result, tt.expected)
+			}
+		})
+	}
+}
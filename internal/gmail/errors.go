@@ -0,0 +1,51 @@
+package gmail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// ErrInsufficientScope is returned when the saved token doesn't grant the scope a call needs
+// (e.g. a read-only token used to apply labels). Retrying can't help; the user has to
+// re-authorize with 'email-sentinel init --reauth'.
+var ErrInsufficientScope = errors.New("gmail token lacks a required OAuth scope")
+
+// IsInsufficientScopeError reports whether err was caused by a token missing a required scope
+// Google reports this as a 401 or 403 with reason "insufficientPermissions", an
+// "insufficient_scope" WWW-Authenticate challenge, or an "insufficient ... scopes" message.
+func IsInsufficientScopeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrInsufficientScope) {
+		return true
+	}
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || (apiErr.Code != 401 && apiErr.Code != 403) {
+		return false
+	}
+
+	for _, item := range apiErr.Errors {
+		if strings.EqualFold(item.Reason, "insufficientPermissions") {
+			return true
+		}
+	}
+	if strings.Contains(strings.ToLower(apiErr.Header.Get("WWW-Authenticate")), "insufficient_scope") {
+		return true
+	}
+
+	msg := strings.ToLower(apiErr.Error())
+	return strings.Contains(msg, "insufficient") && (strings.Contains(msg, "scope") || strings.Contains(msg, "permission"))
+}
+
+// wrapScopeError tags insufficient-scope API errors with ErrInsufficientScope
+func wrapScopeError(err error) error {
+	if IsInsufficientScopeError(err) {
+		return fmt.Errorf("%w: %v", ErrInsufficientScope, err)
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package gmail
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsInsufficientScopeError(t *testing.T) {
+	scopeErr := &googleapi.Error{
+		Code:    403,
+		Message: "Request had insufficient authentication scopes.",
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "Nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "Insufficient scope API error",
+			err:      scopeErr,
+			expected: true,
+		},
+		{
+			name:     "Wrapped insufficient scope API error",
+			err:      fmt.Errorf("unable to apply label 'x': %w", scopeErr),
+			expected: true,
+		},
+		{
+			name:     "Sentinel error",
+			err:      wrapScopeError(scopeErr),
+			expected: true,
+		},
+		{
+			name: "Unauthorized with insufficient_scope challenge",
+			err: &googleapi.Error{
+				Code:    401,
+				Message: "Request is missing required authentication credential.",
+				Header:  http.Header{"Www-Authenticate": []string{`Bearer realm="https://accounts.google.com/", error="insufficient_scope"`}},
+			},
+			expected: true,
+		},
+		{
+			name: "Forbidden with insufficientPermissions reason",
+			err: &googleapi.Error{
+				Code:    403,
+				Message: "Forbidden",
+				Errors:  []googleapi.ErrorItem{{Reason: "insufficientPermissions", Message: "Insufficient Permission"}},
+			},
+			expected: true,
+		},
+		{
+			name:     "Invalid credentials",
+			err:      &googleapi.Error{Code: 401, Message: "Invalid Credentials"},
+			expected: false,
+		},
+		{
+			name:     "Other forbidden error",
+			err:      &googleapi.Error{Code: 403, Message: "Daily Limit Exceeded"},
+			expected: false,
+		},
+		{
+			name:     "Plain error",
+			err:      errors.New("connection refused"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsInsufficientScopeError(tt.err); got != tt.expected {
+				t.Errorf("IsInsufficientScopeError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package gmail
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skippedElements hold no readable text (scripts, styles, document metadata)
+var skippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Head:     true,
+	atom.Title:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+}
+
+// blockElements start a new line so words from adjacent blocks don't run together
+var blockElements = map[atom.Atom]bool{
+	atom.Br:         true,
+	atom.P:          true,
+	atom.Div:        true,
+	atom.Tr:         true,
+	atom.Li:         true,
+	atom.Table:      true,
+	atom.Ul:         true,
+	atom.Ol:         true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Hr:         true,
+	atom.Blockquote: true,
+	atom.Section:    true,
+	atom.Header:     true,
+	atom.Footer:     true,
+}
+
+// HTMLToText converts an HTML email body or snippet into readable plain text
+// Tags are stripped, entities decoded, script/style content dropped and whitespace collapsed.
+// Block elements become line breaks; table cells are separated by spaces.
+func HTMLToText(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return collapseWhitespace(s)
+	}
+
+	var b strings.Builder
+	skipDepth := 0
+
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or malformed input: keep whatever was extracted so far
+			break
+		}
+
+		switch tt {
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+
+			if skippedElements[a] {
+				if tt == html.StartTagToken {
+					skipDepth++
+				} else if tt == html.EndTagToken && skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+
+			if blockElements[a] {
+				b.WriteByte('\n')
+			} else if a == atom.Td || a == atom.Th {
+				b.WriteByte(' ')
+			}
+
+		case html.TextToken:
+			if skipDepth == 0 {
+				b.Write(z.Text())
+			}
+		}
+	}
+
+	return collapseWhitespace(b.String())
+}
+
+// collapseWhitespace squeezes runs of spaces within lines and keeps at most one blank line between paragraphs
+// strings.Fields also splits on non-breaking spaces (&nbsp;), common padding in marketing emails.
+func collapseWhitespace(s string) string {
+	s = strings.Map(dropZeroWidth, s)
+
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank = len(out) > 0
+			continue
+		}
+		if blank {
+			out = append(out, "")
+			blank = false
+		}
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// dropZeroWidth removes invisible characters (&zwnj;, BOM, ...) that preheaders use as padding
+func dropZeroWidth(r rune) rune {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+		return -1
+	default:
+		return r
+	}
+}
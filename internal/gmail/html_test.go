@@ -0,0 +1,127 @@
+package gmail
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// otpEmailHTML is a trimmed-down transactional email in the usual table-layout style
+const otpEmailHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Your Acme sign-in code</title>
+  <style>.code { font-size: 32px; letter-spacing: 6px; } td { padding: 0 }</style>
+  <script>window.dataLayer = [{"event": "open", "id": 555111}];</script>
+</head>
+<body>
+  <div style="display:none">Use this code to finish signing in&nbsp;&zwnj;&nbsp;</div>
+  <table width="100%"><tr><td align="center">
+    <table><tr><td>
+      <h1>Hi&nbsp;Alex,</h1>
+      <p>Your verification code is:</p>
+      <p class="code"><strong>48</strong><strong>29</strong><strong>13</strong></p>
+      <p>This code expires in 10&nbsp;minutes. Don&#39;t share it with anyone &mdash; Acme will never ask for it.</p>
+    </td></tr></table>
+  </td></tr></table>
+</body>
+</html>`
+
+// trialEmailHTML is a marketing-style trial email with entities, inline styles and a tracking pixel
+const trialEmailHTML = `<html><body style="margin:0">
+<table role="presentation"><tr>
+  <td><img src="https://t.example.com/open.gif" width="1" height="1" alt=""></td>
+  <td><h2>Welcome to StreamPlus&trade;!</h2></td>
+</tr><tr>
+  <td colspan="2">
+    Your <b>free trial</b> ends on <span style="color:#e50914">March&nbsp;15,&nbsp;2025</span>.<br>
+    After that you&rsquo;ll be charged <b>$12.99/month</b> &amp; can cancel anytime.
+  </td>
+</tr></table>
+<style>@media (max-width:600px){ .x{display:block} }</style>
+</body></html>`
+
+// TestHTMLToText tests extraction from realistic email HTML
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name        string
+		html        string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "OTP email",
+			html: otpEmailHTML,
+			contains: []string{
+				"Hi Alex,",
+				"Your verification code is:",
+				"\n482913\n",
+				"expires in 10 minutes. Don't share it with anyone — Acme",
+			},
+			notContains: []string{"<", "&nbsp;", "&#39;", "\u200c", "font-size", "dataLayer", "555111", "Your Acme sign-in code"},
+		},
+		{
+			name: "Trial email",
+			html: trialEmailHTML,
+			contains: []string{
+				"Welcome to StreamPlus™!",
+				"free trial ends on March 15, 2025.",
+				"you’ll be charged $12.99/month & can cancel anytime.",
+			},
+			notContains: []string{"<", "&amp;", "&trade;", "max-width", "open.gif"},
+		},
+		{
+			name:     "Plain text is only whitespace-collapsed",
+			html:     "Your code is   739104\n\n\n\nThanks",
+			contains: []string{"Your code is 739104\n\nThanks"},
+		},
+		{
+			name:     "Snippet with entities",
+			html:     "Don&#39;t miss out &ndash; your code is 739104",
+			contains: []string{"Don't miss out – your code is 739104"},
+		},
+		{
+			name:        "Unclosed script",
+			html:        "<p>Code: 739104</p><script>var x = 1;",
+			contains:    []string{"Code: 739104"},
+			notContains: []string{"var x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HTMLToText(tt.html)
+
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("HTMLToText() = %q, expected it to contain %q", got, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("HTMLToText() = %q, expected it not to contain %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+// TestExtractBodyHTMLOnly tests that an HTML-only message is converted to text
+func TestExtractBodyHTMLOnly(t *testing.T) {
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/alternative",
+		Parts: []*gmail.MessagePart{
+			{
+				MimeType: "text/html",
+				Body:     &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte(otpEmailHTML))},
+			},
+		},
+	}
+
+	body := ExtractBody(payload)
+	if !strings.Contains(body, "482913") || strings.Contains(body, "<") {
+		t.Errorf("ExtractBody() = %q, expected clean text containing the code", body)
+	}
+}
@@ -0,0 +1,78 @@
+package gmail
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// ApplyLabel adds the named label to a message, creating the label if it doesn't exist
+// Requires a token authorized with the gmail.modify scope
+func (c *Client) ApplyLabel(messageID, labelName string) error {
+	labelName = strings.TrimSpace(labelName)
+	if labelName == "" {
+		return fmt.Errorf("label name cannot be empty")
+	}
+
+	// Refresh token if needed before making API call
+	if err := c.RefreshTokenIfNeeded(); err != nil {
+		return err
+	}
+
+	labelID, err := c.resolveLabelID(labelName)
+	if err != nil {
+		return err
+	}
+
+	modifyRequest := &gmail.ModifyMessageRequest{
+		AddLabelIds: []string{labelID},
+	}
+
+	if _, err := c.service.Users.Messages.Modify("me", messageID, modifyRequest).Do(); err != nil {
+		return wrapScopeError(fmt.Errorf("unable to apply label '%s': %w", labelName, err))
+	}
+
+	return nil
+}
+
+// resolveLabelID returns the ID of the named label, creating it if needed
+// Resolved IDs are cached for the lifetime of the client
+func (c *Client) resolveLabelID(labelName string) (string, error) {
+	key := strings.ToLower(labelName)
+
+	c.labelMu.Lock()
+	defer c.labelMu.Unlock()
+
+	if id, ok := c.labelIDs[key]; ok {
+		return id, nil
+	}
+
+	resp, err := c.service.Users.Labels.List("me").Do()
+	if err != nil {
+		return "", wrapScopeError(fmt.Errorf("unable to list labels: %w", err))
+	}
+
+	if c.labelIDs == nil {
+		c.labelIDs = make(map[string]string)
+	}
+	for _, label := range resp.Labels {
+		c.labelIDs[strings.ToLower(label.Name)] = label.Id
+	}
+
+	if id, ok := c.labelIDs[key]; ok {
+		return id, nil
+	}
+
+	created, err := c.service.Users.Labels.Create("me", &gmail.Label{
+		Name:                  labelName,
+		LabelListVisibility:   "labelShow",
+		MessageListVisibility: "show",
+	}).Do()
+	if err != nil {
+		return "", wrapScopeError(fmt.Errorf("unable to create label '%s': %w", labelName, err))
+	}
+
+	c.labelIDs[key] = created.Id
+	return created.Id, nil
+}
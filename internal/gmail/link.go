@@ -0,0 +1,68 @@
+package gmail
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// IsValidGmailURL validates that a URL is a legitimate Gmail link
+// Used before handing a URL to the OS so only https://mail.google.com/mail/... links are opened
+func IsValidGmailURL(urlStr string) bool {
+	// Parse URL
+	parsedURL, err := url.Parse(urlStr)
+	if err != nil {
+		return false
+	}
+
+	// Must be HTTPS
+	if parsedURL.Scheme != "https" {
+		return false
+	}
+
+	// Must be mail.google.com domain
+	if !strings.HasSuffix(parsedURL.Host, "mail.google.com") {
+		return false
+	}
+
+	// Path should start with /mail/
+	if !strings.HasPrefix(parsedURL.Path, "/mail/") {
+		return false
+	}
+
+	return true
+}
+
+// OpenGmailLink opens a Gmail link in the default browser
+// The URL is validated first to prevent command injection attacks
+func OpenGmailLink(urlStr string) error {
+	if !IsValidGmailURL(urlStr) {
+		return fmt.Errorf("blocked invalid Gmail URL: %s", urlStr)
+	}
+
+	return OpenURL(urlStr)
+}
+
+// OpenURL opens a URL in the default browser (cross-platform)
+// It doesn't validate the URL; callers check it first (see IsValidGmailURL).
+func OpenURL(rawURL string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "windows":
+		// Not "cmd /c start": cmd.exe would treat & in the query string as a command separator
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	default:
+		cmd = exec.Command("xdg-open", rawURL)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package gmail
+
+import "testing"
+
+func TestIsValidGmailURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{BuildGmailLink("abc123"), true},
+		{"https://mail.google.com/mail/u/0/#inbox/abc", true},
+		{"http://mail.google.com/mail/u/0/#all/abc", false},
+		{"https://evil.example.com/mail/u/0/#all/abc", false},
+		{"https://mail.google.com/settings", false},
+		{"javascript:alert(1)", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := IsValidGmailURL(tt.url); got != tt.want {
+				t.Errorf("IsValidGmailURL(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package gmail
 
 import (
 	"strings"
+	"time"
 
 	"google.golang.org/api/gmail/v1"
 )
@@ -13,13 +14,20 @@ type EmailMessage struct {
 	Subject string
 	Snippet string
 	Date    string
+
+	// ReceivedAt is when Gmail received the message, from its internalDate (zero if the API didn't say)
+	// Unlike the Date header it is set by Gmail, so senders can't backdate it.
+	ReceivedAt time.Time
 }
 
 // ParseMessage extracts relevant fields from a Gmail API message
 func ParseMessage(msg *gmail.Message) *EmailMessage {
 	email := &EmailMessage{
 		ID:      msg.Id,
-		Snippet: msg.Snippet,
+		Snippet: HTMLToText(msg.Snippet), // Snippets can carry entities like &#39; and stray tags
+	}
+	if msg.InternalDate > 0 {
+		email.ReceivedAt = time.UnixMilli(msg.InternalDate)
 	}
 
 	// Extract headers
@@ -51,6 +59,31 @@ func GetFromAddress(from string) string {
 	return strings.TrimSpace(from)
 }
 
+// NormalizeAddress returns the canonical form of an email address for comparisons
+// It strips the display name, lowercases, and drops a "+tag" from the local part.
+// For Gmail, which ignores dots in the local part and serves googlemail.com too,
+// it also removes the dots and uses gmail.com. Other providers keep their dots.
+// Example: "John Doe <John.Doe+news@googlemail.com>" -> "johndoe@gmail.com"
+func NormalizeAddress(address string) string {
+	address = strings.ToLower(GetFromAddress(address))
+
+	at := strings.LastIndex(address, "@")
+	if at <= 0 {
+		return address
+	}
+	local, domain := address[:at], address[at+1:]
+
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+
+	return local + "@" + domain
+}
+
 // GetFromDomain extracts the domain from an email address
 // Example: "john@example.com" -> "example.com"
 func GetFromDomain(email string) string {
@@ -0,0 +1,77 @@
+package gmail
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// TestParseMessage tests header extraction and the received date
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		internalDate int64
+		expected     time.Time
+	}{
+		{name: "Internal date in epoch milliseconds", internalDate: 1735732800123, expected: time.UnixMilli(1735732800123)},
+		{name: "Missing internal date", internalDate: 0, expected: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &gmail.Message{
+				Id:           "abc123",
+				Snippet:      "It&#39;s ready",
+				InternalDate: tt.internalDate,
+				Payload: &gmail.MessagePart{
+					Headers: []*gmail.MessagePartHeader{
+						{Name: "From", Value: "Jane <jane@example.com>"},
+						{Name: "subject", Value: "Report"},
+						{Name: "Date", Value: "Wed, 1 Jan 2025 12:00:00 +0000"},
+					},
+				},
+			}
+
+			email := ParseMessage(msg)
+			if email.ID != "abc123" || email.From != "Jane <jane@example.com>" || email.Subject != "Report" {
+				t.Errorf("ParseMessage() = %+v, want ID, From and Subject from the message", email)
+			}
+			if email.Snippet != "It's ready" {
+				t.Errorf("Snippet = %q, want %q", email.Snippet, "It's ready")
+			}
+			if !email.ReceivedAt.Equal(tt.expected) {
+				t.Errorf("ReceivedAt = %v, want %v", email.ReceivedAt, tt.expected)
+			}
+		})
+	}
+}
+
+// TestNormalizeAddress tests plus aliases, Gmail dots and display names
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain address", "johndoe@gmail.com", "johndoe@gmail.com"},
+		{"Plus alias", "johndoe+news@gmail.com", "johndoe@gmail.com"},
+		{"Gmail dots", "john.doe@gmail.com", "johndoe@gmail.com"},
+		{"Dots and plus", "John.Doe+news@Gmail.com", "johndoe@gmail.com"},
+		{"Googlemail", "john.doe@googlemail.com", "johndoe@gmail.com"},
+		{"Display name", "John Doe <john.doe+news@gmail.com>", "johndoe@gmail.com"},
+		{"Quoted display name", `"Doe, John" <John.Doe@company.com>`, "john.doe@company.com"},
+		{"Other domains keep dots", "jane.doe+billing@company.com", "jane.doe@company.com"},
+		{"Leading plus is kept", "+tag@example.com", "+tag@example.com"},
+		{"Not an address", "  Newsletter  ", "newsletter"},
+		{"Empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeAddress(tt.input); got != tt.expected {
+				t.Errorf("NormalizeAddress(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
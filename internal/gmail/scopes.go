@@ -0,0 +1,183 @@
+package gmail
+
+import (
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
+)
+
+// maxListResults is the largest page Gmail's messages.list returns
+const maxListResults = 500
+
+// categoryLabels maps Gmail category search terms to the label IDs Gmail puts on messages
+var categoryLabels = map[string]string{
+	"category:primary":    "CATEGORY_PERSONAL",
+	"category:social":     "CATEGORY_SOCIAL",
+	"category:promotions": "CATEGORY_PROMOTIONS",
+	"category:updates":    "CATEGORY_UPDATES",
+	"category:forums":     "CATEGORY_FORUMS",
+}
+
+// ScopeMessages is the result of fetching messages for several scope queries
+type ScopeMessages struct {
+	Messages   []*gmail.Message           // unique messages, in fetch order
+	FetchedBy  map[string]map[string]bool // message ID -> scope queries that returned it
+	Calls      int                        // list queries sent to Gmail
+	CallsSaved int                        // list queries avoided by merging category scopes
+}
+
+// scopeFetch is one Gmail list query standing in for one or more scope queries
+type scopeFetch struct {
+	query  string   // query sent to Gmail
+	covers []string // scope queries it answers for
+	limit  int64
+}
+
+// GetMessagesForScopes fetches recent messages for each scope query, deduplicated by ID
+// Queries that only select Gmail categories (e.g. "category:social") are merged into one
+// OR query with a proportionally larger page, so N category filters cost one round trip.
+// Anything else (inbox, spam-only, queries with filter operators) is fetched on its own.
+// A failed query doesn't stop the others; the last error is returned with what was fetched.
+func (c *Client) GetMessagesForScopes(maxResults int64, queries []string) (*ScopeMessages, error) {
+	fetches := planScopeFetches(queries, maxResults)
+
+	result := &ScopeMessages{
+		FetchedBy: make(map[string]map[string]bool),
+		Calls:     len(fetches),
+	}
+	for _, f := range fetches {
+		result.CallsSaved += len(f.covers) - 1
+	}
+	if result.CallsSaved > 0 {
+		log.Debug("Merged category scopes into one query", "queries", len(queries), "api_calls", result.Calls, "calls_saved", result.CallsSaved)
+	}
+
+	var fetchErr error
+	for _, f := range fetches {
+		messages, err := c.GetRecentMessagesWithQuery(f.limit, f.query)
+		if err != nil {
+			// Scope errors get a single re-auth message from the caller instead
+			if !IsInsufficientScopeError(err) {
+				log.Warn("Error fetching messages", "query", f.query, "error", err)
+			}
+			fetchErr = err
+			continue
+		}
+
+		for _, msg := range messages {
+			if _, ok := result.FetchedBy[msg.Id]; !ok {
+				result.FetchedBy[msg.Id] = make(map[string]bool)
+				result.Messages = append(result.Messages, msg)
+			}
+			for _, q := range f.coveringQueries(msg) {
+				result.FetchedBy[msg.Id][q] = true
+			}
+		}
+	}
+
+	return result, fetchErr
+}
+
+// planScopeFetches decides which Gmail queries to send for a set of scope queries
+// Category-only queries are merged when there are two or more; order is otherwise kept.
+func planScopeFetches(queries []string, maxResults int64) []scopeFetch {
+	var fetches []scopeFetch
+	var merged []string // category-only queries
+	var terms []string  // their category terms, deduplicated
+	merge := -1         // index of the merged fetch in fetches
+
+	seen := make(map[string]bool)
+	for _, q := range queries {
+		if seen[q] {
+			continue
+		}
+		seen[q] = true
+
+		categories, ok := categoryTerms(q)
+		if !ok {
+			fetches = append(fetches, scopeFetch{query: q, covers: []string{q}, limit: maxResults})
+			continue
+		}
+
+		if merge < 0 {
+			merge = len(fetches)
+			fetches = append(fetches, scopeFetch{})
+		}
+		merged = append(merged, q)
+		for _, term := range categories {
+			if !containsTerm(terms, term) {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	if merge >= 0 {
+		if len(merged) == 1 {
+			fetches[merge] = scopeFetch{query: merged[0], covers: merged, limit: maxResults}
+		} else {
+			// Keep the per-scope page size so a busy category can't crowd out the others
+			limit := min(maxResults*int64(len(merged)), maxListResults)
+			fetches[merge] = scopeFetch{query: strings.Join(terms, " OR "), covers: merged, limit: limit}
+		}
+	}
+
+	return fetches
+}
+
+// categoryTerms returns the category terms of a query that selects only Gmail categories
+// Accepts a single term ("category:social") or the combined scope form
+// "(category:primary) OR (category:social)".
+func categoryTerms(query string) ([]string, bool) {
+	if query == "" {
+		return nil, false
+	}
+
+	var terms []string
+	for _, part := range strings.Split(query, " OR ") {
+		term := strings.TrimSpace(part)
+		if strings.HasPrefix(term, "(") && strings.HasSuffix(term, ")") {
+			term = strings.TrimSpace(term[1 : len(term)-1])
+		}
+		if _, ok := categoryLabels[term]; !ok {
+			return nil, false
+		}
+		terms = append(terms, term)
+	}
+	return terms, true
+}
+
+// coveringQueries returns the scope queries a message fetched by f belongs to
+// For a merged fetch the message's category label says which scopes returned it;
+// a message without a known category label is credited to all of them.
+func (f scopeFetch) coveringQueries(msg *gmail.Message) []string {
+	if len(f.covers) == 1 {
+		return f.covers
+	}
+
+	var covering []string
+	for _, q := range f.covers {
+		terms, _ := categoryTerms(q)
+		for _, term := range terms {
+			if containsTerm(msg.LabelIds, categoryLabels[term]) {
+				covering = append(covering, q)
+				break
+			}
+		}
+	}
+	if len(covering) == 0 {
+		return f.covers
+	}
+	return covering
+}
+
+// containsTerm reports whether list contains s
+func containsTerm(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
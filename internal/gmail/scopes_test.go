@@ -0,0 +1,136 @@
+package gmail
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestPlanScopeFetches(t *testing.T) {
+	tests := []struct {
+		name    string
+		queries []string
+		want    []scopeFetch
+	}{
+		{
+			name:    "Single scope",
+			queries: []string{"in:inbox"},
+			want:    []scopeFetch{{query: "in:inbox", covers: []string{"in:inbox"}, limit: 10}},
+		},
+		{
+			name:    "Single category is not rewritten",
+			queries: []string{"category:social"},
+			want:    []scopeFetch{{query: "category:social", covers: []string{"category:social"}, limit: 10}},
+		},
+		{
+			name:    "Categories merge into one OR query",
+			queries: []string{"category:social", "category:updates"},
+			want: []scopeFetch{
+				{query: "category:social OR category:updates", covers: []string{"category:social", "category:updates"}, limit: 20},
+			},
+		},
+		{
+			name:    "Combined scope merges with its parts",
+			queries: []string{"(category:primary) OR (category:social)", "category:social", "category:forums"},
+			want: []scopeFetch{
+				{
+					query:  "category:primary OR category:social OR category:forums",
+					covers: []string{"(category:primary) OR (category:social)", "category:social", "category:forums"},
+					limit:  30,
+				},
+			},
+		},
+		{
+			name:    "Spam and inbox stay separate",
+			queries: []string{"in:inbox", "category:promotions", "in:spam", "category:updates"},
+			want: []scopeFetch{
+				{query: "in:inbox", covers: []string{"in:inbox"}, limit: 10},
+				{query: "category:promotions OR category:updates", covers: []string{"category:promotions", "category:updates"}, limit: 20},
+				{query: "in:spam", covers: []string{"in:spam"}, limit: 10},
+			},
+		},
+		{
+			name:    "Queries with filter operators stay separate",
+			queries: []string{"(category:promotions) (from:shop.com)", "category:promotions"},
+			want: []scopeFetch{
+				{query: "(category:promotions) (from:shop.com)", covers: []string{"(category:promotions) (from:shop.com)"}, limit: 10},
+				{query: "category:promotions", covers: []string{"category:promotions"}, limit: 10},
+			},
+		},
+		{
+			name:    "Duplicates and all mail",
+			queries: []string{"", "in:inbox", ""},
+			want: []scopeFetch{
+				{query: "", covers: []string{""}, limit: 10},
+				{query: "in:inbox", covers: []string{"in:inbox"}, limit: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planScopeFetches(tt.queries, 10)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planScopeFetches(%q) = %+v, want %+v", tt.queries, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanScopeFetchesCapsPageSize(t *testing.T) {
+	queries := []string{"category:primary", "category:social", "category:promotions", "category:updates", "category:forums"}
+
+	fetches := planScopeFetches(queries, 200)
+	if len(fetches) != 1 || fetches[0].limit != maxListResults {
+		t.Errorf("planScopeFetches() = %+v, want one fetch limited to %d", fetches, maxListResults)
+	}
+}
+
+func TestCoveringQueries(t *testing.T) {
+	merged := scopeFetch{
+		query:  "category:primary OR category:social OR category:updates",
+		covers: []string{"(category:primary) OR (category:social)", "category:social", "category:updates"},
+	}
+
+	tests := []struct {
+		name   string
+		fetch  scopeFetch
+		labels []string
+		want   []string
+	}{
+		{
+			name:   "Unmerged fetch",
+			fetch:  scopeFetch{query: "in:inbox", covers: []string{"in:inbox"}},
+			labels: []string{"INBOX"},
+			want:   []string{"in:inbox"},
+		},
+		{
+			name:   "Social message",
+			fetch:  merged,
+			labels: []string{"INBOX", "CATEGORY_SOCIAL"},
+			want:   []string{"(category:primary) OR (category:social)", "category:social"},
+		},
+		{
+			name:   "Updates message",
+			fetch:  merged,
+			labels: []string{"CATEGORY_UPDATES", "UNREAD"},
+			want:   []string{"category:updates"},
+		},
+		{
+			name:   "No category label",
+			fetch:  merged,
+			labels: []string{"INBOX"},
+			want:   merged.covers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fetch.coveringQueries(&gmail.Message{LabelIds: tt.labels})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coveringQueries(%q) = %q, want %q", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
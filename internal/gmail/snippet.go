@@ -0,0 +1,87 @@
+package gmail
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// RichSnippetLength is the length a body-based snippet is cut to
+const RichSnippetLength = 200
+
+// boilerplateLines match body lines with nothing worth previewing:
+// unsubscribe footers, "view in browser" links, image alt text and bare URLs
+var boilerplateLines = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)unsubscribe`),
+	regexp.MustCompile(`(?i)view (this|it|the) (email|message)? ?(in|on) (a|your)? ?(web )?browser`),
+	regexp.MustCompile(`(?i)(having )?trouble (viewing|reading) this`),
+	regexp.MustCompile(`(?i)(email|notification|subscription) preferences`),
+	regexp.MustCompile(`(?i)^\[?(image|img|pixel|spacer|logo)\b[^\]]*\]?$`),
+	regexp.MustCompile(`(?i)^(https?://|www\.)\S+$`),
+	regexp.MustCompile(`^[\p{P}\p{S}\s]*$`), // separators like ---- or ****
+}
+
+// inlineURL matches URLs wrapped in <> or [] that plain-text bodies put after link text
+var inlineURL = regexp.MustCompile(`\s*[<\[]https?://[^>\]\s]*[>\]]`)
+
+// RichSnippet returns a preview built from the body when it says at least as much as Gmail's snippet
+// Gmail snippets are short and often start with preheader or "view in browser" text.
+func RichSnippet(snippet, body string) string {
+	rich := BodySnippet(body, RichSnippetLength)
+	if rich == "" || utf8.RuneCountInString(rich) < utf8.RuneCountInString(snippet) {
+		return snippet
+	}
+	return rich
+}
+
+// BodySnippet returns the start of a plain-text body as a single line of at most maxLen runes
+// Boilerplate lines are dropped and the text is cut on a word boundary.
+func BodySnippet(body string, maxLen int) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.Map(dropZeroWidth, body), "\n") {
+		line = strings.Join(strings.Fields(inlineURL.ReplaceAllString(line, "")), " ")
+		if line == "" || isBoilerplateLine(line) {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(line)
+
+		// Enough text for the preview; skip the rest of a long body
+		if utf8.RuneCountInString(b.String()) > maxLen {
+			break
+		}
+	}
+
+	return TruncateWords(b.String(), maxLen)
+}
+
+// isBoilerplateLine reports whether a body line is footer, tracking or link noise
+func isBoilerplateLine(line string) bool {
+	for _, re := range boilerplateLines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// TruncateWords shortens s to at most maxLen runes, cutting at a word boundary and adding "..."
+// A single very long word is cut mid-word rather than dropped.
+func TruncateWords(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+
+	cut := string(runes[:maxLen-3])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-") + "..."
+}
@@ -0,0 +1,113 @@
+package gmail
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// newsletterBody is a plain-text marketing email with the usual header and footer noise
+const newsletterBody = `View this email in your browser <https://news.example.com/v/abc123>
+[image: StreamPlus]
+
+Your free trial ends on March 15, 2025 [https://click.example.com/t/xyz].
+After that you'll be charged $12.99/month and can cancel anytime.
+
+-----
+https://click.example.com/track/open?id=998877
+Manage your email preferences | Unsubscribe <https://news.example.com/u/abc123>`
+
+// TestBodySnippet tests that previews skip boilerplate and respect the length cap
+func TestBodySnippet(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		maxLen int
+		want   string
+	}{
+		{
+			name:   "newsletter noise is dropped",
+			body:   newsletterBody,
+			maxLen: 200,
+			want:   "Your free trial ends on March 15, 2025. After that you'll be charged $12.99/month and can cancel anytime.",
+		},
+		{
+			name:   "cut on a word boundary",
+			body:   "Your package from Acme is out for delivery and should arrive today",
+			maxLen: 30,
+			want:   "Your package from Acme is...",
+		},
+		{
+			name:   "zero-width preheader padding removed",
+			body:   "Meeting moved‌‌ to 3pm\n​​\nSee you there",
+			maxLen: 200,
+			want:   "Meeting moved to 3pm See you there",
+		},
+		{
+			name:   "only boilerplate",
+			body:   "Unsubscribe here\nhttps://example.com/x\n****",
+			maxLen: 200,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BodySnippet(tt.body, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("BodySnippet() = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.maxLen {
+				t.Errorf("BodySnippet() is %d runes, want at most %d", n, tt.maxLen)
+			}
+		})
+	}
+}
+
+// TestRichSnippet tests when the body preview replaces Gmail's snippet
+func TestRichSnippet(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+
+	tests := []struct {
+		name    string
+		snippet string
+		body    string
+		want    string
+	}{
+		{"body says more", "Your trial", "Your trial ends tomorrow, renew now", "Your trial ends tomorrow, renew now"},
+		{"empty body keeps snippet", "Your trial ends", "", "Your trial ends"},
+		{"shorter body keeps snippet", "Your trial ends tomorrow", "Hi", "Your trial ends tomorrow"},
+		{"long body is capped", "word", long, TruncateWords(strings.TrimSpace(long), RichSnippetLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RichSnippet(tt.snippet, tt.body); got != tt.want {
+				t.Errorf("RichSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTruncateWords tests rune-safe truncation
+func TestTruncateWords(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		maxLen int
+		want   string
+	}{
+		{"fits", "short text", 20, "short text"},
+		{"word boundary", "hello wonderful world", 19, "hello wonderful..."},
+		{"one long word", "supercalifragilistic", 10, "superca..."},
+		{"multibyte runes", "café crème brûlée au chocolat", 16, "café crème..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateWords(tt.input, tt.maxLen); got != tt.want {
+				t.Errorf("TruncateWords(%q, %d) = %q, want %q", tt.input, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Package log provides leveled logging for the watcher and its background tasks
+// Interactive terminals get the familiar emoji status lines; anything else (a pipe,
+// a log file, systemd) gets plain key=value lines from log/slog that are easy to parse.
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+)
+
+// iconKey is the attribute carrying the emoji shown on interactive output
+const iconKey = "icon"
+
+var (
+	mu      sync.RWMutex
+	logger  = slog.New(newHandler(os.Stderr, slog.LevelInfo)) // stderr until Setup, so command output stays clean
+	logFile *os.File
+)
+
+// levelIcons are used when a message doesn't carry its own Icon
+var levelIcons = map[slog.Level]string{
+	slog.LevelDebug: "🐛",
+	slog.LevelWarn:  "⚠️ ",
+	slog.LevelError: "❌",
+}
+
+// Setup configures the level and output of the process-wide logger
+// output is "stdout" (default), "stderr" or a file path that is appended to
+func Setup(level, output string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	var file *os.File
+	switch strings.ToLower(strings.TrimSpace(output)) {
+	case "", "stdout":
+	case "stderr":
+		out = os.Stderr
+	default:
+		file, err = os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		out = file
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if logFile != nil {
+		logFile.Close()
+	}
+	logFile = file
+	logger = slog.New(newHandler(out, lvl))
+
+	return nil
+}
+
+// ParseLevel parses a level name (debug, info, warn, error); empty means info
+func ParseLevel(s string) (slog.Level, error) {
+	if strings.TrimSpace(s) == "" {
+		return slog.LevelInfo, nil
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("unknown log level '%s': expected debug, info, warn or error", s)
+	}
+	return lvl, nil
+}
+
+// Icon sets the emoji shown in front of a message on interactive output
+// Structured output drops it
+func Icon(emoji string) slog.Attr {
+	return slog.String(iconKey, emoji)
+}
+
+// Debug logs at debug level
+func Debug(msg string, args ...any) { current().Debug(msg, args...) }
+
+// Info logs at info level
+func Info(msg string, args ...any) { current().Info(msg, args...) }
+
+// Warn logs at warn level
+func Warn(msg string, args ...any) { current().Warn(msg, args...) }
+
+// Error logs at error level
+func Error(msg string, args ...any) { current().Error(msg, args...) }
+
+// current returns the configured logger
+func current() *slog.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return logger
+}
+
+// newHandler picks friendly output for terminals and structured text otherwise
+func newHandler(out *os.File, level slog.Leveler) slog.Handler {
+	if isatty.IsTerminal(out.Fd()) || isatty.IsCygwinTerminal(out.Fd()) {
+		return newFriendlyHandler(out, level)
+	}
+	return newTextHandler(out, level)
+}
+
+// newTextHandler returns slog's key=value handler without the icon attribute
+func newTextHandler(out io.Writer, level slog.Leveler) slog.Handler {
+	return slog.NewTextHandler(out, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == iconKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+// friendlyHandler writes "[15:04:05] 📧 message key=value" lines for people watching a terminal
+type friendlyHandler struct {
+	mu    *sync.Mutex
+	out   io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+}
+
+func newFriendlyHandler(out io.Writer, level slog.Leveler) *friendlyHandler {
+	return &friendlyHandler{mu: &sync.Mutex{}, out: out, level: level}
+}
+
+func (h *friendlyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *friendlyHandler) Handle(_ context.Context, r slog.Record) error {
+	icon := levelIcons[r.Level]
+	var fields []string
+
+	addAttr := func(a slog.Attr) bool {
+		if a.Key == iconKey {
+			icon = a.Value.String()
+		} else if a.Key != "" {
+			fields = append(fields, a.Key+"="+quoteIfNeeded(a.Value.String()))
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		addAttr(a)
+	}
+	r.Attrs(addAttr)
+
+	var b strings.Builder
+	if !r.Time.IsZero() {
+		b.WriteString("[" + r.Time.Format("15:04:05") + "] ")
+	}
+	if icon != "" {
+		b.WriteString(icon + " ")
+	}
+	b.WriteString(r.Message)
+	for _, f := range fields {
+		b.WriteString(" " + f)
+	}
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, b.String())
+	return err
+}
+
+func (h *friendlyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup is a no-op: groups aren't used and friendly output stays flat
+func (h *friendlyHandler) WithGroup(name string) slog.Handler {
+	return h
+}
+
+// quoteIfNeeded quotes values that would be ambiguous in key=value output
+func quoteIfNeeded(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
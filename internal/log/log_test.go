@@ -0,0 +1,160 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseLevel tests level names from monitoring.log_level
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected slog.Level
+		wantErr  bool
+	}{
+		{input: "", expected: slog.LevelInfo},
+		{input: "debug", expected: slog.LevelDebug},
+		{input: "WARN", expected: slog.LevelWarn},
+		{input: " error ", expected: slog.LevelError},
+		{input: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseLevel(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("ParseLevel(%q) = %v, %v; want %v", tt.input, got, err, tt.expected)
+			}
+		})
+	}
+}
+
+// TestHandlers tests friendly (terminal) and structured output for the same record
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     func(*bytes.Buffer) slog.Handler
+		level       slog.Level
+		args        []any
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "Friendly with icon",
+			handler:     func(b *bytes.Buffer) slog.Handler { return newFriendlyHandler(b, slog.LevelInfo) },
+			level:       slog.LevelInfo,
+			args:        []any{Icon("📧"), "filter", "Jobs", "subject", "Offer letter"},
+			contains:    []string{"] 📧 Match filter=Jobs subject=\"Offer letter\"\n"},
+			notContains: []string{"icon"},
+		},
+		{
+			name:     "Friendly level icon",
+			handler:  func(b *bytes.Buffer) slog.Handler { return newFriendlyHandler(b, slog.LevelInfo) },
+			level:    slog.LevelWarn,
+			contains: []string{"⚠️  Match"},
+		},
+		{
+			name:    "Friendly below level",
+			handler: func(b *bytes.Buffer) slog.Handler { return newFriendlyHandler(b, slog.LevelInfo) },
+			level:   slog.LevelDebug,
+		},
+		{
+			name:        "Structured drops icon",
+			handler:     func(b *bytes.Buffer) slog.Handler { return newTextHandler(b, slog.LevelInfo) },
+			level:       slog.LevelInfo,
+			args:        []any{Icon("📧"), "filter", "Jobs"},
+			contains:    []string{"level=INFO", "msg=Match", "filter=Jobs"},
+			notContains: []string{"📧", "icon"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(tt.handler(&buf))
+			logger.Log(context.Background(), tt.level, "Match", tt.args...)
+
+			got := buf.String()
+			if len(tt.contains) == 0 && got != "" {
+				t.Errorf("expected no output, got %q", got)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("output = %q, expected it to contain %q", got, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("output = %q, expected it not to contain %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+// TestFriendlyHandlerTimestamp tests the clock prefix on terminal lines
+func TestFriendlyHandlerTimestamp(t *testing.T) {
+	var buf bytes.Buffer
+	h := newFriendlyHandler(&buf, slog.LevelInfo)
+
+	r := slog.NewRecord(time.Date(2025, 1, 15, 9, 5, 7, 0, time.UTC), slog.LevelInfo, "Checked 10 messages", 0)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if got := buf.String(); got != "[09:05:07] Checked 10 messages\n" {
+		t.Errorf("Handle() wrote %q", got)
+	}
+}
+
+// TestEmailRedaction tests the sender/subject attributes with redact_logs on and off
+func TestEmailRedaction(t *testing.T) {
+	defer SetRedact(false)
+
+	tests := []struct {
+		name   string
+		redact bool
+		want   []any
+	}{
+		{
+			name:   "Off",
+			redact: false,
+			want:   []any{"from", "Boss@Work.com", "subject", "Q3 numbers"},
+		},
+		{
+			name:   "On",
+			redact: true,
+			want:   []any{"from", HashAddress("boss@work.com")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRedact(tt.redact)
+
+			got := Email("Boss@Work.com", "Q3 numbers")
+			if len(got) != len(tt.want) {
+				t.Fatalf("Email() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Email()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if h := HashAddress("boss@work.com"); !strings.HasPrefix(h, "sha256:") || len(h) != len("sha256:")+8 || strings.Contains(h, "boss") {
+		t.Errorf("HashAddress() = %q, expected a short sha256 prefix", h)
+	}
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+)
+
+// redact is set by SetRedact (monitoring.redact_logs)
+var redact atomic.Bool
+
+// SetRedact turns PII-safe logging on or off
+// When on, email addresses are replaced by a short hash and subjects are omitted.
+func SetRedact(on bool) {
+	redact.Store(on)
+}
+
+// Redacting reports whether PII-safe logging is on
+func Redacting() bool {
+	return redact.Load()
+}
+
+// Address returns addr unchanged, or a short stable hash of it when redacting
+// The same address always maps to the same hash, so log lines can still be correlated.
+func Address(addr string) string {
+	if !Redacting() || addr == "" {
+		return addr
+	}
+	return HashAddress(addr)
+}
+
+// HashAddress returns a short, case-insensitive hash of an email address like "sha256:3f9a2c1b"
+func HashAddress(addr string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(addr))))
+	return "sha256:" + hex.EncodeToString(sum[:4])
+}
+
+// Email returns the attributes describing an email's sender and subject
+// When redacting only the hashed sender is included.
+func Email(sender, subject string) []any {
+	if Redacting() {
+		return []any{"from", Address(sender)}
+	}
+	return []any{"from", sender, "subject", subject}
+}
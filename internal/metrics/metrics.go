@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// namespace prefixes every metric name
+const namespace = "email_sentinel_"
+
+// Counters and gauges for the running watcher
+// Updated from the monitoring loop and notification senders; safe for concurrent use.
+var (
+	emailsChecked       atomic.Int64
+	gmailAPIErrors      atomic.Int64
+	aiTokensUsed        atomic.Int64
+	consecutiveFailures atomic.Int64
+	lastCheckUnix       atomic.Int64 // 0 until the first check
+
+	mu                     sync.Mutex
+	matchesByFilter        = make(map[string]int64)
+	notificationsByChannel = make(map[string]int64)
+)
+
+// AddEmailsChecked counts new emails run through the filters
+func AddEmailsChecked(n int) {
+	emailsChecked.Add(int64(n))
+}
+
+// IncMatch counts an email matched by filterName
+func IncMatch(filterName string) {
+	mu.Lock()
+	defer mu.Unlock()
+	matchesByFilter[filterName]++
+}
+
+// IncNotification counts a notification delivered on channel ("desktop", "mobile", "webhook")
+func IncNotification(channel string) {
+	mu.Lock()
+	defer mu.Unlock()
+	notificationsByChannel[channel]++
+}
+
+// IncGmailAPIError counts a failed Gmail API fetch
+func IncGmailAPIError() {
+	gmailAPIErrors.Add(1)
+}
+
+// AddAITokens counts tokens spent on AI summaries
+func AddAITokens(n int) {
+	aiTokensUsed.Add(int64(n))
+}
+
+// SetConsecutiveFailures records the circuit breaker's current failure streak
+func SetConsecutiveFailures(n int) {
+	consecutiveFailures.Store(int64(n))
+}
+
+// SetLastCheck records when the last email check finished
+func SetLastCheck(t time.Time) {
+	lastCheckUnix.Store(t.Unix())
+}
+
+// Reset zeroes every metric (used by tests)
+func Reset() {
+	emailsChecked.Store(0)
+	gmailAPIErrors.Store(0)
+	aiTokensUsed.Store(0)
+	consecutiveFailures.Store(0)
+	lastCheckUnix.Store(0)
+
+	mu.Lock()
+	defer mu.Unlock()
+	matchesByFilter = make(map[string]int64)
+	notificationsByChannel = make(map[string]int64)
+}
+
+// WriteText writes every metric in the Prometheus text exposition format
+func WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	writeMetric(bw, "emails_checked_total", "counter", "New emails checked against filters.", emailsChecked.Load())
+
+	mu.Lock()
+	matches := copyCounts(matchesByFilter)
+	notifications := copyCounts(notificationsByChannel)
+	mu.Unlock()
+	writeLabeled(bw, "matches_total", "counter", "Emails matched, by filter.", "filter", matches)
+	writeLabeled(bw, "notifications_sent_total", "counter", "Notifications delivered, by channel.", "channel", notifications)
+
+	writeMetric(bw, "gmail_api_errors_total", "counter", "Failed Gmail API fetches.", gmailAPIErrors.Load())
+	writeMetric(bw, "ai_tokens_used_total", "counter", "Tokens spent on AI summaries.", aiTokensUsed.Load())
+	writeMetric(bw, "consecutive_failures", "gauge", "Consecutive failed checks (circuit breaker).", consecutiveFailures.Load())
+	writeMetric(bw, "last_check_timestamp_seconds", "gauge", "Unix time of the last email check (0 = none yet).", lastCheckUnix.Load())
+
+	return bw.Flush()
+}
+
+// writeMetric writes one unlabeled metric with its HELP and TYPE lines
+func writeMetric(w io.Writer, name, kind, help string, value int64) {
+	fmt.Fprintf(w, "# HELP %s%s %s\n# TYPE %s%s %s\n%s%s %d\n",
+		namespace, name, help, namespace, name, kind, namespace, name, value)
+}
+
+// writeLabeled writes a metric with one sample per label value, sorted for stable output
+func writeLabeled(w io.Writer, name, kind, help, label string, values map[string]int64) {
+	fmt.Fprintf(w, "# HELP %s%s %s\n# TYPE %s%s %s\n", namespace, name, help, namespace, name, kind)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s%s{%s=\"%s\"} %d\n", namespace, name, label, escapeLabelValue(k), values[k])
+	}
+}
+
+// escapeLabelValue escapes a label value as the exposition format requires
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// copyCounts returns a copy of a counter map so it can be written without holding the lock
+func copyCounts(src map[string]int64) map[string]int64 {
+	dst := make(map[string]int64, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteText(w)
+	})
+}
+
+// Serve starts the metrics endpoint on addr in the background
+// The listener is opened before returning, so a port that's in use is reported right away.
+func Serve(addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go server.Serve(listener)
+
+	return server, nil
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteText(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	AddEmailsChecked(7)
+	AddEmailsChecked(3)
+	IncMatch("Job Alerts")
+	IncMatch("Job Alerts")
+	IncMatch(`Say "hi"`)
+	IncNotification("desktop")
+	IncNotification("webhook")
+	IncGmailAPIError()
+	AddAITokens(120)
+	SetConsecutiveFailures(2)
+	SetLastCheck(time.Unix(1700000000, 0))
+
+	var b strings.Builder
+	if err := WriteText(&b); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	out := b.String()
+
+	want := []string{
+		"# TYPE email_sentinel_emails_checked_total counter",
+		"email_sentinel_emails_checked_total 10\n",
+		`email_sentinel_matches_total{filter="Job Alerts"} 2`,
+		`email_sentinel_matches_total{filter="Say \"hi\""} 1`,
+		`email_sentinel_notifications_sent_total{channel="desktop"} 1`,
+		`email_sentinel_notifications_sent_total{channel="webhook"} 1`,
+		"email_sentinel_gmail_api_errors_total 1\n",
+		"email_sentinel_ai_tokens_used_total 120\n",
+		"# TYPE email_sentinel_consecutive_failures gauge",
+		"email_sentinel_consecutive_failures 2\n",
+		"email_sentinel_last_check_timestamp_seconds 1700000000\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("WriteText() output missing %q\n%s", w, out)
+		}
+	}
+
+	// Label values are sorted so scrapes are stable
+	if strings.Index(out, `filter="Job Alerts"`) > strings.Index(out, `filter="Say`) {
+		t.Errorf("matches_total samples not sorted by filter\n%s", out)
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{`a"b`, `a\"b`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines", `two\nlines`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLabelValue(tt.input); got != tt.expected {
+			t.Errorf("escapeLabelValue(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	Reset()
+	defer Reset()
+	AddEmailsChecked(1)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), "email_sentinel_emails_checked_total 1\n") {
+		t.Errorf("body missing emails_checked_total:\n%s", body)
+	}
+}
@@ -0,0 +1,147 @@
+//go:build !windows
+// +build !windows
+
+package notify
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/log"
+)
+
+// actionWaitTimeout bounds how long we wait for the user to click a notification
+const actionWaitTimeout = 10 * time.Minute
+
+var (
+	notifySendActionsOnce sync.Once
+	notifySendHasActions  bool
+)
+
+// sendNotificationWithOpenAction shows a notification that opens link when clicked
+// Uses notify-send --action on Linux (libnotify 0.7.9+) and terminal-notifier on macOS.
+// Returns false if actions aren't supported here, so the caller can send a plain notification.
+func sendNotificationWithOpenAction(title, message, link string, opts DesktopOptions) bool {
+	// Only validated Gmail links are ever handed to the OS
+	if !gmail.IsValidGmailURL(link) {
+		return false
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return sendTerminalNotifier(title, message, link, opts)
+	default:
+		return sendNotifySendWithAction(title, message, link, opts)
+	}
+}
+
+// sendNotifySendWithAction uses notify-send's --wait/--action support
+// notify-send prints the invoked action key on stdout once the user clicks
+func sendNotifySendWithAction(title, message, link string, opts DesktopOptions) bool {
+	if !notifySendSupportsActions() {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), actionWaitTimeout)
+	args := append(notifySendOptionArgs(opts),
+		"--action=default=Open Email",
+		"--action=open=Open Email",
+		"--wait",
+		title,
+		message,
+	)
+	cmd := exec.CommandContext(ctx, "notify-send", args...)
+
+	var stdout strings.Builder
+	cmd.Stdout = &stdout
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return false
+	}
+	RecordDesktopSuccess()
+
+	// Wait for a click (or dismissal) in the background
+	go func() {
+		defer cancel()
+		if err := cmd.Wait(); err != nil {
+			return
+		}
+		if isOpenAction(stdout.String()) {
+			if err := gmail.OpenGmailLink(link); err != nil {
+				log.Warn("Failed to open email", "error", err)
+			}
+		}
+	}()
+
+	return true
+}
+
+// sendTerminalNotifier uses terminal-notifier (brew install terminal-notifier) on macOS
+// Clicking the notification opens the link
+func sendTerminalNotifier(title, message, link string, opts DesktopOptions) bool {
+	path, err := exec.LookPath("terminal-notifier")
+	if err != nil {
+		return false
+	}
+
+	args := []string{
+		"-title", "Email Sentinel",
+		"-subtitle", title,
+		"-message", message,
+		"-open", link,
+	}
+	if opts.Sound {
+		args = append(args, "-sound", "default")
+	}
+
+	cmd := exec.Command(path, args...)
+	if err := cmd.Start(); err != nil {
+		return false
+	}
+	RecordDesktopSuccess()
+
+	// Reap the process without blocking the caller
+	go cmd.Wait()
+
+	return true
+}
+
+// notifySendSupportsActions reports whether the installed notify-send accepts --action
+// Checked once per process
+func notifySendSupportsActions() bool {
+	notifySendActionsOnce.Do(func() {
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return
+		}
+
+		out, err := exec.Command(path, "--help").CombinedOutput()
+		if err != nil {
+			return
+		}
+		notifySendHasActions = helpListsActions(string(out))
+	})
+
+	return notifySendHasActions
+}
+
+// helpListsActions reports whether notify-send --help output documents --action and --wait
+func helpListsActions(help string) bool {
+	return strings.Contains(help, "--action") && strings.Contains(help, "--wait")
+}
+
+// isOpenAction reports whether notify-send output means the user clicked to open the email
+func isOpenAction(output string) bool {
+	switch strings.TrimSpace(output) {
+	case "default", "open":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+// +build !windows
+
+package notify
+
+import "testing"
+
+func TestHelpListsActions(t *testing.T) {
+	tests := []struct {
+		name string
+		help string
+		want bool
+	}{
+		{"libnotify 0.8", "  -A, --action=[NAME=]Text...  Specifies the actions to display to the user.\n  -w, --wait  Wait for the notification to be closed before exiting.", true},
+		{"old libnotify", "  -u, --urgency=LEVEL  Specifies the urgency level\n  -t, --expire-time=TIME", false},
+		{"action without wait", "  -A, --action=[NAME=]Text...", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := helpListsActions(tt.help); got != tt.want {
+				t.Errorf("helpListsActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOpenAction(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"default\n", true},
+		{"open\n", true},
+		{"", false},    // dismissed
+		{"1\n", false}, // unexpected action id
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if got := isOpenAction(tt.output); got != tt.want {
+				t.Errorf("isOpenAction(%q) = %v, want %v", tt.output, got, tt.want)
+			}
+		})
+	}
+}
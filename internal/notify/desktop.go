@@ -2,14 +2,78 @@ package notify
 
 import (
 	"fmt"
-
-	"github.com/gen2brain/beeep"
+	"sync/atomic"
+	"time"
 )
 
+// defaultDesktopDuration is how long a notification stays up when no duration is configured
+const defaultDesktopDuration = 5 * time.Second
+
+// DesktopOptions controls how desktop notifications are shown
+// The zero value is a short, silent notification.
+type DesktopOptions struct {
+	Duration time.Duration // how long the notification stays up (where the platform allows it)
+	Sound    bool          // play the platform's notification sound
+}
+
+// desktopEnabled is the runtime desktop notification switch
+// Set from config at startup and toggled from the system tray
+var desktopEnabled atomic.Bool
+
+// SetDesktopEnabled turns desktop alert notifications on or off
+func SetDesktopEnabled(enabled bool) {
+	desktopEnabled.Store(enabled)
+}
+
+// DesktopEnabled reports whether desktop alert notifications are on
+func DesktopEnabled() bool {
+	return desktopEnabled.Load()
+}
+
+// ToggleDesktopEnabled flips desktop alert notifications and returns the new setting
+func ToggleDesktopEnabled() bool {
+	for {
+		old := desktopEnabled.Load()
+		if desktopEnabled.CompareAndSwap(old, !old) {
+			return !old
+		}
+	}
+}
+
+// desktopOptions holds the current DesktopOptions, set from config at startup
+var desktopOptions atomic.Pointer[DesktopOptions]
+
+// SetDesktopOptions sets the duration and sound used for desktop notifications
+func SetDesktopOptions(opts DesktopOptions) {
+	desktopOptions.Store(&opts)
+}
+
+// currentDesktopOptions returns the configured DesktopOptions (short and silent if never set)
+func currentDesktopOptions() DesktopOptions {
+	if opts := desktopOptions.Load(); opts != nil {
+		return *opts
+	}
+	return DesktopOptions{}
+}
+
+// displayDuration returns how long the notification should stay up
+func (o DesktopOptions) displayDuration() time.Duration {
+	if o.Duration <= 0 {
+		return defaultDesktopDuration
+	}
+	return o.Duration
+}
+
+// longToast reports whether a Windows toast should use the long (~25s) display
+// Windows only offers short (~7s) and long toasts, so pick the closer one.
+func (o DesktopOptions) longToast() bool {
+	return o.displayDuration() >= 16*time.Second
+}
+
 // SendDesktopNotification sends a native OS notification
+// Uses the duration and sound set with SetDesktopOptions where the platform supports them
 func SendDesktopNotification(title, message string) error {
-	// Use beeep to send cross-platform notification
-	err := beeep.Notify(title, message, "")
+	err := sendNativeNotification(title, message, currentDesktopOptions())
 	if err != nil {
 		RecordDesktopFailure()
 		return fmt.Errorf("failed to send desktop notification: %w", err)
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDesktopOptionsDisplay(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     DesktopOptions
+		duration time.Duration
+		long     bool
+	}{
+		{"Unset is short", DesktopOptions{}, defaultDesktopDuration, false},
+		{"Negative is short", DesktopOptions{Duration: -time.Second}, defaultDesktopDuration, false},
+		{"Default config", DesktopOptions{Duration: 10 * time.Second}, 10 * time.Second, false},
+		{"Long", DesktopOptions{Duration: 30 * time.Second}, 30 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.displayDuration(); got != tt.duration {
+				t.Errorf("displayDuration() = %v, want %v", got, tt.duration)
+			}
+			if got := tt.opts.longToast(); got != tt.long {
+				t.Errorf("longToast() = %v, want %v", got, tt.long)
+			}
+		})
+	}
+}
+
+func TestCurrentDesktopOptions(t *testing.T) {
+	t.Cleanup(func() { desktopOptions.Store(nil) })
+
+	if got := currentDesktopOptions(); got != (DesktopOptions{}) {
+		t.Errorf("currentDesktopOptions() before SetDesktopOptions = %+v, want zero value", got)
+	}
+
+	want := DesktopOptions{Duration: 10 * time.Second, Sound: true}
+	SetDesktopOptions(want)
+	if got := currentDesktopOptions(); got != want {
+		t.Errorf("currentDesktopOptions() = %+v, want %+v", got, want)
+	}
+}
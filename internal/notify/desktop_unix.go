@@ -0,0 +1,52 @@
+//go:build !windows
+// +build !windows
+
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/gen2brain/beeep"
+)
+
+// notifySendSound is the freedesktop sound theme name played for new alerts
+const notifySendSound = "message-new-email"
+
+// sendNativeNotification shows a plain notification with the given options
+// Uses notify-send on Linux and osascript on macOS when sound is on, falling back
+// to beeep (which can't set a duration or sound) when those aren't available.
+func sendNativeNotification(title, message string, opts DesktopOptions) error {
+	switch runtime.GOOS {
+	case "darwin":
+		if opts.Sound {
+			if path, err := exec.LookPath("osascript"); err == nil {
+				script := fmt.Sprintf("display notification %q with title %q sound name \"default\"", message, title)
+				return exec.Command(path, "-e", script).Run()
+			}
+		}
+	default:
+		if path, err := exec.LookPath("notify-send"); err == nil {
+			args := append(notifySendOptionArgs(opts), title, message)
+			return exec.Command(path, args...).Run()
+		}
+	}
+
+	return beeep.Notify(title, message, "")
+}
+
+// notifySendOptionArgs returns the notify-send flags for the app name, duration and sound
+// Sound is a hint: notification daemons that don't play sounds ignore it.
+func notifySendOptionArgs(opts DesktopOptions) []string {
+	args := []string{
+		"--app-name=Email Sentinel",
+		fmt.Sprintf("--expire-time=%d", opts.displayDuration().Milliseconds()),
+	}
+	if opts.Sound {
+		args = append(args, "--hint=string:sound-name:"+notifySendSound)
+	} else {
+		args = append(args, "--hint=boolean:suppress-sound:true")
+	}
+	return args
+}
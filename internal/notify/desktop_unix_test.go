@@ -0,0 +1,37 @@
+//go:build !windows
+// +build !windows
+
+package notify
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotifySendOptionArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts DesktopOptions
+		want []string
+	}{
+		{
+			name: "Unset is short and silent",
+			opts: DesktopOptions{},
+			want: []string{"--app-name=Email Sentinel", "--expire-time=5000", "--hint=boolean:suppress-sound:true"},
+		},
+		{
+			name: "Configured duration and sound",
+			opts: DesktopOptions{Duration: 10 * time.Second, Sound: true},
+			want: []string{"--app-name=Email Sentinel", "--expire-time=10000", "--hint=string:sound-name:message-new-email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notifySendOptionArgs(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("notifySendOptionArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+//go:build windows
+// +build windows
+
+package notify
+
+import (
+	"github.com/gen2brain/beeep"
+	"github.com/go-toast/toast"
+)
+
+// sendNativeNotification shows a plain toast notification with the given options
+// Falls back to beeep (e.g. on Windows 7, where toasts aren't available)
+func sendNativeNotification(title, message string, opts DesktopOptions) error {
+	notification := newToast(toastContent{Title: title, Message: message})
+	applyToastOptions(&notification, opts)
+
+	if err := notification.Push(); err != nil {
+		return beeep.Notify(title, message, "")
+	}
+	return nil
+}
+
+// applyToastOptions silences a toast when sound is off and lengthens it for long durations
+// A toast that is already long (e.g. a critical alert) stays long.
+func applyToastOptions(n *toast.Notification, opts DesktopOptions) {
+	if !opts.Sound {
+		n.Audio = toast.Silent
+		n.Loop = false
+	}
+	if opts.longToast() {
+		n.Duration = toast.Long
+	}
+}
+
+// newToast builds a toast notification with escaped text and buttons
+func newToast(c toastContent) toast.Notification {
+	n := toast.Notification{
+		AppID:   AppID,
+		Title:   escapeToastText(c.Title),
+		Message: escapeToastText(c.Message),
+		Audio:   toast.Default,
+	}
+	for _, b := range c.Buttons {
+		n.Actions = append(n.Actions, toast.Action{
+			Type:      "protocol",
+			Label:     escapeToastAttr(b.Label),
+			Arguments: escapeToastAttr(b.URI),
+		})
+	}
+	return n
+}
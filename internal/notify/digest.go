@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// digestPreviewCount is how many emails a digest notification lists by name
+const digestPreviewCount = 3
+
+// DigestEntry is one email held back for a digest notification
+type DigestEntry struct {
+	From    string
+	Subject string
+}
+
+// Digest is a batch of matches for one filter, sent as a single notification
+type Digest struct {
+	Filter  string   // matched filter name(s)
+	Topics  []string // ntfy topics the individual pushes would have gone to
+	Entries []DigestEntry
+}
+
+// Digester batches matches per filter and sends each batch when its interval ends
+// The first match for a filter starts its window; matches until the window closes
+// are sent together. Safe for concurrent use.
+type Digester struct {
+	mu      sync.Mutex
+	pending map[string]*pendingDigest
+	send    func(Digest)
+}
+
+// pendingDigest is a digest waiting for its timer
+type pendingDigest struct {
+	digest Digest
+	timer  *time.Timer
+}
+
+// NewDigester creates a Digester that delivers batches with send
+func NewDigester(send func(Digest)) *Digester {
+	return &Digester{
+		pending: make(map[string]*pendingDigest),
+		send:    send,
+	}
+}
+
+// Add holds an email for filterName's digest, starting a window of interval if none is open
+func (d *Digester) Add(filterName string, interval time.Duration, topics []string, entry DigestEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p, ok := d.pending[filterName]
+	if !ok {
+		p = &pendingDigest{digest: Digest{Filter: filterName}}
+		p.timer = time.AfterFunc(interval, func() { d.flush(filterName) })
+		d.pending[filterName] = p
+	}
+
+	p.digest.Entries = append(p.digest.Entries, entry)
+	for _, topic := range topics {
+		if !containsString(p.digest.Topics, topic) {
+			p.digest.Topics = append(p.digest.Topics, topic)
+		}
+	}
+}
+
+// Pending returns the number of emails waiting in digests
+func (d *Digester) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	count := 0
+	for _, p := range d.pending {
+		count += len(p.digest.Entries)
+	}
+	return count
+}
+
+// FlushAll sends every pending digest now (used on shutdown)
+func (d *Digester) FlushAll() {
+	d.mu.Lock()
+	names := make([]string, 0, len(d.pending))
+	for name, p := range d.pending {
+		p.timer.Stop()
+		names = append(names, name)
+	}
+	d.mu.Unlock()
+
+	for _, name := range names {
+		d.flush(name)
+	}
+}
+
+// flush sends and forgets one filter's digest
+// The send runs outside the lock so a slow notification can't block Add.
+func (d *Digester) flush(filterName string) {
+	d.mu.Lock()
+	p, ok := d.pending[filterName]
+	delete(d.pending, filterName)
+	d.mu.Unlock()
+
+	if ok && len(p.digest.Entries) > 0 {
+		d.send(p.digest)
+	}
+}
+
+// Summary returns the notification title and message for a digest
+// e.g. "📬 7 new matches for Newsletters" listing the first few senders and subjects
+func (dg Digest) Summary() (title, message string) {
+	n := len(dg.Entries)
+	if n == 1 {
+		title = fmt.Sprintf("📬 1 new match for %s", dg.Filter)
+	} else {
+		title = fmt.Sprintf("📬 %d new matches for %s", n, dg.Filter)
+	}
+
+	lines := make([]string, 0, digestPreviewCount+1)
+	for i, e := range dg.Entries {
+		if i == digestPreviewCount {
+			lines = append(lines, fmt.Sprintf("…and %d more", n-digestPreviewCount))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", senderName(e.From), e.Subject))
+	}
+
+	return title, strings.Join(lines, "\n")
+}
+
+// senderName shortens "Name <addr>" to the display name for compact digest lines
+func senderName(from string) string {
+	if i := strings.Index(from, "<"); i > 0 {
+		if name := strings.Trim(strings.TrimSpace(from[:i]), `"`); name != "" {
+			return name
+		}
+	}
+	return from
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
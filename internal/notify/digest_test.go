@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDigesterBatchesPerFilter(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []Digest
+	)
+	d := NewDigester(func(dg Digest) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, dg)
+	})
+
+	d.Add("Newsletters", time.Hour, []string{"news"}, DigestEntry{From: "a@example.com", Subject: "one"})
+	d.Add("Newsletters", time.Hour, []string{"news"}, DigestEntry{From: "b@example.com", Subject: "two"})
+	d.Add("Deals", time.Hour, nil, DigestEntry{From: "c@example.com", Subject: "three"})
+
+	if got := d.Pending(); got != 3 {
+		t.Fatalf("Pending() = %d, want 3", got)
+	}
+
+	d.FlushAll()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 2 {
+		t.Fatalf("FlushAll() sent %d digests, want 2", len(sent))
+	}
+	for _, dg := range sent {
+		switch dg.Filter {
+		case "Newsletters":
+			if len(dg.Entries) != 2 || len(dg.Topics) != 1 || dg.Topics[0] != "news" {
+				t.Errorf("Newsletters digest = %+v, want 2 entries for topic news", dg)
+			}
+		case "Deals":
+			if len(dg.Entries) != 1 {
+				t.Errorf("Deals digest = %+v, want 1 entry", dg)
+			}
+		default:
+			t.Errorf("unexpected digest for %q", dg.Filter)
+		}
+	}
+	if got := d.Pending(); got != 0 {
+		t.Errorf("Pending() after FlushAll = %d, want 0", got)
+	}
+}
+
+func TestDigesterFlushesOnTimer(t *testing.T) {
+	done := make(chan Digest, 1)
+	d := NewDigester(func(dg Digest) { done <- dg })
+
+	d.Add("Newsletters", 20*time.Millisecond, nil, DigestEntry{Subject: "one"})
+
+	select {
+	case dg := <-done:
+		if dg.Filter != "Newsletters" || len(dg.Entries) != 1 {
+			t.Errorf("digest = %+v, want one Newsletters entry", dg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("digest was not sent when its interval ended")
+	}
+
+	// A new match after the flush opens a new window
+	d.Add("Newsletters", time.Hour, nil, DigestEntry{Subject: "two"})
+	if got := d.Pending(); got != 1 {
+		t.Errorf("Pending() = %d, want 1", got)
+	}
+}
+
+func TestDigestSummary(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     int
+		wantTitle   string
+		wantLines   int
+		wantMessage string
+	}{
+		{"single match", 1, "📬 1 new match for Newsletters", 1, "Weekly News: issue 1"},
+		{"several matches", 3, "📬 3 new matches for Newsletters", 3, "Weekly News: issue 3"},
+		{"more than the preview", 7, "📬 7 new matches for Newsletters", 4, "…and 4 more"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := Digest{Filter: "Newsletters"}
+			for i := 1; i <= tt.entries; i++ {
+				dg.Entries = append(dg.Entries, DigestEntry{
+					From:    `"Weekly News" <news@example.com>`,
+					Subject: "issue " + string(rune('0'+i)),
+				})
+			}
+
+			title, message := dg.Summary()
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			lines := strings.Split(message, "\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("message has %d lines, want %d:\n%s", len(lines), tt.wantLines, message)
+			}
+			if !strings.Contains(message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", message, tt.wantMessage)
+			}
+		})
+	}
+}
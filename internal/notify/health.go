@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/metrics"
 )
 
 // NotificationHealth tracks the health status of notification delivery
@@ -35,6 +37,7 @@ func RecordDesktopSuccess() {
 
 	health.desktopFailures = 0
 	health.lastDesktopOK = time.Now()
+	metrics.IncNotification("desktop")
 }
 
 // RecordDesktopFailure records a failed desktop notification
@@ -53,6 +56,7 @@ func RecordMobileSuccess() {
 
 	health.mobileFailures = 0
 	health.lastMobileOK = time.Now()
+	metrics.IncNotification("mobile")
 }
 
 // RecordMobileFailure records a failed mobile notification
@@ -4,86 +4,204 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
 const ntfyBaseURL = "https://ntfy.sh"
 
-// SendMobileNotification sends a push notification via ntfy.sh
-func SendMobileNotification(topic, title, message string) error {
-	if topic == "" {
-		return fmt.Errorf("ntfy topic is empty")
-	}
+// ntfy message priorities (1 = min ... 5 = urgent, which breaks through Do Not Disturb)
+const (
+	ntfyPriorityDefault = 4 // "high" - what every push used before the priority was configurable
+	ntfyPriorityMax     = 5
+)
 
-	url := fmt.Sprintf("%s/%s", ntfyBaseURL, topic)
+// labelEmoji maps common filter labels to ntfy emoji shortcodes
+// ntfy shows a tag that is a known shortcode as an emoji in front of the title.
+var labelEmoji = map[string]string{
+	"work":      "briefcase",
+	"jobs":      "briefcase",
+	"personal":  "house",
+	"family":    "house",
+	"urgent":    "rotating_light",
+	"important": "exclamation",
+	"finance":   "moneybag",
+	"bills":     "moneybag",
+	"invoices":  "receipt",
+	"banking":   "bank",
+	"shopping":  "shopping_cart",
+	"orders":    "package",
+	"shipping":  "package",
+	"travel":    "airplane",
+	"security":  "lock",
+	"school":    "school",
+	"calendar":  "calendar",
+	"meetings":  "calendar",
+	"social":    "speech_balloon",
+	"news":      "newspaper",
+	"health":    "hospital",
+}
 
-	// Create request body with title and message
-	body := fmt.Sprintf("%s\n\n%s", title, message)
+// MobileOptions controls where and how ntfy pushes are sent
+// The zero value sends to ntfy.sh at high priority without credentials.
+type MobileOptions struct {
+	Server   string // ntfy server URL ("" = https://ntfy.sh)
+	Priority int    // ntfy priority 1-5 for regular alerts (0 = 4, "high")
+	Auth     string // access token, or "user:password" for basic auth ("" = none)
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBufferString(body))
+// mobileOptions holds the current MobileOptions, set from config at startup
+var mobileOptions atomic.Pointer[MobileOptions]
+
+// SetMobileOptions sets the server, priority and credentials used for mobile notifications
+func SetMobileOptions(opts MobileOptions) {
+	mobileOptions.Store(&opts)
+}
+
+// MobileOptionsFromConfig returns the ntfy settings from notifications.mobile in app-config.yaml
+// An invalid server is an error rather than a fallback, so alerts never go to the public ntfy.sh by mistake.
+func MobileOptionsFromConfig(m *appconfig.MobileNotifConfig) (MobileOptions, error) {
+	server, err := m.GetServer()
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return MobileOptions{}, err
 	}
+	return MobileOptions{
+		Server:   server,
+		Priority: m.Priority,
+		Auth:     m.GetAuth(),
+	}, nil
+}
 
-	// Set headers
-	req.Header.Set("Title", title)
-	req.Header.Set("Priority", "high")
-	req.Header.Set("Tags", "email,alert")
+// currentMobileOptions returns the configured MobileOptions (ntfy.sh at high priority if never set)
+func currentMobileOptions() MobileOptions {
+	if opts := mobileOptions.Load(); opts != nil {
+		return *opts
+	}
+	return MobileOptions{}
+}
 
-	// Send request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		RecordMobileFailure()
-		return fmt.Errorf("failed to send mobile notification: %w", err)
+// ntfyPriority returns the ntfy priority for an alert of the given email priority
+// High and critical emails always go out at max priority so they break through Do Not Disturb.
+func (o MobileOptions) ntfyPriority(emailPriority int) int {
+	if emailPriority >= storage.PriorityHigh {
+		return ntfyPriorityMax
 	}
-	defer resp.Body.Close()
+	if o.Priority < 1 || o.Priority > ntfyPriorityMax {
+		return ntfyPriorityDefault
+	}
+	return o.Priority
+}
 
-	if resp.StatusCode != http.StatusOK {
-		RecordMobileFailure()
-		return fmt.Errorf("ntfy.sh returned status %d", resp.StatusCode)
+// server returns the ntfy server URL without a trailing slash
+func (o MobileOptions) server() string {
+	if server := strings.TrimRight(strings.TrimSpace(o.Server), "/"); server != "" {
+		return server
 	}
+	return ntfyBaseURL
+}
 
-	RecordMobileSuccess()
-	return nil
+// setAuth adds the Authorization header for a protected ntfy server or topic
+// Credentials with a colon are sent as basic auth, anything else as a bearer token.
+func (o MobileOptions) setAuth(req *http.Request) {
+	auth := strings.TrimSpace(o.Auth)
+	if auth == "" {
+		return
+	}
+	if user, pass, ok := strings.Cut(auth, ":"); ok {
+		req.SetBasicAuth(user, pass)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+auth)
+}
+
+// SendMobileNotification sends a push notification via ntfy
+func SendMobileNotification(topic, title, message string) error {
+	opts := currentMobileOptions()
+	return sendNtfy(opts, topic, title, message, opts.ntfyPriority(storage.PriorityNormal), []string{"email", "alert"})
 }
 
 // SendMobileEmailAlert sends a mobile notification for a matched email
 func SendMobileEmailAlert(topic, filterName, from, subject string) error {
-	title := fmt.Sprintf("📧 %s", filterName)
-	message := fmt.Sprintf("From: %s\nSubject: %s", from, subject)
-
-	return SendMobileNotification(topic, title, message)
+	return SendMobileEmailAlertWithLabels(topic, filterName, nil, from, subject, storage.PriorityNormal)
 }
 
 // SendMobileEmailAlertWithLabels sends a mobile notification for a matched email with labels
-func SendMobileEmailAlertWithLabels(topic, filterName string, labels []string, from, subject string) error {
+// priority is the email's priority (storage.PriorityNormal/High/Critical); high and above
+// are pushed at ntfy's max priority, everything else at the configured one.
+func SendMobileEmailAlertWithLabels(topic, filterName string, labels []string, from, subject string, priority int) error {
 	title := fmt.Sprintf("📧 %s", filterName)
 	message := fmt.Sprintf("From: %s\nSubject: %s", from, subject)
 
-	// Add labels as tags for ntfy.sh
-	tags := "email,alert"
 	if len(labels) > 0 {
 		labelsStr := ""
 		for _, label := range labels {
 			labelsStr += "🏷️ " + label + " "
-			tags += "," + label // Add labels as tags for better filtering
 		}
 		message = fmt.Sprintf("%s\n%s", labelsStr, message)
 	}
 
-	// Create custom request to include label tags
-	url := fmt.Sprintf("%s/%s", ntfyBaseURL, topic)
+	opts := currentMobileOptions()
+	return sendNtfy(opts, topic, title, message, opts.ntfyPriority(priority), alertTags(labels, priority))
+}
+
+// alertTags returns the ntfy tags for an alert: an emoji for urgent mail and known
+// labels, then the labels themselves so they can be filtered on in the app
+func alertTags(labels []string, priority int) []string {
+	tags := []string{"email", "alert"}
+	seen := map[string]bool{"email": true, "alert": true}
+	add := func(tag string) {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	if priority >= storage.PriorityHigh {
+		add("rotating_light")
+	}
+	for _, label := range labels {
+		tag := strings.ToLower(strings.TrimSpace(strings.ReplaceAll(label, ",", " ")))
+		add(labelEmoji[tag])
+		add(tag)
+	}
+	return tags
+}
+
+// newNtfyRequest builds the POST that publishes one message to an ntfy topic
+func newNtfyRequest(opts MobileOptions, topic, title, message string, priority int, tags []string) (*http.Request, error) {
+	url := fmt.Sprintf("%s/%s", opts.server(), topic)
+
+	// Create request body with title and message
 	body := fmt.Sprintf("%s\n\n%s", title, message)
 
 	req, err := http.NewRequest("POST", url, bytes.NewBufferString(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set headers with label tags
-	req.Header.Set("Title", title)
-	req.Header.Set("Priority", "high")
-	req.Header.Set("Tags", tags)
+	req.Header.Set("X-Title", title)
+	req.Header.Set("X-Priority", strconv.Itoa(priority))
+	if len(tags) > 0 {
+		req.Header.Set("X-Tags", strings.Join(tags, ","))
+	}
+	opts.setAuth(req)
+	return req, nil
+}
+
+// sendNtfy publishes a message to an ntfy topic and records the delivery health
+func sendNtfy(opts MobileOptions, topic, title, message string, priority int, tags []string) error {
+	if topic == "" {
+		return fmt.Errorf("ntfy topic is empty")
+	}
+
+	req, err := newNtfyRequest(opts, topic, title, message, priority, tags)
+	if err != nil {
+		return err
+	}
 
 	// Send request
 	client := &http.Client{}
@@ -94,9 +212,13 @@ func SendMobileEmailAlertWithLabels(topic, filterName string, labels []string, f
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		RecordMobileFailure()
+		return fmt.Errorf("%s returned status %d (check notifications.mobile.auth)", opts.server(), resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		RecordMobileFailure()
-		return fmt.Errorf("ntfy.sh returned status %d", resp.StatusCode)
+		return fmt.Errorf("%s returned status %d", opts.server(), resp.StatusCode)
 	}
 
 	RecordMobileSuccess()
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+// TestNtfyPriority tests the configured priority and the escalation for urgent mail
+func TestNtfyPriority(t *testing.T) {
+	tests := []struct {
+		name          string
+		configured    int
+		emailPriority int
+		expected      int
+	}{
+		{"Unset uses high", 0, storage.PriorityNormal, 4},
+		{"Configured priority", 2, storage.PriorityNormal, 2},
+		{"Out of range uses high", 9, storage.PriorityNormal, 4},
+		{"High priority email escalates", 2, storage.PriorityHigh, 5},
+		{"Critical email escalates", 3, storage.PriorityCritical, 5},
+		{"Escalates even when unset", 0, storage.PriorityHigh, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MobileOptions{Priority: tt.configured}.ntfyPriority(tt.emailPriority)
+			if got != tt.expected {
+				t.Errorf("ntfyPriority(%d) with priority %d = %d, want %d", tt.emailPriority, tt.configured, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestAlertTags tests the ntfy tags built from filter labels
+func TestAlertTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		priority int
+		expected []string
+	}{
+		{"No labels", nil, storage.PriorityNormal, []string{"email", "alert"}},
+		{"Known label gets an emoji", []string{"Work"}, storage.PriorityNormal, []string{"email", "alert", "briefcase", "work"}},
+		{"Unknown label is a plain tag", []string{"clients"}, storage.PriorityNormal, []string{"email", "alert", "clients"}},
+		{"Urgent mail gets a siren", []string{"work"}, storage.PriorityHigh, []string{"email", "alert", "rotating_light", "briefcase", "work"}},
+		{"Duplicates and commas", []string{"bills", "finance", "a,b"}, storage.PriorityNormal, []string{"email", "alert", "moneybag", "bills", "finance", "a b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := alertTags(tt.labels, tt.priority)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("alertTags(%v, %d) = %v, want %v", tt.labels, tt.priority, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestNewNtfyRequest tests the ntfy publish request, its headers and credentials
+func TestNewNtfyRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     MobileOptions
+		url      string
+		wantAuth string // expected Authorization header ("" = none)
+	}{
+		{
+			name: "Default server without auth",
+			opts: MobileOptions{},
+			url:  "https://ntfy.sh/alerts-x7k2",
+		},
+		{
+			name:     "Self-hosted server with token",
+			opts:     MobileOptions{Server: "https://ntfy.example.com/", Auth: "tk_abc123"},
+			url:      "https://ntfy.example.com/alerts-x7k2",
+			wantAuth: "Bearer tk_abc123",
+		},
+		{
+			name:     "Basic auth",
+			opts:     MobileOptions{Server: "http://localhost:8080", Auth: "phil:s3cret:pw"},
+			url:      "http://localhost:8080/alerts-x7k2",
+			wantAuth: "Basic " + base64.StdEncoding.EncodeToString([]byte("phil:s3cret:pw")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := newNtfyRequest(tt.opts, "alerts-x7k2", "📧 Work", "From: boss@company.com", 5, []string{"email", "rotating_light", "work"})
+			if err != nil {
+				t.Fatalf("newNtfyRequest() error = %v", err)
+			}
+
+			if req.Method != "POST" || req.URL.String() != tt.url {
+				t.Errorf("request = %s %s, want POST %s", req.Method, req.URL, tt.url)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.wantAuth {
+				t.Errorf("Authorization header = %q, want %q", got, tt.wantAuth)
+			}
+
+			headers := map[string]string{
+				"X-Title":    "📧 Work",
+				"X-Priority": "5",
+				"X-Tags":     "email,rotating_light,work",
+			}
+			for name, want := range headers {
+				if got := req.Header.Get(name); got != want {
+					t.Errorf("%s header = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestSendNtfyAuthFailure tests that a rejected push names the auth setting
+func TestSendNtfyAuthFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tk_good" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := sendNtfy(MobileOptions{Server: server.URL, Auth: "tk_good"}, "alerts", "Title", "Message", 4, nil); err != nil {
+		t.Errorf("sendNtfy() with a valid token error = %v", err)
+	}
+
+	err := sendNtfy(MobileOptions{Server: server.URL}, "alerts", "Title", "Message", 4, nil)
+	if err == nil || !strings.Contains(err.Error(), "notifications.mobile.auth") {
+		t.Errorf("sendNtfy() without a token error = %v, want a hint about notifications.mobile.auth", err)
+	}
+}
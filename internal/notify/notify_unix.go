@@ -6,16 +6,19 @@ package notify
 import (
 	"fmt"
 
+	"github.com/datateamsix/email-sentinel/internal/gmail"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
 // SendAlertNotification sends a desktop notification for an email alert
 // On Linux/macOS, this uses the beeep library for cross-platform notifications
+// Where the notification daemon supports actions (notify-send --action on Linux,
+// terminal-notifier on macOS), clicking the notification opens the email in Gmail
 //
 // Behavior:
 //   - Title: Email subject with priority indicator
 //   - Body: "From: <sender>" + AI summary (if available)
-//   - Priority 1 emails show 🔥 HIGH PRIORITY indicator
+//   - Priority 1 emails show 🔥 HIGH PRIORITY indicator, priority 2 🚨 CRITICAL
 //   - AI-summarized emails show 🤖 icon and summary
 func SendAlertNotification(a storage.Alert) error {
 	// Build message with filter labels if present
@@ -56,27 +59,37 @@ func SendAlertNotification(a storage.Alert) error {
 		message = aiMessage
 	} else if a.Snippet != "" {
 		// Fall back to snippet if no AI summary
-		snippet := a.Snippet
-		// Truncate snippet if too long
-		if len(snippet) > 120 {
-			snippet = snippet[:117] + "..."
-		}
+		// Truncate snippet if too long (rich snippets are already at this length)
+		snippet := gmail.TruncateWords(a.Snippet, gmail.RichSnippetLength)
 		// Append snippet to message
 		message = message + "\n\n" + snippet
 	}
 
 	// Build title with priority indicator
 	var title string
-	if a.Priority == 1 {
+	if a.Priority >= storage.PriorityCritical {
+		title = "🚨 CRITICAL: " + a.Subject
+	} else if a.Priority == storage.PriorityHigh {
 		title = "🔥 HIGH PRIORITY: " + a.Subject
 	} else {
 		title = "📧 " + a.Subject
 	}
 
+	// Prefer a clickable notification that opens the email; fall back to plain text
+	if sendNotificationWithOpenAction(title, message, a.GmailLink, currentDesktopOptions()) {
+		return nil
+	}
+
 	// Send using cross-platform desktop notification
 	return SendDesktopNotification(title, message)
 }
 
+// SendOTPNotification shows the notification for a detected verification code
+// Buttons are Windows-only, so elsewhere this is a plain desktop notification.
+func SendOTPNotification(title, message, link string) error {
+	return SendDesktopNotification(title, message)
+}
+
 // SendTestNotification sends a test desktop notification to verify notifications work
 func SendTestNotification() error {
 	testAlert := storage.Alert{
@@ -26,86 +26,46 @@ const (
 //
 // Behavior:
 //   - Title: Email subject
-//   - Body: "From: <sender>" + AI summary (if available)
-//   - Clicking opens the Gmail link in default browser
+//   - Body: AI summary with its first action item and question (if available), then "From: <sender>"
+//   - Falls back to "From: <sender>" + snippet without a summary
+//   - "Open Email" button opens the Gmail link in default browser
 //   - Priority 1 emails use an urgent visual style
-//   - AI-summarized emails show 🤖 icon and summary
+//   - Subject, sender and summary are escaped, since the toast is built as XML
 func SendAlertNotification(a storage.Alert) error {
-	// Build message with filter labels if present
-	message := fmt.Sprintf("From: %s", a.Sender)
-	if len(a.FilterLabels) > 0 {
-		labelsStr := ""
-		for _, label := range a.FilterLabels {
-			labelsStr += "🏷️ " + label + " "
-		}
-		message = labelsStr + "\n" + message
-	}
-
-	notification := toast.Notification{
-		AppID:   AppID,
-		Title:   a.Subject,
-		Message: message,
-		Actions: []toast.Action{
-			{
-				Type:      "protocol",
-				Label:     "Open Email",
-				Arguments: a.GmailLink,
-			},
-		},
-		Audio: toast.Default, // System default notification sound
-	}
-
-	// Prioritize AI summary over snippet if available
-	if a.AISummary != nil && a.AISummary.Summary != "" {
-		// Use AI summary instead of snippet
-		aiMessage := message + "\n\n🤖 " + a.AISummary.Summary
-
-		// Add questions if present (max 2 for space)
-		if len(a.AISummary.Questions) > 0 {
-			aiMessage += "\n\n❓ "
-			if len(a.AISummary.Questions) == 1 {
-				aiMessage += a.AISummary.Questions[0]
-			} else {
-				aiMessage += fmt.Sprintf("%s (+ %d more)", a.AISummary.Questions[0], len(a.AISummary.Questions)-1)
-			}
-		}
-
-		// Add action items if present (max 2 for space)
-		if len(a.AISummary.ActionItems) > 0 {
-			aiMessage += "\n✅ "
-			if len(a.AISummary.ActionItems) == 1 {
-				aiMessage += a.AISummary.ActionItems[0]
-			} else {
-				aiMessage += fmt.Sprintf("%s (+ %d more)", a.AISummary.ActionItems[0], len(a.AISummary.ActionItems)-1)
-			}
-		}
-
-		notification.Message = aiMessage
-	} else if a.Snippet != "" {
-		// Fall back to snippet if no AI summary
-		snippet := a.Snippet
-		// Truncate snippet if too long (Windows toast has character limits)
-		if len(snippet) > 120 { // Reduced from 150 to account for labels
-			snippet = snippet[:117] + "..."
-		}
-		// Append snippet to message
-		notification.Message = message + "\n\n" + snippet
-	}
+	notification := newToast(alertToast(a))
 
 	// For priority alerts, use different audio and visual cues
-	if a.Priority == 1 {
+	if a.Priority >= storage.PriorityCritical {
+		// Critical alerts loop an alarm until dismissed
+		notification.Audio = toast.LoopingAlarm
+		notification.Loop = true
+		notification.Duration = toast.Long
+	} else if a.Priority == storage.PriorityHigh {
 		// Use reminder audio for urgent alerts (more attention-grabbing)
 		notification.Audio = toast.Reminder
+	}
 
-		// Add priority indicator to title and message
-		notification.Title = "🔥 HIGH PRIORITY: " + a.Subject
-	} else {
-		// Normal priority - use standard audio
-		notification.Audio = toast.Default
+	return pushToast(&notification)
+}
 
-		// Add email icon to normal notifications
-		notification.Title = "📧 " + a.Subject
-	}
+// SendOTPNotification shows the notification for a detected verification code
+// The toast gets "Open Email" and, if the email-sentinel-otp: protocol could be
+// registered, "Copy OTP" buttons.
+func SendOTPNotification(title, message, link string) error {
+	notification := newToast(toastContent{
+		Title:   title,
+		Message: message,
+		Buttons: toastButtons(link, registerOTPProtocol()),
+	})
+	notification.Audio = toast.Reminder
+
+	return pushToast(&notification)
+}
+
+// pushToast applies the desktop options, shows the toast and records the delivery health
+func pushToast(notification *toast.Notification) error {
+	// Apply the configured sound and duration (no sound means silent, even for critical alerts)
+	applyToastOptions(notification, currentDesktopOptions())
 
 	// Push the notification
 	err := notification.Push()
@@ -124,7 +84,7 @@ func SendTestNotification() error {
 		Subject:    "Email Sentinel Test",
 		Sender:     "test@example.com",
 		Snippet:    "If you can see this, Windows toast notifications are working! ✅",
-		GmailLink:  "https://mail.google.com",
+		GmailLink:  "https://mail.google.com/mail/u/0/#inbox",
 		FilterName: "Test",
 		Priority:   0,
 	}
@@ -138,7 +98,7 @@ func SendPriorityTestNotification() error {
 		Subject:    "URGENT: This is a high priority test",
 		Sender:     "boss@company.com",
 		Snippet:    "This demonstrates how urgent alerts appear with different styling.",
-		GmailLink:  "https://mail.google.com",
+		GmailLink:  "https://mail.google.com/mail/u/0/#inbox",
 		FilterName: "VIP Sender",
 		Priority:   1,
 	}
@@ -0,0 +1,58 @@
+//go:build windows
+// +build windows
+
+package notify
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+var (
+	otpProtocolOnce       sync.Once
+	otpProtocolRegistered bool
+)
+
+// registerOTPProtocol registers OTPProtocol for the current user, once per run
+// Opening the URI runs 'email-sentinel otp get', which copies the newest code.
+// HKCU needs no admin rights; returns false if registration failed.
+func registerOTPProtocol() bool {
+	otpProtocolOnce.Do(func() {
+		exe, err := os.Executable()
+		if err != nil {
+			return
+		}
+		otpProtocolRegistered = writeProtocolKeys(OTPProtocol, "URL:Email Sentinel OTP", fmt.Sprintf(`"%s" otp get`, exe)) == nil
+	})
+	return otpProtocolRegistered
+}
+
+// writeProtocolKeys creates the HKCU\Software\Classes entries for a URI scheme
+func writeProtocolKeys(scheme, description, command string) error {
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, `Software\Classes\`+scheme, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to create protocol key: %w", err)
+	}
+	defer key.Close()
+
+	if err := key.SetStringValue("", description); err != nil {
+		return fmt.Errorf("failed to set protocol description: %w", err)
+	}
+	if err := key.SetStringValue("URL Protocol", ""); err != nil {
+		return fmt.Errorf("failed to mark protocol: %w", err)
+	}
+
+	cmdKey, _, err := registry.CreateKey(key, `shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to create protocol command key: %w", err)
+	}
+	defer cmdKey.Close()
+
+	if err := cmdKey.SetStringValue("", command); err != nil {
+		return fmt.Errorf("failed to set protocol command: %w", err)
+	}
+	return nil
+}
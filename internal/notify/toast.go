@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+// OTPProtocol is the URI scheme the "Copy OTP" toast button opens
+// It's registered per user on Windows to run 'email-sentinel otp get'.
+const OTPProtocol = "email-sentinel-otp"
+
+// toastSnippetLength is the snippet length for toasts without an AI summary
+// Shorter than gmail.RichSnippetLength to leave room for labels.
+const toastSnippetLength = 120
+
+// toastButton is an action button on a Windows toast that opens a URI
+type toastButton struct {
+	Label string
+	URI   string
+}
+
+// toastContent is the text and buttons of a Windows toast, before escaping
+type toastContent struct {
+	Title   string
+	Message string
+	Buttons []toastButton
+}
+
+// alertToast builds the toast for an email alert
+// The AI summary and its first action item and question are the body when present;
+// otherwise the snippet is shown under the sender.
+func alertToast(a storage.Alert) toastContent {
+	// Build message with filter labels if present
+	message := fmt.Sprintf("From: %s", a.Sender)
+	if len(a.FilterLabels) > 0 {
+		labelsStr := ""
+		for _, label := range a.FilterLabels {
+			labelsStr += "🏷️ " + label + " "
+		}
+		message = labelsStr + "\n" + message
+	}
+
+	if a.AISummary != nil && strings.TrimSpace(a.AISummary.Summary) != "" {
+		body := "🤖 " + strings.TrimSpace(a.AISummary.Summary)
+		if item := firstOfMany(a.AISummary.ActionItems); item != "" {
+			body += "\n✅ " + item
+		}
+		if question := firstOfMany(a.AISummary.Questions); question != "" {
+			body += "\n❓ " + question
+		}
+		message = body + "\n\n" + message
+	} else if a.Snippet != "" {
+		// Fall back to snippet if no AI summary (Windows toast has character limits)
+		message = message + "\n\n" + gmail.TruncateWords(a.Snippet, toastSnippetLength)
+	}
+
+	var title string
+	if a.Priority >= storage.PriorityCritical {
+		title = "🚨 CRITICAL: " + a.Subject
+	} else if a.Priority == storage.PriorityHigh {
+		title = "🔥 HIGH PRIORITY: " + a.Subject
+	} else {
+		title = "📧 " + a.Subject
+	}
+
+	return toastContent{Title: title, Message: message, Buttons: toastButtons(a.GmailLink, false)}
+}
+
+// toastButtons returns up to two buttons: "Open Email" for a valid Gmail link and "Copy OTP"
+func toastButtons(link string, copyOTP bool) []toastButton {
+	var buttons []toastButton
+	if gmail.IsValidGmailURL(link) {
+		buttons = append(buttons, toastButton{Label: "Open Email", URI: link})
+	}
+	if copyOTP {
+		buttons = append(buttons, toastButton{Label: "Copy OTP", URI: OTPProtocol + ":copy"})
+	}
+	return buttons
+}
+
+// firstOfMany returns the first item, noting how many more there are (toasts only fit one line)
+func firstOfMany(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	default:
+		return fmt.Sprintf("%s (+ %d more)", items[0], len(items)-1)
+	}
+}
+
+// The toast library pastes text unescaped into XML inside a PowerShell
+// double-quoted here-string, so subjects, senders and summaries (all
+// controlled by whoever sent the email) must be escaped for both.
+
+// powerShellEscaper neutralizes characters PowerShell expands or treats as quotes in a here-string
+// A backtick-escaped quote can't close the here-string; PowerShell also accepts curly quotes.
+var powerShellEscaper = strings.NewReplacer(
+	"`", "``",
+	"$", "`$",
+	`"`, "`\"",
+	"“", "`“",
+	"”", "`”",
+	"„", "`„",
+)
+
+// escapeToastText escapes text for the toast's <text><![CDATA[...]]></text> elements
+func escapeToastText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if !isXMLChar(r) {
+			return -1
+		}
+		return r
+	}, s)
+	// End the CDATA section before ">" so "]]>" can't close it early
+	s = strings.ReplaceAll(s, "]]>", "]]]]><![CDATA[>")
+	return powerShellEscaper.Replace(s)
+}
+
+// escapeToastAttr escapes a value for a quoted XML attribute, e.g. a button's label or URI
+func escapeToastAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s)) // also replaces characters XML doesn't allow
+	return powerShellEscaper.Replace(b.String())
+}
+
+// isXMLChar reports whether r may appear in an XML 1.0 document
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+const testGmailLink = "https://mail.google.com/mail/u/0/#all/m1"
+
+// TestAlertToast tests the toast body with and without an AI summary, and its buttons
+func TestAlertToast(t *testing.T) {
+	tests := []struct {
+		name        string
+		alert       storage.Alert
+		wantTitle   string
+		wantMessage string
+		wantButtons []toastButton
+	}{
+		{
+			name:        "Snippet without summary",
+			alert:       storage.Alert{Subject: "Invoice", Sender: "billing@vendor.com", Snippet: "Your invoice is ready", GmailLink: testGmailLink},
+			wantTitle:   "📧 Invoice",
+			wantMessage: "From: billing@vendor.com\n\nYour invoice is ready",
+			wantButtons: []toastButton{{Label: "Open Email", URI: testGmailLink}},
+		},
+		{
+			name: "Summary is the body",
+			alert: storage.Alert{
+				Subject:      "Q3 review",
+				Sender:       "boss@company.com",
+				Snippet:      "Hi team",
+				GmailLink:    testGmailLink,
+				FilterLabels: []string{"work"},
+				Priority:     storage.PriorityHigh,
+				AISummary: &storage.EmailSummary{
+					Summary:     "Review moved to Friday.",
+					ActionItems: []string{"Send slides", "Book room"},
+					Questions:   []string{"Can you present?"},
+				},
+			},
+			wantTitle:   "🔥 HIGH PRIORITY: Q3 review",
+			wantMessage: "🤖 Review moved to Friday.\n✅ Send slides (+ 1 more)\n❓ Can you present?\n\n🏷️ work \nFrom: boss@company.com",
+			wantButtons: []toastButton{{Label: "Open Email", URI: testGmailLink}},
+		},
+		{
+			name: "Empty summary falls back to snippet",
+			alert: storage.Alert{
+				Subject:   "Outage",
+				Sender:    "ops@company.com",
+				Snippet:   "Service down",
+				Priority:  storage.PriorityCritical,
+				AISummary: &storage.EmailSummary{Summary: "  "},
+			},
+			wantTitle:   "🚨 CRITICAL: Outage",
+			wantMessage: "From: ops@company.com\n\nService down",
+		},
+		{
+			name:        "No button for a non-Gmail link",
+			alert:       storage.Alert{Subject: "Hi", Sender: "a@b.com", GmailLink: "https://evil.example.com/mail/"},
+			wantTitle:   "📧 Hi",
+			wantMessage: "From: a@b.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := alertToast(tt.alert)
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if !reflect.DeepEqual(got.Buttons, tt.wantButtons) {
+				t.Errorf("Buttons = %v, want %v", got.Buttons, tt.wantButtons)
+			}
+		})
+	}
+}
+
+func TestToastButtons(t *testing.T) {
+	got := toastButtons(testGmailLink, true)
+	want := []toastButton{{Label: "Open Email", URI: testGmailLink}, {Label: "Copy OTP", URI: "email-sentinel-otp:copy"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toastButtons() = %v, want %v", got, want)
+	}
+}
+
+// TestEscapeToastText tests that email text can't break out of the toast XML or the PowerShell script
+func TestEscapeToastText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain text", "Meeting at 3pm", "Meeting at 3pm"},
+		{"CDATA terminator", `x]]></text><image src="evil"/>`, "x]]]]><![CDATA[></text><image src=`\"evil`\"/>"},
+		{"PowerShell expansion", "Pay $(Remove-Item ~) `n now", "Pay `$(Remove-Item ~) ``n now"},
+		{"Here-string terminator", "line\n\"@\nStart-Process calc", "line\n`\"@\nStart-Process calc"},
+		{"Curly quote terminator", "line\n”@", "line\n`”@"},
+		{"Control characters dropped", "a\x00b\x1bc\td", "abc\td"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeToastText(tt.input); got != tt.expected {
+				t.Errorf("escapeToastText(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestEscapeToastTextParses tests that escaped text stays a single text element
+func TestEscapeToastTextParses(t *testing.T) {
+	input := `Re: <b>]]><text>injected</text> & more`
+	// Undo the PowerShell escaping, which PowerShell removes before the XML is parsed
+	escaped := strings.NewReplacer("`\"", `"`, "`$", "$", "``", "`").Replace(escapeToastText(input))
+
+	var doc struct {
+		Text []string `xml:"text"`
+	}
+	if err := xml.Unmarshal([]byte("<binding><text><![CDATA["+escaped+"]]></text></binding>"), &doc); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+	if len(doc.Text) != 1 || doc.Text[0] != input {
+		t.Errorf("parsed text = %q, want one element %q", doc.Text, input)
+	}
+}
+
+func TestEscapeToastAttr(t *testing.T) {
+	input := `https://mail.google.com/mail/u/0/#all/m1?a=1&b="2"$x`
+	want := "https://mail.google.com/mail/u/0/#all/m1?a=1&amp;b=&#34;2&#34;`$x"
+	if got := escapeToastAttr(input); got != want {
+		t.Errorf("escapeToastAttr(%q) = %q, want %q", input, got, want)
+	}
+}
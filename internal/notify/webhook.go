@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/metrics"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+// webhookTimeout bounds each webhook request so a slow endpoint can't stall monitoring
+const webhookTimeout = 10 * time.Second
+
+// WebhookPayload is the default JSON body posted to webhooks
+// Its fields are also available to custom templates (e.g. {{.Subject}})
+type WebhookPayload struct {
+	FilterName string   `json:"filter_name"`
+	Sender     string   `json:"sender"`
+	Subject    string   `json:"subject"`
+	Snippet    string   `json:"snippet"`
+	GmailLink  string   `json:"gmail_link"`
+	Priority   int      `json:"priority"`
+	Labels     []string `json:"labels"`
+	Timestamp  string   `json:"timestamp"`
+}
+
+// NewWebhookPayload builds the webhook payload for an alert
+func NewWebhookPayload(a storage.Alert) WebhookPayload {
+	labels := a.FilterLabels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	return WebhookPayload{
+		FilterName: a.FilterName,
+		Sender:     a.Sender,
+		Subject:    a.Subject,
+		Snippet:    a.Snippet,
+		GmailLink:  a.GmailLink,
+		Priority:   a.Priority,
+		Labels:     labels,
+		Timestamp:  a.Timestamp.Format(time.RFC3339),
+	}
+}
+
+// SendWebhookNotification POSTs the default JSON payload for an alert to url
+func SendWebhookNotification(url string, a storage.Alert) error {
+	return SendWebhookWithTemplate(url, "", a)
+}
+
+// SendWebhookWithTemplate POSTs an alert to url
+// If tmpl is empty the default JSON payload is sent; otherwise tmpl is rendered as a
+// Go text/template over WebhookPayload. Use the json function to quote values safely:
+//
+//	{"text": {{json (printf "📧 %s: %s" .FilterName .Subject)}}}
+func SendWebhookWithTemplate(url, tmpl string, a storage.Alert) error {
+	if url == "" {
+		return fmt.Errorf("webhook url is empty")
+	}
+
+	body, err := RenderWebhookBody(tmpl, NewWebhookPayload(a))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	metrics.IncNotification("webhook")
+	return nil
+}
+
+// RenderWebhookBody renders the request body for a payload
+// The result must be valid JSON
+func RenderWebhookBody(tmpl string, payload WebhookPayload) ([]byte, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+		}
+		return data, nil
+	}
+
+	t, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": jsonValue,
+		"join": strings.Join,
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, payload); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook template did not produce valid JSON (use {{json .Field}} to quote values)")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// jsonValue encodes v as a JSON literal for use inside templates
+func jsonValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
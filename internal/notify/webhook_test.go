@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRenderWebhookBody(t *testing.T) {
+	payload := WebhookPayload{
+		FilterName: "Work",
+		Sender:     `"Boss" <boss@company.com>`,
+		Subject:    "Quarterly \"numbers\"",
+		Priority:   1,
+		Labels:     []string{"work"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		field    string
+		expected interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "Default payload",
+			template: "",
+			field:    "subject",
+			expected: "Quarterly \"numbers\"",
+		},
+		{
+			name:     "Slack text template",
+			template: `{"text": {{json (printf "📧 %s: %s" .FilterName .Subject)}}}`,
+			field:    "text",
+			expected: "📧 Work: Quarterly \"numbers\"",
+		},
+		{
+			name:     "Discord content template",
+			template: `{"content": {{json .Sender}}}`,
+			field:    "content",
+			expected: `"Boss" <boss@company.com>`,
+		},
+		{
+			name:     "Unquoted value is rejected",
+			template: `{"text": "{{.Subject}}"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "Bad template syntax",
+			template: `{"text": {{json .Subject}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := RenderWebhookBody(tt.template, payload)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("RenderWebhookBody() expected error, got %s", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderWebhookBody() error = %v", err)
+			}
+
+			var decoded map[string]interface{}
+			if err := json.Unmarshal(body, &decoded); err != nil {
+				t.Fatalf("body is not JSON: %v", err)
+			}
+			if decoded[tt.field] != tt.expected {
+				t.Errorf("%s = %v, want %v", tt.field, decoded[tt.field], tt.expected)
+			}
+		})
+	}
+}
@@ -4,13 +4,48 @@ Copyright © 2025 Datateamsix <research@dt6.io>
 package otp
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/atotto/clipboard"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
 )
 
+// ErrClipboardUnavailable is returned when no system clipboard is available
+// (e.g. headless Linux without xclip, xsel or wl-clipboard installed)
+var ErrClipboardUnavailable = errors.New("system clipboard is not available")
+
+// clipboardBackend abstracts the system clipboard so the auto-clear logic can be tested
+type clipboardBackend interface {
+	ReadAll() (string, error)
+	WriteAll(text string) error
+}
+
+// systemClipboard is the cross-platform clipboard provided by atotto/clipboard
+type systemClipboard struct{}
+
+func (systemClipboard) ReadAll() (string, error) {
+	if clipboard.Unsupported {
+		return "", ErrClipboardUnavailable
+	}
+	return clipboard.ReadAll()
+}
+
+func (systemClipboard) WriteAll(text string) error {
+	if clipboard.Unsupported {
+		return ErrClipboardUnavailable
+	}
+	return clipboard.WriteAll(text)
+}
+
 var (
+	clipboardMu     sync.Mutex
+	backend         clipboardBackend = systemClipboard{}
 	lastCopiedCode  string
 	autoClearTimer  *time.Timer
 	clipboardActive bool
@@ -18,8 +53,10 @@ var (
 
 // CopyToClipboard copies an OTP code to the system clipboard
 func CopyToClipboard(code string) error {
-	err := clipboard.WriteAll(code)
-	if err != nil {
+	clipboardMu.Lock()
+	defer clipboardMu.Unlock()
+
+	if err := backend.WriteAll(code); err != nil {
 		return fmt.Errorf("failed to copy to clipboard: %w", err)
 	}
 
@@ -29,17 +66,48 @@ func CopyToClipboard(code string) error {
 	return nil
 }
 
+// AutoCopy copies a detected code and schedules it to be cleared after clearAfter
+// (0 = never clear). Clipboard problems are logged as warnings rather than returned,
+// so a headless machine never breaks OTP detection. Returns true if the code was copied.
+func AutoCopy(code string, clearAfter time.Duration) bool {
+	if err := CopyToClipboard(code); err != nil {
+		if errors.Is(err, ErrClipboardUnavailable) {
+			log.Warn("OTP auto-copy skipped (install xclip, xsel or wl-clipboard)", "error", ErrClipboardUnavailable)
+		} else {
+			log.Warn("OTP auto-copy failed", "error", err)
+		}
+		return false
+	}
+
+	if clearAfter > 0 {
+		ScheduleAutoClear(clearAfter)
+	}
+
+	return true
+}
+
 // ScheduleAutoClear schedules the clipboard to be cleared after the given duration
-func ScheduleAutoClear(duration time.Duration) {
+// The clipboard is only cleared if it still holds the copied code, so anything the
+// user copied in the meantime is left alone. The returned channel is closed once
+// the timer fires.
+func ScheduleAutoClear(duration time.Duration) <-chan struct{} {
+	clipboardMu.Lock()
+	defer clipboardMu.Unlock()
+
 	// Cancel any existing timer
 	if autoClearTimer != nil {
 		autoClearTimer.Stop()
 	}
 
+	done := make(chan struct{})
 	autoClearTimer = time.AfterFunc(duration, func() {
+		defer close(done)
+
+		clipboardMu.Lock()
+		defer clipboardMu.Unlock()
+
 		if clipboardActive {
-			// Clear clipboard
-			clipboard.WriteAll("")
+			clearIfUnchanged(backend, lastCopiedCode)
 
 			// Zero out the last copied code
 			SecureZeroString(&lastCopiedCode)
@@ -47,6 +115,52 @@ func ScheduleAutoClear(duration time.Duration) {
 			clipboardActive = false
 		}
 	})
+
+	return done
+}
+
+// clearIfUnchanged empties the clipboard only if it still contains code
+// Returns true if the clipboard was cleared
+func clearIfUnchanged(cb clipboardBackend, code string) bool {
+	if code == "" {
+		return false
+	}
+	return clearIfHash(cb, CodeHash(code))
+}
+
+// clearIfHash empties the clipboard only if it still contains the code with this CodeHash
+func clearIfHash(cb clipboardBackend, hash string) bool {
+	if hash == "" {
+		return false
+	}
+
+	current, err := cb.ReadAll()
+	if err != nil || current == "" || CodeHash(current) != hash {
+		// Unreadable or replaced by the user - leave it alone
+		return false
+	}
+
+	if err := cb.WriteAll(""); err != nil {
+		log.Warn("Failed to clear clipboard", "error", err)
+		return false
+	}
+
+	return true
+}
+
+// CodeHash identifies a copied code without keeping the code itself
+// It lets another process clear the clipboard only if the code is still there.
+func CodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// ClearIfHash empties the clipboard if it still holds the code with the given CodeHash
+// Returns true if the clipboard was cleared
+func ClearIfHash(hash string) bool {
+	clipboardMu.Lock()
+	defer clipboardMu.Unlock()
+	return clearIfHash(backend, hash)
 }
 
 // SecureZeroString overwrites a string in memory (best effort)
@@ -67,13 +181,15 @@ func SecureZeroString(s *string) {
 
 // GetClipboard retrieves the current clipboard content
 func GetClipboard() (string, error) {
-	return clipboard.ReadAll()
+	return backend.ReadAll()
 }
 
 // ClearClipboard immediately clears the clipboard
 func ClearClipboard() error {
-	err := clipboard.WriteAll("")
-	if err != nil {
+	clipboardMu.Lock()
+	defer clipboardMu.Unlock()
+
+	if err := backend.WriteAll(""); err != nil {
 		return fmt.Errorf("failed to clear clipboard: %w", err)
 	}
 
@@ -85,5 +201,7 @@ func ClearClipboard() error {
 
 // IsClipboardActive returns whether the clipboard contains an OTP code
 func IsClipboardActive() bool {
+	clipboardMu.Lock()
+	defer clipboardMu.Unlock()
 	return clipboardActive
 }
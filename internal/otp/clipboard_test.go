@@ -0,0 +1,122 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClipboard is an in-memory clipboardBackend for tests
+type fakeClipboard struct {
+	content string
+	readErr error
+}
+
+func (f *fakeClipboard) ReadAll() (string, error) {
+	if f.readErr != nil {
+		return "", f.readErr
+	}
+	return f.content, nil
+}
+
+func (f *fakeClipboard) WriteAll(text string) error {
+	f.content = text
+	return nil
+}
+
+func TestClearIfUnchanged(t *testing.T) {
+	tests := []struct {
+		name          string
+		clipboard     string
+		code          string
+		readErr       error
+		expectCleared bool
+		expectContent string
+	}{
+		{
+			name:          "Clipboard still holds code",
+			clipboard:     "123456",
+			code:          "123456",
+			expectCleared: true,
+			expectContent: "",
+		},
+		{
+			name:          "User copied something else",
+			clipboard:     "meeting notes",
+			code:          "123456",
+			expectCleared: false,
+			expectContent: "meeting notes",
+		},
+		{
+			name:          "Clipboard unreadable",
+			clipboard:     "123456",
+			code:          "123456",
+			readErr:       ErrClipboardUnavailable,
+			expectCleared: false,
+			expectContent: "123456",
+		},
+		{
+			name:          "No code recorded",
+			clipboard:     "",
+			code:          "",
+			expectCleared: false,
+			expectContent: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := &fakeClipboard{content: tt.clipboard, readErr: tt.readErr}
+
+			cleared := clearIfUnchanged(cb, tt.code)
+			if cleared != tt.expectCleared {
+				t.Errorf("clearIfUnchanged() = %v, want %v", cleared, tt.expectCleared)
+			}
+			if cb.content != tt.expectContent {
+				t.Errorf("clipboard content = %q, want %q", cb.content, tt.expectContent)
+			}
+		})
+	}
+}
+
+func TestScheduleAutoClear_LeavesUserContent(t *testing.T) {
+	cb := &fakeClipboard{}
+	original := backend
+	backend = cb
+	defer func() { backend = original }()
+
+	if err := CopyToClipboard("654321"); err != nil {
+		t.Fatalf("CopyToClipboard() error = %v", err)
+	}
+
+	// User copies something else before the timer fires
+	cb.content = "something else"
+
+	select {
+	case <-ScheduleAutoClear(10 * time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("auto-clear timer did not fire")
+	}
+
+	if cb.content != "something else" {
+		t.Errorf("clipboard content = %q, want user content preserved", cb.content)
+	}
+	if IsClipboardActive() {
+		t.Error("IsClipboardActive() = true after auto-clear")
+	}
+}
+
+func TestAutoCopy_Unavailable(t *testing.T) {
+	original := backend
+	backend = unavailableClipboard{}
+	defer func() { backend = original }()
+
+	if AutoCopy("111111", time.Minute) {
+		t.Error("AutoCopy() = true with no clipboard available")
+	}
+}
+
+// unavailableClipboard simulates headless Linux
+type unavailableClipboard struct{}
+
+func (unavailableClipboard) ReadAll() (string, error)   { return "", ErrClipboardUnavailable }
+func (unavailableClipboard) WriteAll(text string) error { return ErrClipboardUnavailable }
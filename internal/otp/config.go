@@ -13,13 +13,18 @@ import (
 
 // OTPRulesYAML represents the YAML structure for OTP rules
 type OTPRulesYAML struct {
-	Enabled             bool              `yaml:"enabled"`
-	ExpiryDuration      string            `yaml:"expiry_duration"`
-	ConfidenceThreshold float64           `yaml:"confidence_threshold"`
-	AutoCopy            bool              `yaml:"auto_copy_to_clipboard"`
-	AutoClearDuration   string            `yaml:"clipboard_auto_clear"`
-	CustomPatterns      []CustomPattern   `yaml:"custom_patterns"`
-	TrustedSenders      []string          `yaml:"trusted_otp_senders"`
+	Enabled                 bool              `yaml:"enabled"`
+	ExpiryDuration          string            `yaml:"expiry_duration"`
+	ExpiryOverrides         map[string]string `yaml:"expiry_overrides"`
+	ConfidenceThreshold     float64           `yaml:"confidence_threshold"`
+	AutoCopy                bool              `yaml:"auto_copy_to_clipboard"`
+	AutoClearDuration       string            `yaml:"clipboard_auto_clear"`
+	CustomPatterns          []CustomPattern   `yaml:"custom_patterns"`
+	TrustedSenders          []string          `yaml:"trusted_otp_senders"`
+	TrustedDomains          []string          `yaml:"trusted_otp_domains"`
+	RequireTrustedSender    bool              `yaml:"require_trusted_sender"`
+	TriggerPhrases          []string          `yaml:"trigger_phrases"`
+	RequireTriggerProximity int               `yaml:"require_trigger_proximity"`
 }
 
 // LoadOTPRules loads OTP rules from a YAML file
@@ -40,21 +45,35 @@ func LoadOTPRules(path string) (*OTPRules, error) {
 		return nil, fmt.Errorf("invalid expiry_duration: %w", err)
 	}
 
+	expiryOverrides := make(map[string]time.Duration, len(yamlRules.ExpiryOverrides))
+	for sender, value := range yamlRules.ExpiryOverrides {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry_overrides for %s: %w", sender, err)
+		}
+		expiryOverrides[sender] = d
+	}
+
 	autoClearDuration, err := time.ParseDuration(yamlRules.AutoClearDuration)
 	if err != nil {
 		return nil, fmt.Errorf("invalid clipboard_auto_clear: %w", err)
 	}
 
 	rules := &OTPRules{
-		Enabled:              yamlRules.Enabled,
-		ExpiryDuration:       expiryDuration,
-		ConfidenceThreshold:  yamlRules.ConfidenceThreshold,
-		AutoCopy:             yamlRules.AutoCopy,
-		AutoClearDuration:    autoClearDuration,
-		EnableSecureClipboard: yamlRules.AutoCopy, // Enable if auto-copy is on
-		CustomPatterns:       yamlRules.CustomPatterns,
-		TrustedSenders:       yamlRules.TrustedSenders,
-		MaxProcessingTime:    500 * time.Millisecond,
+		Enabled:                 yamlRules.Enabled,
+		ExpiryDuration:          expiryDuration,
+		ExpiryOverrides:         expiryOverrides,
+		ConfidenceThreshold:     yamlRules.ConfidenceThreshold,
+		AutoCopy:                yamlRules.AutoCopy,
+		AutoClearDuration:       autoClearDuration,
+		EnableSecureClipboard:   yamlRules.AutoCopy, // Enable if auto-copy is on
+		CustomPatterns:          yamlRules.CustomPatterns,
+		TrustedSenders:          yamlRules.TrustedSenders,
+		TrustedDomains:          yamlRules.TrustedDomains,
+		RequireTrustedSender:    yamlRules.RequireTrustedSender,
+		MaxProcessingTime:       500 * time.Millisecond,
+		TriggerPhrases:          yamlRules.TriggerPhrases,
+		RequireTriggerProximity: yamlRules.RequireTriggerProximity,
 	}
 
 	return rules, nil
@@ -62,14 +81,27 @@ func LoadOTPRules(path string) (*OTPRules, error) {
 
 // SaveOTPRules saves OTP rules to a YAML file
 func SaveOTPRules(path string, rules *OTPRules) error {
+	var expiryOverrides map[string]string
+	if len(rules.ExpiryOverrides) > 0 {
+		expiryOverrides = make(map[string]string, len(rules.ExpiryOverrides))
+		for sender, d := range rules.ExpiryOverrides {
+			expiryOverrides[sender] = d.String()
+		}
+	}
+
 	yamlRules := OTPRulesYAML{
-		Enabled:             rules.Enabled,
-		ExpiryDuration:      rules.ExpiryDuration.String(),
-		ConfidenceThreshold: rules.ConfidenceThreshold,
-		AutoCopy:            rules.AutoCopy,
-		AutoClearDuration:   rules.AutoClearDuration.String(),
-		CustomPatterns:      rules.CustomPatterns,
-		TrustedSenders:      rules.TrustedSenders,
+		Enabled:                 rules.Enabled,
+		ExpiryDuration:          rules.ExpiryDuration.String(),
+		ExpiryOverrides:         expiryOverrides,
+		ConfidenceThreshold:     rules.ConfidenceThreshold,
+		AutoCopy:                rules.AutoCopy,
+		AutoClearDuration:       rules.AutoClearDuration.String(),
+		CustomPatterns:          rules.CustomPatterns,
+		TrustedSenders:          rules.TrustedSenders,
+		TrustedDomains:          rules.TrustedDomains,
+		RequireTrustedSender:    rules.RequireTrustedSender,
+		TriggerPhrases:          rules.TriggerPhrases,
+		RequireTriggerProximity: rules.RequireTriggerProximity,
 	}
 
 	data, err := yaml.Marshal(&yamlRules)
@@ -112,8 +144,21 @@ func DefaultOTPRules() *OTPRules {
 			"@okta.com",
 			"@twilio.com",
 		},
-		BlockedPatterns:   []string{},
-		MaxProcessingTime: 500 * time.Millisecond,
+		BlockedPatterns:         []string{},
+		MaxProcessingTime:       500 * time.Millisecond,
+		TriggerPhrases:          DefaultTriggerPhrases(),
+		RequireTriggerProximity: 0, // Off - any code with enough confidence is accepted
+	}
+}
+
+// DefaultTriggerPhrases returns the phrases that mark text as being about an OTP code
+func DefaultTriggerPhrases() []string {
+	return []string{
+		"verification code", "confirm your", "security code",
+		"authentication code", "login code", "access code",
+		"one-time password", "otp", "2fa", "two-factor",
+		"verify your account", "confirm your email",
+		"confirm your identity",
 	}
 }
 
@@ -141,6 +186,10 @@ func MergeWithDefaults(userRules *OTPRules) *OTPRules {
 		userRules.TrustedSenders = defaults.TrustedSenders
 	}
 
+	if len(userRules.TriggerPhrases) == 0 {
+		userRules.TriggerPhrases = defaults.TriggerPhrases
+	}
+
 	return userRules
 }
 
@@ -155,6 +204,12 @@ otp_rules:
   # Format: duration string (5m, 10m, 30m, 1h)
   expiry_duration: "5m"
 
+  # Per-sender code lifetimes, overriding expiry_duration
+  # Keys are a sender address or a domain (subdomains included)
+  expiry_overrides:
+    "github.com": "15m"
+    "alerts@mybank.com": "3m"
+
   # Minimum confidence score to treat as valid OTP (0.0 to 1.0)
   # Higher = fewer false positives, but might miss some codes
   confidence_threshold: 0.7
@@ -173,8 +228,21 @@ otp_rules:
       regex: "Code:\\s*([A-Z0-9]{6})"
       confidence: 0.8
 
+  # Only accept a code if one of the trigger phrases appears within this
+  # many characters of it (0 = off). Cuts false positives from promo emails
+  # like "verify your account to get 20% off" that also contain numbers.
+  require_trigger_proximity: 0
+
+  # Phrases that mark text as being about an OTP code
+  trigger_phrases:
+    - "verification code"
+    - "security code"
+    - "login code"
+    - "one-time password"
+
   # Sender domains known to send OTP codes
-  # Emails from these domains get higher confidence scores
+  # Codes from trusted senders get a +0.2 confidence boost, so borderline
+  # codes (e.g. a bare 6-digit number) only clear the threshold for them
   trusted_otp_senders:
     - "accounts.google.com"
     - "amazon.com"
@@ -182,5 +250,12 @@ otp_rules:
     - "microsoft.com"
     - "paypal.com"
     - "noreply@"
+
+  # Whole domains to trust (subdomains included)
+  trusted_otp_domains:
+    - "github.com"
+
+  # Only accept codes from trusted senders/domains
+  require_trusted_sender: false
 `
 }
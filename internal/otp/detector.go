@@ -9,8 +9,13 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/gmail"
 )
 
+// TrustedSenderBoost is added to a code's confidence when it comes from a trusted sender
+const TrustedSenderBoost = 0.2
+
 // Detector handles OTP code detection
 type Detector struct {
 	patterns []OTPPattern
@@ -90,6 +95,11 @@ func (d *Detector) Detect(ctx DetectionContext) *OTPResult {
 		return nil
 	}
 
+	// Optionally ignore codes from senders that aren't trusted at all
+	if bestMatch != nil && d.rules.RequireTrustedSender && !IsTrustedSender(ctx.Sender, d.rules) {
+		return nil
+	}
+
 	return bestMatch
 }
 
@@ -97,24 +107,15 @@ func (d *Detector) Detect(ctx DetectionContext) *OTPResult {
 func (d *Detector) detectInText(text string, source string, sender string, subject string) *OTPResult {
 	var bestMatch *OTPResult
 
-	for _, pattern := range d.patterns {
-		matches := pattern.Regex.FindStringSubmatch(text)
-		if len(matches) <= pattern.CaptureGroup {
-			continue
-		}
-
-		code := matches[pattern.CaptureGroup]
-
-		// Validate code if validator exists
-		if pattern.Validator != nil && !pattern.Validator(code) {
-			continue
-		}
-
-		// Normalize code
-		code = NormalizeCode(code)
+	// With proximity required, later matches get a chance when the first isn't near a trigger
+	maxMatches := 1
+	if d.rules.RequireTriggerProximity > 0 {
+		maxMatches = -1
+	}
 
-		// Check for false positives
-		if IsLikelyFalsePositive(code, text) {
+	for _, pattern := range d.patterns {
+		code := d.findCode(pattern, text, maxMatches)
+		if code == "" {
 			continue
 		}
 
@@ -132,7 +133,7 @@ func (d *Detector) detectInText(text string, source string, sender string, subje
 			Confidence: confidence,
 			Source:     source,
 			Pattern:    pattern.Name,
-			ExpiresAt:  time.Now().Add(d.rules.ExpiryDuration),
+			ExpiresAt:  time.Now().Add(d.rules.ExpiryFor(sender)),
 		}
 
 		if bestMatch == nil || result.Confidence > bestMatch.Confidence {
@@ -143,17 +144,47 @@ func (d *Detector) detectInText(text string, source string, sender string, subje
 	return bestMatch
 }
 
+// findCode returns the first acceptable code matched by pattern, or "" if none
+func (d *Detector) findCode(pattern OTPPattern, text string, maxMatches int) string {
+	for _, loc := range pattern.Regex.FindAllStringSubmatchIndex(text, maxMatches) {
+		start, end := 2*pattern.CaptureGroup, 2*pattern.CaptureGroup+1
+		if len(loc) <= end || loc[start] < 0 {
+			continue
+		}
+
+		code := text[loc[start]:loc[end]]
+
+		// Validate code if validator exists
+		if pattern.Validator != nil && !pattern.Validator(code) {
+			continue
+		}
+
+		// Require a trigger phrase close to the code if configured
+		if d.rules.RequireTriggerProximity > 0 &&
+			!HasTriggerNear(text, loc[start], loc[end], d.rules.TriggerPhrases, d.rules.RequireTriggerProximity) {
+			continue
+		}
+
+		// Normalize code
+		code = NormalizeCode(code)
+
+		// Check for false positives
+		if IsLikelyFalsePositive(code, text) {
+			continue
+		}
+
+		return code
+	}
+
+	return ""
+}
+
 // calculateConfidence computes the confidence score with adjustments
 func (d *Detector) calculateConfidence(code string, baseConfidence float64, text string, sender string, subject string) float64 {
 	confidence := baseConfidence
 
 	// Boost for trusted senders
-	for _, trustedSender := range d.rules.TrustedSenders {
-		if strings.Contains(strings.ToLower(sender), strings.ToLower(trustedSender)) {
-			confidence += 0.1
-			break
-		}
-	}
+	confidence = ScoreWithTrust(confidence, sender, d.rules)
 
 	// Boost for OTP context in subject
 	if HasOTPContext(subject) {
@@ -174,6 +205,98 @@ func (d *Detector) calculateConfidence(code string, baseConfidence float64, text
 	return confidence
 }
 
+// ScoreWithTrust boosts confidence by TrustedSenderBoost when sender is trusted, capped at 1.0
+func ScoreWithTrust(confidence float64, sender string, rules *OTPRules) float64 {
+	if IsTrustedSender(sender, rules) {
+		confidence += TrustedSenderBoost
+	}
+
+	if confidence > 1.0 {
+		confidence = 1.0
+	}
+
+	return confidence
+}
+
+// IsTrustedSender reports whether sender matches the trusted senders or domains
+// Only the sender's address is compared, never the display name, which the sender controls.
+// TrustedSenders match an exact address, or any address with a local part prefix ("noreply@");
+// entries without a local part ("github.com") and TrustedDomains match the domain or a subdomain.
+func IsTrustedSender(sender string, rules *OTPRules) bool {
+	if rules == nil || sender == "" {
+		return false
+	}
+
+	address := strings.ToLower(gmail.GetFromAddress(sender))
+	domain := strings.ToLower(gmail.GetFromDomain(sender))
+
+	for _, trustedSender := range rules.TrustedSenders {
+		trustedSender = strings.ToLower(strings.TrimSpace(trustedSender))
+		at := strings.Index(trustedSender, "@")
+		switch {
+		case trustedSender == "":
+			continue
+		case at <= 0:
+			if matchesDomain(domain, trustedSender) {
+				return true
+			}
+		case at == len(trustedSender)-1:
+			if strings.HasPrefix(address, trustedSender) {
+				return true
+			}
+		case address == trustedSender:
+			return true
+		}
+	}
+
+	for _, trustedDomain := range rules.TrustedDomains {
+		if matchesDomain(domain, strings.ToLower(strings.TrimSpace(trustedDomain))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesDomain reports whether domain is trustedDomain or a subdomain of it
+func matchesDomain(domain, trustedDomain string) bool {
+	trustedDomain = strings.TrimPrefix(trustedDomain, "@")
+	if domain == "" || trustedDomain == "" {
+		return false
+	}
+	return domain == trustedDomain || strings.HasSuffix(domain, "."+trustedDomain)
+}
+
+// ExpiryFor returns how long a code from sender stays valid
+// ExpiryOverrides keys are either a full address ("alerts@mybank.com") or a domain
+// that also covers its subdomains ("github.com"). An address beats a domain and a
+// longer domain beats a shorter one; with no match the global ExpiryDuration applies.
+func (r *OTPRules) ExpiryFor(sender string) time.Duration {
+	address := strings.ToLower(gmail.GetFromAddress(sender))
+	domain := strings.ToLower(gmail.GetFromDomain(sender))
+
+	best, bestKey := r.ExpiryDuration, ""
+	for key, d := range r.ExpiryOverrides {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if at := strings.Index(key, "@"); at > 0 {
+			if key == address {
+				return d
+			}
+			continue
+		}
+
+		key = strings.TrimPrefix(key, "@")
+		if key == "" || (domain != key && !strings.HasSuffix(domain, "."+key)) {
+			continue
+		}
+		if len(key) > len(bestKey) {
+			best, bestKey = d, key
+		}
+	}
+
+	return best
+}
+
 // RegisterPattern adds a custom pattern to the detector
 func (d *Detector) RegisterPattern(pattern OTPPattern) {
 	d.patterns = append(d.patterns, pattern)
@@ -226,6 +349,12 @@ func validateOTPRules(rules *OTPRules) error {
 		return fmt.Errorf("expiry duration cannot be negative")
 	}
 
+	for sender, d := range rules.ExpiryOverrides {
+		if d <= 0 {
+			return fmt.Errorf("expiry override for %s must be positive", sender)
+		}
+	}
+
 	if rules.MaxProcessingTime <= 0 {
 		rules.MaxProcessingTime = 500 * time.Millisecond // Default
 	}
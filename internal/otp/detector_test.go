@@ -0,0 +1,212 @@
+/*
+Copyright © 2025 Datateamsix <research@dt6.io>
+*/
+package otp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDetectTriggerProximity tests that require_trigger_proximity rejects promo emails
+// that false-positive with the defaults, while still accepting real OTP emails
+func TestDetectTriggerProximity(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		sender  string
+		code    string // expected code with proximity on ("" = none)
+	}{
+		{
+			name:    "Welcome discount with product numbers",
+			subject: "Verify your account and get 20% off",
+			body: "Welcome to StyleHub! Thanks for signing up. Our spring collection just landed - " +
+				"shop the Linen Shirt (style 482913) and the Relaxed Denim Jacket (style 771204) " +
+				"before they sell out. Free shipping over $50.",
+			sender: "hello@stylehub.com",
+		},
+		{
+			name:    "Promo code far from any trigger",
+			subject: "Confirm your email for an extra gift",
+			body: "Spring savings are here and your favorites are back in stock. " +
+				"Treat yourself this weekend with free gift wrap on every item. " +
+				"Use code SPRING24 at checkout to save 20% sitewide.",
+			sender: "deals@shop.example",
+		},
+		{
+			name:    "Real verification code",
+			subject: "Your sign-in code",
+			body:    "Your verification code is 482913. It expires in 10 minutes. If you didn't request this, ignore this email.",
+			sender:  "no-reply@accounts.google.com",
+			code:    "482913",
+		},
+		{
+			name:    "Real code after a long greeting",
+			subject: "Security alert",
+			body: "Hi Alex, we noticed a sign-in attempt from a new device in Denver, Colorado. " +
+				"If this was you, enter this security code: 739104 to continue.",
+			sender: "noreply@github.com",
+			code:   "739104",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Defaults accept a code in every case, including the promos
+			if result := DetectOTP(tt.subject, tt.body, "", tt.sender, DefaultOTPRules()); result == nil {
+				t.Fatalf("DetectOTP() with defaults found no code, test case no longer exercises a false positive")
+			}
+
+			rules := DefaultOTPRules()
+			rules.RequireTriggerProximity = 40
+
+			result := DetectOTP(tt.subject, tt.body, "", tt.sender, rules)
+			got := ""
+			if result != nil {
+				got = result.Code
+			}
+			if got != tt.code {
+				t.Errorf("DetectOTP() with proximity = %q, expected %q", got, tt.code)
+			}
+		})
+	}
+}
+
+// TestHasTriggerNear tests the distance window and word-boundary matching
+func TestHasTriggerNear(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		code     string
+		phrases  []string
+		distance int
+		expected bool
+	}{
+		{name: "Phrase right before code", text: "Your OTP: 123987", code: "123987", phrases: []string{"otp"}, distance: 10, expected: true},
+		{name: "Phrase after code", text: "123987 is your login code", code: "123987", phrases: []string{"login code"}, distance: 20, expected: true},
+		{name: "Phrase too far away", text: "Your OTP is below. Lots of filler text here. 123987", code: "123987", phrases: []string{"otp"}, distance: 10, expected: false},
+		{name: "Phrase inside another word", text: "Hotpot special 123987", code: "123987", phrases: []string{"otp"}, distance: 20, expected: false},
+		{name: "Case insensitive", text: "VERIFICATION CODE 123987", code: "123987", phrases: []string{"verification code"}, distance: 20, expected: true},
+		{name: "Empty phrases use defaults", text: "2FA 123987", code: "123987", phrases: nil, distance: 5, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := strings.Index(tt.text, tt.code)
+			got := HasTriggerNear(tt.text, start, start+len(tt.code), tt.phrases, tt.distance)
+			if got != tt.expected {
+				t.Errorf("HasTriggerNear(%q) = %v, expected %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestScoreWithTrust tests that trusted senders get the confidence boost, capped at 1.0
+func TestScoreWithTrust(t *testing.T) {
+	rules := DefaultOTPRules()
+	rules.TrustedDomains = []string{"mybank.com"}
+
+	tests := []struct {
+		name       string
+		confidence float64
+		sender     string
+		expected   float64
+	}{
+		{name: "Trusted sender", confidence: 0.6, sender: "GitHub <noreply@github.com>", expected: 0.8},
+		{name: "Trusted subdomain", confidence: 0.6, sender: "alerts@secure.mybank.com", expected: 0.8},
+		{name: "Untrusted sender", confidence: 0.6, sender: "random@spam.com", expected: 0.6},
+		{name: "Lookalike domain", confidence: 0.6, sender: "alerts@notmybank.com", expected: 0.6},
+		{name: "Capped at 1.0", confidence: 0.9, sender: "noreply@github.com", expected: 1.0},
+		{name: "Spoofed display name", confidence: 0.6, sender: `"verify@twilio.com" <x@evil.example>`, expected: 0.6},
+		{name: "Trusted address inside another", confidence: 0.6, sender: "verify@twilio.com.evil.example", expected: 0.6},
+		{name: "Trusted prefix", confidence: 0.6, sender: "Shop <noreply@shop.example>", expected: 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreWithTrust(tt.confidence, tt.sender, rules)
+			if got < tt.expected-0.0001 || got > tt.expected+0.0001 {
+				t.Errorf("ScoreWithTrust(%.2f, %q) = %.2f, expected %.2f", tt.confidence, tt.sender, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDetectTrustedSender tests that a bare 6-digit code clears the threshold only for trusted senders
+func TestDetectTrustedSender(t *testing.T) {
+	subject := "Sign in to GitHub"
+	body := "Enter 739104 on the sign-in page to continue."
+
+	if result := DetectOTP(subject, body, "", "noreply@github.com", DefaultOTPRules()); result == nil || result.Code != "739104" {
+		t.Errorf("DetectOTP() from trusted sender = %v, expected code 739104", result)
+	}
+
+	if result := DetectOTP(subject, body, "", "random@spam.com", DefaultOTPRules()); result != nil {
+		t.Errorf("DetectOTP() from untrusted sender = %q (%.2f), expected no code", result.Code, result.Confidence)
+	}
+
+	// A strong match clears the threshold on its own unless trusted senders are required
+	strongBody := "Your verification code is 739104."
+	if result := DetectOTP(subject, strongBody, "", "random@spam.com", DefaultOTPRules()); result == nil {
+		t.Error("DetectOTP() with strong pattern from untrusted sender found no code")
+	}
+
+	rules := DefaultOTPRules()
+	rules.RequireTrustedSender = true
+	if result := DetectOTP(subject, strongBody, "", "random@spam.com", rules); result != nil {
+		t.Errorf("DetectOTP() with RequireTrustedSender = %q, expected no code", result.Code)
+	}
+
+	// The display name is chosen by the sender, so a trusted address there doesn't count
+	spoofed := `"noreply@github.com" <x@evil.example>`
+	if result := DetectOTP(subject, strongBody, "", spoofed, rules); result != nil {
+		t.Errorf("DetectOTP() from spoofed display name = %q, expected no code", result.Code)
+	}
+	if result := DetectOTP(subject, body, "", spoofed, DefaultOTPRules()); result != nil {
+		t.Errorf("DetectOTP() from spoofed display name = %q (%.2f), expected no boost", result.Code, result.Confidence)
+	}
+}
+
+// TestExpiryFor tests per-sender expiry overrides and the global fallback
+func TestExpiryFor(t *testing.T) {
+	rules := DefaultOTPRules()
+	rules.ExpiryOverrides = map[string]time.Duration{
+		"github.com":        15 * time.Minute,
+		"mybank.com":        5 * time.Minute,
+		"secure.mybank.com": 3 * time.Minute,
+		"Alerts@MyBank.com": 2 * time.Minute,
+	}
+
+	tests := []struct {
+		name   string
+		sender string
+		want   time.Duration
+	}{
+		{name: "Domain override", sender: "GitHub <noreply@github.com>", want: 15 * time.Minute},
+		{name: "Subdomain inherits domain", sender: "noreply@mail.github.com", want: 15 * time.Minute},
+		{name: "Longer domain wins", sender: "otp@secure.mybank.com", want: 3 * time.Minute},
+		{name: "Address beats domain", sender: "alerts@mybank.com", want: 2 * time.Minute},
+		{name: "Lookalike domain", sender: "noreply@notgithub.com", want: rules.ExpiryDuration},
+		{name: "No override", sender: "random@example.com", want: rules.ExpiryDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.ExpiryFor(tt.sender); got != tt.want {
+				t.Errorf("ExpiryFor(%q) = %v, expected %v", tt.sender, got, tt.want)
+			}
+		})
+	}
+
+	// Detected codes expire according to their sender
+	rules.TrustedSenders = append(rules.TrustedSenders, "github.com")
+	result := DetectOTP("Sign in to GitHub", "Your verification code is 739104.", "", "noreply@github.com", rules)
+	if result == nil {
+		t.Fatal("DetectOTP() found no code")
+	}
+	if left := time.Until(result.ExpiresAt); left < 14*time.Minute || left > 15*time.Minute {
+		t.Errorf("ExpiresAt is %v away, expected about 15m", left.Round(time.Second))
+	}
+}
@@ -200,6 +200,61 @@ func HasOTPContext(text string) bool {
 	return false
 }
 
+// HasTriggerNear reports whether a trigger phrase appears within distance characters of text[start:end]
+// Phrases only match on word boundaries, so "otp" doesn't match inside "hotpot".
+// An empty phrase list falls back to DefaultTriggerPhrases.
+func HasTriggerNear(text string, start, end int, phrases []string, distance int) bool {
+	if len(phrases) == 0 {
+		phrases = DefaultTriggerPhrases()
+	}
+
+	from := start - distance
+	if from < 0 {
+		from = 0
+	}
+	to := end + distance
+	if to > len(text) {
+		to = len(text)
+	}
+
+	window := strings.ToLower(text[from:to])
+	for _, phrase := range phrases {
+		phrase = strings.ToLower(strings.TrimSpace(phrase))
+		if phrase != "" && containsWord(window, phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsWord reports whether phrase occurs in text with no letter or digit directly on either side
+func containsWord(text, phrase string) bool {
+	for offset := 0; offset < len(text); {
+		idx := strings.Index(text[offset:], phrase)
+		if idx < 0 {
+			return false
+		}
+		idx += offset
+
+		before := idx == 0 || !isWordChar(text[idx-1])
+		afterIdx := idx + len(phrase)
+		after := afterIdx >= len(text) || !isWordChar(text[afterIdx])
+		if before && after {
+			return true
+		}
+
+		offset = idx + 1
+	}
+
+	return false
+}
+
+// isWordChar reports whether b is an ASCII letter or digit
+func isWordChar(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
 // NormalizeCode removes common separators from codes
 func NormalizeCode(code string) string {
 	// Remove hyphens, spaces, underscores
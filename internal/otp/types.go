@@ -28,16 +28,21 @@ type OTPPattern struct {
 
 // OTPRules represents the configuration for OTP detection
 type OTPRules struct {
-	Enabled              bool            // Enable/disable OTP detection
-	ExpiryDuration       time.Duration   // How long codes remain valid
-	ConfidenceThreshold  float64         // Minimum confidence to accept (0.0 to 1.0)
-	AutoCopy             bool            // Auto-copy to clipboard
-	AutoClearDuration    time.Duration   // Auto-clear clipboard duration
-	EnableSecureClipboard bool           // Enable secure clipboard features
-	CustomPatterns       []CustomPattern // User-defined patterns
-	TrustedSenders       []string        // Email domains/addresses that boost confidence
-	BlockedPatterns      []string        // Patterns to never match (e.g., invoice numbers)
-	MaxProcessingTime    time.Duration   // Maximum time for detection
+	Enabled                 bool                     // Enable/disable OTP detection
+	ExpiryDuration          time.Duration            // How long codes remain valid
+	ExpiryOverrides         map[string]time.Duration // Sender address or domain -> code lifetime, overriding ExpiryDuration
+	ConfidenceThreshold     float64                  // Minimum confidence to accept (0.0 to 1.0)
+	AutoCopy                bool                     // Auto-copy to clipboard
+	AutoClearDuration       time.Duration            // Auto-clear clipboard duration
+	EnableSecureClipboard   bool                     // Enable secure clipboard features
+	CustomPatterns          []CustomPattern          // User-defined patterns
+	TrustedSenders          []string                 // Addresses, "prefix@" forms or domains that boost confidence
+	TrustedDomains          []string                 // Sender domains (and subdomains) that boost confidence
+	RequireTrustedSender    bool                     // Only accept codes from trusted senders
+	BlockedPatterns         []string                 // Patterns to never match (e.g., invoice numbers)
+	MaxProcessingTime       time.Duration            // Maximum time for detection
+	TriggerPhrases          []string                 // Phrases that indicate an OTP email (e.g., "verification code")
+	RequireTriggerProximity int                      // If > 0, a trigger phrase must appear within this many characters of the code
 }
 
 // CustomPattern represents a user-defined OTP pattern
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/datateamsix/email-sentinel/internal/config"
+	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/storage"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,11 +22,12 @@ type MessageMetadata struct {
 	Body    string
 }
 
-// PriorityRules defines the conditions for marking emails as urgent (priority 1)
+// PriorityRules defines the conditions for marking emails as high (1) or critical (2) priority
 type PriorityRules struct {
 	UrgentKeywords []string `yaml:"urgent_keywords"`
-	VIPSenders     []string `yaml:"vip_senders"`
+	VIPSenders     []string `yaml:"vip_senders"` // addresses, or globs like "*@board.company.com"
 	VIPDomains     []string `yaml:"vip_domains"`
+	VIPPatterns    []string `yaml:"vip_patterns"` // regular expressions matched against the address
 }
 
 // NotificationSettings controls when and how notifications are sent
@@ -32,6 +35,10 @@ type NotificationSettings struct {
 	QuietHoursStart string `yaml:"quiet_hours_start"` // e.g., "22:00"
 	QuietHoursEnd   string `yaml:"quiet_hours_end"`   // e.g., "08:00"
 	WeekendMode     string `yaml:"weekend_mode"`      // "normal", "quiet", "disabled"
+	AllowUrgent     bool   `yaml:"allow_urgent"`      // Let critical (priority 2) alerts through during quiet hours
+
+	// Location is the timezone quiet hours and weekends are evaluated in (nil = now's own zone)
+	Location *time.Location `yaml:"-"`
 }
 
 // Rules represents the complete rules configuration
@@ -120,94 +127,188 @@ func SaveRules(path string, rules *Rules) error {
 	return nil
 }
 
-// EvaluatePriorityRules determines if a message should be marked as priority (1) or normal (0)
-// Returns 1 if:
-//   - Subject or snippet contains urgent keywords
-//   - Sender matches VIP senders list
-//   - Sender's domain matches VIP domains list
-// Otherwise returns 0
+// EvaluatePriorityRules determines a message's priority: normal (0), high (1) or critical (2)
+// An urgent keyword in the subject, snippet or body, or a sender on the VIP
+// senders/domains/patterns lists, makes a message high priority. Both together make it critical.
 func EvaluatePriorityRules(rules *Rules, msg MessageMetadata) int {
 	if rules == nil {
-		return 0 // No rules, default to normal priority
+		return storage.PriorityNormal // No rules, default to normal priority
 	}
 
-	// Check urgent keywords in subject and snippet
+	urgent := hasUrgentKeyword(rules, msg)
+	vip := isVIPSender(rules, msg.Sender)
+
+	switch {
+	case urgent && vip:
+		return storage.PriorityCritical
+	case urgent || vip:
+		return storage.PriorityHigh
+	default:
+		return storage.PriorityNormal
+	}
+}
+
+// hasUrgentKeyword reports whether the subject, snippet or body contains an urgent keyword
+func hasUrgentKeyword(rules *Rules, msg MessageMetadata) bool {
 	searchText := strings.ToLower(msg.Subject + " " + msg.Snippet + " " + msg.Body)
 	for _, keyword := range rules.PriorityRules.UrgentKeywords {
 		if strings.Contains(searchText, strings.ToLower(keyword)) {
-			return 1 // Urgent keyword found
+			return true
 		}
 	}
+	return false
+}
 
-	// Extract sender email address
-	senderEmail := gmail.GetFromAddress(msg.Sender)
-	senderEmailLower := strings.ToLower(senderEmail)
-
-	// Check VIP senders (exact match)
+// isVIPSender reports whether the sender's address or domain is on the VIP lists
+// Senders and patterns are matched against the normalized address (lowercase, no
+// display name or +tag, and for Gmail no dots), so aliases of a VIP match too.
+func isVIPSender(rules *Rules, sender string) bool {
+	// Check VIP senders (glob when the entry contains *, otherwise the same address)
+	senderCanonical := gmail.NormalizeAddress(sender)
 	for _, vipSender := range rules.PriorityRules.VIPSenders {
-		if strings.ToLower(vipSender) == senderEmailLower {
-			return 1 // VIP sender
+		vipSender = strings.ToLower(strings.TrimSpace(vipSender))
+		if strings.Contains(vipSender, "*") {
+			if matchSenderGlob(vipSender, senderCanonical) {
+				return true
+			}
+		} else if gmail.NormalizeAddress(vipSender) == senderCanonical {
+			return true
 		}
 	}
 
-	// Check VIP domains
-	senderDomain := gmail.GetFromDomain(msg.Sender)
-	senderDomainLower := strings.ToLower(senderDomain)
+	// Check VIP patterns (case-insensitive regex, compiled once and cached by the filter
+	// package; invalid ones never match and are reported by 'config validate')
+	for _, pattern := range rules.PriorityRules.VIPPatterns {
+		re, err := filter.CompilePattern(pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(senderCanonical) {
+			return true
+		}
+	}
 
+	// Check VIP domains
+	senderDomainLower := strings.ToLower(gmail.GetFromDomain(sender))
 	for _, vipDomain := range rules.PriorityRules.VIPDomains {
 		if strings.ToLower(vipDomain) == senderDomainLower {
-			return 1 // VIP domain
+			return true
 		}
 	}
 
-	return 0 // Normal priority
+	return false
+}
+
+// matchSenderGlob reports whether address matches a VIP sender entry
+// "*" matches any run of characters; entries without one must match exactly.
+func matchSenderGlob(pattern, address string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == address
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(address, parts[0]) {
+		return false
+	}
+	address = address[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(address, part)
+		if i < 0 {
+			return false
+		}
+		address = address[i+len(part):]
+	}
+	return len(address) >= len(last) && strings.HasSuffix(address, last)
 }
 
 // IsQuietTime checks if the current time falls within quiet hours
 // Returns true if notifications should be suppressed
 func (r *Rules) IsQuietTime() bool {
-	// If quiet hours not configured, return false
-	if r.NotificationSettings.QuietHoursStart == "" || r.NotificationSettings.QuietHoursEnd == "" {
+	return IsWithinQuietHours(r, time.Now())
+}
+
+// IsWithinQuietHours checks if now falls within the configured quiet hours
+// Ranges that cross midnight (e.g., 22:00 to 07:00) are supported.
+// Returns false if quiet hours are not configured or can't be parsed
+func IsWithinQuietHours(rules *Rules, now time.Time) bool {
+	if rules == nil {
 		return false
 	}
 
-	now := time.Now()
-	currentTime := now.Format("15:04")
+	start, ok := parseClock(rules.NotificationSettings.QuietHoursStart)
+	if !ok {
+		return false
+	}
+	end, ok := parseClock(rules.NotificationSettings.QuietHoursEnd)
+	if !ok || start == end {
+		return false
+	}
 
-	start := r.NotificationSettings.QuietHoursStart
-	end := r.NotificationSettings.QuietHoursEnd
+	now = localTime(rules, now)
+	current := now.Hour()*60 + now.Minute()
 
 	// Handle overnight quiet hours (e.g., 22:00 to 08:00)
 	if start > end {
-		// Quiet hours span midnight
-		return currentTime >= start || currentTime < end
+		return current >= start || current < end
 	}
 
 	// Normal quiet hours (e.g., 12:00 to 14:00)
-	return currentTime >= start && currentTime < end
+	return current >= start && current < end
 }
 
-// ShouldNotifyOnWeekend checks if notifications should be sent on weekends
-// based on the weekend_mode setting and message priority
-func (r *Rules) ShouldNotifyOnWeekend(priority int) bool {
-	now := time.Now()
-	weekday := now.Weekday()
+// ShouldNotify decides whether a push notification should be sent for an alert
+// Alerts are always saved to history; this only gates desktop/mobile pushes.
+// During quiet hours only critical (priority 2) alerts get through, and only when AllowUrgent is set.
+// Weekend mode is applied on top, so either one can suppress the push
+func ShouldNotify(rules *Rules, now time.Time, priority int) bool {
+	if IsWithinQuietHours(rules, now) && !(priority >= storage.PriorityCritical && rules.NotificationSettings.AllowUrgent) {
+		return false
+	}
+	return ApplyWeekendMode(rules, now, priority)
+}
+
+// ApplyWeekendMode reports whether a notification may be sent under the weekend_mode setting
+// On Saturday and Sunday (in the configured timezone, or now's if unset), "quiet" and
+// "disabled" only let high and critical alerts through. Suppressed alerts still appear in history
+func ApplyWeekendMode(rules *Rules, now time.Time, priority int) bool {
+	if rules == nil {
+		return true
+	}
 
-	// Check if it's weekend (Saturday or Sunday)
-	isWeekend := weekday == time.Saturday || weekday == time.Sunday
-	if !isWeekend {
+	weekday := localTime(rules, now).Weekday()
+	if weekday != time.Saturday && weekday != time.Sunday {
 		return true // Not weekend, always notify
 	}
 
-	// It's weekend - check mode
-	switch r.NotificationSettings.WeekendMode {
-	case "disabled":
-		return false // No notifications on weekends
-	case "quiet":
-		return priority == 1 // Only priority 1 (urgent) notifications
-	case "normal":
-		fallthrough
+	switch rules.NotificationSettings.WeekendMode {
+	case "disabled", "quiet":
+		return priority >= storage.PriorityHigh // Only high and critical notifications
 	default:
-		return true // Notify as usual
+		return true // "normal" - notify as usual
 	}
 }
+
+// localTime converts now into the configured notification timezone
+func localTime(rules *Rules, now time.Time) time.Time {
+	if rules.NotificationSettings.Location == nil {
+		return now
+	}
+	return now.In(rules.NotificationSettings.Location)
+}
+
+// parseClock parses an "HH:MM" string into minutes since midnight
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// ShouldNotifyOnWeekend checks if notifications should be sent on weekends
+// based on the weekend_mode setting and message priority
+func (r *Rules) ShouldNotifyOnWeekend(priority int) bool {
+	return ApplyWeekendMode(r, time.Now(), priority)
+}
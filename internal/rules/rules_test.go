@@ -2,6 +2,7 @@ package rules
 
 import (
 	"testing"
+	"time"
 )
 
 func TestEvaluatePriorityRules_UrgentKeywords(t *testing.T) {
@@ -65,6 +66,7 @@ func TestEvaluatePriorityRules_VIPSenders(t *testing.T) {
 	rules.PriorityRules.VIPSenders = []string{
 		"boss@company.com",
 		"ceo@company.com",
+		"john.doe@gmail.com",
 	}
 
 	tests := []struct {
@@ -92,6 +94,21 @@ func TestEvaluatePriorityRules_VIPSenders(t *testing.T) {
 			sender:   "CEO@COMPANY.COM",
 			expected: 1,
 		},
+		{
+			name:     "VIP sender - plus alias",
+			sender:   "Boss <boss+reports@company.com>",
+			expected: 1,
+		},
+		{
+			name:     "VIP sender - Gmail without dots",
+			sender:   "John Doe <johndoe+news@gmail.com>",
+			expected: 1,
+		},
+		{
+			name:     "Non-VIP sender - dots matter outside Gmail",
+			sender:   "b.oss@company.com",
+			expected: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -158,6 +175,131 @@ func TestEvaluatePriorityRules_VIPDomains(t *testing.T) {
 	}
 }
 
+func TestEvaluatePriorityRules_VIPPatterns(t *testing.T) {
+	rules := DefaultRules()
+	rules.PriorityRules.VIPSenders = []string{
+		"*@board.company.com",
+		"ceo@company.com",
+	}
+	rules.PriorityRules.VIPPatterns = []string{
+		`^(alice|bob)\.[a-z]+@legal\.company\.com$`,
+		`[`, // invalid patterns are skipped
+	}
+
+	tests := []struct {
+		name     string
+		sender   string
+		expected int
+	}{
+		{
+			name:     "VIP glob",
+			sender:   "Jane Director <jane@board.company.com>",
+			expected: 1,
+		},
+		{
+			name:     "VIP glob - case insensitive",
+			sender:   "CHAIR@BOARD.COMPANY.COM",
+			expected: 1,
+		},
+		{
+			name:     "VIP glob - other subdomain",
+			sender:   "jane@notboard.company.com",
+			expected: 0,
+		},
+		{
+			name:     "VIP regex",
+			sender:   "Bob Smith <Bob.Smith@legal.company.com>",
+			expected: 1,
+		},
+		{
+			name:     "VIP regex - normalized address",
+			sender:   "Bob Smith <bob.smith+contracts@legal.company.com>",
+			expected: 1,
+		},
+		{
+			name:     "Non-VIP sender",
+			sender:   "carol.jones@legal.company.com",
+			expected: 0,
+		},
+		{
+			name:     "Exact entry still exact",
+			sender:   "ceo@company.com.evil.com",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := MessageMetadata{
+				Sender:  tt.sender,
+				Subject: "Regular subject",
+				Snippet: "Regular message",
+			}
+			result := EvaluatePriorityRules(rules, msg)
+			if result != tt.expected {
+				t.Errorf("EvaluatePriorityRules() = %d, want %d for sender %s", result, tt.expected, tt.sender)
+			}
+		})
+	}
+}
+
+func TestMatchSenderGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		address string
+		want    bool
+	}{
+		{"boss@company.com", "boss@company.com", true},
+		{"*@company.com", "anyone@company.com", true},
+		{"*@company.com", "anyone@sub.company.com", false},
+		{"*@*.company.com", "anyone@sub.company.com", true},
+		{"ops-*@company.com", "ops-oncall@company.com", true},
+		{"ops-*@company.com", "dev-oncall@company.com", false},
+		{"a*a@x.com", "a@x.com", false},
+		{"*", "anyone@anywhere.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.address, func(t *testing.T) {
+			if got := matchSenderGlob(tt.pattern, tt.address); got != tt.want {
+				t.Errorf("matchSenderGlob(%q, %q) = %v, want %v", tt.pattern, tt.address, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluatePriorityRules_Critical(t *testing.T) {
+	rules := DefaultRules()
+	rules.PriorityRules.VIPSenders = []string{"ceo@company.com"}
+	rules.PriorityRules.VIPDomains = []string{"partner.io"}
+
+	tests := []struct {
+		name     string
+		sender   string
+		subject  string
+		expected int
+	}{
+		{name: "VIP sender with urgent keyword", sender: "CEO <ceo@company.com>", subject: "URGENT: board deck", expected: 2},
+		{name: "VIP domain with urgent keyword", sender: "ops@partner.io", subject: "Action required on contract", expected: 2},
+		{name: "VIP sender only", sender: "ceo@company.com", subject: "Lunch plans", expected: 1},
+		{name: "Urgent keyword only", sender: "random@example.com", subject: "Urgent request", expected: 1},
+		{name: "Neither", sender: "random@example.com", subject: "Lunch plans", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := MessageMetadata{
+				Sender:  tt.sender,
+				Subject: tt.subject,
+			}
+			result := EvaluatePriorityRules(rules, msg)
+			if result != tt.expected {
+				t.Errorf("EvaluatePriorityRules() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestEvaluatePriorityRules_NilRules(t *testing.T) {
 	msg := MessageMetadata{
 		Sender:  "urgent@example.com",
@@ -170,3 +312,168 @@ func TestEvaluatePriorityRules_NilRules(t *testing.T) {
 		t.Errorf("EvaluatePriorityRules(nil, msg) = %d, want 0", result)
 	}
 }
+
+func TestIsWithinQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		clock    string
+		expected bool
+	}{
+		{name: "Not configured", start: "", end: "", clock: "23:00", expected: false},
+		{name: "Same day - inside", start: "12:00", end: "14:00", clock: "13:30", expected: true},
+		{name: "Same day - before", start: "12:00", end: "14:00", clock: "11:59", expected: false},
+		{name: "Same day - end is exclusive", start: "12:00", end: "14:00", clock: "14:00", expected: false},
+		{name: "Wraps midnight - late evening", start: "22:00", end: "07:00", clock: "23:15", expected: true},
+		{name: "Wraps midnight - after midnight", start: "22:00", end: "07:00", clock: "03:00", expected: true},
+		{name: "Wraps midnight - at start", start: "22:00", end: "07:00", clock: "22:00", expected: true},
+		{name: "Wraps midnight - at end", start: "22:00", end: "07:00", clock: "07:00", expected: false},
+		{name: "Wraps midnight - daytime", start: "22:00", end: "07:00", clock: "12:00", expected: false},
+		{name: "Unpadded hour", start: "9:00", end: "17:00", clock: "10:00", expected: true},
+		{name: "Invalid start", start: "late", end: "07:00", clock: "03:00", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultRules()
+			rules.NotificationSettings.QuietHoursStart = tt.start
+			rules.NotificationSettings.QuietHoursEnd = tt.end
+
+			now := mustClock(t, tt.clock)
+			result := IsWithinQuietHours(rules, now)
+			if result != tt.expected {
+				t.Errorf("IsWithinQuietHours(%s-%s, %s) = %v, want %v", tt.start, tt.end, tt.clock, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShouldNotify_QuietHours(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowUrgent bool
+		clock       string
+		priority    int
+		expected    bool
+	}{
+		{name: "Outside quiet hours", allowUrgent: false, clock: "12:00", priority: 0, expected: true},
+		{name: "Quiet hours - normal priority", allowUrgent: true, clock: "23:30", priority: 0, expected: false},
+		{name: "Quiet hours - high with override", allowUrgent: true, clock: "23:30", priority: 1, expected: false},
+		{name: "Quiet hours - critical with override", allowUrgent: true, clock: "23:30", priority: 2, expected: true},
+		{name: "Quiet hours - critical without override", allowUrgent: false, clock: "01:00", priority: 2, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultRules()
+			rules.NotificationSettings.QuietHoursStart = "22:00"
+			rules.NotificationSettings.QuietHoursEnd = "07:00"
+			rules.NotificationSettings.AllowUrgent = tt.allowUrgent
+
+			result := ShouldNotify(rules, mustClock(t, tt.clock), tt.priority)
+			if result != tt.expected {
+				t.Errorf("ShouldNotify() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShouldNotify_NilRules(t *testing.T) {
+	if !ShouldNotify(nil, time.Now(), 0) {
+		t.Error("ShouldNotify(nil, ...) = false, want true")
+	}
+}
+
+// mustClock returns a time on a fixed weekday (Wednesday) at the given HH:MM
+func mustClock(t *testing.T, clock string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		t.Fatalf("invalid clock %q: %v", clock, err)
+	}
+	return time.Date(2025, time.January, 15, parsed.Hour(), parsed.Minute(), 0, 0, time.Local)
+}
+
+func TestApplyWeekendMode(t *testing.T) {
+	saturday := time.Date(2025, time.January, 18, 12, 0, 0, 0, time.Local)
+	wednesday := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		mode     string
+		now      time.Time
+		priority int
+		expected bool
+	}{
+		{name: "Weekday - disabled mode", mode: "disabled", now: wednesday, priority: 0, expected: true},
+		{name: "Weekend - normal mode", mode: "normal", now: saturday, priority: 0, expected: true},
+		{name: "Weekend - quiet mode normal priority", mode: "quiet", now: saturday, priority: 0, expected: false},
+		{name: "Weekend - quiet mode urgent", mode: "quiet", now: saturday, priority: 1, expected: true},
+		{name: "Weekend - disabled mode normal priority", mode: "disabled", now: saturday, priority: 0, expected: false},
+		{name: "Weekend - disabled mode urgent", mode: "disabled", now: saturday, priority: 1, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultRules()
+			rules.NotificationSettings.WeekendMode = tt.mode
+
+			result := ApplyWeekendMode(rules, tt.now, tt.priority)
+			if result != tt.expected {
+				t.Errorf("ApplyWeekendMode() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShouldNotify_QuietHoursAndWeekend(t *testing.T) {
+	rules := DefaultRules()
+	rules.NotificationSettings.QuietHoursStart = "22:00"
+	rules.NotificationSettings.QuietHoursEnd = "07:00"
+	rules.NotificationSettings.AllowUrgent = false
+	rules.NotificationSettings.WeekendMode = "quiet"
+
+	// Saturday afternoon: outside quiet hours, but weekend mode suppresses normal priority
+	saturdayAfternoon := time.Date(2025, time.January, 18, 15, 0, 0, 0, time.Local)
+	if ShouldNotify(rules, saturdayAfternoon, 0) {
+		t.Error("expected weekend mode to suppress normal priority alert")
+	}
+	if !ShouldNotify(rules, saturdayAfternoon, 1) {
+		t.Error("expected weekend mode to allow urgent alert")
+	}
+
+	// Saturday night: weekend allows urgent, but quiet hours without AllowUrgent suppress it
+	saturdayNight := time.Date(2025, time.January, 18, 23, 0, 0, 0, time.Local)
+	if ShouldNotify(rules, saturdayNight, 1) {
+		t.Error("expected quiet hours to suppress urgent alert without AllowUrgent")
+	}
+}
+
+func TestShouldNotify_Location(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+
+	rules := DefaultRules()
+	rules.NotificationSettings.QuietHoursStart = "20:00"
+	rules.NotificationSettings.QuietHoursEnd = "23:00"
+	rules.NotificationSettings.WeekendMode = "quiet"
+	rules.NotificationSettings.Location = newYork
+
+	// Saturday 03:00 UTC is still Friday 22:00 in New York (EST, UTC-5)
+	now := time.Date(2025, time.January, 18, 3, 0, 0, 0, time.UTC)
+
+	if !IsWithinQuietHours(rules, now) {
+		t.Error("expected 22:00 New York time to be within quiet hours")
+	}
+	if !ApplyWeekendMode(rules, now, 0) {
+		t.Error("expected Friday in New York not to be treated as the weekend")
+	}
+
+	rules.NotificationSettings.Location = nil
+	if IsWithinQuietHours(rules, now) {
+		t.Error("expected 03:00 UTC to be outside quiet hours without a location")
+	}
+}
@@ -0,0 +1,193 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/config"
+)
+
+// authMu serializes read-modify-write updates of auth.json within this process
+// (the token refresh monitor and the check loop both update it)
+var authMu sync.Mutex
+
+// AuthState tracks Gmail authorization health in auth.json so warnings survive
+// restarts and the token monitor's 5-minute cycles
+type AuthState struct {
+	AuthorizedAt       time.Time `json:"authorized_at,omitempty"`        // last successful 'email-sentinel init'
+	LastRefreshFailure time.Time `json:"last_refresh_failure,omitempty"` // cleared by the next successful refresh
+	LastRefreshError   string    `json:"last_refresh_error,omitempty"`
+	NotifiedAt         time.Time `json:"notified_at,omitempty"` // last desktop notification about an auth warning
+}
+
+// AuthWarning describes why Gmail may stop working until the user re-authorizes
+type AuthWarning struct {
+	RefreshFailed bool      // a token refresh failed and hasn't succeeded since
+	ExpiresAt     time.Time // known expiry of the authorization (zero if unknown)
+	Message       string
+}
+
+// AuthPath returns the path to auth.json in the config directory
+func AuthPath() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "auth.json"), nil
+}
+
+// Warning returns the current auth warning, if any
+// lifetime is how long an authorization lasts (0 = no known expiry); window is how
+// long before that expiry to start warning.
+func (a *AuthState) Warning(now time.Time, lifetime, window time.Duration) (*AuthWarning, bool) {
+	if a == nil {
+		return nil, false
+	}
+
+	if !a.LastRefreshFailure.IsZero() {
+		msg := fmt.Sprintf("Gmail token refresh failed at %s", a.LastRefreshFailure.Format("Jan 2 15:04"))
+		if a.LastRefreshError != "" {
+			msg += ": " + a.LastRefreshError
+		}
+		return &AuthWarning{RefreshFailed: true, Message: msg}, true
+	}
+
+	if lifetime <= 0 || a.AuthorizedAt.IsZero() {
+		return nil, false
+	}
+
+	expiresAt := a.AuthorizedAt.Add(lifetime)
+	remaining := expiresAt.Sub(now)
+	if remaining > window {
+		return nil, false
+	}
+
+	msg := fmt.Sprintf("Gmail authorization expired at %s", expiresAt.Format("Jan 2 15:04"))
+	if remaining > 0 {
+		msg = fmt.Sprintf("Gmail authorization expires in %s (%s)", remaining.Round(time.Minute), expiresAt.Format("Jan 2 15:04"))
+	}
+	return &AuthWarning{ExpiresAt: expiresAt, Message: msg}, true
+}
+
+// CheckAuth loads auth.json and returns the current warning, if any
+// An unreadable file is treated as no warning.
+func CheckAuth(now time.Time, lifetime, window time.Duration) (*AuthWarning, bool) {
+	a, err := LoadAuthState()
+	if err != nil {
+		return nil, false
+	}
+	return a.Warning(now, lifetime, window)
+}
+
+// LoadAuthState reads auth.json
+// A missing file returns an empty state
+func LoadAuthState() (*AuthState, error) {
+	path, err := AuthPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &AuthState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth state: %w", err)
+	}
+
+	var a AuthState
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("failed to parse auth state: %w", err)
+	}
+
+	return &a, nil
+}
+
+// RecordAuthorized marks a fresh authorization from 'email-sentinel init'
+// Any earlier refresh failure no longer applies.
+func RecordAuthorized() error {
+	return updateAuthState(func(a *AuthState) bool {
+		*a = AuthState{AuthorizedAt: time.Now()}
+		return true
+	})
+}
+
+// RecordRefreshFailure remembers a failed token refresh until one succeeds
+func RecordRefreshFailure(refreshErr error) error {
+	return updateAuthState(func(a *AuthState) bool {
+		a.LastRefreshFailure = time.Now()
+		a.LastRefreshError = refreshErr.Error()
+		return true
+	})
+}
+
+// RecordRefreshSuccess clears a recorded refresh failure
+// The file is only written when there was a failure to clear.
+func RecordRefreshSuccess() error {
+	return updateAuthState(func(a *AuthState) bool {
+		if a.LastRefreshFailure.IsZero() {
+			return false
+		}
+		a.LastRefreshFailure = time.Time{}
+		a.LastRefreshError = ""
+		a.NotifiedAt = time.Time{}
+		return true
+	})
+}
+
+// MarkAuthNotified records that the user was notified about an auth warning
+func MarkAuthNotified(now time.Time) error {
+	return updateAuthState(func(a *AuthState) bool {
+		a.NotifiedAt = now
+		return true
+	})
+}
+
+// updateAuthState loads auth.json, applies fn and saves it if fn reports a change
+func updateAuthState(fn func(a *AuthState) bool) error {
+	authMu.Lock()
+	defer authMu.Unlock()
+
+	a, err := LoadAuthState()
+	if err != nil {
+		// A corrupt file shouldn't block recording new state
+		a = &AuthState{}
+	}
+
+	if !fn(a) {
+		return nil
+	}
+	return a.save()
+}
+
+// save writes the state to auth.json
+func (a *AuthState) save() error {
+	path, err := AuthPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal auth state: %w", err)
+	}
+
+	if _, err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write auth state: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to save auth state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package state
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAuthWarning tests when an auth warning is raised
+func TestAuthWarning(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name        string
+		state       *AuthState
+		lifetime    time.Duration
+		wantWarning bool
+		wantRefresh bool
+		wantText    string
+	}{
+		{
+			name:  "No state",
+			state: nil,
+		},
+		{
+			name:     "Fresh authorization",
+			state:    &AuthState{AuthorizedAt: now.Add(-2 * day)},
+			lifetime: week,
+		},
+		{
+			name:        "Within the warning window",
+			state:       &AuthState{AuthorizedAt: now.Add(-week + 3*time.Hour)},
+			lifetime:    week,
+			wantWarning: true,
+			wantText:    "expires in 3h0m0s",
+		},
+		{
+			name:        "Already expired",
+			state:       &AuthState{AuthorizedAt: now.Add(-week - time.Hour)},
+			lifetime:    week,
+			wantWarning: true,
+			wantText:    "expired at",
+		},
+		{
+			name:  "No known lifetime",
+			state: &AuthState{AuthorizedAt: now.Add(-30 * day)},
+		},
+		{
+			name:        "Refresh failed",
+			state:       &AuthState{LastRefreshFailure: now.Add(-time.Hour), LastRefreshError: "invalid_grant"},
+			wantWarning: true,
+			wantRefresh: true,
+			wantText:    "invalid_grant",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, ok := tt.state.Warning(now, tt.lifetime, day)
+			if ok != tt.wantWarning {
+				t.Fatalf("Warning() ok = %v, want %v", ok, tt.wantWarning)
+			}
+			if !ok {
+				return
+			}
+			if warning.RefreshFailed != tt.wantRefresh {
+				t.Errorf("RefreshFailed = %v, want %v", warning.RefreshFailed, tt.wantRefresh)
+			}
+			if !strings.Contains(warning.Message, tt.wantText) {
+				t.Errorf("Message = %q, want it to contain %q", warning.Message, tt.wantText)
+			}
+		})
+	}
+}
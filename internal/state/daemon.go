@@ -0,0 +1,169 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/config"
+)
+
+// DaemonEnv is set on a process re-executed by 'start --daemon' so it doesn't detach again
+const DaemonEnv = "EMAIL_SENTINEL_DAEMON"
+
+// PIDPath returns the path to the daemon's pidfile in the config directory
+func PIDPath() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "email-sentinel.pid"), nil
+}
+
+// DaemonLogPath returns the path the daemon's output is written to
+func DaemonLogPath() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "daemon.log"), nil
+}
+
+// IsDaemonProcess reports whether this process was started by 'start --daemon'
+func IsDaemonProcess() bool {
+	return os.Getenv(DaemonEnv) != ""
+}
+
+// StartDaemon re-executes the current binary with args, detached from the terminal
+// Output is appended to the daemon log. If the process exits within startupWait
+// (e.g. a config error) that is reported as an error. Returns the PID of the new process
+func StartDaemon(args []string, startupWait time.Duration) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	if _, err := config.EnsureConfigDir(); err != nil {
+		return 0, err
+	}
+
+	logPath, err := DaemonLogPath()
+	if err != nil {
+		return 0, err
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open daemon log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), DaemonEnv+"=1")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start daemon: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case err := <-exited:
+		if err == nil {
+			return 0, fmt.Errorf("daemon exited during startup")
+		}
+		return 0, fmt.Errorf("daemon exited during startup: %w", err)
+	case <-time.After(startupWait):
+		return cmd.Process.Pid, nil
+	}
+}
+
+// StartDetached starts the current binary with args in the background, detached like the daemon
+// The process isn't waited for and has no output; env entries are added to its environment.
+func StartDetached(args []string, env ...string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start background process: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// WritePIDFile records the current process as the running daemon
+func WritePIDFile() error {
+	path, err := PIDPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write pidfile: %w", err)
+	}
+
+	return nil
+}
+
+// ReadPIDFile returns the PID stored in the pidfile
+// Returns an os.IsNotExist error if no daemon has been started
+func ReadPIDFile() (int, error) {
+	path, err := PIDPath()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid pidfile %s", path)
+	}
+
+	return pid, nil
+}
+
+// RemovePIDFile deletes the pidfile if it still belongs to this process
+// A newer daemon may have replaced it, in which case it is left alone
+func RemovePIDFile() error {
+	pid, err := ReadPIDFile()
+	if err != nil || pid != os.Getpid() {
+		return nil
+	}
+
+	path, err := PIDPath()
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// RunningDaemonPID returns the PID of the running daemon, or 0 if none is running
+// A pidfile left behind by a crashed daemon is treated as not running
+func RunningDaemonPID() int {
+	pid, err := ReadPIDFile()
+	if err != nil || !ProcessAlive(pid) {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,53 @@
+//go:build windows
+// +build windows
+
+package state
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	// detachedProcess runs the daemon without a console window
+	detachedProcess = 0x00000008
+
+	// processQueryLimitedInformation is the minimal access right needed to read an exit code
+	processQueryLimitedInformation = 0x1000
+
+	// stillActive is the exit code reported for a process that hasn't exited
+	stillActive = 259
+)
+
+// detachedProcAttr starts the daemon detached from the console in its own process group
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+}
+
+// ProcessAlive checks whether the process exists and hasn't exited yet
+func ProcessAlive(pid int) bool {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// StopProcess terminates the process
+// Windows has no SIGTERM for detached processes, so this is not a graceful shutdown
+func StopProcess(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
@@ -0,0 +1,129 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/config"
+)
+
+// PauseState records whether monitoring is paused, shared between the CLI,
+// tray and running watcher via pause.json
+type PauseState struct {
+	Paused   bool      `json:"paused"`
+	PausedAt time.Time `json:"paused_at,omitempty"`
+	Until    time.Time `json:"until,omitempty"` // zero = paused until resumed
+}
+
+// PausePath returns the path to pause.json in the config directory
+func PausePath() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "pause.json"), nil
+}
+
+// IsActive reports whether monitoring is paused at the given time
+// A pause with an elapsed Until is treated as resumed
+func (p *PauseState) IsActive(now time.Time) bool {
+	if p == nil || !p.Paused {
+		return false
+	}
+	return p.Until.IsZero() || now.Before(p.Until)
+}
+
+// LoadPauseState reads pause.json
+// A missing file means monitoring is not paused
+func LoadPauseState() (*PauseState, error) {
+	path, err := PausePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &PauseState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pause state: %w", err)
+	}
+
+	var p PauseState
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse pause state: %w", err)
+	}
+
+	return &p, nil
+}
+
+// Pause pauses monitoring for d (0 = until resumed)
+func Pause(d time.Duration) (*PauseState, error) {
+	now := time.Now()
+	p := &PauseState{
+		Paused:   true,
+		PausedAt: now,
+	}
+	if d > 0 {
+		p.Until = now.Add(d)
+	}
+
+	if err := p.save(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Resume clears any pause
+func Resume() error {
+	path, err := PausePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear pause state: %w", err)
+	}
+	return nil
+}
+
+// IsPaused reports whether monitoring is currently paused
+// Errors reading the state are treated as not paused so monitoring never silently stops
+func IsPaused() bool {
+	p, err := LoadPauseState()
+	if err != nil {
+		return false
+	}
+	return p.IsActive(time.Now())
+}
+
+// save writes the state to pause.json
+func (p *PauseState) save() error {
+	path, err := PausePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pause state: %w", err)
+	}
+
+	if _, err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write pause state: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to save pause state: %w", err)
+	}
+
+	return nil
+}
@@ -5,17 +5,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/datateamsix/email-sentinel/internal/config"
+	"github.com/datateamsix/email-sentinel/internal/log"
 )
 
+const (
+	// seenMaxAge is how long a processed message ID is remembered
+	seenMaxAge = 30 * 24 * time.Hour
+
+	// maxSeenMessages caps the set during long-running sessions; the oldest IDs are evicted first
+	// Far above what a poll can return (fetch_limit per scope), so evicted IDs are long out of the fetch window
+	maxSeenMessages = 20000
+
+	// seenLowWater is the size the set is trimmed to once it passes maxSeenMessages,
+	// so the sort is paid once per few thousand messages rather than on every check
+	seenLowWater = maxSeenMessages * 9 / 10
+)
+
+// seenFileName is the state file in the config directory
+const seenFileName = "seen_messages.json"
+
 // SeenMessages tracks which message IDs have been processed
+// Changes are kept in memory; Flush (after each check and on shutdown) evicts old IDs and writes them to disk
 type SeenMessages struct {
 	mu       sync.RWMutex
 	messages map[string]time.Time // message ID -> timestamp when seen
 	filePath string
+	dirty    bool // unsaved changes since the last Flush
 }
 
 // State represents the persistent state file
@@ -29,15 +49,18 @@ type SeenMessage struct {
 	SeenAt    time.Time `json:"seen_at"`
 }
 
-// NewSeenMessages creates a new SeenMessages tracker
+// NewSeenMessages creates a new SeenMessages tracker backed by the config directory
 func NewSeenMessages() (*SeenMessages, error) {
 	configDir, err := config.ConfigDir()
 	if err != nil {
 		return nil, err
 	}
 
-	filePath := filepath.Join(configDir, "seen_messages.json")
+	return loadSeenMessages(filepath.Join(configDir, seenFileName))
+}
 
+// loadSeenMessages creates a SeenMessages tracker backed by filePath, loading it if it exists
+func loadSeenMessages(filePath string) (*SeenMessages, error) {
 	sm := &SeenMessages{
 		messages: make(map[string]time.Time),
 		filePath: filePath,
@@ -52,7 +75,7 @@ func NewSeenMessages() (*SeenMessages, error) {
 	}
 
 	// Cleanup old messages (older than 30 days)
-	sm.CleanupOld(30 * 24 * time.Hour)
+	sm.CleanupOld(seenMaxAge)
 
 	return sm, nil
 }
@@ -66,24 +89,47 @@ func (sm *SeenMessages) IsSeen(messageID string) bool {
 }
 
 // MarkSeen marks a message ID as seen with current timestamp
-func (sm *SeenMessages) MarkSeen(messageID string) error {
+// The change is saved by the next Flush
+func (sm *SeenMessages) MarkSeen(messageID string) {
+	now := time.Now()
 	sm.mu.Lock()
-	sm.messages[messageID] = time.Now()
+	sm.messages[messageID] = now
+	sm.dirty = true
 	sm.mu.Unlock()
-
-	return sm.save()
 }
 
 // MarkMultipleSeen marks multiple message IDs as seen
-func (sm *SeenMessages) MarkMultipleSeen(messageIDs []string) error {
+// The change is saved by the next Flush
+func (sm *SeenMessages) MarkMultipleSeen(messageIDs []string) {
 	now := time.Now()
 	sm.mu.Lock()
 	for _, id := range messageIDs {
 		sm.messages[id] = now
 	}
+	sm.dirty = true
 	sm.mu.Unlock()
+}
 
-	return sm.save()
+// Flush evicts expired and excess IDs, then writes the set to disk if it changed since the last Flush
+func (sm *SeenMessages) Flush() error {
+	sm.mu.Lock()
+	if sm.evictLocked(time.Now()) > 0 {
+		sm.dirty = true
+	}
+	if !sm.dirty {
+		sm.mu.Unlock()
+		return nil
+	}
+	sm.dirty = false
+	sm.mu.Unlock()
+
+	if err := sm.save(); err != nil {
+		sm.mu.Lock()
+		sm.dirty = true
+		sm.mu.Unlock()
+		return err
+	}
+	return nil
 }
 
 // Count returns the number of seen messages
@@ -97,6 +143,7 @@ func (sm *SeenMessages) Count() int {
 func (sm *SeenMessages) Clear() error {
 	sm.mu.Lock()
 	sm.messages = make(map[string]time.Time)
+	sm.dirty = false
 	sm.mu.Unlock()
 
 	return sm.save()
@@ -107,8 +154,6 @@ func (sm *SeenMessages) CleanupOld(maxAge time.Duration) int {
 	cutoff := time.Now().Add(-maxAge)
 
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
 	cleaned := 0
 	for id, seenAt := range sm.messages {
 		if seenAt.Before(cutoff) {
@@ -116,14 +161,50 @@ func (sm *SeenMessages) CleanupOld(maxAge time.Duration) int {
 			cleaned++
 		}
 	}
-
 	if cleaned > 0 {
-		sm.save() // Save after cleanup
+		sm.dirty = true
 	}
+	sm.mu.Unlock()
+
+	// Save after cleanup (save takes the lock itself)
+	sm.Flush()
 
 	return cleaned
 }
 
+// evictLocked keeps the set bounded while the watcher runs for weeks without a restart
+// Drops IDs older than seenMaxAge and, past maxSeenMessages, the oldest IDs down to seenLowWater.
+// The caller must hold sm.mu for writing.
+func (sm *SeenMessages) evictLocked(now time.Time) int {
+	cutoff := now.Add(-seenMaxAge)
+	evicted := 0
+	for id, seenAt := range sm.messages {
+		if seenAt.Before(cutoff) {
+			delete(sm.messages, id)
+			evicted++
+		}
+	}
+
+	if len(sm.messages) <= maxSeenMessages {
+		return evicted
+	}
+
+	ids := make([]string, 0, len(sm.messages))
+	for id := range sm.messages {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return sm.messages[ids[i]].Before(sm.messages[ids[j]])
+	})
+
+	for _, id := range ids[:len(ids)-seenLowWater] {
+		delete(sm.messages, id)
+		evicted++
+	}
+
+	return evicted
+}
+
 // load reads the state from disk
 func (sm *SeenMessages) load() error {
 	data, err := os.ReadFile(sm.filePath)
@@ -168,7 +249,7 @@ func (sm *SeenMessages) save() error {
 	}
 
 	// Ensure config directory exists
-	if _, err := config.EnsureConfigDir(); err != nil {
+	if err := os.MkdirAll(filepath.Dir(sm.filePath), 0700); err != nil {
 		return err
 	}
 
@@ -191,7 +272,7 @@ func (sm *SeenMessages) writeWithRetry(data []byte) error {
 
 		// Log warning on first failure
 		if attempt == 0 {
-			fmt.Printf("⚠️  Failed to save state file (attempt %d/%d): %v\n", attempt+1, maxRetries, err)
+			log.Warn("Failed to save state file, retrying", "attempt", attempt+1, "max_attempts", maxRetries, "error", err)
 		}
 
 		// Exponential backoff: 100ms, 200ms, 400ms
@@ -202,7 +283,6 @@ func (sm *SeenMessages) writeWithRetry(data []byte) error {
 	}
 
 	// All retries failed
-	fmt.Printf("❌ CRITICAL: Failed to save state file after %d attempts: %v\n", maxRetries, lastErr)
-	fmt.Printf("   This may cause duplicate alerts after restart\n")
+	log.Error("CRITICAL: Failed to save state file, this may cause duplicate alerts after restart", "attempts", maxRetries, "error", lastErr)
 	return fmt.Errorf("failed to save state after %d attempts: %w", maxRetries, lastErr)
 }
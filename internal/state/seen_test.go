@@ -0,0 +1,148 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEvictLocked tests that the seen set stays bounded by age and size
+func TestEvictLocked(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		fill        func(m map[string]time.Time)
+		wantEvicted int
+		wantKept    []string
+		wantGone    []string
+	}{
+		{
+			name: "Expired IDs are dropped",
+			fill: func(m map[string]time.Time) {
+				m["old"] = now.Add(-seenMaxAge - time.Hour)
+				m["recent"] = now.Add(-time.Hour)
+			},
+			wantEvicted: 1,
+			wantKept:    []string{"recent"},
+			wantGone:    []string{"old"},
+		},
+		{
+			name: "Oldest IDs go first past the cap",
+			fill: func(m map[string]time.Time) {
+				for i := 0; i < maxSeenMessages; i++ {
+					m[fmt.Sprintf("msg-%d", i)] = now.Add(-time.Hour)
+				}
+				m["oldest"] = now.Add(-2 * time.Hour)
+				m["newest"] = now
+			},
+			wantEvicted: maxSeenMessages + 2 - seenLowWater,
+			wantKept:    []string{"newest"},
+			wantGone:    []string{"oldest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := &SeenMessages{messages: make(map[string]time.Time)}
+			tt.fill(sm.messages)
+
+			if got := sm.evictLocked(now); got != tt.wantEvicted {
+				t.Errorf("evictLocked() = %d, want %d", got, tt.wantEvicted)
+			}
+			if len(sm.messages) > maxSeenMessages {
+				t.Errorf("len = %d, want at most %d", len(sm.messages), maxSeenMessages)
+			}
+			for _, id := range tt.wantKept {
+				if _, ok := sm.messages[id]; !ok {
+					t.Errorf("%s was evicted, expected it to be kept", id)
+				}
+			}
+			for _, id := range tt.wantGone {
+				if _, ok := sm.messages[id]; ok {
+					t.Errorf("%s was kept, expected it to be evicted", id)
+				}
+			}
+		})
+	}
+}
+
+// TestSeenMessagesRoundTrip tests that flushed IDs survive a reload and expired ones don't
+func TestSeenMessagesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), seenFileName)
+
+	sm, err := loadSeenMessages(path)
+	if err != nil {
+		t.Fatalf("loadSeenMessages() error = %v", err)
+	}
+	sm.MarkSeen("msg-1")
+	sm.MarkMultipleSeen([]string{"msg-2", "msg-3"})
+
+	// Nothing is written until Flush
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("state file written before Flush (err = %v)", err)
+	}
+
+	// An expired ID is dropped (and the cleanup saved) on the next load
+	sm.mu.Lock()
+	sm.messages["expired"] = time.Now().Add(-seenMaxAge - time.Hour)
+	sm.mu.Unlock()
+
+	if err := sm.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	loaded, err := loadSeenMessages(path)
+	if err != nil {
+		t.Fatalf("loadSeenMessages() error = %v", err)
+	}
+	for _, id := range []string{"msg-1", "msg-2", "msg-3"} {
+		if !loaded.IsSeen(id) {
+			t.Errorf("IsSeen(%q) = false after reload", id)
+		}
+	}
+	if loaded.IsSeen("expired") || loaded.Count() != 3 {
+		t.Errorf("Count() = %d after reload, want 3 without the expired ID", loaded.Count())
+	}
+
+	reloaded, err := loadSeenMessages(path)
+	if err != nil || reloaded.Count() != 3 {
+		t.Errorf("loadSeenMessages() after cleanup = %d IDs, %v; want 3", reloaded.Count(), err)
+	}
+}
+
+// TestSeenMessagesBounded tests that Flush trims more than maxSeenMessages IDs down to the newest seenLowWater
+func TestSeenMessagesBounded(t *testing.T) {
+	sm, err := loadSeenMessages(filepath.Join(t.TempDir(), seenFileName))
+	if err != nil {
+		t.Fatalf("loadSeenMessages() error = %v", err)
+	}
+
+	ids := make([]string, maxSeenMessages)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("old-%d", i)
+	}
+	sm.MarkMultipleSeen(ids)
+
+	// Make one of the existing IDs clearly the oldest
+	sm.mu.Lock()
+	sm.messages["old-0"] = time.Now().Add(-time.Hour)
+	sm.mu.Unlock()
+
+	sm.MarkSeen("new")
+	if sm.Count() != maxSeenMessages+1 {
+		t.Errorf("Count() before Flush = %d, want %d", sm.Count(), maxSeenMessages+1)
+	}
+
+	if err := sm.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if sm.Count() != seenLowWater {
+		t.Errorf("Count() = %d, want %d", sm.Count(), seenLowWater)
+	}
+	if sm.IsSeen("old-0") || !sm.IsSeen("new") {
+		t.Errorf("expected the oldest ID to be evicted and the newest kept")
+	}
+}
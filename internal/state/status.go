@@ -0,0 +1,136 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/config"
+)
+
+// statusGracePeriod is how long past NextCheck a watcher may be silent
+// before it is considered no longer running (e.g. killed without cleanup)
+const statusGracePeriod = 2 * time.Minute
+
+// RuntimeStatus is the monitoring loop's health, shared with the dashboard via status.json
+type RuntimeStatus struct {
+	PID                 int       `json:"pid"`
+	StartedAt           time.Time `json:"started_at"`
+	LastCheck           time.Time `json:"last_check,omitempty"`
+	NextCheck           time.Time `json:"next_check,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	StoppedAt           time.Time `json:"stopped_at,omitempty"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// NewRuntimeStatus creates a status for the current process
+func NewRuntimeStatus() *RuntimeStatus {
+	now := time.Now()
+	return &RuntimeStatus{
+		PID:       os.Getpid(),
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// StatusPath returns the path to status.json in the config directory
+func StatusPath() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "status.json"), nil
+}
+
+// RecordCheck updates the status after a check cycle
+// A nil err resets the failure count; otherwise it is incremented
+func (s *RuntimeStatus) RecordCheck(err error, nextCheck time.Time) {
+	now := time.Now()
+	s.LastCheck = now
+	s.NextCheck = nextCheck
+	s.UpdatedAt = now
+
+	if err != nil {
+		s.ConsecutiveFailures++
+		s.LastError = err.Error()
+	} else {
+		s.ConsecutiveFailures = 0
+		s.LastError = ""
+	}
+}
+
+// IsRunning reports whether the watcher that wrote this status still appears alive
+func (s *RuntimeStatus) IsRunning(now time.Time) bool {
+	if s == nil || !s.StoppedAt.IsZero() {
+		return false
+	}
+
+	deadline := s.UpdatedAt
+	if s.NextCheck.After(deadline) {
+		deadline = s.NextCheck
+	}
+
+	return now.Before(deadline.Add(statusGracePeriod))
+}
+
+// Save writes the status to status.json
+// Written to a temp file and renamed so readers never see a partial file
+func (s *RuntimeStatus) Save() error {
+	path, err := StatusPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	if _, err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write status: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to save status: %w", err)
+	}
+
+	return nil
+}
+
+// MarkStopped records a clean shutdown
+func (s *RuntimeStatus) MarkStopped() error {
+	now := time.Now()
+	s.StoppedAt = now
+	s.UpdatedAt = now
+	s.NextCheck = time.Time{}
+	return s.Save()
+}
+
+// LoadRuntimeStatus reads status.json
+// Returns an os.IsNotExist error if the watcher has never run
+func LoadRuntimeStatus() (*RuntimeStatus, error) {
+	path, err := StatusPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var status RuntimeStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse status: %w", err)
+	}
+
+	return &status, nil
+}
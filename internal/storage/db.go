@@ -3,14 +3,15 @@ package storage
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/datateamsix/email-sentinel/internal/config"
+	"github.com/datateamsix/email-sentinel/internal/log"
 
 	_ "modernc.org/sqlite"
 )
@@ -25,13 +26,18 @@ func retryDatabaseOperation(operation func() error, maxRetries int, operationNam
 		if err == nil {
 			// Success!
 			if attempt > 1 {
-				log.Printf("✅ %s succeeded on attempt %d/%d", operationName, attempt, maxRetries)
+				log.Info("Database operation succeeded after retry", log.Icon("✅"), "operation", operationName, "attempt", attempt, "max_attempts", maxRetries)
 			}
 			return nil
 		}
 
 		lastErr = err
 
+		// A duplicate won't go away on retry
+		if IsDuplicateAlert(err) {
+			return err
+		}
+
 		// Don't retry on last attempt
 		if attempt == maxRetries {
 			break
@@ -39,45 +45,13 @@ func retryDatabaseOperation(operation func() error, maxRetries int, operationNam
 
 		// Exponential backoff: 100ms, 200ms, 400ms
 		backoff := time.Duration(100*(1<<(attempt-1))) * time.Millisecond
-		log.Printf("⚠️  %s failed (attempt %d/%d), retrying in %v: %v", operationName, attempt, maxRetries, backoff, err)
+		log.Warn("Database operation failed, retrying", "operation", operationName, "attempt", attempt, "max_attempts", maxRetries, "retry_in", backoff, "error", err)
 		time.Sleep(backoff)
 	}
 
 	return fmt.Errorf("failed after %d attempts: %w", maxRetries, lastErr)
 }
 
-// writeToFailureLog writes an alert to a local file if database operations fail
-// This ensures no alerts are lost even if the database is completely unavailable
-func writeToFailureLog(alert *Alert) error {
-	configDir, err := config.EnsureConfigDir()
-	if err != nil {
-		return fmt.Errorf("failed to get config directory: %w", err)
-	}
-
-	logPath := filepath.Join(configDir, "failed_alerts.log")
-	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open failure log: %w", err)
-	}
-	defer f.Close()
-
-	// Write alert as JSON line
-	logEntry := fmt.Sprintf("[%s] Filter: %s | From: %s | Subject: %s | Priority: %d | Gmail: %s\n",
-		alert.Timestamp.Format(time.RFC3339),
-		alert.FilterName,
-		alert.Sender,
-		alert.Subject,
-		alert.Priority,
-		alert.GmailLink,
-	)
-
-	if _, err := f.WriteString(logEntry); err != nil {
-		return fmt.Errorf("failed to write to failure log: %w", err)
-	}
-
-	return nil
-}
-
 // Alert represents an email notification stored in the database
 type Alert struct {
 	ID           int64
@@ -88,12 +62,30 @@ type Alert struct {
 	Labels       string   // Gmail labels
 	MessageID    string
 	GmailLink    string
-	FilterName   string
-	FilterLabels []string      // Filter categories (not stored in DB, populated at runtime)
+	FilterName   string        // Matched filter(s), joined with FilterNameSeparator when several matched
+	FilterLabels []string      // Labels of the matched filter(s) (stored comma-separated)
 	Priority     int
 	AISummary    *EmailSummary // AI-generated summary (optional, loaded from ai_summaries table)
 }
 
+// Alert priority levels (the alerts.priority CHECK constraint allows exactly these)
+const (
+	PriorityNormal   = 0 // No priority rule matched
+	PriorityHigh     = 1 // Urgent keyword or VIP sender
+	PriorityCritical = 2 // Urgent keyword from a VIP sender; may bypass quiet hours
+)
+
+// FilterNameSeparator joins the names of all filters that matched the same email
+const FilterNameSeparator = ", "
+
+// FilterNames returns the individual names of the filters that matched the alert
+func (a Alert) FilterNames() []string {
+	if a.FilterName == "" {
+		return nil
+	}
+	return strings.Split(a.FilterName, FilterNameSeparator)
+}
+
 // OTPAlert represents an OTP code extracted from an email
 type OTPAlert struct {
 	ID          int64
@@ -123,7 +115,7 @@ CREATE TABLE IF NOT EXISTS alerts (
     message_id TEXT NOT NULL UNIQUE,
     gmail_link TEXT NOT NULL,
     filter_name TEXT NOT NULL,
-    priority INTEGER DEFAULT 0 CHECK(priority IN (0, 1))
+    priority INTEGER DEFAULT 0 CHECK(priority IN (0, 1, 2))
 );
 
 CREATE INDEX IF NOT EXISTS idx_timestamp ON alerts(timestamp DESC);
@@ -139,14 +131,21 @@ CREATE TABLE IF NOT EXISTS filter_labels (
 CREATE INDEX IF NOT EXISTS idx_label ON filter_labels(label COLLATE NOCASE);
 `
 
-// InitDB initializes the SQLite database and creates tables if needed
-func InitDB() (*sql.DB, error) {
+// DBPath returns the path of the alert history database in the config directory
+func DBPath() (string, error) {
 	configDir, err := config.EnsureConfigDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get config directory: %w", err)
+		return "", fmt.Errorf("failed to get config directory: %w", err)
 	}
+	return filepath.Join(configDir, "history.db"), nil
+}
 
-	dbPath := filepath.Join(configDir, "history.db")
+// InitDB initializes the SQLite database and creates tables if needed
+func InitDB() (*sql.DB, error) {
+	dbPath, err := DBPath()
+	if err != nil {
+		return nil, err
+	}
 
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
@@ -180,18 +179,30 @@ func InitDB() (*sql.DB, error) {
 	return db, nil
 }
 
+// defaultBackupKeep is how many database backups are kept when no limit is configured
+const defaultBackupKeep = 5
+
+// BackupOptions controls where database backups are written and how many are kept
+type BackupOptions struct {
+	Dir      string // empty = backups/ in the config directory
+	KeepLast int    // newest backups to keep; <= 0 uses defaultBackupKeep
+}
+
 // BackupDatabase creates a backup of the database using SQLite's VACUUM INTO
-// This creates a clean, defragmented copy of the database
-func BackupDatabase(db *sql.DB) error {
-	configDir, err := config.EnsureConfigDir()
-	if err != nil {
-		return fmt.Errorf("failed to get config directory: %w", err)
+// This creates a clean, defragmented copy of the database. Returns the backup path.
+func BackupDatabase(db *sql.DB, opts BackupOptions) (string, error) {
+	backupDir := opts.Dir
+	if backupDir == "" {
+		configDir, err := config.EnsureConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get config directory: %w", err)
+		}
+		backupDir = filepath.Join(configDir, "backups")
 	}
 
 	// Create backups directory if it doesn't exist
-	backupDir := filepath.Join(configDir, "backups")
 	if err := os.MkdirAll(backupDir, 0700); err != nil {
-		return fmt.Errorf("failed to create backup directory: %w", err)
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
 	}
 
 	// Generate backup filename with timestamp
@@ -200,24 +211,28 @@ func BackupDatabase(db *sql.DB) error {
 
 	// Use VACUUM INTO for atomic, consistent backup
 	// This is the recommended way to backup SQLite databases
-	log.Printf("📦 Creating database backup: %s", backupPath)
-	_, err = db.Exec(fmt.Sprintf("VACUUM INTO '%s'", backupPath))
+	log.Debug("Creating database backup", "path", backupPath)
+	_, err := db.Exec(fmt.Sprintf("VACUUM INTO '%s'", strings.ReplaceAll(backupPath, "'", "''")))
 	if err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+		return "", fmt.Errorf("failed to create backup: %w", err)
 	}
 
-	log.Printf("✅ Database backup created successfully")
+	log.Info("Database backup created", log.Icon("✅"), "path", backupPath)
 
-	// Rotate old backups - keep only the last 5
-	if err := rotateBackups(backupDir, 5); err != nil {
-		log.Printf("⚠️  Failed to rotate old backups: %v", err)
+	keep := opts.KeepLast
+	if keep <= 0 {
+		keep = defaultBackupKeep
+	}
+	if err := rotateBackups(backupDir, keep); err != nil {
+		log.Warn("Failed to rotate old backups", "error", err)
 		// Don't fail the backup operation if rotation fails
 	}
 
-	return nil
+	return backupPath, nil
 }
 
-// rotateBackups removes old backup files, keeping only the most recent N backups
+// rotateBackups removes old database backups, keeping only the most recent N
+// Only history_backup_*.db files are considered, so a shared directory is safe.
 func rotateBackups(backupDir string, keepCount int) error {
 	entries, err := os.ReadDir(backupDir)
 	if err != nil {
@@ -227,20 +242,20 @@ func rotateBackups(backupDir string, keepCount int) error {
 	// Filter for backup files
 	var backups []os.DirEntry
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".db" {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, "history_backup_") && filepath.Ext(name) == ".db" {
 			backups = append(backups, entry)
 		}
 	}
 
 	// If we have more backups than we want to keep, remove the oldest
 	if len(backups) > keepCount {
-		// Sort by name (which includes timestamp) - oldest first
-		// Since we use YYYYMMDD_HHMMSS format, alphabetical sort = chronological sort
+		// ReadDir sorts by name, and the YYYYMMDD_HHMMSS timestamp makes that chronological
 		for i := 0; i < len(backups)-keepCount; i++ {
 			oldBackup := filepath.Join(backupDir, backups[i].Name())
-			log.Printf("🗑️  Removing old backup: %s", backups[i].Name())
+			log.Info("Removing old backup", log.Icon("🗑️ "), "file", backups[i].Name())
 			if err := os.Remove(oldBackup); err != nil {
-				log.Printf("⚠️  Failed to remove old backup %s: %v", backups[i].Name(), err)
+				log.Warn("Failed to remove old backup", "file", backups[i].Name(), "error", err)
 			}
 		}
 	}
@@ -250,10 +265,10 @@ func rotateBackups(backupDir string, keepCount int) error {
 
 // AutoBackupOnStartup creates a backup when the application starts
 // This ensures we have a recent backup before any operations
-func AutoBackupOnStartup(db *sql.DB) {
-	log.Println("🔄 Running automatic startup backup...")
-	if err := BackupDatabase(db); err != nil {
-		log.Printf("⚠️  Startup backup failed: %v", err)
+func AutoBackupOnStartup(db *sql.DB, opts BackupOptions) {
+	log.Info("Running automatic startup backup...", log.Icon("🔄"))
+	if _, err := BackupDatabase(db, opts); err != nil {
+		log.Warn("Startup backup failed", "error", err)
 		// Don't fail app startup if backup fails
 	}
 }
@@ -266,24 +281,24 @@ func CloseDB(db *sql.DB) error {
 	return db.Close()
 }
 
-// isDuplicateKeyError checks if an error is a UNIQUE constraint violation
-// This happens when trying to insert an alert with a message_id that already exists
-func isDuplicateKeyError(err error) bool {
+// ErrDuplicateAlert is returned when an alert for the same message_id is already saved
+// Expected when the seen-messages state is cleared but the database isn't; callers can ignore it
+var ErrDuplicateAlert = errors.New("alert already saved for this message")
+
+// IsDuplicateAlert reports whether err is a duplicate message_id (UNIQUE constraint) failure
+func IsDuplicateAlert(err error) bool {
 	if err == nil {
 		return false
 	}
-	// SQLite error message for UNIQUE constraint violations
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "UNIQUE constraint failed") ||
-		strings.Contains(errMsg, "constraint failed")
+	return errors.Is(err, ErrDuplicateAlert) || strings.Contains(err.Error(), "UNIQUE constraint failed")
 }
 
 // InsertAlert saves a new alert to the database
-// If the message_id already exists, it returns an error (duplicate)
+// If the message_id already exists, it returns an error wrapping ErrDuplicateAlert
 func InsertAlert(db *sql.DB, a *Alert) error {
 	query := `
-		INSERT INTO alerts (timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO alerts (timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := db.Exec(
@@ -297,9 +312,13 @@ func InsertAlert(db *sql.DB, a *Alert) error {
 		a.GmailLink,
 		a.FilterName,
 		a.Priority,
+		strings.Join(a.FilterLabels, ","),
 	)
 
 	if err != nil {
+		if IsDuplicateAlert(err) {
+			return fmt.Errorf("%w: %s", ErrDuplicateAlert, a.MessageID)
+		}
 		return fmt.Errorf("failed to insert alert: %w", err)
 	}
 
@@ -315,7 +334,7 @@ func InsertAlert(db *sql.DB, a *Alert) error {
 // InsertAlertWithRetry saves an alert with automatic retry on failure
 // This prevents data loss during temporary database issues (locks, disk full, etc.)
 // Falls back to writing to a local log file if all retries fail
-// Silently ignores duplicate message_id errors (UNIQUE constraint failures)
+// Returns ErrDuplicateAlert (without retrying or logging) if the message_id is already saved
 func InsertAlertWithRetry(db *sql.DB, a *Alert) error {
 	const maxRetries = 3
 
@@ -324,24 +343,21 @@ func InsertAlertWithRetry(db *sql.DB, a *Alert) error {
 	}, maxRetries, "Insert alert")
 
 	if err != nil {
-		// Check if this is a duplicate message_id (UNIQUE constraint failure)
-		// This is normal behavior when state file is cleared but DB isn't
-		if isDuplicateKeyError(err) {
-			// Silently ignore - message already in database
-			return nil
+		// Duplicate message_id is normal when the state file is cleared but the DB isn't
+		if IsDuplicateAlert(err) {
+			return ErrDuplicateAlert
 		}
 
 		// All retries failed - write to failure log to prevent data loss
-		log.Printf("❌ CRITICAL: Failed to save alert to database after %d retries", maxRetries)
-		log.Printf("   Writing to failure log: %s", a.Subject)
+		log.Error("CRITICAL: Failed to save alert to database, writing to failure log", append([]any{"retries", maxRetries, "filter", a.FilterName}, log.Email(a.Sender, a.Subject)...)...)
 
 		if logErr := writeToFailureLog(a); logErr != nil {
-			log.Printf("❌ FATAL: Could not write to failure log: %v", logErr)
-			log.Printf("   Alert data: Filter=%s From=%s Subject=%s", a.FilterName, a.Sender, a.Subject)
+			log.Error("FATAL: Could not write to failure log",
+				append([]any{"error", logErr, "filter", a.FilterName}, log.Email(a.Sender, a.Subject)...)...)
 			return fmt.Errorf("database insert failed and backup log failed: %w", err)
 		}
 
-		log.Printf("✅ Alert saved to failure log (can be recovered later)")
+		log.Info("Alert saved to failure log (can be recovered later)", log.Icon("✅"))
 		return nil // Don't fail the entire monitoring process
 	}
 
@@ -354,13 +370,20 @@ func GetTodayAlerts(db *sql.DB) ([]Alert, error) {
 	now := time.Now()
 	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
-	return getAlertsSince(db, midnight)
+	alerts, err := getAlertsSince(db, midnight)
+	if err != nil {
+		return nil, err
+	}
+
+	loadAISummaries(db, alerts)
+
+	return alerts, nil
 }
 
 // GetRecentAlerts returns the N most recent alerts
 func GetRecentAlerts(db *sql.DB, limit int) ([]Alert, error) {
 	query := `
-		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority
+		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels
 		FROM alerts
 		ORDER BY timestamp DESC
 		LIMIT ?
@@ -377,29 +400,65 @@ func GetRecentAlerts(db *sql.DB, limit int) ([]Alert, error) {
 		return nil, err
 	}
 
-	// Populate FilterLabels from filter configuration
-	if err := PopulateFilterLabels(alerts); err != nil {
-		// Log error but don't fail - alerts can still be shown
-		fmt.Printf("Warning: Could not populate filter labels: %v\n", err)
+	loadAISummaries(db, alerts)
+
+	return alerts, nil
+}
+
+// GetAlertsAfter returns alerts with an ID greater than afterID, oldest first
+// Alert IDs only ever increase (AUTOINCREMENT), so the last ID seen works as a cursor
+// for following new alerts, including ones written by another process.
+func GetAlertsAfter(db *sql.DB, afterID int64) ([]Alert, error) {
+	query := `
+		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels
+		FROM alerts
+		WHERE id > ?
+		ORDER BY id ASC
+	`
+
+	rows, err := db.Query(query, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query new alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts, err := scanAlerts(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	loadAISummaries(db, alerts)
+
+	return alerts, nil
+}
+
+// GetLastAlertID returns the highest alert ID, or 0 if there are no alerts
+// Use it as the starting GetAlertsAfter cursor: timestamps are received dates,
+// so the newest alert by timestamp isn't necessarily the last one inserted.
+func GetLastAlertID(db *sql.DB) (int64, error) {
+	var id int64
+	if err := db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM alerts").Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get last alert ID: %w", err)
 	}
+	return id, nil
+}
 
-	// Load AI summaries for each alert (if available)
+// loadAISummaries attaches the AI summary for each alert (if available)
+func loadAISummaries(db *sql.DB, alerts []Alert) {
 	for i := range alerts {
 		summary, err := GetAISummaryByMessageID(db, alerts[i].MessageID)
 		if err != nil {
 			// Log error but don't fail
-			fmt.Printf("Warning: Could not load AI summary for %s: %v\n", alerts[i].MessageID, err)
+			log.Warn("Could not load AI summary", "message_id", alerts[i].MessageID, "error", err)
 		}
 		alerts[i].AISummary = summary
 	}
-
-	return alerts, nil
 }
 
 // getAlertsSince returns all alerts since the given time
 func getAlertsSince(db *sql.DB, since time.Time) ([]Alert, error) {
 	query := `
-		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority
+		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels
 		FROM alerts
 		WHERE timestamp >= ?
 		ORDER BY timestamp DESC
@@ -414,6 +473,120 @@ func getAlertsSince(db *sql.DB, since time.Time) ([]Alert, error) {
 	return scanAlerts(rows)
 }
 
+// GetAlertsBetween returns alerts with timestamps in [start, end), newest first
+func GetAlertsBetween(db *sql.DB, start, end time.Time) ([]Alert, error) {
+	query := `
+		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels
+		FROM alerts
+		WHERE timestamp >= ? AND timestamp < ?
+		ORDER BY timestamp DESC
+	`
+
+	rows, err := db.Query(query, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAlerts(rows)
+}
+
+// GetAlertByMessageID returns the alert for a Gmail message ID (nil if not found)
+func GetAlertByMessageID(db *sql.DB, messageID string) (*Alert, error) {
+	query := `
+		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels
+		FROM alerts
+		WHERE message_id = ?
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`
+
+	rows, err := db.Query(query, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert: %w", err)
+	}
+	defer rows.Close()
+
+	alerts, err := scanAlerts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	return &alerts[0], nil
+}
+
+// GetRecentAlertsByFilter returns the N most recent alerts for a filter, newest first
+// Includes alerts where the filter matched together with others
+func GetRecentAlertsByFilter(db *sql.DB, filterName string, limit int) ([]Alert, error) {
+	query := `
+		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels
+		FROM alerts
+		WHERE instr(?1 || filter_name || ?1, ?1 || ?2 || ?1) > 0
+		ORDER BY timestamp DESC
+		LIMIT ?3
+	`
+
+	rows, err := db.Query(query, FilterNameSeparator, filterName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAlerts(rows)
+}
+
+// RenameFilterInAlerts replaces a filter name in past alerts, including alerts it shared with other filters
+// Names are compared case-insensitively, like 'filter report'. Returns the number of alerts updated.
+func RenameFilterInAlerts(db *sql.DB, oldName, newName string) (int64, error) {
+	rows, err := db.Query(
+		"SELECT id, filter_name FROM alerts WHERE instr(lower(?1 || filter_name || ?1), lower(?1 || ?2 || ?1)) > 0",
+		FilterNameSeparator, oldName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query alerts: %w", err)
+	}
+
+	renamed := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var filterName string
+		if err := rows.Scan(&id, &filterName); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan alert: %w", err)
+		}
+
+		names := Alert{FilterName: filterName}.FilterNames()
+		for i, name := range names {
+			if strings.EqualFold(name, oldName) {
+				names[i] = newName
+			}
+		}
+		renamed[id] = strings.Join(names, FilterNameSeparator)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read alerts: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	for id, filterName := range renamed {
+		if _, err := tx.Exec("UPDATE alerts SET filter_name = ? WHERE id = ?", filterName, id); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to rename filter in alert %d: %w", id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit rename: %w", err)
+	}
+
+	return int64(len(renamed)), nil
+}
+
 // CountTodayAlerts returns the count of alerts since midnight
 func CountTodayAlerts(db *sql.DB) (int, error) {
 	now := time.Now()
@@ -445,13 +618,11 @@ func DeleteAlertsBefore(db *sql.DB, cutoff time.Time) (int64, error) {
 	return deleted, nil
 }
 
-// CleanupDailyAlerts deletes all alerts from before today (midnight)
-// This is called at 12:00 AM daily to wipe yesterday's alerts
-func CleanupDailyAlerts(db *sql.DB) (int64, error) {
-	now := time.Now()
-	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	deleted, err := DeleteAlertsBefore(db, midnight)
+// CleanupDailyAlerts deletes alerts outside the retention window
+// With retentionDays = 0 everything from before today (midnight in loc) is wiped,
+// otherwise alerts older than retentionDays*24h are deleted
+func CleanupDailyAlerts(db *sql.DB, retentionDays int, loc *time.Location) (int64, error) {
+	deleted, err := DeleteAlertsBefore(db, RetentionCutoff(time.Now().In(loc), retentionDays))
 	if err != nil {
 		return 0, fmt.Errorf("daily cleanup failed: %w", err)
 	}
@@ -476,6 +647,14 @@ func DeleteAllAlerts(db *sql.DB) (int64, error) {
 	return deleted, nil
 }
 
+// RetentionCutoff returns the time before which alerts should be deleted
+func RetentionCutoff(now time.Time, retentionDays int) time.Time {
+	if retentionDays <= 0 {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	return now.Add(-time.Duration(retentionDays) * 24 * time.Hour)
+}
+
 // DeleteAlerts24HoursOld deletes alerts older than 24 hours
 // Returns the number of alerts deleted
 func DeleteAlerts24HoursOld(db *sql.DB) (int64, error) {
@@ -494,6 +673,7 @@ func scanAlerts(rows *sql.Rows) ([]Alert, error) {
 	for rows.Next() {
 		var a Alert
 		var timestamp int64
+		var filterLabels string
 
 		err := rows.Scan(
 			&a.ID,
@@ -506,6 +686,7 @@ func scanAlerts(rows *sql.Rows) ([]Alert, error) {
 			&a.GmailLink,
 			&a.FilterName,
 			&a.Priority,
+			&filterLabels,
 		)
 
 		if err != nil {
@@ -513,6 +694,7 @@ func scanAlerts(rows *sql.Rows) ([]Alert, error) {
 		}
 
 		a.Timestamp = time.Unix(timestamp, 0)
+		a.FilterLabels = splitFilterLabels(filterLabels)
 		alerts = append(alerts, a)
 	}
 
@@ -719,6 +901,79 @@ func DeleteExpiredOTPAlerts(db *sql.DB) (int64, error) {
 	return deleted, nil
 }
 
+// DeleteInactiveOTPAlerts deletes OTP alerts whose code has expired or been marked inactive
+// Returns the number of alerts that were deleted
+func DeleteInactiveOTPAlerts(db *sql.DB) (int64, error) {
+	query := "DELETE FROM otp_alerts WHERE is_active = 0 OR expires_at <= ?"
+
+	result, err := db.Exec(query, time.Now().Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete inactive OTP alerts: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted count: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// DeleteAllOTPAlerts deletes every stored OTP alert, active or not
+// Returns the number of alerts that were deleted
+func DeleteAllOTPAlerts(db *sql.DB) (int64, error) {
+	result, err := db.Exec("DELETE FROM otp_alerts")
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete all OTP alerts: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted count: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// TrimOTPAlerts deletes the oldest OTP alerts so at most keep remain (keep <= 0 keeps everything)
+// Returns the number of alerts that were deleted
+func TrimOTPAlerts(db *sql.DB, keep int) (int64, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+
+	query := `
+		DELETE FROM otp_alerts
+		WHERE id NOT IN (SELECT id FROM otp_alerts ORDER BY timestamp DESC, id DESC LIMIT ?)
+	`
+
+	result, err := db.Exec(query, keep)
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim OTP alerts: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted count: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// CountOTPAlerts returns the number of stored OTP alerts and how many of them are inactive or expired
+func CountOTPAlerts(db *sql.DB) (total, inactive int, err error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN is_active = 0 OR expires_at <= ? THEN 1 ELSE 0 END), 0)
+		FROM otp_alerts
+	`
+
+	if err := db.QueryRow(query, time.Now().Unix()).Scan(&total, &inactive); err != nil {
+		return 0, 0, fmt.Errorf("failed to count OTP alerts: %w", err)
+	}
+
+	return total, inactive, nil
+}
+
 // scanOTPAlerts is a helper function to scan rows into OTPAlert structs
 func scanOTPAlerts(rows *sql.Rows) ([]OTPAlert, error) {
 	var alerts []OTPAlert
@@ -769,6 +1024,17 @@ func scanOTPAlerts(rows *sql.Rows) ([]OTPAlert, error) {
 	return alerts, nil
 }
 
+// splitFilterLabels splits the stored comma-separated filter labels (nil if none)
+func splitFilterLabels(stored string) []string {
+	var labels []string
+	for _, label := range strings.Split(stored, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
 // boolToInt converts a boolean to an integer (0 or 1) for SQLite storage
 func boolToInt(b bool) int {
 	if b {
@@ -777,58 +1043,6 @@ func boolToInt(b bool) int {
 	return 0
 }
 
-// PopulateFilterLabels populates the FilterLabels field for alerts by loading
-// the filter configuration and matching filter names
-func PopulateFilterLabels(alerts []Alert) error {
-	// Import filter package to load config
-	// We need to do this dynamically to avoid import cycles
-	// For now, we'll use a simpler approach: check the filter name for common patterns
-
-	for i := range alerts {
-		// For now, use a simple heuristic: check if filter name contains "otp"
-		// This can be enhanced later to load actual filter config
-		filterNameLower := ""
-		for _, ch := range alerts[i].FilterName {
-			if ch >= 'A' && ch <= 'Z' {
-				filterNameLower += string(ch + 32)
-			} else {
-				filterNameLower += string(ch)
-			}
-		}
-
-		// Check if filter name suggests OTP
-		if containsSubstring(filterNameLower, "otp") ||
-		   containsSubstring(filterNameLower, "code") ||
-		   containsSubstring(filterNameLower, "verification") ||
-		   containsSubstring(filterNameLower, "2fa") ||
-		   containsSubstring(filterNameLower, "authentication") {
-			alerts[i].FilterLabels = []string{"otp"}
-		}
-	}
-
-	return nil
-}
-
-// containsSubstring checks if a string contains a substring (simple implementation)
-func containsSubstring(s, substr string) bool {
-	if len(substr) > len(s) {
-		return false
-	}
-	for i := 0; i <= len(s)-len(substr); i++ {
-		match := true
-		for j := 0; j < len(substr); j++ {
-			if s[i+j] != substr[j] {
-				match = false
-				break
-			}
-		}
-		if match {
-			return true
-		}
-	}
-	return false
-}
-
 // ======================================
 // AI Summary Functions
 // ======================================
@@ -844,32 +1058,25 @@ type EmailSummary struct {
 	Model       string
 	GeneratedAt time.Time
 	TokensUsed  int
+	ContentHash string // Hash of the normalized email content (see ai.ContentHash)
 }
 
 // InsertAISummary saves an AI-generated summary to the database
 func InsertAISummary(db *sql.DB, summary *EmailSummary) error {
-	// Convert slices to JSON strings using standard library
-	questionsJSON := "[]"
-	if len(summary.Questions) > 0 {
-		bytes, err := json.Marshal(summary.Questions)
-		if err != nil {
-			return fmt.Errorf("failed to marshal questions: %w", err)
-		}
-		questionsJSON = string(bytes)
+	// Convert slices to JSON strings
+	questionsJSON, err := marshalStringSlice(summary.Questions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal questions: %w", err)
 	}
 
-	actionItemsJSON := "[]"
-	if len(summary.ActionItems) > 0 {
-		bytes, err := json.Marshal(summary.ActionItems)
-		if err != nil {
-			return fmt.Errorf("failed to marshal action_items: %w", err)
-		}
-		actionItemsJSON = string(bytes)
+	actionItemsJSON, err := marshalStringSlice(summary.ActionItems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal action_items: %w", err)
 	}
 
 	query := `
-		INSERT INTO ai_summaries (message_id, summary, questions, action_items, provider, model, generated_at, tokens_used)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO ai_summaries (message_id, summary, questions, action_items, provider, model, generated_at, tokens_used, content_hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := db.Exec(
@@ -882,6 +1089,7 @@ func InsertAISummary(db *sql.DB, summary *EmailSummary) error {
 		summary.Model,
 		summary.GeneratedAt.Unix(),
 		summary.TokensUsed,
+		summary.ContentHash,
 	)
 
 	if err != nil {
@@ -900,16 +1108,44 @@ func InsertAISummary(db *sql.DB, summary *EmailSummary) error {
 // GetAISummaryByMessageID retrieves an AI summary for a specific message
 func GetAISummaryByMessageID(db *sql.DB, messageID string) (*EmailSummary, error) {
 	query := `
-		SELECT id, message_id, summary, questions, action_items, provider, model, generated_at, tokens_used
+		SELECT id, message_id, summary, questions, action_items, provider, model, generated_at, tokens_used, content_hash
 		FROM ai_summaries
 		WHERE message_id = ?
 	`
 
+	return scanAISummary(db.QueryRow(query, messageID))
+}
+
+// GetAISummaryByContentHash retrieves the most recent AI summary for identical email content
+// Summaries generated before notBefore are ignored (zero time = no limit)
+func GetAISummaryByContentHash(db *sql.DB, contentHash string, notBefore time.Time) (*EmailSummary, error) {
+	if contentHash == "" {
+		return nil, nil
+	}
+
+	var since int64
+	if !notBefore.IsZero() {
+		since = notBefore.Unix()
+	}
+
+	query := `
+		SELECT id, message_id, summary, questions, action_items, provider, model, generated_at, tokens_used, content_hash
+		FROM ai_summaries
+		WHERE content_hash = ? AND generated_at >= ?
+		ORDER BY generated_at DESC
+		LIMIT 1
+	`
+
+	return scanAISummary(db.QueryRow(query, contentHash, since))
+}
+
+// scanAISummary scans a single ai_summaries row (nil if there is no row)
+func scanAISummary(row *sql.Row) (*EmailSummary, error) {
 	var summary EmailSummary
 	var generatedAt int64
 	var questionsJSON, actionItemsJSON string
 
-	err := db.QueryRow(query, messageID).Scan(
+	err := row.Scan(
 		&summary.ID,
 		&summary.MessageID,
 		&summary.Summary,
@@ -919,6 +1155,7 @@ func GetAISummaryByMessageID(db *sql.DB, messageID string) (*EmailSummary, error
 		&summary.Model,
 		&generatedAt,
 		&summary.TokensUsed,
+		&summary.ContentHash,
 	)
 
 	if err == sql.ErrNoRows {
@@ -930,40 +1167,108 @@ func GetAISummaryByMessageID(db *sql.DB, messageID string) (*EmailSummary, error
 
 	summary.GeneratedAt = time.Unix(generatedAt, 0)
 
-	// Parse JSON strings back to slices using standard library
-	if questionsJSON != "" && questionsJSON != "[]" {
-		if err := json.Unmarshal([]byte(questionsJSON), &summary.Questions); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal questions: %w", err)
-		}
+	// Parse JSON strings back to slices
+	if summary.Questions, err = unmarshalStringSlice(questionsJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal questions: %w", err)
 	}
-	if actionItemsJSON != "" && actionItemsJSON != "[]" {
-		if err := json.Unmarshal([]byte(actionItemsJSON), &summary.ActionItems); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal action_items: %w", err)
-		}
+	if summary.ActionItems, err = unmarshalStringSlice(actionItemsJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal action_items: %w", err)
 	}
 
 	return &summary, nil
 }
 
-// ======================================
-// Digital Accounts Functions
-// ======================================
+// GetTokenUsageSince returns the tokens used by AI summaries generated since a time
+// An empty provider totals every provider
+func GetTokenUsageSince(db *sql.DB, provider string, since time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(tokens_used), 0)
+		FROM ai_summaries
+		WHERE generated_at >= ? AND (? = '' OR provider = ?)
+	`
 
-// Account represents a digital account (subscription, trial, or free)
-type Account struct {
-	ID             int64
-	ServiceName    string
-	EmailAddress   string
-	AccountType    string  // "trial", "paid", "free"
-	Status         string  // "active", "cancelled"
-	PriceMonthly   float64
-	TrialEndDate   *time.Time
+	var total int
+	if err := db.QueryRow(query, since.Unix(), provider, provider).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to query token usage: %w", err)
+	}
+	return total, nil
+}
+
+// GetTokenUsageByProvider returns the tokens used since a time, keyed by provider
+func GetTokenUsageByProvider(db *sql.DB, since time.Time) (map[string]int, error) {
+	query := `
+		SELECT provider, COALESCE(SUM(tokens_used), 0)
+		FROM ai_summaries
+		WHERE generated_at >= ?
+		GROUP BY provider
+	`
+
+	rows, err := db.Query(query, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := make(map[string]int)
+	for rows.Next() {
+		var provider string
+		var tokens int
+		if err := rows.Scan(&provider, &tokens); err != nil {
+			return nil, fmt.Errorf("failed to scan token usage: %w", err)
+		}
+		usage[provider] = tokens
+	}
+
+	return usage, rows.Err()
+}
+
+// marshalStringSlice encodes a string slice as a JSON array ("[]" when empty)
+func marshalStringSlice(values []string) (string, error) {
+	if len(values) == 0 {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// unmarshalStringSlice decodes a JSON array into a string slice (nil when empty)
+func unmarshalStringSlice(data string) ([]string, error) {
+	if data == "" || data == "[]" {
+		return nil, nil
+	}
+
+	var values []string
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ======================================
+// Digital Accounts Functions
+// ======================================
+
+// Account represents a digital account (subscription, trial, or free)
+type Account struct {
+	ID             int64
+	ServiceName    string
+	EmailAddress   string
+	AccountType    string  // "trial", "paid", "free"
+	Status         string  // "active", "cancelled"
+	PriceMonthly   float64
+	Currency       string  // ISO 4217 code of PriceMonthly, e.g. "USD"
+	TrialEndDate   *time.Time
 	GmailMessageID string
 	DetectedAt     time.Time
 	UpdatedAt      time.Time
 	Confidence     float64
 	CancelURL      string
 	Category       string
+	ConfirmedAt    *time.Time // When the user confirmed the account in review (nil = unconfirmed)
 }
 
 // InsertAccount saves a new account to the database
@@ -972,8 +1277,8 @@ func InsertAccount(db *sql.DB, acc *Account) error {
 		INSERT INTO accounts (
 			service_name, email_address, account_type, status, price_monthly,
 			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
-			cancel_url, category
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			cancel_url, category, currency
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var trialEndUnix *int64
@@ -996,6 +1301,7 @@ func InsertAccount(db *sql.DB, acc *Account) error {
 		acc.Confidence,
 		acc.CancelURL,
 		acc.Category,
+		accountCurrency(acc),
 	)
 
 	if err != nil {
@@ -1017,7 +1323,7 @@ func GetAllAccounts(db *sql.DB) ([]Account, error) {
 		SELECT
 			id, service_name, email_address, account_type, status, price_monthly,
 			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
-			cancel_url, category
+			cancel_url, category, currency, confirmed_at
 		FROM accounts
 		ORDER BY detected_at DESC
 	`
@@ -1037,7 +1343,7 @@ func GetAccountsByType(db *sql.DB, accountType string) ([]Account, error) {
 		SELECT
 			id, service_name, email_address, account_type, status, price_monthly,
 			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
-			cancel_url, category
+			cancel_url, category, currency, confirmed_at
 		FROM accounts
 		WHERE account_type = ? AND status = 'active'
 		ORDER BY detected_at DESC
@@ -1058,7 +1364,7 @@ func GetActiveTrials(db *sql.DB) ([]Account, error) {
 		SELECT
 			id, service_name, email_address, account_type, status, price_monthly,
 			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
-			cancel_url, category
+			cancel_url, category, currency, confirmed_at
 		FROM accounts
 		WHERE account_type = 'trial' AND status = 'active' AND trial_end_date IS NOT NULL
 		ORDER BY trial_end_date ASC
@@ -1073,13 +1379,37 @@ func GetActiveTrials(db *sql.DB) ([]Account, error) {
 	return scanAccounts(rows)
 }
 
+// GetTrialsExpiringWithin returns active trials ending within the next days days, soonest first
+// Trials whose end date has already passed are left out.
+func GetTrialsExpiringWithin(db *sql.DB, days int) ([]Account, error) {
+	query := `
+		SELECT
+			id, service_name, email_address, account_type, status, price_monthly,
+			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
+			cancel_url, category, currency, confirmed_at
+		FROM accounts
+		WHERE account_type = 'trial' AND status = 'active'
+			AND trial_end_date IS NOT NULL AND trial_end_date >= ? AND trial_end_date <= ?
+		ORDER BY trial_end_date ASC
+	`
+
+	now := time.Now()
+	rows, err := db.Query(query, now.Unix(), now.AddDate(0, 0, days).Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expiring trials: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAccounts(rows)
+}
+
 // SearchAccounts searches for accounts by service name (case-insensitive)
 func SearchAccounts(db *sql.DB, searchTerm string) ([]Account, error) {
 	query := `
 		SELECT
 			id, service_name, email_address, account_type, status, price_monthly,
 			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
-			cancel_url, category
+			cancel_url, category, currency, confirmed_at
 		FROM accounts
 		WHERE service_name LIKE ? COLLATE NOCASE
 		ORDER BY detected_at DESC
@@ -1095,8 +1425,80 @@ func SearchAccounts(db *sql.DB, searchTerm string) ([]Account, error) {
 	return scanAccounts(rows)
 }
 
-// UpdateAccountStatus updates the status of an account
-func UpdateAccountStatus(db *sql.DB, id int64, status string) error {
+// GetAccountByServiceAndEmail finds the most recent account for a service and email
+// Matching is case-insensitive. Returns nil if no account exists
+func GetAccountByServiceAndEmail(db *sql.DB, serviceName, email string) (*Account, error) {
+	query := `
+		SELECT
+			id, service_name, email_address, account_type, status, price_monthly,
+			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
+			cancel_url, category, currency, confirmed_at
+		FROM accounts
+		WHERE service_name = ? COLLATE NOCASE AND email_address = ? COLLATE NOCASE
+		ORDER BY detected_at DESC
+		LIMIT 1
+	`
+
+	rows, err := db.Query(query, serviceName, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account: %w", err)
+	}
+	defer rows.Close()
+
+	accounts, err := scanAccounts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, nil // No account found
+	}
+
+	return &accounts[0], nil
+}
+
+// UpdateAccountStatus updates the status of all accounts matching a service and email
+func UpdateAccountStatus(db *sql.DB, serviceName, email, status string) error {
+	query := `
+		UPDATE accounts SET status = ?, updated_at = ?
+		WHERE service_name = ? COLLATE NOCASE AND email_address = ? COLLATE NOCASE
+	`
+
+	result, err := db.Exec(query, status, time.Now().Unix(), serviceName, email)
+	if err != nil {
+		return fmt.Errorf("failed to update account status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("account '%s' (%s) not found", serviceName, email)
+	}
+
+	return nil
+}
+
+// ExpirePastTrials marks active trials whose trial_end_date has passed as expired
+// Returns the number of trials updated
+func ExpirePastTrials(db *sql.DB, now time.Time) (int64, error) {
+	query := `
+		UPDATE accounts SET status = 'expired', updated_at = ?
+		WHERE account_type = 'trial' AND status = 'active'
+			AND trial_end_date IS NOT NULL AND trial_end_date < ?
+	`
+
+	result, err := db.Exec(query, now.Unix(), now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire trials: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// UpdateAccountStatusByID updates the status of an account
+func UpdateAccountStatusByID(db *sql.DB, id int64, status string) error {
 	query := "UPDATE accounts SET status = ?, updated_at = ? WHERE id = ?"
 
 	result, err := db.Exec(query, status, time.Now().Unix(), id)
@@ -1116,7 +1518,7 @@ func UpdateAccountStatus(db *sql.DB, id int64, status string) error {
 	return nil
 }
 
-// DeleteAccount deletes an account by ID
+// DeleteAccount deletes an account by ID, along with its sent-alert records
 func DeleteAccount(db *sql.DB, id int64) error {
 	query := "DELETE FROM accounts WHERE id = ?"
 
@@ -1125,6 +1527,11 @@ func DeleteAccount(db *sql.DB, id int64) error {
 		return fmt.Errorf("failed to delete account: %w", err)
 	}
 
+	// Foreign keys aren't enforced, so ON DELETE CASCADE doesn't fire
+	if _, err := db.Exec("DELETE FROM account_alerts WHERE account_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete account alerts: %w", err)
+	}
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get affected rows: %w", err)
@@ -1137,21 +1544,433 @@ func DeleteAccount(db *sql.DB, id int64) error {
 	return nil
 }
 
-// GetTotalMonthlySpend calculates the total monthly spend across all active paid accounts
-func GetTotalMonthlySpend(db *sql.DB) (float64, error) {
+// GetAccountByID returns a single account by ID
+// Returns nil if no account exists
+func GetAccountByID(db *sql.DB, id int64) (*Account, error) {
 	query := `
-		SELECT COALESCE(SUM(price_monthly), 0)
+		SELECT
+			id, service_name, email_address, account_type, status, price_monthly,
+			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
+			cancel_url, category, currency, confirmed_at
+		FROM accounts
+		WHERE id = ?
+	`
+
+	rows, err := db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account: %w", err)
+	}
+	defer rows.Close()
+
+	accounts, err := scanAccounts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, nil // No account found
+	}
+
+	return &accounts[0], nil
+}
+
+// GetLowConfidenceAccounts returns unconfirmed accounts detected with a confidence below threshold
+// Lowest confidence first, these are the most likely misdetections
+func GetLowConfidenceAccounts(db *sql.DB, threshold float64) ([]Account, error) {
+	query := `
+		SELECT
+			id, service_name, email_address, account_type, status, price_monthly,
+			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
+			cancel_url, category, currency, confirmed_at
+		FROM accounts
+		WHERE confidence < ? AND confirmed_at IS NULL
+		ORDER BY confidence ASC, detected_at DESC
+	`
+
+	rows, err := db.Query(query, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAccounts(rows)
+}
+
+// GetUnconfirmedAccounts returns detected accounts the user hasn't confirmed yet
+// Most recently detected first, at most limit rows (0 = no limit)
+func GetUnconfirmedAccounts(db *sql.DB, limit int) ([]Account, error) {
+	query := `
+		SELECT
+			id, service_name, email_address, account_type, status, price_monthly,
+			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
+			cancel_url, category, currency, confirmed_at
+		FROM accounts
+		WHERE confirmed_at IS NULL
+		ORDER BY detected_at DESC
+	`
+	var args []any
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unconfirmed accounts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAccounts(rows)
+}
+
+// UpdateAccount saves every field of an existing account and bumps updated_at
+func UpdateAccount(db *sql.DB, acc *Account) error {
+	acc.UpdatedAt = time.Now()
+
+	result, err := updateAccountRow(db, acc)
+	if err != nil {
+		return fmt.Errorf("failed to update account: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("account with ID %d not found", acc.ID)
+	}
+
+	return nil
+}
+
+// ConfirmAccount marks an account as verified by the user
+// Confirmed accounts drop out of review and low-confidence cleanup
+func ConfirmAccount(db *sql.DB, id int64) error {
+	query := "UPDATE accounts SET confirmed_at = ?, updated_at = ? WHERE id = ?"
+
+	now := time.Now().Unix()
+	result, err := db.Exec(query, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to confirm account: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("account with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// updateAccountRow writes all columns of acc to its row, in or outside a transaction
+func updateAccountRow(exec interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, acc *Account) (sql.Result, error) {
+	var trialEndUnix *int64
+	if acc.TrialEndDate != nil {
+		unix := acc.TrialEndDate.Unix()
+		trialEndUnix = &unix
+	}
+
+	var confirmedUnix *int64
+	if acc.ConfirmedAt != nil {
+		unix := acc.ConfirmedAt.Unix()
+		confirmedUnix = &unix
+	}
+
+	update := `
+		UPDATE accounts SET
+			service_name = ?, email_address = ?, account_type = ?, status = ?,
+			price_monthly = ?, trial_end_date = ?, gmail_message_id = ?,
+			detected_at = ?, updated_at = ?, confidence = ?, cancel_url = ?, category = ?,
+			currency = ?, confirmed_at = ?
+		WHERE id = ?
+	`
+	return exec.Exec(
+		update,
+		acc.ServiceName,
+		acc.EmailAddress,
+		acc.AccountType,
+		acc.Status,
+		acc.PriceMonthly,
+		trialEndUnix,
+		acc.GmailMessageID,
+		acc.DetectedAt.Unix(),
+		acc.UpdatedAt.Unix(),
+		acc.Confidence,
+		acc.CancelURL,
+		acc.Category,
+		accountCurrency(acc),
+		confirmedUnix,
+		acc.ID,
+	)
+}
+
+// MergeAccounts merges two rows describing the same account into keepID and deletes dropID
+// The higher-confidence row wins for service name and other descriptive fields,
+// dates take the latest value, and a non-empty cancel URL is never lost.
+func MergeAccounts(db *sql.DB, keepID, dropID int64) (*Account, error) {
+	if keepID == dropID {
+		return nil, fmt.Errorf("cannot merge account %d with itself", keepID)
+	}
+
+	keep, err := GetAccountByID(db, keepID)
+	if err != nil {
+		return nil, err
+	}
+	if keep == nil {
+		return nil, fmt.Errorf("account with ID %d not found", keepID)
+	}
+
+	drop, err := GetAccountByID(db, dropID)
+	if err != nil {
+		return nil, err
+	}
+	if drop == nil {
+		return nil, fmt.Errorf("account with ID %d not found", dropID)
+	}
+
+	merged := mergeAccountFields(*keep, *drop)
+	merged.ID = keepID
+	merged.UpdatedAt = time.Now()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err := updateAccountRow(tx, &merged); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update merged account: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM accounts WHERE id = ?", dropID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete merged account: %w", err)
+	}
+
+	// Keep sent trial alerts so the merged account isn't alerted twice
+	if _, err := tx.Exec("UPDATE account_alerts SET account_id = ? WHERE account_id = ?", keepID, dropID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to move account alerts: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	return &merged, nil
+}
+
+// mergeAccountFields combines two accounts, preferring the higher-confidence one
+func mergeAccountFields(a, b Account) Account {
+	primary, secondary := a, b
+	if b.Confidence > a.Confidence {
+		primary, secondary = b, a
+	}
+
+	merged := primary
+	if merged.ServiceName == "" {
+		merged.ServiceName = secondary.ServiceName
+	}
+	if merged.EmailAddress == "" {
+		merged.EmailAddress = secondary.EmailAddress
+	}
+	if merged.AccountType == "" {
+		merged.AccountType = secondary.AccountType
+	}
+	if merged.Status == "" {
+		merged.Status = secondary.Status
+	}
+	if merged.Category == "" {
+		merged.Category = secondary.Category
+	}
+	if merged.PriceMonthly == 0 {
+		merged.PriceMonthly = secondary.PriceMonthly
+		merged.Currency = secondary.Currency
+	}
+	if merged.CancelURL == "" {
+		merged.CancelURL = secondary.CancelURL
+	}
+
+	// Dates take the latest value; the newest detection also owns the message ID
+	if secondary.DetectedAt.After(merged.DetectedAt) {
+		merged.DetectedAt = secondary.DetectedAt
+		merged.GmailMessageID = secondary.GmailMessageID
+	}
+	if secondary.TrialEndDate != nil && (merged.TrialEndDate == nil || secondary.TrialEndDate.After(*merged.TrialEndDate)) {
+		merged.TrialEndDate = secondary.TrialEndDate
+	}
+
+	// Confirming either row confirms the merged account
+	if merged.ConfirmedAt == nil {
+		merged.ConfirmedAt = secondary.ConfirmedAt
+	}
+
+	return merged
+}
+
+// CurrencySpend is the monthly spend in a single currency
+// Amounts in different currencies are never summed together.
+type CurrencySpend struct {
+	Currency string
+	Monthly  float64
+	Count    int
+}
+
+// GetTotalMonthlySpend calculates the monthly spend across all active priced accounts, per currency
+func GetTotalMonthlySpend(db *sql.DB) ([]CurrencySpend, error) {
+	query := `
+		SELECT currency, COALESCE(SUM(price_monthly), 0), COUNT(*)
 		FROM accounts
 		WHERE status = 'active' AND price_monthly > 0
+		GROUP BY currency
+		ORDER BY SUM(price_monthly) DESC
 	`
 
-	var total float64
-	err := db.QueryRow(query).Scan(&total)
+	spends, err := queryCurrencySpend(db, query)
 	if err != nil {
-		return 0, fmt.Errorf("failed to calculate total spend: %w", err)
+		return nil, fmt.Errorf("failed to calculate total spend: %w", err)
 	}
 
-	return total, nil
+	return spends, nil
+}
+
+// CategorySpend is the monthly spend for a single account category in one currency
+type CategorySpend struct {
+	Category string
+	Currency string
+	Monthly  float64
+	Count    int
+}
+
+// GetMonthlySpend sums price_monthly over active paid accounts, per currency
+func GetMonthlySpend(db *sql.DB) ([]CurrencySpend, error) {
+	query := `
+		SELECT currency, COALESCE(SUM(price_monthly), 0), COUNT(*)
+		FROM accounts
+		WHERE status = 'active' AND account_type = 'paid' AND price_monthly > 0
+		GROUP BY currency
+		ORDER BY SUM(price_monthly) DESC
+	`
+
+	spends, err := queryCurrencySpend(db, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate monthly spend: %w", err)
+	}
+
+	return spends, nil
+}
+
+// queryCurrencySpend runs a (currency, sum, count) query
+func queryCurrencySpend(db *sql.DB, query string) ([]CurrencySpend, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spends []CurrencySpend
+	for rows.Next() {
+		var cs CurrencySpend
+		if err := rows.Scan(&cs.Currency, &cs.Monthly, &cs.Count); err != nil {
+			return nil, err
+		}
+		spends = append(spends, cs)
+	}
+
+	return spends, rows.Err()
+}
+
+// GetSpendByCategory returns monthly spend for active paid accounts grouped by category and currency
+func GetSpendByCategory(db *sql.DB) ([]CategorySpend, error) {
+	query := `
+		SELECT COALESCE(NULLIF(category, ''), 'other') AS cat, currency, COALESCE(SUM(price_monthly), 0), COUNT(*)
+		FROM accounts
+		WHERE status = 'active' AND account_type = 'paid'
+		GROUP BY cat, currency
+		ORDER BY currency, SUM(price_monthly) DESC
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spend by category: %w", err)
+	}
+	defer rows.Close()
+
+	var spends []CategorySpend
+	for rows.Next() {
+		var cs CategorySpend
+		if err := rows.Scan(&cs.Category, &cs.Currency, &cs.Monthly, &cs.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan category spend: %w", err)
+		}
+		spends = append(spends, cs)
+	}
+
+	return spends, rows.Err()
+}
+
+// accountCurrency returns the account's currency, defaulting to USD like the column does
+func accountCurrency(acc *Account) string {
+	if acc.Currency == "" {
+		return "USD"
+	}
+	return strings.ToUpper(acc.Currency)
+}
+
+// HasAccountAlert reports whether an alert of alertType was already sent for an account
+// alertDate scopes the alert (the trial end date), so a trial that gets a new end date is alerted again
+func HasAccountAlert(db *sql.DB, accountID int64, alertType string, alertDate time.Time) (bool, error) {
+	query := `
+		SELECT COUNT(*) FROM account_alerts
+		WHERE account_id = ? AND alert_type = ? AND alert_date = ? AND sent_at IS NOT NULL
+	`
+
+	var count int
+	if err := db.QueryRow(query, accountID, alertType, alertDate.Unix()).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to query account alerts: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// RecordAccountAlert records that an alert of alertType was sent for an account
+func RecordAccountAlert(db *sql.DB, accountID int64, alertType string, alertDate time.Time) error {
+	query := `
+		INSERT INTO account_alerts (account_id, alert_type, alert_date, sent_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	if _, err := db.Exec(query, accountID, alertType, alertDate.Unix(), time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to record account alert: %w", err)
+	}
+
+	return nil
+}
+
+// GetPricedTrials returns active trials with a known price (charges if not cancelled)
+func GetPricedTrials(db *sql.DB) ([]Account, error) {
+	query := `
+		SELECT
+			id, service_name, email_address, account_type, status, price_monthly,
+			trial_end_date, gmail_message_id, detected_at, updated_at, confidence,
+			cancel_url, category, currency, confirmed_at
+		FROM accounts
+		WHERE account_type = 'trial' AND status = 'active' AND price_monthly > 0
+		ORDER BY trial_end_date ASC
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query priced trials: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAccounts(rows)
 }
 
 // scanAccounts is a helper function to scan rows into Account structs
@@ -1161,7 +1980,7 @@ func scanAccounts(rows *sql.Rows) ([]Account, error) {
 	for rows.Next() {
 		var acc Account
 		var detectedAt, updatedAt int64
-		var trialEndUnix sql.NullInt64
+		var trialEndUnix, confirmedUnix sql.NullInt64
 
 		err := rows.Scan(
 			&acc.ID,
@@ -1177,6 +1996,8 @@ func scanAccounts(rows *sql.Rows) ([]Account, error) {
 			&acc.Confidence,
 			&acc.CancelURL,
 			&acc.Category,
+			&acc.Currency,
+			&confirmedUnix,
 		)
 
 		if err != nil {
@@ -1190,6 +2011,10 @@ func scanAccounts(rows *sql.Rows) ([]Account, error) {
 			t := time.Unix(trialEndUnix.Int64, 0)
 			acc.TrialEndDate = &t
 		}
+		if confirmedUnix.Valid {
+			t := time.Unix(confirmedUnix.Int64, 0)
+			acc.ConfirmedAt = &t
+		}
 
 		accounts = append(accounts, acc)
 	}
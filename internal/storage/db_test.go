@@ -0,0 +1,1006 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
+)
+
+// openTestDB creates a fresh database with the full schema and migrations applied
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if err := RunMigrations(db); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	return db
+}
+
+func TestStringSliceRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+	}{
+		{name: "Empty", values: nil},
+		{name: "Embedded commas", values: []string{"Can we meet Monday, Tuesday, or Friday?", "a,b"}},
+		{name: "Quotes", values: []string{`She said "approved"`, `'single' and "double"`}},
+		{name: "Newlines and tabs", values: []string{"line one\nline two", "col1\tcol2"}},
+		{name: "Brackets", values: []string{"[urgent] review [draft]", "]", "[\"nested\"]"}},
+		{name: "Non-ASCII", values: []string{"Réunion à 15h ?", "会议 📅", "naïve café"}},
+		{name: "Backslashes", values: []string{`C:\Users\report.pdf`, `\u0041 literal`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := marshalStringSlice(tt.values)
+			if err != nil {
+				t.Fatalf("marshalStringSlice() error = %v", err)
+			}
+
+			decoded, err := unmarshalStringSlice(encoded)
+			if err != nil {
+				t.Fatalf("unmarshalStringSlice(%q) error = %v", encoded, err)
+			}
+
+			if !reflect.DeepEqual(decoded, tt.values) {
+				t.Errorf("round trip = %#v, want %#v", decoded, tt.values)
+			}
+		})
+	}
+}
+
+func TestAISummaryRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+
+	summary := &EmailSummary{
+		MessageID:   "msg-123",
+		Summary:     "Contract renewal, needs signature",
+		Questions:   []string{"Can you sign by Friday, March 3rd?", `Is "Plan B" still OK?`},
+		ActionItems: []string{"Sign the contract\n(page 4)", "Reply to Zoë 👍"},
+		Provider:    "gemini",
+		Model:       "gemini-test",
+		GeneratedAt: time.Now().Truncate(time.Second),
+		TokensUsed:  42,
+	}
+
+	if err := InsertAISummary(db, summary); err != nil {
+		t.Fatalf("InsertAISummary() error = %v", err)
+	}
+
+	loaded, err := GetAISummaryByMessageID(db, "msg-123")
+	if err != nil {
+		t.Fatalf("GetAISummaryByMessageID() error = %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("GetAISummaryByMessageID() returned nil")
+	}
+
+	if !reflect.DeepEqual(loaded.Questions, summary.Questions) {
+		t.Errorf("Questions = %#v, want %#v", loaded.Questions, summary.Questions)
+	}
+	if !reflect.DeepEqual(loaded.ActionItems, summary.ActionItems) {
+		t.Errorf("ActionItems = %#v, want %#v", loaded.ActionItems, summary.ActionItems)
+	}
+}
+
+func TestCheckStatsIncrement(t *testing.T) {
+	db := openTestDB(t)
+
+	stats, err := GetTodayStats(db)
+	if err != nil {
+		t.Fatalf("GetTodayStats() error = %v", err)
+	}
+	if stats.EmailsChecked != 0 || stats.Matches != 0 || stats.Checks != 0 {
+		t.Errorf("GetTodayStats() on empty db = %+v, want zero counters", stats)
+	}
+
+	if err := IncrementCheckStats(db, 10, 2); err != nil {
+		t.Fatalf("IncrementCheckStats() error = %v", err)
+	}
+	if err := IncrementCheckStats(db, 5, 0); err != nil {
+		t.Fatalf("IncrementCheckStats() error = %v", err)
+	}
+
+	stats, err = GetTodayStats(db)
+	if err != nil {
+		t.Fatalf("GetTodayStats() error = %v", err)
+	}
+	if stats.EmailsChecked != 15 || stats.Matches != 2 || stats.Checks != 2 {
+		t.Errorf("GetTodayStats() = %+v, want 15 checked, 2 matches, 2 checks", stats)
+	}
+}
+
+func TestGetAISummaryByContentHash(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now().Truncate(time.Second)
+
+	for _, s := range []*EmailSummary{
+		{MessageID: "old", Summary: "old summary", Provider: "gemini", Model: "m", GeneratedAt: now.Add(-48 * time.Hour), ContentHash: "abc"},
+		{MessageID: "new", Summary: "new summary", Provider: "gemini", Model: "m", GeneratedAt: now.Add(-1 * time.Hour), ContentHash: "abc"},
+		{MessageID: "other", Summary: "other summary", Provider: "gemini", Model: "m", GeneratedAt: now, ContentHash: "xyz"},
+	} {
+		if err := InsertAISummary(db, s); err != nil {
+			t.Fatalf("InsertAISummary() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		hash      string
+		notBefore time.Time
+		wantID    string // expected MessageID ("" = no match)
+	}{
+		{"most recent match", "abc", time.Time{}, "new"},
+		{"within ttl", "abc", now.Add(-24 * time.Hour), "new"},
+		{"all expired", "abc", now.Add(-30 * time.Minute), ""},
+		{"unknown hash", "nope", time.Time{}, ""},
+		{"empty hash", "", time.Time{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAISummaryByContentHash(db, tt.hash, tt.notBefore)
+			if err != nil {
+				t.Fatalf("GetAISummaryByContentHash() error = %v", err)
+			}
+			gotID := ""
+			if got != nil {
+				gotID = got.MessageID
+			}
+			if gotID != tt.wantID {
+				t.Errorf("GetAISummaryByContentHash() = %q, want %q", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestTokenUsage(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now().Truncate(time.Second)
+
+	for _, s := range []*EmailSummary{
+		{MessageID: "a", Provider: "claude", Model: "m", GeneratedAt: now.Add(-48 * time.Hour), TokensUsed: 500},
+		{MessageID: "b", Provider: "claude", Model: "m", GeneratedAt: now.Add(-1 * time.Hour), TokensUsed: 120},
+		{MessageID: "c", Provider: "claude", Model: "m", GeneratedAt: now, TokensUsed: 80},
+		{MessageID: "d", Provider: "gemini", Model: "m", GeneratedAt: now, TokensUsed: 40},
+	} {
+		if err := InsertAISummary(db, s); err != nil {
+			t.Fatalf("InsertAISummary() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		provider string
+		since    time.Time
+		want     int
+	}{
+		{"one provider today", "claude", now.Add(-24 * time.Hour), 200},
+		{"one provider all time", "claude", time.Time{}, 700},
+		{"all providers today", "", now.Add(-24 * time.Hour), 240},
+		{"unused provider", "openai", time.Time{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetTokenUsageSince(db, tt.provider, tt.since)
+			if err != nil {
+				t.Fatalf("GetTokenUsageSince() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetTokenUsageSince(%q) = %d, want %d", tt.provider, got, tt.want)
+			}
+		})
+	}
+
+	byProvider, err := GetTokenUsageByProvider(db, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetTokenUsageByProvider() error = %v", err)
+	}
+	if len(byProvider) != 2 || byProvider["claude"] != 200 || byProvider["gemini"] != 40 {
+		t.Errorf("GetTokenUsageByProvider() = %v, want claude:200 gemini:40", byProvider)
+	}
+}
+
+func TestAlertLookups(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now().Truncate(time.Second)
+
+	for i, a := range []*Alert{
+		{MessageID: "m1", FilterName: "Jobs", Subject: "first", Timestamp: now.Add(-3 * time.Hour)},
+		{MessageID: "m2", FilterName: "Jobs", Subject: "second", Timestamp: now.Add(-2 * time.Hour)},
+		{MessageID: "m3", FilterName: "Bank", Subject: "third", Timestamp: now.Add(-1 * time.Hour)},
+		{MessageID: "m4", FilterName: "Bank, Jobs Abroad", Subject: "fourth", Timestamp: now.Add(-30 * time.Minute)},
+	} {
+		if err := InsertAlert(db, a); err != nil {
+			t.Fatalf("InsertAlert(%d) error = %v", i, err)
+		}
+	}
+
+	t.Run("by message id", func(t *testing.T) {
+		got, err := GetAlertByMessageID(db, "m2")
+		if err != nil {
+			t.Fatalf("GetAlertByMessageID() error = %v", err)
+		}
+		if got == nil || got.Subject != "second" {
+			t.Errorf("GetAlertByMessageID() = %+v, want subject %q", got, "second")
+		}
+	})
+
+	t.Run("unknown message id", func(t *testing.T) {
+		got, err := GetAlertByMessageID(db, "missing")
+		if err != nil || got != nil {
+			t.Errorf("GetAlertByMessageID() = %+v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("by filter newest first", func(t *testing.T) {
+		got, err := GetRecentAlertsByFilter(db, "Jobs", 1)
+		if err != nil {
+			t.Fatalf("GetRecentAlertsByFilter() error = %v", err)
+		}
+		if len(got) != 1 || got[0].MessageID != "m2" {
+			t.Errorf("GetRecentAlertsByFilter() = %+v, want only m2", got)
+		}
+	})
+
+	t.Run("by filter with several matched filters", func(t *testing.T) {
+		got, err := GetRecentAlertsByFilter(db, "Bank", 10)
+		if err != nil {
+			t.Fatalf("GetRecentAlertsByFilter() error = %v", err)
+		}
+		if len(got) != 2 || got[0].MessageID != "m4" || got[1].MessageID != "m3" {
+			t.Errorf("GetRecentAlertsByFilter() = %+v, want m4 and m3", got)
+		}
+		if names := got[0].FilterNames(); !reflect.DeepEqual(names, []string{"Bank", "Jobs Abroad"}) {
+			t.Errorf("FilterNames() = %#v", names)
+		}
+	})
+
+	t.Run("after an id oldest first", func(t *testing.T) {
+		recent, err := GetRecentAlerts(db, 3)
+		if err != nil || len(recent) != 3 {
+			t.Fatalf("GetRecentAlerts() = %d alerts, %v", len(recent), err)
+		}
+
+		// The cursor is the oldest of the three, so the two after it come back
+		got, err := GetAlertsAfter(db, recent[2].ID)
+		if err != nil {
+			t.Fatalf("GetAlertsAfter() error = %v", err)
+		}
+		if len(got) != 2 || got[0].MessageID != "m3" || got[1].MessageID != "m4" {
+			t.Errorf("GetAlertsAfter() = %+v, want m3 then m4", got)
+		}
+
+		if got, err := GetAlertsAfter(db, recent[0].ID); err != nil || len(got) != 0 {
+			t.Errorf("GetAlertsAfter(latest) = %+v, %v; want none", got, err)
+		}
+	})
+}
+
+func TestGetLastAlertID(t *testing.T) {
+	db := openTestDB(t)
+
+	if id, err := GetLastAlertID(db); err != nil || id != 0 {
+		t.Fatalf("GetLastAlertID(empty) = %d, %v; want 0, nil", id, err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	newer := &Alert{MessageID: "m1", FilterName: "Jobs", Subject: "newer", Timestamp: now}
+	older := &Alert{MessageID: "m2", FilterName: "Jobs", Subject: "older", Timestamp: now.Add(-24 * time.Hour)}
+	for _, a := range []*Alert{newer, older} {
+		if err := InsertAlert(db, a); err != nil {
+			t.Fatalf("InsertAlert(%s) error = %v", a.MessageID, err)
+		}
+	}
+
+	// An email received earlier but saved later is still the last alert
+	saved, err := GetAlertByMessageID(db, "m2")
+	if err != nil || saved == nil {
+		t.Fatalf("GetAlertByMessageID() = %+v, %v", saved, err)
+	}
+	if id, err := GetLastAlertID(db); err != nil || id != saved.ID {
+		t.Errorf("GetLastAlertID() = %d, %v; want %d", id, err, saved.ID)
+	}
+}
+
+func TestGetTrialsExpiringWithin(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now().Truncate(time.Second)
+	day := 24 * time.Hour
+
+	trials := []struct {
+		service string
+		status  string
+		endsIn  time.Duration
+	}{
+		{"Later", "active", 6 * day},
+		{"Soonest", "active", 2 * time.Hour},
+		{"Outside window", "active", 10 * day},
+		{"Already ended", "active", -day},
+		{"Cancelled", "cancelled", day},
+	}
+	for _, tr := range trials {
+		end := now.Add(tr.endsIn)
+		acc := &Account{
+			ServiceName: tr.service, EmailAddress: "me@example.com", AccountType: "trial", Status: tr.status,
+			TrialEndDate: &end, DetectedAt: now, UpdatedAt: now, Confidence: 0.9,
+		}
+		if err := InsertAccount(db, acc); err != nil {
+			t.Fatalf("InsertAccount() error = %v", err)
+		}
+	}
+
+	got, err := GetTrialsExpiringWithin(db, 7)
+	if err != nil {
+		t.Fatalf("GetTrialsExpiringWithin() error = %v", err)
+	}
+
+	var names []string
+	for _, acc := range got {
+		names = append(names, acc.ServiceName)
+	}
+	if want := []string{"Soonest", "Later"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GetTrialsExpiringWithin(7) = %q, want %q", names, want)
+	}
+}
+
+func TestMergeAccounts(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now().Truncate(time.Second)
+	earlyEnd := now.Add(7 * 24 * time.Hour)
+	lateEnd := now.Add(14 * 24 * time.Hour)
+
+	misdetected := &Account{
+		ServiceName: "Netflix Inc", EmailAddress: "me@example.com", AccountType: "trial", Status: "active",
+		TrialEndDate: &lateEnd, GmailMessageID: "newer", DetectedAt: now, UpdatedAt: now,
+		Confidence: 0.72, CancelURL: "https://netflix.com/cancel",
+	}
+	confident := &Account{
+		ServiceName: "Netflix", EmailAddress: "me@example.com", AccountType: "trial", Status: "active",
+		PriceMonthly: 15.49, TrialEndDate: &earlyEnd, GmailMessageID: "older", DetectedAt: now.Add(-time.Hour),
+		UpdatedAt: now.Add(-time.Hour), Confidence: 0.95, Category: "streaming",
+	}
+	for _, acc := range []*Account{misdetected, confident} {
+		if err := InsertAccount(db, acc); err != nil {
+			t.Fatalf("InsertAccount() error = %v", err)
+		}
+	}
+
+	merged, err := MergeAccounts(db, misdetected.ID, confident.ID)
+	if err != nil {
+		t.Fatalf("MergeAccounts() error = %v", err)
+	}
+
+	got, err := GetAccountByID(db, misdetected.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetAccountByID() = %+v, %v; want merged account", got, err)
+	}
+	if got.ServiceName != "Netflix" || got.Confidence != 0.95 || got.Category != "streaming" || got.PriceMonthly != 15.49 {
+		t.Errorf("merged account = %+v, want fields from the higher-confidence row", got)
+	}
+	if got.CancelURL != "https://netflix.com/cancel" {
+		t.Errorf("CancelURL = %q, want the non-empty URL kept", got.CancelURL)
+	}
+	if !got.DetectedAt.Equal(now) || got.GmailMessageID != "newer" {
+		t.Errorf("DetectedAt = %v (%s), want latest detection %v (newer)", got.DetectedAt, got.GmailMessageID, now)
+	}
+	if got.TrialEndDate == nil || !got.TrialEndDate.Equal(lateEnd) {
+		t.Errorf("TrialEndDate = %v, want latest %v", got.TrialEndDate, lateEnd)
+	}
+	if merged.ID != misdetected.ID {
+		t.Errorf("MergeAccounts() ID = %d, want %d", merged.ID, misdetected.ID)
+	}
+
+	if dropped, err := GetAccountByID(db, confident.ID); err != nil || dropped != nil {
+		t.Errorf("GetAccountByID(dropped) = %+v, %v; want nil, nil", dropped, err)
+	}
+
+	t.Run("missing account", func(t *testing.T) {
+		if _, err := MergeAccounts(db, misdetected.ID, confident.ID); err == nil {
+			t.Error("MergeAccounts() with a deleted ID should fail")
+		}
+	})
+
+	t.Run("same account", func(t *testing.T) {
+		if _, err := MergeAccounts(db, misdetected.ID, misdetected.ID); err == nil {
+			t.Error("MergeAccounts() with the same ID twice should fail")
+		}
+	})
+}
+
+func TestGetLowConfidenceAccounts(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+
+	for _, c := range []float64{0.9, 0.71, 0.75} {
+		acc := &Account{ServiceName: "svc", EmailAddress: "me@example.com", AccountType: "free", Status: "active",
+			DetectedAt: now, UpdatedAt: now, Confidence: c}
+		if err := InsertAccount(db, acc); err != nil {
+			t.Fatalf("InsertAccount() error = %v", err)
+		}
+	}
+
+	got, err := GetLowConfidenceAccounts(db, 0.8)
+	if err != nil {
+		t.Fatalf("GetLowConfidenceAccounts() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Confidence != 0.71 || got[1].Confidence != 0.75 {
+		t.Errorf("GetLowConfidenceAccounts() = %+v, want 0.71 then 0.75", got)
+	}
+}
+
+// TestAccountReview tests editing and confirming detected accounts
+func TestAccountReview(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+
+	older := &Account{ServiceName: "Netflx", EmailAddress: "me@example.com", AccountType: "trial", Status: "active",
+		DetectedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour), Confidence: 0.72}
+	newer := &Account{ServiceName: "Spotify", EmailAddress: "me@example.com", AccountType: "paid", Status: "active",
+		PriceMonthly: 10.99, DetectedAt: now, UpdatedAt: now, Confidence: 0.9, Category: "streaming"}
+	for _, acc := range []*Account{older, newer} {
+		if err := InsertAccount(db, acc); err != nil {
+			t.Fatalf("InsertAccount() error = %v", err)
+		}
+	}
+
+	got, err := GetUnconfirmedAccounts(db, 0)
+	if err != nil || len(got) != 2 || got[0].ID != newer.ID {
+		t.Fatalf("GetUnconfirmedAccounts() = %+v, %v; want both, newest first", got, err)
+	}
+	if limited, _ := GetUnconfirmedAccounts(db, 1); len(limited) != 1 {
+		t.Errorf("GetUnconfirmedAccounts(limit 1) returned %d accounts", len(limited))
+	}
+
+	older.ServiceName = "Netflix"
+	older.PriceMonthly = 15.49
+	older.Category = "streaming"
+	if err := UpdateAccount(db, older); err != nil {
+		t.Fatalf("UpdateAccount() error = %v", err)
+	}
+	if err := ConfirmAccount(db, older.ID); err != nil {
+		t.Fatalf("ConfirmAccount() error = %v", err)
+	}
+
+	edited, err := GetAccountByID(db, older.ID)
+	if err != nil || edited == nil {
+		t.Fatalf("GetAccountByID() = %+v, %v", edited, err)
+	}
+	if edited.ServiceName != "Netflix" || edited.PriceMonthly != 15.49 || edited.Category != "streaming" || edited.ConfirmedAt == nil {
+		t.Errorf("edited account = %+v, want the new name, price and category, confirmed", edited)
+	}
+
+	remaining, _ := GetUnconfirmedAccounts(db, 0)
+	if len(remaining) != 1 || remaining[0].ID != newer.ID {
+		t.Errorf("GetUnconfirmedAccounts() after confirm = %+v, want only %d", remaining, newer.ID)
+	}
+
+	// Full detector confidence isn't a confirmation
+	newer.Confidence = 1.0
+	if err := UpdateAccount(db, newer); err != nil {
+		t.Fatalf("UpdateAccount() error = %v", err)
+	}
+	if remaining, _ := GetUnconfirmedAccounts(db, 0); len(remaining) != 1 || remaining[0].ID != newer.ID {
+		t.Errorf("GetUnconfirmedAccounts() = %+v, want the unconfirmed 1.0-confidence account %d", remaining, newer.ID)
+	}
+
+	// Later edits keep the confirmation
+	edited.Category = "video"
+	if err := UpdateAccount(db, edited); err != nil {
+		t.Fatalf("UpdateAccount() error = %v", err)
+	}
+	if again, _ := GetAccountByID(db, older.ID); again == nil || again.ConfirmedAt == nil {
+		t.Errorf("account after edit = %+v, want it still confirmed", again)
+	}
+
+	missing := &Account{ID: 999}
+	if err := UpdateAccount(db, missing); err == nil {
+		t.Error("UpdateAccount() with an unknown ID should fail")
+	}
+	if err := ConfirmAccount(db, 999); err == nil {
+		t.Error("ConfirmAccount() with an unknown ID should fail")
+	}
+}
+
+// TestAccountAlerts tests that sent trial alerts are recorded per threshold and trial end date
+func TestAccountAlerts(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+	trialEnd := now.Add(3 * 24 * time.Hour).Truncate(time.Second)
+
+	trial := &Account{ServiceName: "Netflix", EmailAddress: "me@example.com", AccountType: "trial", Status: "active",
+		TrialEndDate: &trialEnd, DetectedAt: now, UpdatedAt: now, Confidence: 0.9}
+	if err := InsertAccount(db, trial); err != nil {
+		t.Fatalf("InsertAccount() error = %v", err)
+	}
+
+	if sent, err := HasAccountAlert(db, trial.ID, "trial_expiring_3d", trialEnd); err != nil || sent {
+		t.Fatalf("HasAccountAlert() before sending = %v, %v; want false", sent, err)
+	}
+	if err := RecordAccountAlert(db, trial.ID, "trial_expiring_3d", trialEnd); err != nil {
+		t.Fatalf("RecordAccountAlert() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		alertType string
+		alertDate time.Time
+		want      bool
+	}{
+		{name: "Same threshold and end date", alertType: "trial_expiring_3d", alertDate: trialEnd, want: true},
+		{name: "Other threshold", alertType: "trial_expiring_1d", alertDate: trialEnd, want: false},
+		{name: "Trial extended", alertType: "trial_expiring_3d", alertDate: trialEnd.Add(7 * 24 * time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent, err := HasAccountAlert(db, trial.ID, tt.alertType, tt.alertDate)
+			if err != nil || sent != tt.want {
+				t.Errorf("HasAccountAlert() = %v, %v; want %v", sent, err, tt.want)
+			}
+		})
+	}
+
+	if err := DeleteAccount(db, trial.ID); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	if sent, _ := HasAccountAlert(db, trial.ID, "trial_expiring_3d", trialEnd); sent {
+		t.Error("HasAccountAlert() = true after DeleteAccount, want the records removed")
+	}
+}
+
+// TestRecoverFailedAlerts tests re-inserting alerts from the JSON lines failure log
+func TestRecoverFailedAlerts(t *testing.T) {
+	db := openTestDB(t)
+	logPath := filepath.Join(t.TempDir(), failureLogName)
+
+	ts := time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)
+	existing := &Alert{Timestamp: ts, Sender: "a@x.com", Subject: "Already saved", MessageID: "msg-1", GmailLink: "link-1", FilterName: "Work"}
+	if err := InsertAlert(db, existing); err != nil {
+		t.Fatalf("InsertAlert() error = %v", err)
+	}
+
+	for _, a := range []*Alert{
+		{Timestamp: ts, Sender: "a@x.com", Subject: "Already saved", MessageID: "msg-1", GmailLink: "link-1", FilterName: "Work"},
+		{Timestamp: ts, Sender: "b@y.com", Subject: "Lost during outage", Snippet: "Can we meet?", MessageID: "msg-2", GmailLink: "link-2", FilterName: "Work", Priority: 1},
+	} {
+		if err := appendFailureLog(logPath, a); err != nil {
+			t.Fatalf("appendFailureLog() error = %v", err)
+		}
+	}
+
+	// A redacted entry has no real sender or subject, so it isn't restored
+	log.SetRedact(true)
+	err := appendFailureLog(logPath, &Alert{Timestamp: ts, Sender: "d@w.com", Subject: "Secret", MessageID: "msg-4", GmailLink: "link-4", FilterName: "Work"})
+	log.SetRedact(false)
+	if err != nil {
+		t.Fatalf("appendFailureLog() error = %v", err)
+	}
+
+	// A line in the old text format can't be recovered and stays in the log
+	legacy := "[2025-01-15T09:30:00Z] Filter: Work | From: c@z.com | Subject: Old | Priority: 0 | Gmail: link-3"
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	f.WriteString(legacy + "\n")
+	f.Close()
+
+	result, err := recoverFailedAlerts(db, logPath)
+	if err != nil {
+		t.Fatalf("recoverFailedAlerts() error = %v", err)
+	}
+
+	want := RecoveryResult{Recovered: 1, Skipped: 1, Redacted: 1, Unreadable: 1}
+	if *result != want {
+		t.Errorf("recoverFailedAlerts() = %+v, want %+v", *result, want)
+	}
+
+	got, err := GetAlertByMessageID(db, "msg-2")
+	if err != nil || got == nil {
+		t.Fatalf("GetAlertByMessageID() = %v, %v", got, err)
+	}
+	if got.Subject != "Lost during outage" || got.Snippet != "Can we meet?" || got.Priority != 1 || !got.Timestamp.Equal(ts) {
+		t.Errorf("Recovered alert = %+v", got)
+	}
+
+	if got, _ := GetAlertByMessageID(db, "msg-4"); got != nil {
+		t.Errorf("Redacted alert was restored: %+v", got)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"Redacted":true`) || lines[1] != legacy {
+		t.Errorf("Failure log after recovery = %q, want the redacted and unreadable lines", data)
+	}
+	if _, err := os.Stat(logPath + ".recovering"); !os.IsNotExist(err) {
+		t.Errorf("Moved-aside log still exists: %v", err)
+	}
+
+	// Running again is a no-op
+	result, err = recoverFailedAlerts(db, logPath)
+	if err != nil || *result != (RecoveryResult{Redacted: 1, Unreadable: 1}) {
+		t.Errorf("Second recoverFailedAlerts() = %+v, %v", result, err)
+	}
+}
+
+// TestRecoverFailedAlertsRequeue tests that alerts go back to the same log when the database is still down
+func TestRecoverFailedAlertsRequeue(t *testing.T) {
+	db := openTestDB(t)
+	logPath := filepath.Join(t.TempDir(), failureLogName)
+
+	for _, id := range []string{"msg-1", "msg-2"} {
+		if err := appendFailureLog(logPath, &Alert{Timestamp: time.Now(), Sender: "a@x.com", Subject: "Hi", MessageID: id, GmailLink: "link", FilterName: "Work"}); err != nil {
+			t.Fatalf("appendFailureLog() error = %v", err)
+		}
+	}
+	db.Close()
+
+	result, err := recoverFailedAlerts(db, logPath)
+	if err == nil {
+		t.Fatal("recoverFailedAlerts() error = nil, want the database error")
+	}
+	if result.Requeued != 2 {
+		t.Errorf("Requeued = %d, want 2", result.Requeued)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("Failure log = %q, want both alerts back", data)
+	}
+}
+
+// TestAlertFilterLabels tests that the matched filter's labels are stored with the alert
+func TestAlertFilterLabels(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		name   string
+		labels []string
+	}{
+		{name: "No labels", labels: nil},
+		{name: "Single label", labels: []string{"otp"}},
+		{name: "Multiple labels", labels: []string{"work", "urgent"}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgID := fmt.Sprintf("msg-%d", i)
+			a := &Alert{Timestamp: time.Now(), Sender: "a@x.com", Subject: "Hi", MessageID: msgID, GmailLink: "link", FilterName: "Filter", FilterLabels: tt.labels}
+			if err := InsertAlert(db, a); err != nil {
+				t.Fatalf("InsertAlert() error = %v", err)
+			}
+
+			got, err := GetAlertByMessageID(db, msgID)
+			if err != nil || got == nil {
+				t.Fatalf("GetAlertByMessageID() = %v, %v", got, err)
+			}
+			if !reflect.DeepEqual(got.FilterLabels, tt.labels) {
+				t.Errorf("FilterLabels = %#v, want %#v", got.FilterLabels, tt.labels)
+			}
+		})
+	}
+}
+
+// TestInsertAlertDuplicate tests that a second alert for the same message is reported as a duplicate
+func TestInsertAlertDuplicate(t *testing.T) {
+	db := openTestDB(t)
+
+	newAlert := func() *Alert {
+		return &Alert{Timestamp: time.Now(), Sender: "a@x.com", Subject: "Hi", MessageID: "msg-1", GmailLink: "link", FilterName: "Work"}
+	}
+
+	if err := InsertAlertWithRetry(db, newAlert()); err != nil {
+		t.Fatalf("InsertAlertWithRetry() error = %v", err)
+	}
+
+	err := InsertAlert(db, newAlert())
+	if !IsDuplicateAlert(err) || !errors.Is(err, ErrDuplicateAlert) {
+		t.Errorf("InsertAlert() error = %v, want ErrDuplicateAlert", err)
+	}
+
+	if err := InsertAlertWithRetry(db, newAlert()); err != ErrDuplicateAlert {
+		t.Errorf("InsertAlertWithRetry() error = %v, want ErrDuplicateAlert", err)
+	}
+
+	if IsDuplicateAlert(nil) || IsDuplicateAlert(errors.New("CHECK constraint failed: priority")) {
+		t.Error("IsDuplicateAlert() = true for a non-duplicate error")
+	}
+}
+
+// TestCriticalPriorityMigration tests that an old 0/1-only alerts table is rebuilt to accept critical alerts
+func TestCriticalPriorityMigration(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "old.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	oldSchema := strings.Replace(schema, "CHECK(priority IN (0, 1, 2))", "CHECK(priority IN (0, 1))", 1)
+	if _, err := db.Exec(oldSchema); err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO alerts (timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority)
+		VALUES (?, 'a@x.com', 'Old', '', '', 'old-1', 'link', 'Work', 1)`, time.Now().Unix()); err != nil {
+		t.Fatalf("Failed to insert old alert: %v", err)
+	}
+
+	if err := RunMigrations(db); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	old, err := GetAlertByMessageID(db, "old-1")
+	if err != nil || old == nil || old.Priority != PriorityHigh {
+		t.Fatalf("GetAlertByMessageID(old-1) = %+v, %v; want the old alert with priority 1", old, err)
+	}
+
+	critical := &Alert{Timestamp: time.Now(), Sender: "a@x.com", Subject: "New", MessageID: "new-1", GmailLink: "link", FilterName: "Work", Priority: PriorityCritical}
+	if err := InsertAlert(db, critical); err != nil {
+		t.Errorf("InsertAlert(priority 2) error = %v", err)
+	}
+
+	invalid := &Alert{Timestamp: time.Now(), Sender: "a@x.com", Subject: "Bad", MessageID: "bad-1", GmailLink: "link", FilterName: "Work", Priority: 3}
+	if err := InsertAlert(db, invalid); err == nil {
+		t.Error("InsertAlert(priority 3) succeeded, want a CHECK constraint error")
+	}
+
+	// Running the migration again leaves the rebuilt table alone
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	defer tx.Rollback()
+	if err := Migration_007_AllowCriticalPriority(tx); err != nil {
+		t.Errorf("Migration_007_AllowCriticalPriority() second run error = %v", err)
+	}
+}
+
+func TestSpendByCurrency(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+
+	for _, acc := range []*Account{
+		{ServiceName: "Netflix", AccountType: "paid", PriceMonthly: 15.49, Currency: "USD", Category: "streaming"},
+		{ServiceName: "Spotify", AccountType: "paid", PriceMonthly: 9.99, Category: "streaming"}, // defaults to USD
+		{ServiceName: "Zeit", AccountType: "paid", PriceMonthly: 12.00, Currency: "EUR", Category: "news"},
+		{ServiceName: "BBC", AccountType: "paid", PriceMonthly: 8.00, Currency: "GBP", Status: "cancelled"},
+	} {
+		acc.EmailAddress = "me@example.com"
+		if acc.Status == "" {
+			acc.Status = "active"
+		}
+		acc.DetectedAt, acc.UpdatedAt = now, now
+		if err := InsertAccount(db, acc); err != nil {
+			t.Fatalf("InsertAccount() error = %v", err)
+		}
+	}
+
+	monthly, err := GetMonthlySpend(db)
+	if err != nil {
+		t.Fatalf("GetMonthlySpend() error = %v", err)
+	}
+	want := []CurrencySpend{{"USD", 25.48, 2}, {"EUR", 12.00, 1}}
+	if len(monthly) != len(want) {
+		t.Fatalf("GetMonthlySpend() = %+v, want %+v", monthly, want)
+	}
+	for i := range want {
+		if monthly[i].Currency != want[i].Currency || math.Abs(monthly[i].Monthly-want[i].Monthly) > 0.001 || monthly[i].Count != want[i].Count {
+			t.Errorf("GetMonthlySpend()[%d] = %+v, want %+v", i, monthly[i], want[i])
+		}
+	}
+
+	categories, err := GetSpendByCategory(db)
+	if err != nil {
+		t.Fatalf("GetSpendByCategory() error = %v", err)
+	}
+	if len(categories) != 2 || categories[0].Currency != "EUR" || categories[0].Category != "news" ||
+		categories[1].Currency != "USD" || categories[1].Category != "streaming" {
+		t.Errorf("GetSpendByCategory() = %+v, want news in EUR and streaming in USD", categories)
+	}
+
+	accounts, err := GetAllAccounts(db)
+	if err != nil {
+		t.Fatalf("GetAllAccounts() error = %v", err)
+	}
+	for _, acc := range accounts {
+		if acc.Currency == "" {
+			t.Errorf("%s Currency is empty, want it stored", acc.ServiceName)
+		}
+	}
+}
+
+func TestClearOTPAlerts(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now().Truncate(time.Second)
+
+	for i, otp := range []*OTPAlert{
+		{OTPCode: "111111", Timestamp: now, ExpiresAt: now.Add(5 * time.Minute), IsActive: true},
+		{OTPCode: "222222", Timestamp: now.Add(-10 * time.Minute), ExpiresAt: now.Add(-5 * time.Minute), IsActive: true},
+		{OTPCode: "333333", Timestamp: now.Add(-time.Hour), ExpiresAt: now.Add(-55 * time.Minute), IsActive: false},
+	} {
+		if err := InsertOTPAlert(db, otp); err != nil {
+			t.Fatalf("InsertOTPAlert(%d) error = %v", i, err)
+		}
+	}
+
+	total, inactive, err := CountOTPAlerts(db)
+	if err != nil {
+		t.Fatalf("CountOTPAlerts() error = %v", err)
+	}
+	if total != 3 || inactive != 2 {
+		t.Errorf("CountOTPAlerts() = %d, %d; want 3, 2", total, inactive)
+	}
+
+	// An expired code still flagged active is cleared along with inactive ones
+	deleted, err := DeleteInactiveOTPAlerts(db)
+	if err != nil {
+		t.Fatalf("DeleteInactiveOTPAlerts() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeleteInactiveOTPAlerts() = %d, want 2", deleted)
+	}
+
+	remaining, err := GetRecentOTPAlerts(db, 10)
+	if err != nil {
+		t.Fatalf("GetRecentOTPAlerts() error = %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].OTPCode != "111111" {
+		t.Errorf("remaining codes = %+v, want only the active one", remaining)
+	}
+
+	for i := 0; i < 3; i++ {
+		code := &OTPAlert{OTPCode: fmt.Sprintf("90000%d", i), Timestamp: now.Add(time.Duration(i) * time.Second), ExpiresAt: now.Add(time.Hour), IsActive: true}
+		if err := InsertOTPAlert(db, code); err != nil {
+			t.Fatalf("InsertOTPAlert() error = %v", err)
+		}
+	}
+	if deleted, err := TrimOTPAlerts(db, 2); err != nil || deleted != 2 {
+		t.Errorf("TrimOTPAlerts(2) = %d, %v; want 2, nil", deleted, err)
+	}
+	if deleted, err := TrimOTPAlerts(db, 0); err != nil || deleted != 0 {
+		t.Errorf("TrimOTPAlerts(0) = %d, %v; want 0, nil", deleted, err)
+	}
+	newest, err := GetRecentOTPAlerts(db, 10)
+	if err != nil {
+		t.Fatalf("GetRecentOTPAlerts() error = %v", err)
+	}
+	if len(newest) != 2 || newest[0].OTPCode != "900002" || newest[1].OTPCode != "900001" {
+		t.Errorf("codes after trim = %+v, want the two newest", newest)
+	}
+
+	deleted, err = DeleteAllOTPAlerts(db)
+	if err != nil || deleted != 2 {
+		t.Errorf("DeleteAllOTPAlerts() = %d, %v; want 2, nil", deleted, err)
+	}
+	if total, _, _ := CountOTPAlerts(db); total != 0 {
+		t.Errorf("CountOTPAlerts() after DeleteAllOTPAlerts = %d, want 0", total)
+	}
+}
+
+// TestBackupDatabase tests that backups land in the configured directory and old ones are pruned
+func TestBackupDatabase(t *testing.T) {
+	db := openTestDB(t)
+	dir := t.TempDir()
+
+	// Older backups plus files that aren't database backups
+	for _, name := range []string{
+		"history_backup_20200101_000000.db",
+		"history_backup_20200102_000000.db",
+		"history_backup_20200103_000000.db",
+		"config_backup_20200101_000000.yaml",
+		"other.db",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	path, err := BackupDatabase(db, BackupOptions{Dir: dir, KeepLast: 2})
+	if err != nil {
+		t.Fatalf("BackupDatabase() error = %v", err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Errorf("backup written to %s, want it in %s", path, dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read backup directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	want := []string{
+		"config_backup_20200101_000000.yaml",
+		"history_backup_20200103_000000.db",
+		filepath.Base(path),
+		"other.db",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("backup directory = %q, want %q", names, want)
+	}
+}
+
+// TestCountMatchesByFilter tests per-filter match counts, including filters that never matched
+func TestCountMatchesByFilter(t *testing.T) {
+	alerts := []Alert{
+		{FilterName: "Boss"},
+		{FilterName: "Boss" + FilterNameSeparator + "Work"},
+		{FilterName: "work"},
+		{FilterName: "Removed Filter"},
+	}
+
+	got := CountMatchesByFilter(alerts, []string{"Work", "Boss", "Receipts"})
+	want := []CountEntry{{Key: "Work", Count: 2}, {Key: "Boss", Count: 2}, {Key: "Receipts", Count: 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CountMatchesByFilter() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRenameFilterInAlerts(t *testing.T) {
+	db := openTestDB(t)
+
+	names := map[string]string{
+		"m1": "Work",
+		"m2": "Boss" + FilterNameSeparator + "work",
+		"m3": "Workshop",
+		"m4": "Boss",
+	}
+	for id, name := range names {
+		if err := InsertAlert(db, &Alert{Timestamp: time.Now(), FilterName: name, MessageID: id}); err != nil {
+			t.Fatalf("InsertAlert(%s) error = %v", id, err)
+		}
+	}
+
+	updated, err := RenameFilterInAlerts(db, "Work", "Work Email")
+	if err != nil {
+		t.Fatalf("RenameFilterInAlerts() error = %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("RenameFilterInAlerts() updated = %d, want 2", updated)
+	}
+
+	want := map[string]string{
+		"m1": "Work Email",
+		"m2": "Boss" + FilterNameSeparator + "Work Email",
+		"m3": "Workshop",
+		"m4": "Boss",
+	}
+	for id, name := range want {
+		alert, err := GetAlertByMessageID(db, id)
+		if err != nil {
+			t.Fatalf("GetAlertByMessageID(%s) error = %v", id, err)
+		}
+		if alert.FilterName != name {
+			t.Errorf("alert %s filter_name = %q, want %q", id, alert.FilterName, name)
+		}
+	}
+}
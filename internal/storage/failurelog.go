@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/datateamsix/email-sentinel/internal/config"
+	"github.com/datateamsix/email-sentinel/internal/log"
+)
+
+// failureLogName is the file alerts are written to when the database is unavailable
+const failureLogName = "failed_alerts.log"
+
+// RecoveryResult summarizes a run of RecoverFailedAlerts
+type RecoveryResult struct {
+	Recovered  int // Alerts inserted into the database
+	Skipped    int // Alerts already in the database (same message_id)
+	Requeued   int // Alerts that still couldn't be saved and went back to the failure log
+	Redacted   int // Alerts logged with redact_logs on, left in the log since their sender and subject are gone
+	Unreadable int // Lines that aren't JSON alerts (e.g. the old text format), left in the log
+}
+
+// failureLogEntry is one JSON line of the failure log
+// Redacted marks entries whose sender was hashed and subject dropped, so they aren't restored as real alerts.
+type failureLogEntry struct {
+	Alert
+	Redacted bool `json:",omitempty"`
+}
+
+// FailureLogPath returns the path of the failure log in the config directory
+func FailureLogPath() (string, error) {
+	configDir, err := config.EnsureConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, failureLogName), nil
+}
+
+// writeToFailureLog writes an alert to a local file if database operations fail
+// This ensures no alerts are lost even if the database is completely unavailable
+func writeToFailureLog(alert *Alert) error {
+	logPath, err := FailureLogPath()
+	if err != nil {
+		return err
+	}
+	return appendFailureLog(logPath, alert)
+}
+
+// appendFailureLog appends an alert to the failure log as a JSON line
+// With redact_logs on, the sender is hashed and the subject/snippet omitted; the Gmail link still identifies the message
+func appendFailureLog(path string, alert *Alert) error {
+	entry := failureLogEntry{Alert: *alert}
+	entry.ID = 0
+	if log.Redacting() {
+		entry.Sender = log.Address(entry.Sender)
+		entry.Subject = ""
+		entry.Snippet = ""
+		entry.AISummary = nil
+		entry.Redacted = true
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	return appendFailureLines(path, []string{string(line)})
+}
+
+// appendFailureLines appends raw lines to the failure log
+func appendFailureLines(path string, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open failure log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		return fmt.Errorf("failed to write to failure log: %w", err)
+	}
+
+	return nil
+}
+
+// RecoverFailedAlerts re-inserts alerts from the failure log once the database is healthy
+// The log is moved aside first, so alerts the watcher appends meanwhile go to a fresh log.
+func RecoverFailedAlerts(db *sql.DB) (*RecoveryResult, error) {
+	logPath, err := FailureLogPath()
+	if err != nil {
+		return nil, err
+	}
+	return recoverFailedAlerts(db, logPath)
+}
+
+// recoverFailedAlerts implements RecoverFailedAlerts for a specific log file
+// Lines that can't be restored are appended back to path, unchanged.
+func recoverFailedAlerts(db *sql.DB, path string) (*RecoveryResult, error) {
+	result := &RecoveryResult{}
+
+	// A leftover file from an interrupted run is finished first; path is picked up by the next run
+	processing := path + ".recovering"
+	if _, err := os.Stat(processing); os.IsNotExist(err) {
+		if err := os.Rename(path, processing); err != nil {
+			if os.IsNotExist(err) {
+				return result, nil
+			}
+			return nil, fmt.Errorf("failed to move failure log aside: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(processing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read failure log: %w", err)
+	}
+
+	var kept []string
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var entry failureLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil || entry.MessageID == "" {
+			result.Unreadable++
+			kept = append(kept, line)
+			continue
+		}
+		if entry.Redacted {
+			result.Redacted++
+			kept = append(kept, line)
+			continue
+		}
+
+		a := &entry.Alert
+		a.ID = 0
+		err := retryDatabaseOperation(func() error {
+			return InsertAlert(db, a)
+		}, 3, "Recover alert")
+		switch {
+		case err == nil:
+			result.Recovered++
+		case IsDuplicateAlert(err):
+			result.Skipped++
+		default:
+			// Keep this and the remaining lines; the database is still unhealthy
+			result.Requeued++
+			kept = append(kept, line)
+			for _, rest := range lines[i+1:] {
+				if rest = strings.TrimSpace(rest); rest != "" {
+					result.Requeued++
+					kept = append(kept, rest)
+				}
+			}
+			if logErr := finishRecovery(path, processing, kept); logErr != nil {
+				return result, logErr
+			}
+			return result, err
+		}
+	}
+
+	return result, finishRecovery(path, processing, kept)
+}
+
+// finishRecovery appends the lines that weren't restored back to the failure log and removes the moved-aside copy
+func finishRecovery(path, processing string, kept []string) error {
+	if err := appendFailureLines(path, kept); err != nil {
+		return fmt.Errorf("failed to requeue alerts (still in %s): %w", processing, err)
+	}
+	if err := os.Remove(processing); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", processing, err)
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ package storage
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"strings"
 	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
 )
 
 // GetSchemaVersion retrieves the current schema version from the database
@@ -80,6 +82,12 @@ func RunMigrations(db *sql.DB) error {
 		{1, "Add OTP alerts table", Migration_001_AddOTPTable},
 		{2, "Add AI summaries table", Migration_002_AddAISummariesTable},
 		{3, "Add digital accounts table", Migration_003_AddAccountsTable},
+		{4, "Add daily check stats table", Migration_004_AddCheckStatsTable},
+		{5, "Add content hash to AI summaries", Migration_005_AddSummaryContentHash},
+		{6, "Add filter labels to alerts", Migration_006_AddAlertFilterLabels},
+		{7, "Allow critical priority on alerts", Migration_007_AllowCriticalPriority},
+		{8, "Add currency to accounts", Migration_008_AddAccountCurrency},
+		{9, "Add confirmed_at to accounts", Migration_009_AddAccountConfirmedAt},
 	}
 
 	// Run each pending migration
@@ -89,7 +97,7 @@ func RunMigrations(db *sql.DB) error {
 			continue
 		}
 
-		log.Printf("Running migration %d: %s", m.version, m.name)
+		log.Info("Running migration", "version", m.version, "name", m.name)
 
 		// Start transaction
 		tx, err := db.Begin()
@@ -114,7 +122,7 @@ func RunMigrations(db *sql.DB) error {
 			return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
 		}
 
-		log.Printf("Migration %d completed successfully", m.version)
+		log.Info("Migration completed successfully", "version", m.version)
 	}
 
 	return nil
@@ -228,3 +236,179 @@ func Migration_003_AddAccountsTable(tx *sql.Tx) error {
 
 	return nil
 }
+
+// Migration_004_AddCheckStatsTable creates the check_stats table for daily counters
+// One row per local calendar day with the number of messages examined and matched
+// This migration is idempotent - safe to run multiple times
+func Migration_004_AddCheckStatsTable(tx *sql.Tx) error {
+	schema := `
+		CREATE TABLE IF NOT EXISTS check_stats (
+			day TEXT PRIMARY KEY,
+			emails_checked INTEGER NOT NULL DEFAULT 0,
+			matches INTEGER NOT NULL DEFAULT 0,
+			checks INTEGER NOT NULL DEFAULT 0,
+			updated_at INTEGER NOT NULL
+		);
+	`
+
+	if _, err := tx.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create check_stats table: %w", err)
+	}
+
+	return nil
+}
+
+// Migration_005_AddSummaryContentHash adds a content_hash column to ai_summaries
+// Lets identical emails with different message IDs reuse a cached summary
+// This migration is idempotent - safe to run multiple times
+func Migration_005_AddSummaryContentHash(tx *sql.Tx) error {
+	exists, err := columnExists(tx, "ai_summaries", "content_hash")
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := tx.Exec(`ALTER TABLE ai_summaries ADD COLUMN content_hash TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("failed to add content_hash column: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_summary_content_hash ON ai_summaries(content_hash, generated_at DESC)`); err != nil {
+		return fmt.Errorf("failed to create content_hash index: %w", err)
+	}
+
+	return nil
+}
+
+// Migration_006_AddAlertFilterLabels adds a filter_labels column to alerts
+// Stores the matched filter's labels (comma-separated) so they no longer have to be guessed at read time
+// Existing rows are backfilled to empty - their labels were never recorded
+// This migration is idempotent - safe to run multiple times
+func Migration_006_AddAlertFilterLabels(tx *sql.Tx) error {
+	exists, err := columnExists(tx, "alerts", "filter_labels")
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := tx.Exec(`ALTER TABLE alerts ADD COLUMN filter_labels TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("failed to add filter_labels column: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE alerts SET filter_labels = '' WHERE filter_labels IS NULL`); err != nil {
+		return fmt.Errorf("failed to backfill filter_labels: %w", err)
+	}
+
+	return nil
+}
+
+// Migration_007_AllowCriticalPriority widens the alerts priority CHECK constraint to IN (0, 1, 2)
+// SQLite can't alter a constraint, so the table is rebuilt and existing 0/1 rows are copied as-is
+// This migration is idempotent - databases created with the new schema are left alone
+func Migration_007_AllowCriticalPriority(tx *sql.Tx) error {
+	var tableSQL string
+	if err := tx.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'alerts'`).Scan(&tableSQL); err != nil {
+		return fmt.Errorf("failed to read alerts schema: %w", err)
+	}
+	if !strings.Contains(tableSQL, "CHECK(priority IN (0, 1))") {
+		return nil
+	}
+
+	rebuild := `
+		CREATE TABLE alerts_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp INTEGER NOT NULL,
+			sender TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			snippet TEXT,
+			labels TEXT,
+			message_id TEXT NOT NULL UNIQUE,
+			gmail_link TEXT NOT NULL,
+			filter_name TEXT NOT NULL,
+			priority INTEGER DEFAULT 0 CHECK(priority IN (0, 1, 2)),
+			filter_labels TEXT NOT NULL DEFAULT ''
+		);
+
+		INSERT INTO alerts_new (id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels)
+		SELECT id, timestamp, sender, subject, snippet, labels, message_id, gmail_link, filter_name, priority, filter_labels
+		FROM alerts;
+
+		DROP TABLE alerts;
+		ALTER TABLE alerts_new RENAME TO alerts;
+
+		CREATE INDEX IF NOT EXISTS idx_timestamp ON alerts(timestamp DESC);
+		CREATE INDEX IF NOT EXISTS idx_message_id ON alerts(message_id);
+	`
+
+	if _, err := tx.Exec(rebuild); err != nil {
+		return fmt.Errorf("failed to rebuild alerts table: %w", err)
+	}
+
+	return nil
+}
+
+// Migration_008_AddAccountCurrency adds a currency column to accounts
+// Prices were only ever parsed from "$" amounts before, so existing rows are backfilled to USD
+// This migration is idempotent - safe to run multiple times
+func Migration_008_AddAccountCurrency(tx *sql.Tx) error {
+	exists, err := columnExists(tx, "accounts", "currency")
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := tx.Exec(`ALTER TABLE accounts ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD'`); err != nil {
+			return fmt.Errorf("failed to add currency column: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Migration_009_AddAccountConfirmedAt records when the user confirmed an account in review
+// Confirmation used to set confidence to 1.0, which well-detected accounts already have,
+// so existing rows start unconfirmed and show up in review once.
+// This migration is idempotent - safe to run multiple times
+func Migration_009_AddAccountConfirmedAt(tx *sql.Tx) error {
+	exists, err := columnExists(tx, "accounts", "confirmed_at")
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := tx.Exec(`ALTER TABLE accounts ADD COLUMN confirmed_at INTEGER`); err != nil {
+			return fmt.Errorf("failed to add confirmed_at column: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// columnExists reports whether a table has the named column
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s columns: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan %s columns: %w", table, err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+
+	return false, rows.Err()
+}
@@ -3,89 +3,55 @@ package storage
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
 )
 
-// StartDailyCleanup runs a cleanup task at 12:00 AM every day
-// It deletes all alerts from before today (midnight)
+// StartDailyCleanup runs a cleanup task at 12:00 AM (in loc) every day
+// It deletes alerts outside the retention window (0 = everything before today)
+// and OTP codes older than 24 hours
 // Runs in a goroutine until stopChan is closed
-func StartDailyCleanup(db *sql.DB, stopChan <-chan struct{}) {
+func StartDailyCleanup(db *sql.DB, retentionDays int, loc *time.Location, stopChan <-chan struct{}) {
 	for {
 		// Calculate time until next midnight
-		now := time.Now()
+		now := time.Now().In(loc)
 		nextMidnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
 		durationUntilMidnight := nextMidnight.Sub(now)
 
-		log.Printf("📅 Daily cleanup scheduled for %s (in %v)", nextMidnight.Format("2006-01-02 15:04:05"), durationUntilMidnight.Round(time.Second))
+		log.Info("Daily cleanup scheduled", log.Icon("📅"), "at", nextMidnight.Format("2006-01-02 15:04:05"), "in", durationUntilMidnight.Round(time.Second))
 
 		select {
 		case <-time.After(durationUntilMidnight):
 			// It's midnight, run cleanup
-			deleted, err := CleanupDailyAlerts(db)
+			deleted, err := CleanupDailyAlerts(db, retentionDays, loc)
 			if err != nil {
-				log.Printf("❌ Daily cleanup failed: %v", err)
+				log.Error("Daily cleanup failed", "error", err)
 			} else {
-				log.Printf("✅ Daily cleanup completed: deleted %d alert(s) from previous days", deleted)
+				log.Info("Daily cleanup completed", log.Icon("✅"), "deleted", deleted)
+			}
+
+			otpDeleted, err := DeleteExpiredOTPAlerts(db)
+			if err != nil {
+				log.Error("Failed to delete old OTP alerts", "error", err)
+			} else if otpDeleted > 0 {
+				log.Info("Deleted old OTP alerts", log.Icon("🔐"), "count", otpDeleted)
 			}
 
 		case <-stopChan:
-			log.Println("🛑 Daily cleanup scheduler stopped")
+			log.Info("Daily cleanup scheduler stopped", log.Icon("🛑"))
 			return
 		}
 	}
 }
 
 // RunCleanupNow immediately runs the cleanup (useful for testing/manual trigger)
-func RunCleanupNow(db *sql.DB) error {
-	deleted, err := CleanupDailyAlerts(db)
+func RunCleanupNow(db *sql.DB, retentionDays int, loc *time.Location) error {
+	deleted, err := CleanupDailyAlerts(db, retentionDays, loc)
 	if err != nil {
 		return fmt.Errorf("cleanup failed: %w", err)
 	}
 
-	log.Printf("🧹 Manual cleanup completed: deleted %d alert(s)", deleted)
+	log.Info("Manual cleanup completed", log.Icon("🧹"), "deleted", deleted)
 	return nil
 }
-
-// StartOTPCleanup runs OTP cleanup every 1 minute
-// It expires inactive OTP codes and deletes old ones
-// Runs in a goroutine until stopChan is closed
-func StartOTPCleanup(db *sql.DB, stopChan <-chan struct{}) {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	log.Println("🔐 OTP cleanup scheduler started (runs every 1 minute)")
-
-	// Run immediately on start
-	runOTPCleanup(db)
-
-	for {
-		select {
-		case <-ticker.C:
-			runOTPCleanup(db)
-
-		case <-stopChan:
-			log.Println("🛑 OTP cleanup scheduler stopped")
-			return
-		}
-	}
-}
-
-// runOTPCleanup executes the OTP cleanup tasks
-func runOTPCleanup(db *sql.DB) {
-	// Mark expired codes as inactive
-	expired, err := ExpireOTPAlerts(db)
-	if err != nil {
-		log.Printf("❌ Failed to expire OTP alerts: %v", err)
-	} else if expired > 0 {
-		log.Printf("🔐 Expired %d OTP alert(s)", expired)
-	}
-
-	// Delete old codes (older than 24h)
-	deleted, err := DeleteExpiredOTPAlerts(db)
-	if err != nil {
-		log.Printf("❌ Failed to delete old OTP alerts: %v", err)
-	} else if deleted > 0 {
-		log.Printf("🔐 Deleted %d old OTP alert(s)", deleted)
-	}
-}
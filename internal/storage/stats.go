@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CheckStats holds the persisted counters for a single day of monitoring
+type CheckStats struct {
+	Day           string // YYYY-MM-DD in local time
+	EmailsChecked int64  // New messages examined against filters
+	Matches       int64  // Filter matches found
+	Checks        int64  // Completed check cycles
+}
+
+// CountEntry is a single key/count pair in an aggregation
+type CountEntry struct {
+	Key   string
+	Count int
+}
+
+// AlertStats summarizes a set of alerts
+type AlertStats struct {
+	Total      int
+	ByFilter   []CountEntry // Sorted by count, descending
+	BySender   []CountEntry // Sorted by count, descending
+	ByPriority map[int]int  // priority level -> count
+}
+
+// ComputeAlertStats aggregates alerts by filter, sender and priority
+func ComputeAlertStats(alerts []Alert) *AlertStats {
+	byFilter := make(map[string]int)
+	bySender := make(map[string]int)
+	byPriority := make(map[int]int)
+
+	for _, alert := range alerts {
+		for _, name := range alert.FilterNames() {
+			byFilter[name]++
+		}
+		bySender[alert.Sender]++
+		byPriority[alert.Priority]++
+	}
+
+	return &AlertStats{
+		Total:      len(alerts),
+		ByFilter:   sortCounts(byFilter),
+		BySender:   sortCounts(bySender),
+		ByPriority: byPriority,
+	}
+}
+
+// CountMatchesByFilter counts how many alerts each named filter matched, in the order given
+// Filters that matched nothing are included with a zero count; names match case-insensitively.
+func CountMatchesByFilter(alerts []Alert, names []string) []CountEntry {
+	counts := make(map[string]int)
+	for _, alert := range alerts {
+		for _, name := range alert.FilterNames() {
+			counts[strings.ToLower(name)]++
+		}
+	}
+
+	entries := make([]CountEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, CountEntry{Key: name, Count: counts[strings.ToLower(name)]})
+	}
+	return entries
+}
+
+// TopN returns at most n entries from a sorted count list
+func TopN(entries []CountEntry, n int) []CountEntry {
+	if n <= 0 || len(entries) <= n {
+		return entries
+	}
+	return entries[:n]
+}
+
+// sortCounts converts a count map into a slice sorted by count (desc), then key
+func sortCounts(counts map[string]int) []CountEntry {
+	entries := make([]CountEntry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, CountEntry{Key: key, Count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Key < entries[j].Key
+	})
+
+	return entries
+}
+
+// statsDay returns the check_stats key for t
+func statsDay(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// IncrementCheckStats adds the results of one check cycle to today's counters
+func IncrementCheckStats(db *sql.DB, checked, matched int) error {
+	now := time.Now()
+
+	query := `
+		INSERT INTO check_stats (day, emails_checked, matches, checks, updated_at)
+		VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT(day) DO UPDATE SET
+			emails_checked = emails_checked + excluded.emails_checked,
+			matches = matches + excluded.matches,
+			checks = checks + 1,
+			updated_at = excluded.updated_at
+	`
+
+	if _, err := db.Exec(query, statsDay(now), checked, matched, now.Unix()); err != nil {
+		return fmt.Errorf("failed to update check stats: %w", err)
+	}
+
+	return nil
+}
+
+// GetTodayStats returns today's counters (zero values if nothing was checked yet)
+func GetTodayStats(db *sql.DB) (*CheckStats, error) {
+	stats := &CheckStats{Day: statsDay(time.Now())}
+
+	query := `
+		SELECT emails_checked, matches, checks
+		FROM check_stats
+		WHERE day = ?
+	`
+
+	err := db.QueryRow(query, stats.Day).Scan(&stats.EmailsChecked, &stats.Matches, &stats.Checks)
+	if err == sql.ErrNoRows {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check stats: %w", err)
+	}
+
+	return stats, nil
+}
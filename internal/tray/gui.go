@@ -1,14 +1,15 @@
 package tray
 
 import (
-	"log"
 	"os/exec"
 	"runtime"
+
+	"github.com/datateamsix/email-sentinel/internal/log"
 )
 
 // addFilterGUI opens an interactive terminal for adding a new filter
 func (app *TrayApp) addFilterGUI() {
-	log.Println("📝 Opening Add Filter dialog...")
+	log.Info("Opening Add Filter dialog...", log.Icon("📝"))
 
 	go func() {
 		var cmd *exec.Cmd
@@ -42,7 +43,7 @@ func (app *TrayApp) addFilterGUI() {
 
 		if cmd != nil {
 			if err := cmd.Start(); err != nil {
-				log.Printf("❌ Error opening Add Filter dialog: %v", err)
+				log.Error("Error opening Add Filter dialog", "error", err)
 			}
 		}
 	}()
@@ -50,7 +51,7 @@ func (app *TrayApp) addFilterGUI() {
 
 // editFilterGUI opens an interactive terminal for editing a filter
 func (app *TrayApp) editFilterGUI() {
-	log.Println("✏️ Opening Edit Filter dialog...")
+	log.Info("Opening Edit Filter dialog...", log.Icon("✏️"))
 
 	go func() {
 		var cmd *exec.Cmd
@@ -84,7 +85,7 @@ func (app *TrayApp) editFilterGUI() {
 
 		if cmd != nil {
 			if err := cmd.Start(); err != nil {
-				log.Printf("❌ Error opening Edit Filter dialog: %v", err)
+				log.Error("Error opening Edit Filter dialog", "error", err)
 			}
 		}
 	}()
@@ -131,7 +132,7 @@ func openTerminalWithCommand(command string, args ...string) {
 
 		if cmd != nil {
 			if err := cmd.Start(); err != nil {
-				log.Printf("❌ Error opening terminal: %v", err)
+				log.Error("Error opening terminal", "error", err)
 			}
 		}
 	}()
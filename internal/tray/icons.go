@@ -12,6 +12,9 @@ var IconNormal []byte
 //go:embed icons/urgent.ico
 var IconUrgent []byte
 
+//go:embed icons/paused.ico
+var IconPaused []byte
+
 // GetNormalIcon returns the normal state icon (no alerts)
 func GetNormalIcon() []byte {
 	return IconNormal
@@ -26,3 +29,8 @@ func GetUrgentIcon() []byte {
 func GetAlertIcon() []byte {
 	return IconUrgent
 }
+
+// GetPausedIcon returns the icon shown while monitoring is paused
+func GetPausedIcon() []byte {
+	return IconPaused
+}
@@ -3,14 +3,18 @@ package tray
 import (
 	"database/sql"
 	"fmt"
-	"log"
-	"net/url"
 	"os/exec"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/log"
+	"github.com/datateamsix/email-sentinel/internal/notify"
+	"github.com/datateamsix/email-sentinel/internal/otp"
+	"github.com/datateamsix/email-sentinel/internal/state"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 	"fyne.io/systray"
 )
@@ -19,6 +23,7 @@ import (
 type TrayApp struct {
 	db              *sql.DB
 	recentAlerts    []*systray.MenuItem
+	recentOTPs      []*systray.MenuItem
 	alertUpdateChan chan storage.Alert
 	quitChan        chan struct{}
 	mu              sync.Mutex
@@ -27,22 +32,33 @@ type TrayApp struct {
 	refreshMu       sync.Mutex
 	iconMu          sync.Mutex // Protects systray icon operations
 	cleanupInterval time.Duration
+	retentionDays   int
+	paused          atomic.Bool // Mirrors pause.json (see state.Pause)
+	onDesktopToggle func(enabled bool) error
+	otpClearAfter   time.Duration
+	desktopMu       sync.Mutex // Serializes desktop notification toggles and saves
 }
 
 // Config holds configuration for the tray app
 type Config struct {
 	DB              *sql.DB
-	CleanupInterval time.Duration // How often to cleanup old alerts (0 = disabled)
+	CleanupInterval time.Duration            // How often to cleanup old alerts (0 = disabled)
+	RetentionDays   int                      // Days of alert history to keep (0 = 24 hours)
+	OnDesktopToggle func(enabled bool) error // Persists the desktop notification toggle (optional)
+	OTPClearAfter   time.Duration            // Clear copied OTP codes from the clipboard after this long (0 = never)
 }
 
 var (
 	globalApp       *TrayApp
 	mRecentAlerts   *systray.MenuItem
+	mRecentOTPs     *systray.MenuItem
 	mManageAlerts   *systray.MenuItem
 	mAddFilter      *systray.MenuItem
 	mEditFilter     *systray.MenuItem
 	mClearAlerts    *systray.MenuItem
 	mOpenHistory    *systray.MenuItem
+	mPause          *systray.MenuItem
+	mDesktop        *systray.MenuItem
 	mQuit           *systray.MenuItem
 )
 
@@ -55,6 +71,9 @@ func Run(cfg Config) {
 		quitChan:        make(chan struct{}),
 		recentAlerts:    make([]*systray.MenuItem, 0),
 		cleanupInterval: cfg.CleanupInterval,
+		retentionDays:   cfg.RetentionDays,
+		onDesktopToggle: cfg.OnDesktopToggle,
+		otpClearAfter:   cfg.OTPClearAfter,
 	}
 
 	systray.Run(onReady, onExit)
@@ -73,6 +92,7 @@ func onReady() {
 
 	// Create menu items
 	mRecentAlerts = systray.AddMenuItem("📬 Recent Alerts", "View recent email alerts")
+	mRecentOTPs = systray.AddMenuItem("🔐 Recent OTP Codes", "Click a code to copy it to the clipboard")
 	systray.AddSeparator()
 
 	// Nested "Manage Filters" menu
@@ -90,6 +110,8 @@ func onReady() {
 	mClearAlerts = systray.AddMenuItem("🗑️ Clear Alerts", "Delete all alerts from history")
 	mOpenHistory = systray.AddMenuItem("📊 Open History", "View all alerts and commands in terminal")
 	systray.AddSeparator()
+	mDesktop = systray.AddMenuItem(desktopMenuTitle(notify.DesktopEnabled()), "Turn desktop notifications on or off")
+	mPause = systray.AddMenuItem("⏸️ Pause monitoring", "Stop checking email until resumed")
 	mQuit = systray.AddMenuItem("❌ Quit", "Quit Email Sentinel")
 
 	// Handle accounts menu clicks
@@ -106,19 +128,23 @@ func onReady() {
 		}
 	}()
 
-	// Load initial alerts
+	// Reflect a pause set before the tray started
+	globalApp.syncPauseState()
+
+	// Load initial alerts and OTP codes
 	go globalApp.loadRecentAlerts()
+	go globalApp.loadRecentOTPs()
 
 	// Start event handlers
 	go globalApp.handleMenuEvents()
 	go globalApp.handleAlertUpdates()
 
-	log.Println("📱 System tray initialized")
+	log.Info("System tray initialized", log.Icon("📱"))
 }
 
 // onExit is called when the system tray is exiting
 func onExit() {
-	log.Println("🛑 System tray shutting down")
+	log.Info("System tray shutting down", log.Icon("🛑"))
 	close(globalApp.quitChan)
 }
 
@@ -135,6 +161,7 @@ func (app *TrayApp) scheduleRefresh() {
 
 	app.refreshTimer = time.AfterFunc(500*time.Millisecond, func() {
 		app.loadRecentAlerts()
+		app.loadRecentOTPs()
 	})
 }
 
@@ -152,7 +179,7 @@ func (app *TrayApp) loadRecentAlerts() {
 	// Fetch recent alerts from database
 	alerts, err := storage.GetRecentAlerts(app.db, 10)
 	if err != nil {
-		log.Printf("Error loading recent alerts: %v", err)
+		log.Warn("Error loading recent alerts", "error", err)
 		// Only add "No alerts yet" if we haven't added it already
 		noAlerts := mRecentAlerts.AddSubMenuItem("No alerts yet", "")
 		noAlerts.Disable()
@@ -171,7 +198,7 @@ func (app *TrayApp) loadRecentAlerts() {
 	// Check if any are urgent
 	hasUrgent := false
 	for _, alert := range alerts {
-		if alert.Priority == 1 {
+		if alert.Priority >= storage.PriorityHigh {
 			hasUrgent = true
 			break
 		}
@@ -180,22 +207,27 @@ func (app *TrayApp) loadRecentAlerts() {
 
 	// Update icon based on alert presence (red flag up if ANY alerts exist)
 	app.iconMu.Lock()
-	if len(alerts) > 0 {
-		// Any alerts present - show alert icon (mailbox with red flag up)
-		if icon := GetAlertIcon(); icon != nil && len(icon) > 0 {
-			systray.SetIcon(icon)
-		}
-		if hasUrgent {
-			systray.SetTooltip(fmt.Sprintf("Email Sentinel - %d alerts (⚠️ %d urgent)", len(alerts), countUrgentAlerts(alerts)))
+	// While paused the paused icon takes precedence; alert state is restored on resume
+	if !app.isPaused() {
+		if len(alerts) > 0 {
+			// Any alerts present - show alert icon (mailbox with red flag up)
+			if icon := GetAlertIcon(); icon != nil && len(icon) > 0 {
+				systray.SetIcon(icon)
+			}
+			if critical := countCriticalAlerts(alerts); critical > 0 {
+				systray.SetTooltip(fmt.Sprintf("Email Sentinel - %d alerts (🚨 %d critical, ⚠️ %d urgent)", len(alerts), critical, countUrgentAlerts(alerts)))
+			} else if hasUrgent {
+				systray.SetTooltip(fmt.Sprintf("Email Sentinel - %d alerts (⚠️ %d urgent)", len(alerts), countUrgentAlerts(alerts)))
+			} else {
+				systray.SetTooltip(fmt.Sprintf("Email Sentinel - %d alerts", len(alerts)))
+			}
 		} else {
-			systray.SetTooltip(fmt.Sprintf("Email Sentinel - %d alerts", len(alerts)))
-		}
-	} else {
-		// No alerts - show normal icon (mailbox with flag down)
-		if icon := GetNormalIcon(); icon != nil && len(icon) > 0 {
-			systray.SetIcon(icon)
+			// No alerts - show normal icon (mailbox with flag down)
+			if icon := GetNormalIcon(); icon != nil && len(icon) > 0 {
+				systray.SetIcon(icon)
+			}
+			systray.SetTooltip("Email Sentinel - No alerts")
 		}
-		systray.SetTooltip("Email Sentinel - No alerts")
 	}
 	app.iconMu.Unlock()
 
@@ -227,7 +259,9 @@ func (app *TrayApp) addAlertMenuItem(alert storage.Alert) {
 		icon = "🔐" // Lock icon for OTP messages
 	} else if hasAISummary {
 		icon = "🤖" // AI icon for summarized emails
-	} else if alert.Priority == 1 {
+	} else if alert.Priority >= storage.PriorityCritical {
+		icon = "🚨" // Siren icon for critical priority
+	} else if alert.Priority == storage.PriorityHigh {
 		icon = "🔥" // Fire icon for high priority
 	}
 
@@ -258,6 +292,11 @@ func (app *TrayApp) addAlertMenuItem(alert storage.Alert) {
 		tooltip = fmt.Sprintf("🔐 OTP Message\nFrom: %s\nFilter: %s\nClick to open in Gmail", alert.Sender, alert.FilterName)
 	}
 
+	// Show the snippet when there's no AI summary to preview the email
+	if !hasAISummary && !isOTP && alert.Snippet != "" {
+		tooltip += "\n\n" + gmail.TruncateWords(alert.Snippet, gmail.RichSnippetLength)
+	}
+
 	// Add AI summary to tooltip if available
 	if hasAISummary && alert.AISummary != nil {
 		tooltip += fmt.Sprintf("\n\n🤖 AI Summary:\n%s", alert.AISummary.Summary)
@@ -303,6 +342,103 @@ func (app *TrayApp) addAlertMenuItem(alert storage.Alert) {
 	}(alert.GmailLink, menuItem)
 }
 
+// loadRecentOTPs rebuilds the OTP submenu from active (non-expired) codes
+// Called from the debounced refresh, so expired codes drop off within 30 seconds
+func (app *TrayApp) loadRecentOTPs() {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	for _, item := range app.recentOTPs {
+		item.Hide()
+	}
+	app.recentOTPs = make([]*systray.MenuItem, 0)
+
+	otps, err := storage.GetActiveOTPAlerts(app.db)
+	if err != nil {
+		log.Warn("Error loading OTP codes", "error", err)
+	}
+
+	if len(otps) == 0 {
+		noCodes := mRecentOTPs.AddSubMenuItem("No active codes", "")
+		noCodes.Disable()
+		app.recentOTPs = append(app.recentOTPs, noCodes)
+		return
+	}
+
+	// Show at most 5 codes, newest first
+	if len(otps) > 5 {
+		otps = otps[:5]
+	}
+
+	for _, otpAlert := range otps {
+		app.addOTPMenuItem(otpAlert)
+	}
+}
+
+// addOTPMenuItem adds a masked OTP code; clicking it copies the full code
+// The code is masked in the title for shoulder-surfing safety and shown in full only in the tooltip
+func (app *TrayApp) addOTPMenuItem(otpAlert storage.OTPAlert) {
+	title := fmt.Sprintf("%s %s (expires %s)", senderName(otpAlert.Sender), maskOTPCode(otpAlert.OTPCode), otpAlert.ExpiresAt.Format("15:04"))
+	tooltip := fmt.Sprintf("Code: %s\nFrom: %s\nSubject: %s\nClick to copy", otpAlert.OTPCode, otpAlert.Sender, otpAlert.Subject)
+
+	menuItem := mRecentOTPs.AddSubMenuItem(title, tooltip)
+	app.recentOTPs = append(app.recentOTPs, menuItem)
+
+	// Handle clicks on this code (copy to clipboard)
+	go func(o storage.OTPAlert, item *systray.MenuItem) {
+		for {
+			select {
+			case <-item.ClickedCh:
+				app.copyOTP(o)
+			case <-app.quitChan:
+				return
+			}
+		}
+	}(otpAlert, menuItem)
+}
+
+// copyOTP copies an OTP code to the clipboard and marks it as copied
+func (app *TrayApp) copyOTP(o storage.OTPAlert) {
+	if time.Now().After(o.ExpiresAt) {
+		log.Warn("OTP has expired", "from", log.Address(o.Sender))
+		app.scheduleRefresh()
+		return
+	}
+
+	if !otp.AutoCopy(o.OTPCode, app.otpClearAfter) {
+		return
+	}
+
+	if err := storage.MarkOTPAsCopied(app.db, o.ID); err != nil {
+		log.Warn("Error marking OTP as copied", "error", err)
+	}
+	log.Info("Copied OTP to clipboard", log.Icon("📋"), "from", log.Address(o.Sender))
+}
+
+// maskOTPCode hides all but the last 3 characters of a code (e.g. "•••123")
+func maskOTPCode(code string) string {
+	runes := []rune(code)
+	visible := 3
+	if len(runes) <= visible {
+		return strings.Repeat("•", len(runes))
+	}
+	return strings.Repeat("•", len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+// senderName returns the display name of a From header, or the address if there is none
+// Example: "GitHub <noreply@github.com>" -> "GitHub"
+func senderName(from string) string {
+	name := from
+	if idx := strings.Index(from, "<"); idx > 0 {
+		name = strings.Trim(strings.TrimSpace(from[:idx]), `"`)
+	}
+	// Count runes so a non-ASCII name isn't cut mid-character
+	if runes := []rune(name); len(runes) > 25 {
+		name = string(runes[:22]) + "..."
+	}
+	return name
+}
+
 // handleMenuEvents handles clicks on main menu items
 func (app *TrayApp) handleMenuEvents() {
 	for {
@@ -319,8 +455,14 @@ func (app *TrayApp) handleMenuEvents() {
 		case <-mOpenHistory.ClickedCh:
 			app.openHistory()
 
+		case <-mPause.ClickedCh:
+			app.togglePause()
+
+		case <-mDesktop.ClickedCh:
+			app.toggleDesktopNotifications()
+
 		case <-mQuit.ClickedCh:
-			log.Println("Quit requested from tray menu")
+			log.Info("Quit requested from tray menu")
 			systray.Quit()
 			return
 
@@ -343,26 +485,31 @@ func (app *TrayApp) handleAlertUpdates() {
 		cleanupTicker = time.NewTicker(app.cleanupInterval)
 		defer cleanupTicker.Stop()
 		cleanupChan = cleanupTicker.C
-		log.Printf("🗑️  Auto-cleanup enabled: checking every %v", app.cleanupInterval)
+		log.Info("Auto-cleanup enabled", log.Icon("🗑️ "), "interval", app.cleanupInterval)
 	} else {
-		log.Println("🗑️  Auto-cleanup disabled")
+		log.Info("Auto-cleanup disabled", log.Icon("🗑️ "))
 	}
 
 	for {
 		select {
 		case alert := <-app.alertUpdateChan:
-			log.Printf("📱 Tray: New alert received - %s", alert.Subject)
+			log.Debug("Tray: new alert received", "filter", alert.FilterName, "message_id", alert.MessageID)
 
 			// Temporarily switch to urgent icon if it's a priority alert
-			if alert.Priority == 1 {
+			if alert.Priority >= storage.PriorityHigh {
 				app.mu.Lock()
 				app.hasUrgent = true
 				app.mu.Unlock()
 
+				tooltip := "Email Sentinel - ⚠️ New urgent alert!"
+				if alert.Priority >= storage.PriorityCritical {
+					tooltip = "Email Sentinel - 🚨 New critical alert!"
+				}
+
 				if icon := GetUrgentIcon(); icon != nil && len(icon) > 0 {
 					app.iconMu.Lock()
 					systray.SetIcon(icon)
-					systray.SetTooltip("Email Sentinel - ⚠️ New urgent alert!")
+					systray.SetTooltip(tooltip)
 					app.iconMu.Unlock()
 				}
 
@@ -377,16 +524,30 @@ func (app *TrayApp) handleAlertUpdates() {
 			app.scheduleRefresh()
 
 		case <-ticker.C:
+			// Pick up pause/resume from the CLI and timed pauses expiring
+			app.syncPauseState()
+
 			// Periodically refresh the alerts (debounced)
 			app.scheduleRefresh()
 
 		case <-cleanupChan:
-			// Delete alerts older than 24 hours (only if cleanup is enabled)
+			// Delete alerts older than 24 hours, or the retention window if configured
+			if app.retentionDays > 0 {
+				deleted, err := storage.DeleteAlertsBefore(app.db, storage.RetentionCutoff(time.Now(), app.retentionDays))
+				if err != nil {
+					log.Warn("Error cleaning up old alerts", "error", err)
+				} else if deleted > 0 {
+					log.Info("Cleaned up old alerts", log.Icon("🗑️ "), "deleted", deleted, "retention_days", app.retentionDays)
+					app.scheduleRefresh()
+				}
+				continue
+			}
+
 			deleted, err := storage.DeleteAlerts24HoursOld(app.db)
 			if err != nil {
-				log.Printf("⚠️  Error cleaning up 24-hour-old alerts: %v", err)
+				log.Warn("Error cleaning up 24-hour-old alerts", "error", err)
 			} else if deleted > 0 {
-				log.Printf("🗑️  Cleaned up %d alert(s) older than 24 hours", deleted)
+				log.Info("Cleaned up alerts older than 24 hours", log.Icon("🗑️ "), "deleted", deleted)
 				app.scheduleRefresh()
 			}
 
@@ -396,6 +557,104 @@ func (app *TrayApp) handleAlertUpdates() {
 	}
 }
 
+// toggleDesktopNotifications flips desktop notifications and persists the new setting
+// The runtime flag is atomic, so alerts being processed concurrently see either value safely
+func (app *TrayApp) toggleDesktopNotifications() {
+	app.desktopMu.Lock()
+	defer app.desktopMu.Unlock()
+
+	enabled := notify.ToggleDesktopEnabled()
+	mDesktop.SetTitle(desktopMenuTitle(enabled))
+
+	if enabled {
+		log.Info("Desktop notifications enabled from tray", log.Icon("🔔"))
+	} else {
+		log.Info("Desktop notifications disabled from tray", log.Icon("🔕"))
+	}
+
+	if app.onDesktopToggle != nil {
+		if err := app.onDesktopToggle(enabled); err != nil {
+			log.Warn("Error saving desktop notification setting", "error", err)
+		}
+	}
+}
+
+// desktopMenuTitle returns the desktop notifications menu title for a setting
+func desktopMenuTitle(enabled bool) string {
+	if enabled {
+		return "✓ Desktop Notifications: On"
+	}
+	return "Desktop Notifications: Off"
+}
+
+// isPaused reports whether the tray is showing the paused state
+func (app *TrayApp) isPaused() bool {
+	return app.paused.Load()
+}
+
+// togglePause pauses monitoring until resumed, or resumes it if already paused
+func (app *TrayApp) togglePause() {
+	if state.IsPaused() {
+		if err := state.Resume(); err != nil {
+			log.Warn("Error resuming monitoring", "error", err)
+			return
+		}
+		log.Info("Monitoring resumed from tray", log.Icon("▶️ "))
+	} else {
+		if _, err := state.Pause(0); err != nil {
+			log.Warn("Error pausing monitoring", "error", err)
+			return
+		}
+		log.Info("Monitoring paused from tray", log.Icon("⏸️ "))
+	}
+
+	app.syncPauseState()
+}
+
+// syncPauseState updates the pause menu item and icon from pause.json
+func (app *TrayApp) syncPauseState() {
+	p, err := state.LoadPauseState()
+	if err != nil {
+		log.Warn("Error reading pause state", "error", err)
+		return
+	}
+
+	paused := p.IsActive(time.Now())
+	wasPaused := app.paused.Swap(paused)
+
+	if paused {
+		mPause.SetTitle("▶️ Resume monitoring")
+		mPause.SetTooltip("Start checking email again")
+
+		tooltip := "Email Sentinel - Paused"
+		if !p.Until.IsZero() {
+			tooltip = fmt.Sprintf("Email Sentinel - Paused until %s", p.Until.Format("15:04"))
+		}
+
+		app.iconMu.Lock()
+		if icon := GetPausedIcon(); icon != nil && len(icon) > 0 {
+			systray.SetIcon(icon)
+		}
+		systray.SetTooltip(tooltip)
+		app.iconMu.Unlock()
+		return
+	}
+
+	mPause.SetTitle("⏸️ Pause monitoring")
+	mPause.SetTooltip("Stop checking email until resumed")
+
+	if wasPaused {
+		// Restore the normal icon, then let the refresh reapply alert state
+		app.iconMu.Lock()
+		if icon := GetNormalIcon(); icon != nil && len(icon) > 0 {
+			systray.SetIcon(icon)
+		}
+		systray.SetTooltip("Email Sentinel - Monitoring Gmail")
+		app.iconMu.Unlock()
+		app.scheduleRefresh()
+	}
+}
+
 // UpdateTrayOnNewAlert is called when a new alert is created
 // This updates the tray icon and menu with the new alert
 func UpdateTrayOnNewAlert(alert storage.Alert) {
@@ -405,7 +664,7 @@ func UpdateTrayOnNewAlert(alert storage.Alert) {
 			// Alert queued for processing
 		default:
 			// Channel full, skip this update
-			log.Println("⚠️  Tray alert channel full, skipping update")
+			log.Warn("Tray alert channel full, skipping update")
 		}
 	}
 }
@@ -439,7 +698,7 @@ func (app *TrayApp) openHistory() {
 
 	if cmd != nil {
 		if err := cmd.Start(); err != nil {
-			log.Printf("Error opening history: %v", err)
+			log.Warn("Error opening history", "error", err)
 		}
 	}
 }
@@ -448,67 +707,28 @@ func (app *TrayApp) openHistory() {
 func (app *TrayApp) clearAlerts() {
 	deleted, err := storage.DeleteAllAlerts(app.db)
 	if err != nil {
-		log.Printf("❌ Error clearing alerts: %v", err)
+		log.Error("Error clearing alerts", "error", err)
 		return
 	}
 
 	if deleted > 0 {
-		log.Printf("🗑️  Cleared %d alert(s) from tray", deleted)
+		log.Info("Cleared alerts from tray", log.Icon("🗑️ "), "deleted", deleted)
 		app.scheduleRefresh()
 	} else {
-		log.Println("✨ No alerts to clear")
-	}
-}
-
-// isValidGmailURL validates that a URL is a legitimate Gmail link
-// This prevents command injection attacks via malicious email subjects
-func isValidGmailURL(urlStr string) bool {
-	// Parse URL
-	parsedURL, err := url.Parse(urlStr)
-	if err != nil {
-		return false
-	}
-
-	// Must be HTTPS
-	if parsedURL.Scheme != "https" {
-		return false
-	}
-
-	// Must be mail.google.com domain
-	if !strings.HasSuffix(parsedURL.Host, "mail.google.com") {
-		return false
-	}
-
-	// Path should start with /mail/
-	if !strings.HasPrefix(parsedURL.Path, "/mail/") {
-		return false
+		log.Info("No alerts to clear", log.Icon("✨"))
 	}
-
-	return true
 }
 
-// openBrowser opens the given URL in the default browser
+// openBrowser opens the given Gmail URL in the default browser
 // URL is validated before execution to prevent command injection
 func openBrowser(urlStr string) {
-	// Validate URL to prevent command injection attacks
-	if !isValidGmailURL(urlStr) {
-		log.Printf("⚠️  Security: Blocked invalid Gmail URL: %s", urlStr)
+	if !gmail.IsValidGmailURL(urlStr) {
+		log.Warn("Security: Blocked invalid Gmail URL", "url", urlStr)
 		return
 	}
 
-	var cmd *exec.Cmd
-
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", urlStr)
-	case "darwin":
-		cmd = exec.Command("open", urlStr)
-	default:
-		cmd = exec.Command("xdg-open", urlStr)
-	}
-
-	if err := cmd.Start(); err != nil {
-		log.Printf("Error opening browser: %v", err)
+	if err := gmail.OpenGmailLink(urlStr); err != nil {
+		log.Warn("Error opening browser", "error", err)
 	}
 }
 
@@ -518,11 +738,22 @@ func isToday(t time.Time) bool {
 	return t.Year() == now.Year() && t.Month() == now.Month() && t.Day() == now.Day()
 }
 
-// countUrgentAlerts counts the number of urgent (priority 1) alerts
+// countUrgentAlerts counts the number of urgent (high or critical priority) alerts
 func countUrgentAlerts(alerts []storage.Alert) int {
 	count := 0
 	for _, alert := range alerts {
-		if alert.Priority == 1 {
+		if alert.Priority >= storage.PriorityHigh {
+			count++
+		}
+	}
+	return count
+}
+
+// countCriticalAlerts counts the number of critical (priority 2) alerts
+func countCriticalAlerts(alerts []storage.Alert) int {
+	count := 0
+	for _, alert := range alerts {
+		if alert.Priority >= storage.PriorityCritical {
 			count++
 		}
 	}
@@ -0,0 +1,46 @@
+package tray
+
+import "testing"
+
+func TestMaskOTPCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"123456", "•••456"},
+		{"AB12-CD34", "••••••D34"},
+		{"123", "•••"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := maskOTPCode(tt.code); got != tt.want {
+				t.Errorf("maskOTPCode(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSenderName(t *testing.T) {
+	tests := []struct {
+		from string
+		want string
+	}{
+		{"GitHub <noreply@github.com>", "GitHub"},
+		{`"Acme Bank" <alerts@acme.com>`, "Acme Bank"},
+		{"noreply@github.com", "noreply@github.com"},
+		{"<noreply@github.com>", "<noreply@github.com>"},
+		{"Very Long Company Name Notifications <a@b.com>", "Very Long Company Name..."},
+		{"Ünïcödé Ñämé Wïth Äccénts Everywhere <a@b.com>", "Ünïcödé Ñämé Wïth Äccé..."},
+		{"日本語の送信者の名前がとても長い場合のテストです <a@b.com>", "日本語の送信者の名前がとても長い場合のテストです"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			if got := senderName(tt.from); got != tt.want {
+				t.Errorf("senderName(%q) = %q, want %q", tt.from, got, tt.want)
+			}
+		})
+	}
+}
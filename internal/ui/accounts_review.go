@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datateamsix/email-sentinel/internal/accounts"
+	"github.com/datateamsix/email-sentinel/internal/storage"
+)
+
+// defaultReviewLimit is how many recent detections a review session walks through
+const defaultReviewLimit = 25
+
+// reviewResult counts what happened during a review session
+type reviewResult struct {
+	Confirmed int
+	Edited    int
+	Deleted   int
+	Skipped   int
+}
+
+// RunAccountsReview walks through recently detected, unconfirmed accounts one at a time
+// letting the user confirm, edit, recategorize or delete each. limit <= 0 uses the default.
+func RunAccountsReview(limit int) error {
+	PrintSection("Review Detected Accounts")
+
+	if limit <= 0 {
+		limit = defaultReviewLimit
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error opening database: %v", err))
+		return err
+	}
+	defer storage.CloseDB(db)
+
+	pending, err := storage.GetUnconfirmedAccounts(db, limit)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading accounts: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	if len(pending) == 0 {
+		PrintSuccess("Nothing to review - every detected account is confirmed")
+		return nil
+	}
+
+	PrintInfo(fmt.Sprintf("%d account(s) to review, most recent first", len(pending)))
+	ColorDim.Println("  Confirmed and edited accounts won't be asked about again.")
+
+	var result reviewResult
+	for i := range pending {
+		fmt.Println()
+		PrintSubsection(fmt.Sprintf("Account %d of %d", i+1, len(pending)))
+		printReviewAccount(pending[i])
+
+		quit, err := reviewAccount(db, &pending[i], &result)
+		if err != nil {
+			PrintWarning(fmt.Sprintf("Could not update #%d: %v", pending[i].ID, err))
+		}
+		if quit {
+			break
+		}
+	}
+
+	fmt.Println()
+	PrintSuccess(fmt.Sprintf("Review finished: %d confirmed, %d edited, %d deleted, %d skipped",
+		result.Confirmed, result.Edited, result.Deleted, result.Skipped))
+	return nil
+}
+
+// reviewAccount prompts for an action on one account and applies it
+// Returns true when the user wants to stop reviewing
+func reviewAccount(db *sql.DB, acc *storage.Account, result *reviewResult) (bool, error) {
+	for {
+		fmt.Println()
+		ColorDim.Println("  [c] confirm  [e] edit name/price  [r] recategorize  [d] delete  [s] skip  [q] quit")
+		action := strings.ToLower(AskInput("Action", "c"))
+
+		switch action {
+		case "c", "confirm":
+			if err := storage.ConfirmAccount(db, acc.ID); err != nil {
+				return false, err
+			}
+			result.Confirmed++
+			PrintSuccess(fmt.Sprintf("Confirmed %s", acc.ServiceName))
+			return false, nil
+
+		case "e", "edit":
+			if !editAccountDetails(acc) {
+				continue
+			}
+			return false, saveReviewedAccount(db, acc, result)
+
+		case "r", "recategorize":
+			category, ok := chooseAccountCategory(acc.Category)
+			if !ok {
+				continue
+			}
+			acc.Category = category
+			return false, saveReviewedAccount(db, acc, result)
+
+		case "d", "delete":
+			if !Confirm(fmt.Sprintf("Delete %s <%s>?", acc.ServiceName, acc.EmailAddress)) {
+				continue
+			}
+			if err := storage.DeleteAccount(db, acc.ID); err != nil {
+				return false, err
+			}
+			result.Deleted++
+			PrintSuccess(fmt.Sprintf("Deleted %s", acc.ServiceName))
+			return false, nil
+
+		case "s", "skip":
+			result.Skipped++
+			return false, nil
+
+		case "q", "quit":
+			return true, nil
+
+		default:
+			PrintError(fmt.Sprintf("Unknown action: %s", action))
+		}
+	}
+}
+
+// saveReviewedAccount stores user edits; an edited account counts as confirmed
+func saveReviewedAccount(db *sql.DB, acc *storage.Account, result *reviewResult) error {
+	now := time.Now()
+	acc.ConfirmedAt = &now
+	if err := storage.UpdateAccount(db, acc); err != nil {
+		return err
+	}
+	result.Edited++
+	PrintSuccess(fmt.Sprintf("Saved %s", acc.ServiceName))
+	return nil
+}
+
+// editAccountDetails prompts for a new service name and monthly price
+// Returns false if the input was invalid and nothing was changed
+func editAccountDetails(acc *storage.Account) bool {
+	name := strings.TrimSpace(AskInput("Service name", acc.ServiceName))
+	if name == "" {
+		PrintError("Service name is required")
+		return false
+	}
+
+	// A bare number keeps the account's currency; "€12,00" or "9.99 GBP" changes it
+	priceInput := strings.TrimSpace(AskInput("Monthly price (0 for free)", fmt.Sprintf("%.2f", acc.PriceMonthly)))
+	currency := acc.Currency
+	price, err := strconv.ParseFloat(priceInput, 64)
+	if err != nil {
+		info, ok := accounts.ParsePrice(priceInput)
+		if !ok {
+			PrintError("Price must be a positive number like 9.99 or €12,00")
+			return false
+		}
+		price, currency = info.Amount, info.Currency
+	}
+	if price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		PrintError("Price must be a positive number like 9.99 or €12,00")
+		return false
+	}
+
+	acc.ServiceName = name
+	acc.PriceMonthly = price
+	acc.Currency = currency
+	return true
+}
+
+// chooseAccountCategory lets the user pick a known category or type a new one
+func chooseAccountCategory(current string) (string, bool) {
+	categories := accountCategories()
+	options := make([]string, 0, len(categories)+1)
+	for _, c := range categories {
+		if c == current {
+			options = append(options, c+" (current)")
+		} else {
+			options = append(options, c)
+		}
+	}
+	options = append(options, "Other (type a name)")
+
+	choice := ConfirmWithOptions("Category", options)
+	switch {
+	case choice < 0:
+		return "", false
+	case choice < len(categories):
+		return categories[choice], true
+	}
+
+	custom := strings.ToLower(strings.TrimSpace(AskInput("Category name", "")))
+	if custom == "" {
+		PrintError("Category name is required")
+		return "", false
+	}
+	return custom, true
+}
+
+// accountCategories returns the detector's built-in categories in display order
+func accountCategories() []string {
+	categories := make([]string, 0, len(accounts.ServiceCategoryKeywords)+1)
+	for c := range accounts.ServiceCategoryKeywords {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+	return append(categories, "other")
+}
+
+// printReviewAccount shows the detected fields of an account being reviewed
+func printReviewAccount(acc storage.Account) {
+	PrintKeyValue("Service", ColorBold.Sprint(acc.ServiceName))
+	PrintKeyValue("Email", acc.EmailAddress)
+	PrintKeyValue("Type", acc.AccountType)
+	if acc.PriceMonthly > 0 {
+		PrintKeyValue("Price", accounts.FormatPrice(acc.PriceMonthly, acc.Currency)+"/month")
+	}
+	if acc.TrialEndDate != nil {
+		PrintKeyValue("Trial ends", acc.TrialEndDate.Format("Jan 2, 2006"))
+	}
+	category := acc.Category
+	if category == "" {
+		category = "(none)"
+	}
+	PrintKeyValue("Category", category)
+	PrintKeyValue("Detected", fmt.Sprintf("%s (%.0f%% confidence)", acc.DetectedAt.Format("Jan 2, 2006"), acc.Confidence*100))
+}
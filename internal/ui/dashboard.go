@@ -7,8 +7,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/state"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
@@ -19,42 +21,54 @@ type Dashboard struct {
 }
 
 // DashboardData holds all status information
+// The JSON form is printed by 'email-sentinel status --json'.
 type DashboardData struct {
 	// Service
-	IsRunning   bool
-	PID         int
-	Uptime      time.Duration
-	LastCheck   time.Time
-	NextCheck   time.Time
-	LastRun     time.Time
-	HasStateInfo bool
+	IsRunning    bool          `json:"running"`
+	PID          int           `json:"pid,omitempty"`
+	Uptime       time.Duration `json:"uptime_ns,omitempty"`
+	LastCheck    time.Time     `json:"last_check"`
+	NextCheck    time.Time     `json:"next_check"`
+	LastRun      time.Time     `json:"last_run"` // last check of a watcher that has stopped
+	HasStateInfo bool          `json:"has_state_info"`
+
+	// API health (from the watcher's status.json)
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LastError           string `json:"last_error,omitempty"`
+
+	// Pause (from pause.json)
+	Paused      bool      `json:"paused"`
+	PausedUntil time.Time `json:"paused_until"` // zero = until resumed
 
 	// Gmail
-	Email       string
-	AuthValid   bool
-	TokenExpiry time.Time
-	TokenExists bool
+	Email       string    `json:"email,omitempty"`
+	AuthValid   bool      `json:"auth_valid"`
+	TokenExpiry time.Time `json:"token_expiry"`
+	TokenExists bool      `json:"token_exists"`
+	AuthWarning string    `json:"auth_warning,omitempty"` // refresh failed or authorization about to expire (from auth.json)
 
 	// Filters
-	FilterCount int
-	Filters     []FilterSummary
+	FilterCount     int             `json:"filter_count"`
+	DisabledFilters int             `json:"disabled_filters"`
+	Filters         []FilterSummary `json:"filters"`
 
 	// Notifications
-	DesktopEnabled bool
-	MobileEnabled  bool
-	NtfyTopic      string
-
-	// Stats (Last 24h)
-	EmailsChecked     int64
-	FiltersMatched    int64
-	NotificationsSent int64
-	PollingInterval   int
+	DesktopEnabled bool   `json:"desktop_enabled"`
+	MobileEnabled  bool   `json:"mobile_enabled"`
+	NtfyTopic      string `json:"ntfy_topic,omitempty"`
+
+	// Stats (today)
+	EmailsChecked     int64 `json:"emails_checked_today"`
+	FiltersMatched    int64 `json:"matches_today"`
+	NotificationsSent int64 `json:"alerts_today"`
+	PollingInterval   int   `json:"polling_interval"`
 }
 
 // FilterSummary represents a brief filter overview
 type FilterSummary struct {
-	Name    string
-	Summary string // Brief description
+	Name     string `json:"name"`
+	Summary  string `json:"summary"`  // Brief description
+	Disabled bool   `json:"disabled"` // Paused with 'filter disable'
 }
 
 // NewDashboard creates a dashboard
@@ -133,6 +147,14 @@ func (d *Dashboard) render(data *DashboardData) {
 		}
 		d.printRow(statusLine, width)
 
+		if data.Paused {
+			pauseLine := fmt.Sprintf("  Monitoring:  %s Paused", ColorYellow.Sprint("⏸"))
+			if !data.PausedUntil.IsZero() {
+				pauseLine += fmt.Sprintf(" until %s", data.PausedUntil.Format("15:04"))
+			}
+			d.printRow(pauseLine, width)
+		}
+
 		if data.Uptime > 0 {
 			d.printRow(fmt.Sprintf("  Uptime:      %s", formatDuration(data.Uptime)), width)
 		}
@@ -144,6 +166,19 @@ func (d *Dashboard) render(data *DashboardData) {
 		if !data.NextCheck.IsZero() {
 			d.printRow(fmt.Sprintf("  Next Check:  in %s", formatRelativeTime(data.NextCheck)), width)
 		}
+
+		if data.ConsecutiveFailures > 0 {
+			d.printRow(fmt.Sprintf("  Gmail API:   %s %d consecutive failure(s)", ColorYellow.Sprint("⚠"), data.ConsecutiveFailures), width)
+			if data.LastError != "" {
+				lastErr := data.LastError
+				if len(lastErr) > 45 {
+					lastErr = lastErr[:42] + "..."
+				}
+				d.printRow(fmt.Sprintf("  Last Error:  %s", lastErr), width)
+			}
+		} else if data.HasStateInfo {
+			d.printRow(fmt.Sprintf("  Gmail API:   %s Healthy", ColorGreen.Sprint("✓")), width)
+		}
 	} else {
 		d.printRow(fmt.Sprintf("  Watcher:     %s Stopped", ColorGray.Sprint("○")), width)
 		if !data.LastRun.IsZero() {
@@ -171,6 +206,15 @@ func (d *Dashboard) render(data *DashboardData) {
 			timeUntilExpiry := time.Until(data.TokenExpiry)
 			d.printRow(fmt.Sprintf("  Token Expiry: in %s", formatDuration(timeUntilExpiry)), width)
 		}
+
+		if data.AuthWarning != "" {
+			warning := data.AuthWarning
+			if len(warning) > 45 {
+				warning = warning[:42] + "..."
+			}
+			d.printRow(fmt.Sprintf("  %s %s", ColorYellow.Sprint("⚠"), warning), width)
+			d.printRow("  Run: email-sentinel init", width)
+		}
 	} else {
 		d.printRow(fmt.Sprintf("  Auth Status: %s Not configured", ColorRed.Sprint("✗")), width)
 		d.printRow("  Run: email-sentinel init", width)
@@ -182,7 +226,11 @@ func (d *Dashboard) render(data *DashboardData) {
 	d.printDivider(width)
 
 	if data.FilterCount > 0 {
-		d.printRow(fmt.Sprintf("  Active Filters: %d", data.FilterCount), width)
+		activeLine := fmt.Sprintf("  Active Filters: %d", data.FilterCount-data.DisabledFilters)
+		if data.DisabledFilters > 0 {
+			activeLine += fmt.Sprintf(" (%d disabled)", data.DisabledFilters)
+		}
+		d.printRow(activeLine, width)
 		d.printRow("  ┌─────────────────────────────────────────────────────┐", width)
 
 		// Show up to 5 filters
@@ -192,7 +240,11 @@ func (d *Dashboard) render(data *DashboardData) {
 		}
 
 		for i := 0; i < displayCount; i++ {
-			filterLine := fmt.Sprintf("  │ %d. %-20s %s", i+1, data.Filters[i].Name, data.Filters[i].Summary)
+			summary := data.Filters[i].Summary
+			if data.Filters[i].Disabled {
+				summary = ColorDim.Sprint("⏸ disabled")
+			}
+			filterLine := fmt.Sprintf("  │ %d. %-20s %s", i+1, data.Filters[i].Name, summary)
 			// Truncate if too long
 			if len(stripANSI(filterLine)) > width-8 {
 				filterLine = filterLine[:width-11] + "..."
@@ -229,14 +281,14 @@ func (d *Dashboard) render(data *DashboardData) {
 	d.printEmptyRow(width)
 
 	// Statistics
-	d.printSectionTitle("Statistics (Last 24h)", width)
+	d.printSectionTitle("Statistics (Today)", width)
 	d.printDivider(width)
 
-	// Note: These are estimates based on polling interval and alerts
+	// Counters are persisted by the watcher on every check cycle
 	if data.EmailsChecked > 0 {
 		d.printRow(fmt.Sprintf("  Emails Checked:   %s", formatNumber(data.EmailsChecked)), width)
 	} else {
-		d.printRow("  Emails Checked:   N/A (not running)", width)
+		d.printRow("  Emails Checked:   0 (no checks today)", width)
 	}
 
 	d.printRow(fmt.Sprintf("  Filters Matched:  %d", data.FiltersMatched), width)
@@ -270,6 +322,25 @@ func GatherDashboardData() (*DashboardData, error) {
 			// This would require calling the Gmail API, so we'll skip for now
 			data.Email = "user@gmail.com" // Placeholder
 		}
+
+		// Refresh failures and known expiry recorded by the watcher
+		lifetime, window := time.Duration(0), 24*time.Hour
+		if appconfig.ConfigExists() {
+			if appCfg, err := appconfig.Load(); err == nil {
+				if d, err := appCfg.Monitoring.Gmail.GetTokenLifetime(); err == nil {
+					lifetime = d
+				}
+				if d, err := appCfg.Monitoring.Gmail.GetAuthWarningWindow(); err == nil {
+					window = d
+				}
+			}
+		}
+		if warning, ok := state.CheckAuth(time.Now(), lifetime, window); ok {
+			data.AuthWarning = warning.Message
+			if warning.RefreshFailed {
+				data.AuthValid = false
+			}
+		}
 	}
 
 	// Load filters
@@ -302,9 +373,14 @@ func GatherDashboardData() (*DashboardData, error) {
 			summary += fmt.Sprintf("subj: %s", subjList)
 		}
 
+		if !f.IsEnabled() {
+			data.DisabledFilters++
+		}
+
 		data.Filters = append(data.Filters, FilterSummary{
-			Name:    f.Name,
-			Summary: summary,
+			Name:     f.Name,
+			Summary:  summary,
+			Disabled: !f.IsEnabled(),
 		})
 	}
 
@@ -322,24 +398,48 @@ func GatherDashboardData() (*DashboardData, error) {
 		// Count today's alerts
 		count, err := storage.CountTodayAlerts(db)
 		if err == nil {
-			data.FiltersMatched = int64(count)
 			data.NotificationsSent = int64(count) // Each alert = 1+ notifications
 		}
 
-		// Estimate emails checked (rough calculation)
-		// If we have alerts from today, estimate based on polling interval
-		if count > 0 {
-			// Assume 24 hours of monitoring, with polling interval
-			checksPerHour := 3600 / int64(cfg.PollingInterval)
-			totalChecks := checksPerHour * 24
-			emailsPerCheck := int64(10) // Default messages fetched per check
-			data.EmailsChecked = totalChecks * emailsPerCheck
+		// Real counters persisted by the watcher
+		stats, err := storage.GetTodayStats(db)
+		if err == nil {
+			data.EmailsChecked = stats.EmailsChecked
+			data.FiltersMatched = stats.Matches
 		}
 	}
 
-	// Service status - since we don't track PID/uptime, mark as not running
-	// This would require implementing a daemon/PID file tracking system
-	data.IsRunning = false
+	// Service status from the watcher's status.json (written each check cycle)
+	status, err := state.LoadRuntimeStatus()
+	if err == nil && status != nil {
+		data.HasStateInfo = true
+		data.IsRunning = status.IsRunning(time.Now())
+		if data.IsRunning {
+			data.PID = status.PID
+			data.Uptime = time.Since(status.StartedAt)
+			data.LastCheck = status.LastCheck
+			data.NextCheck = status.NextCheck
+			data.ConsecutiveFailures = status.ConsecutiveFailures
+			data.LastError = status.LastError
+		} else {
+			data.LastRun = status.LastCheck
+			if data.LastRun.IsZero() {
+				data.LastRun = status.StartedAt
+			}
+		}
+	}
+
+	// A daemon started with 'start --daemon' is running as long as its process is alive
+	if pid := state.RunningDaemonPID(); pid > 0 {
+		data.IsRunning = true
+		data.PID = pid
+	}
+
+	// Pause state (set via 'email-sentinel pause' or the tray)
+	if pause, err := state.LoadPauseState(); err == nil && pause.IsActive(time.Now()) {
+		data.Paused = true
+		data.PausedUntil = pause.Until
+	}
 
 	return data, nil
 }
@@ -4,11 +4,17 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/datateamsix/email-sentinel/internal/accounts"
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
+	"github.com/datateamsix/email-sentinel/internal/config"
 	"github.com/datateamsix/email-sentinel/internal/filter"
+	"github.com/datateamsix/email-sentinel/internal/gmail"
+	"github.com/datateamsix/email-sentinel/internal/state"
 	"github.com/datateamsix/email-sentinel/internal/storage"
 )
 
@@ -372,14 +378,53 @@ func BuildMainMenu() *Menu {
 	})
 
 	// Add status bar
-	menu.SetStatusBar(func() string {
-		return fmt.Sprintf("Status: %s Running | Filters: 3 | Last check: 2 min ago",
-			ColorGreen.Sprint("●"))
-	})
+	menu.SetStatusBar(menuStatusLine)
 
 	return menu
 }
 
+// menuStatusLine summarizes the watcher state, filter count, last check and today's alerts
+// Each part falls back to a neutral value when its source can't be read.
+func menuStatusLine() string {
+	now := time.Now()
+
+	running := false
+	lastCheck := "never"
+	if status, err := state.LoadRuntimeStatus(); err == nil && status != nil {
+		running = status.IsRunning(now)
+		if !status.LastCheck.IsZero() {
+			lastCheck = formatRelativeTime(status.LastCheck)
+		}
+	}
+	if state.RunningDaemonPID() > 0 {
+		running = true
+	}
+
+	indicator := fmt.Sprintf("%s Stopped", ColorGray.Sprint("●"))
+	if running {
+		indicator = fmt.Sprintf("%s Running", ColorGreen.Sprint("●"))
+		if pause, err := state.LoadPauseState(); err == nil && pause.IsActive(now) {
+			indicator = fmt.Sprintf("%s Paused", ColorYellow.Sprint("●"))
+		}
+	}
+
+	filters := "?"
+	if list, err := filter.ListFilters(); err == nil {
+		filters = strconv.Itoa(len(list))
+	}
+
+	alerts := "?"
+	if db, err := storage.InitDB(); err == nil {
+		if count, err := storage.CountTodayAlerts(db); err == nil {
+			alerts = strconv.Itoa(count)
+		}
+		storage.CloseDB(db)
+	}
+
+	return fmt.Sprintf("Status: %s | Filters: %s | Last check: %s | Alerts today: %s",
+		indicator, filters, lastCheck, alerts)
+}
+
 // buildFilterMenu creates the filter management submenu
 func buildFilterMenu() *Menu {
 	menu := NewMenu("Filter Management")
@@ -400,6 +445,18 @@ func buildFilterMenu() *Menu {
 		return handleListFilters()
 	})
 
+	menu.AddItem("5", "📤", "Export Filters", "Save filters to a JSON file", func() error {
+		return handleExportFilters()
+	})
+
+	menu.AddItem("6", "📥", "Import Filters", "Load filters from a JSON file", func() error {
+		return handleImportFilters()
+	})
+
+	menu.AddItem("7", "🏷️", "Rename Filter", "Change a filter's name", func() error {
+		return handleRenameFilter()
+	})
+
 	return menu
 }
 
@@ -407,76 +464,334 @@ func buildFilterMenu() *Menu {
 func buildAccountsMenu() *Menu {
 	menu := NewMenu("Digital Accounts")
 
-	menu.AddItem("1", "📋", "List All Accounts", "View all subscriptions & trials", func() error {
-		PrintSection("Digital Accounts")
-		PrintInfo("Launching accounts list...")
-		fmt.Println()
-		PrintInfo("Run: email-sentinel accounts list")
-		PrintInfo("Or:  email-sentinel accounts list --trials (show only trials)")
-		PrintInfo("Or:  email-sentinel accounts list --paid (show only paid)")
-		return nil
+	menu.AddItem("1", "✅", "Review Detections", "Confirm, fix or delete detected accounts", func() error {
+		return RunAccountsReview(0)
 	})
 
 	menu.AddItem("2", "🔍", "Search Account", "Find which email you used for a service", func() error {
-		PrintSection("Search Account")
-		reader := bufio.NewReader(os.Stdin)
+		return handleSearchAccounts()
+	})
 
-		fmt.Println()
-		ColorGreen.Print("Service name (e.g., Netflix, Spotify): ")
-		service, _ := reader.ReadString('\n')
-		service = strings.TrimSpace(service)
+	menu.AddItem("3", "🔥", "Expiring Trials", "View trials expiring soon", func() error {
+		return handleExpiringTrials()
+	})
+
+	menu.AddItem("4", "💰", "Total Spending", "Calculate monthly/annual costs", func() error {
+		PrintSection("Total Spending")
 
-		if service == "" {
-			PrintError("Service name is required")
-			return fmt.Errorf("service name required")
+		db, err := storage.InitDB()
+		if err != nil {
+			PrintError(fmt.Sprintf("Error opening database: %v", err))
+			return err
 		}
+		defer storage.CloseDB(db)
 
+		monthly, err := storage.GetMonthlySpend(db)
+		if err != nil {
+			PrintError(fmt.Sprintf("Error calculating spending: %v", err))
+			return err
+		}
+
+		if len(monthly) == 0 {
+			PrintKeyValue("Monthly total", accounts.FormatPrice(0, accounts.DefaultCurrency))
+		}
+		// Each currency is totalled on its own; mixed currencies can't be summed
+		for _, cs := range monthly {
+			suffix := ""
+			if len(monthly) > 1 {
+				suffix = " (" + cs.Currency + ")"
+			}
+			PrintKeyValue("Monthly total"+suffix, accounts.FormatPrice(cs.Monthly, cs.Currency))
+			PrintKeyValue("Projected annual"+suffix, accounts.FormatPrice(cs.Monthly*12, cs.Currency))
+		}
 		fmt.Println()
-		PrintInfo(fmt.Sprintf("Searching for '%s'...", service))
-		PrintInfo("Run: email-sentinel accounts search " + service)
+		PrintInfo("Category breakdown: email-sentinel accounts spending")
+		PrintInfo("Export for a spreadsheet: email-sentinel accounts export --format csv --output accounts.csv")
 		return nil
 	})
 
-	menu.AddItem("3", "🔥", "Expiring Trials", "View trials expiring soon", func() error {
-		PrintSection("Expiring Trials")
-		PrintInfo("Showing trials expiring in the next 7 days...")
-		fmt.Println()
-		PrintInfo("Run: email-sentinel accounts list --trials")
-		PrintWarning("Remember to cancel before trial expires to avoid charges!")
-		return nil
+	menu.AddItem("5", "🧹", "Clean Up Accounts", "Remove low-confidence detections", func() error {
+		return handleCleanupAccounts()
 	})
 
-	menu.AddItem("4", "💰", "Total Spending", "Calculate monthly/annual costs", func() error {
-		PrintSection("Total Spending")
-		PrintInfo("Calculating total subscription costs...")
-		fmt.Println()
-		PrintInfo("Run: email-sentinel accounts list")
-		PrintInfo("Total spending is shown at the bottom of the list")
-		return nil
+	menu.AddItem("6", "🎚️", "Detection Types", "Choose which account types are tracked", func() error {
+		return handleDetectTypes()
 	})
 
 	return menu
 }
 
+// expiringTrialDays is how far ahead the menu looks for trials about to end
+const expiringTrialDays = 7
+
+// handleSearchAccounts looks up which email address was used for a service
+func handleSearchAccounts() error {
+	PrintSection("Search Account")
+
+	fmt.Println()
+	service := AskInput("Service name (e.g., Netflix, Spotify)", "")
+	if service == "" {
+		PrintError("Service name is required")
+		return fmt.Errorf("service name required")
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error opening database: %v", err))
+		return err
+	}
+	defer storage.CloseDB(db)
+
+	found, err := storage.SearchAccounts(db, service)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error searching accounts: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	if len(found) == 0 {
+		PrintInfo(fmt.Sprintf("No accounts found for '%s'", service))
+		return nil
+	}
+
+	for _, acc := range found {
+		fmt.Printf("  [%d] %s <%s> ", acc.ID, ColorBold.Sprint(acc.ServiceName), acc.EmailAddress)
+		ColorDim.Printf("(%s, %s)\n", acc.AccountType, acc.Status)
+	}
+	return nil
+}
+
+// handleExpiringTrials lists active trials ending within expiringTrialDays
+func handleExpiringTrials() error {
+	PrintSection("Expiring Trials")
+
+	db, err := storage.InitDB()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error opening database: %v", err))
+		return err
+	}
+	defer storage.CloseDB(db)
+
+	trials, err := storage.GetTrialsExpiringWithin(db, expiringTrialDays)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading trials: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	if len(trials) == 0 {
+		PrintSuccess(fmt.Sprintf("No trials ending in the next %d days", expiringTrialDays))
+		return nil
+	}
+
+	for _, acc := range trials {
+		fmt.Printf("  [%d] %s <%s> ", acc.ID, ColorBold.Sprint(acc.ServiceName), acc.EmailAddress)
+		ColorYellow.Printf("ends %s\n", acc.TrialEndDate.Format("Jan 2"))
+		if acc.CancelURL != "" {
+			ColorDim.Printf("      Cancel: %s\n", acc.CancelURL)
+		}
+	}
+
+	fmt.Println()
+	PrintWarning("Remember to cancel before trial expires to avoid charges!")
+	PrintInfo(fmt.Sprintf("Full details: email-sentinel accounts list --expiring %d", expiringTrialDays))
+	return nil
+}
+
+// defaultCleanupThreshold is the confidence below which accounts are offered for cleanup
+const defaultCleanupThreshold = 0.8
+
+// handleCleanupAccounts lists low-confidence accounts and offers to delete them in bulk
+func handleCleanupAccounts() error {
+	PrintSection("Clean Up Accounts")
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Printf("\nConfidence threshold [%.2f]: ", defaultCleanupThreshold)
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	threshold := defaultCleanupThreshold
+	if input != "" {
+		value, err := strconv.ParseFloat(input, 64)
+		if err != nil || value <= 0 || value > 1 {
+			PrintError("Threshold must be a number between 0 and 1")
+			return fmt.Errorf("invalid threshold: %s", input)
+		}
+		threshold = value
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error opening database: %v", err))
+		return err
+	}
+	defer storage.CloseDB(db)
+
+	accounts, err := storage.GetLowConfidenceAccounts(db, threshold)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading accounts: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	if len(accounts) == 0 {
+		PrintSuccess(fmt.Sprintf("No accounts below %.0f%% confidence", threshold*100))
+		return nil
+	}
+
+	PrintInfo(fmt.Sprintf("%d account(s) below %.0f%% confidence:", len(accounts), threshold*100))
+	fmt.Println()
+	for _, acc := range accounts {
+		fmt.Printf("  [%d] %s <%s> ", acc.ID, acc.ServiceName, acc.EmailAddress)
+		ColorDim.Printf("(%s, %.0f%%)\n", acc.AccountType, acc.Confidence*100)
+	}
+
+	if !ConfirmDangerous(fmt.Sprintf("Delete these %d account(s)?", len(accounts))) {
+		PrintInfo("Cleanup cancelled")
+		return nil
+	}
+
+	removed := 0
+	for _, acc := range accounts {
+		if err := storage.DeleteAccount(db, acc.ID); err != nil {
+			PrintWarning(fmt.Sprintf("Could not remove #%d: %v", acc.ID, err))
+			continue
+		}
+		removed++
+	}
+
+	fmt.Println()
+	PrintSuccess(fmt.Sprintf("Removed %d account(s)", removed))
+	return nil
+}
+
+// handleDetectTypes edits accounts.detect_types in app-config.yaml
+func handleDetectTypes() error {
+	PrintSection("Account Detection Types")
+
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading configuration: %v", err))
+		return err
+	}
+
+	current := "all"
+	if len(appCfg.Accounts.DetectTypes) > 0 {
+		current = strings.Join(appCfg.Accounts.DetectTypes, ", ")
+	}
+	PrintKeyValue("Currently detecting", current)
+	fmt.Println()
+	PrintBullet("trial        - free trials and trial-ending reminders")
+	PrintBullet("paid         - subscription payments and renewals")
+	PrintBullet("free         - new free account signups (\"welcome to...\")")
+	PrintBullet("cancellation - cancelled subscriptions")
+	fmt.Println()
+
+	input := AskInput("Types to detect (comma-separated, 'all' for every type)", "")
+	if input == "" {
+		PrintInfo("No changes made")
+		return nil
+	}
+
+	var types []string
+	if !strings.EqualFold(input, "all") {
+		for _, t := range strings.Split(input, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" {
+				continue
+			}
+			if !isAccountType(t) {
+				PrintError(fmt.Sprintf("Unknown type '%s' (use %s)", t, strings.Join(accounts.AccountTypes, ", ")))
+				return fmt.Errorf("unknown account type: %s", t)
+			}
+			types = append(types, t)
+		}
+	}
+
+	appCfg.Accounts.DetectTypes = types
+	if err := appconfig.Save(appCfg); err != nil {
+		PrintError(fmt.Sprintf("Error saving configuration: %v", err))
+		return err
+	}
+
+	if len(types) == 0 {
+		PrintSuccess("Detecting all account types")
+	} else {
+		PrintSuccess(fmt.Sprintf("Detecting only: %s", strings.Join(types, ", ")))
+	}
+	PrintInfo("Restart monitoring for the change to take effect")
+	return nil
+}
+
+// isAccountType reports whether t is an account type detection can produce
+func isAccountType(t string) bool {
+	for _, known := range accounts.AccountTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
 // buildNotificationsMenu creates the notifications submenu
 func buildNotificationsMenu() *Menu {
 	menu := NewMenu("Notifications")
 
 	menu.AddItem("1", "🖥️", "Desktop Notifications", "Toggle on/off", func() error {
 		PrintSection("Desktop Notifications")
-		PrintSuccess("Desktop notifications are enabled")
+
+		appCfg, err := appconfig.Load()
+		if err != nil {
+			PrintError(fmt.Sprintf("Error loading configuration: %v", err))
+			return err
+		}
+		cfg, err := filter.LoadConfig()
+		if err != nil {
+			PrintError(fmt.Sprintf("Error loading config: %v", err))
+			return err
+		}
+
+		// The watcher only shows toasts when both configs enable them
+		if cfg.Notifications.Desktop && appCfg.Notifications.Desktop.Enabled {
+			PrintSuccess("Desktop notifications are enabled")
+		} else {
+			PrintWarning("Desktop notifications are disabled")
+		}
+		PrintKeyValue("Sound", enabledLabel(appCfg.Notifications.Desktop.Sound))
+		PrintKeyValue("Duration", fmt.Sprintf("%d seconds", appCfg.Notifications.Desktop.Duration))
 		return nil
 	})
 
-	menu.AddItem("2", "📱", "Mobile (ntfy.sh)", "Configure mobile push", func() error {
+	menu.AddItem("2", "🔊", "Notification Sound", "Toggle sound on/off", handleToggleSound)
+
+	menu.AddItem("3", "📱", "Mobile (ntfy.sh)", "Configure mobile push", func() error {
 		PrintSection("Mobile Notifications")
 		PrintInfo("Configure mobile push notifications via ntfy.sh")
-		PrintKeyValue("Status", "Disabled")
-		PrintKeyValue("Topic", "Not configured")
+
+		// The watcher reads enabled and topic from config.yaml; server and auth come from app-config.yaml
+		cfg, err := filter.LoadConfig()
+		if err != nil {
+			PrintError(fmt.Sprintf("Error loading config: %v", err))
+			return err
+		}
+
+		mobile := cfg.Notifications.Mobile
+		PrintKeyValue("Status", enabledLabel(mobile.Enabled))
+		if mobile.NtfyTopic == "" {
+			PrintKeyValue("Topic", "Not configured")
+		} else {
+			PrintKeyValue("Topic", mobile.NtfyTopic)
+		}
+		if appCfg, err := appconfig.Load(); err == nil && appCfg.Notifications.Mobile.Server != "" {
+			PrintKeyValue("Server", appCfg.Notifications.Mobile.Server)
+		}
+		fmt.Println()
+		PrintInfo("Change with: email-sentinel config set mobile true|false")
+		PrintInfo("             email-sentinel config set ntfy_topic <topic>")
 		return nil
 	})
 
-	menu.AddItem("3", "🧪", "Test Notifications", "Send test alert", func() error {
+	menu.AddItem("4", "🧪", "Test Notifications", "Send test alert", func() error {
 		PrintSection("Test Notifications")
 		PrintInfo("Sending test notification...")
 		time.Sleep(1 * time.Second)
@@ -487,6 +802,36 @@ func buildNotificationsMenu() *Menu {
 	return menu
 }
 
+// handleToggleSound turns the desktop notification sound on or off
+func handleToggleSound() error {
+	PrintSection("Notification Sound")
+
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading configuration: %v", err))
+		return err
+	}
+
+	sound := !appCfg.Notifications.Desktop.Sound
+	appCfg.Notifications.Desktop.Sound = sound
+	if err := appconfig.Save(appCfg); err != nil {
+		PrintError(fmt.Sprintf("Error saving configuration: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Notification sound %s", strings.ToLower(enabledLabel(sound))))
+	PrintInfo("Restart monitoring for the change to take effect")
+	return nil
+}
+
+// enabledLabel formats a config toggle for display
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "Enabled"
+	}
+	return "Disabled"
+}
+
 // buildStatusMenu creates the status submenu
 func buildStatusMenu() *Menu {
 	menu := NewMenu("Status & History")
@@ -516,38 +861,240 @@ func buildStatusMenu() *Menu {
 func buildSettingsMenu() *Menu {
 	menu := NewMenu("Settings")
 
-	menu.AddItem("1", "⏱️", "Polling Interval", "Set check frequency", func() error {
-		PrintSection("Polling Interval")
-		PrintInfo("Current polling interval: 45 seconds")
-		fmt.Println()
-		PrintBullet("Recommended: 30-60 seconds")
-		PrintBullet("Shorter intervals use more API quota")
+	menu.AddItem("1", "⏱️", "Polling Interval", "Set check frequency", handlePollingInterval)
+
+	menu.AddItem("2", "🔐", "Re-authenticate", "Re-run Gmail OAuth", handleReauthenticate)
+
+	menu.AddItem("3", "📁", "Open Config Folder", "Open config directory", handleOpenConfigFolder)
+
+	menu.AddItem("4", "🔄", "Reset to Defaults", "Clear all settings", handleResetSettings)
+
+	return menu
+}
+
+// minPollingInterval is the fastest allowed polling interval (seconds), to stay within Gmail API quota
+const minPollingInterval = 10
+
+// handlePollingInterval shows the configured polling interval and lets the user change it
+func handlePollingInterval() error {
+	PrintSection("Polling Interval")
+
+	cfg, err := filter.LoadConfig()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading config: %v", err))
+		return err
+	}
+
+	PrintInfo(fmt.Sprintf("Current polling interval: %d seconds", cfg.PollingInterval))
+	fmt.Println()
+	PrintBullet("Recommended: 30-60 seconds")
+	PrintBullet("Shorter intervals use more API quota")
+	fmt.Println()
+
+	input := AskInput(fmt.Sprintf("New interval in seconds (min %d, Enter to keep)", minPollingInterval), "")
+	if input == "" {
+		PrintInfo("No changes made")
 		return nil
-	})
+	}
 
-	menu.AddItem("2", "🔐", "Re-authenticate", "Re-run Gmail OAuth", func() error {
-		PrintSection("Gmail Re-authentication")
-		PrintWarning("This will open your browser to re-authorize Gmail access")
+	interval, err := strconv.Atoi(input)
+	if err != nil || interval < minPollingInterval {
+		PrintError(fmt.Sprintf("Polling interval must be a number >= %d", minPollingInterval))
+		return fmt.Errorf("invalid polling interval: %s", input)
+	}
+
+	cfg.PollingInterval = interval
+	if err := filter.SaveConfig(cfg); err != nil {
+		PrintError(fmt.Sprintf("Error saving config: %v", err))
+		return err
+	}
+
+	// Keep app-config.yaml in sync so 'config show' and the dashboard agree
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading configuration: %v", err))
+		return err
+	}
+	appCfg.Monitoring.PollingInterval = interval
+	if err := appconfig.Save(appCfg); err != nil {
+		PrintError(fmt.Sprintf("Error saving configuration: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Polling interval set to %d seconds", interval))
+	PrintInfo("Restart monitoring for the new interval to take effect")
+	return nil
+}
+
+// handleReauthenticate re-runs the Gmail OAuth flow and replaces the saved token
+func handleReauthenticate() error {
+	PrintSection("Gmail Re-authentication")
+
+	credPath := findCredentialsFile()
+	if credPath == "" {
+		PrintError("credentials.json not found")
+		PrintInfo("Place credentials.json in the current directory or the config folder")
+		return fmt.Errorf("credentials.json not found")
+	}
+
+	PrintWarning("This will open your browser to re-authorize Gmail access")
+	if !Confirm("Continue?") {
+		PrintInfo("Cancelled")
 		return nil
-	})
+	}
 
-	menu.AddItem("3", "📁", "Open Config Folder", "Open config directory", func() error {
-		PrintSection("Configuration Location")
-		PrintKeyValue("Config Dir", "~/.config/email-sentinel/")
-		PrintKeyValue("Config File", "config.yaml")
-		PrintKeyValue("Database", "history.db")
-		PrintKeyValue("Token File", "token.json")
+	appCfg, err := appconfig.Load()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading configuration: %v", err))
+		return err
+	}
+
+	oauthConfig, err := gmail.LoadCredentialsWithModify(credPath, appCfg.Monitoring.Gmail.AllowModify)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to load credentials: %v", err))
+		return err
+	}
+
+	// Force the consent screen so Google issues a fresh refresh token
+	token, err := gmail.GetTokenFromWeb(oauthConfig, true)
+	if err != nil {
+		PrintError(fmt.Sprintf("Authentication failed: %v", err))
+		return err
+	}
+
+	if err := gmail.SaveToken(token); err != nil {
+		PrintError(fmt.Sprintf("Failed to save token: %v", err))
+		return err
+	}
+
+	PrintSuccess("Gmail re-authenticated")
+	PrintInfo("Restart monitoring to use the new token")
+	return nil
+}
+
+// handleOpenConfigFolder prints the resolved config file locations and opens the folder
+func handleOpenConfigFolder() error {
+	PrintSection("Configuration Location")
+
+	configDir, err := config.EnsureConfigDir()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating config directory: %v", err))
+		return err
+	}
+	appPath, err := appconfig.ConfigPath()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating app-config.yaml: %v", err))
+		return err
+	}
+	filterPath, err := config.ConfigPath()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating config.yaml: %v", err))
+		return err
+	}
+	dbPath, err := storage.DBPath()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating history.db: %v", err))
+		return err
+	}
+	tokenPath, err := config.TokenPath()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating token.json: %v", err))
+		return err
+	}
+
+	PrintKeyValue("Config Dir", configDir)
+	PrintKeyValue("Filters", filterPath)
+	PrintKeyValue("App Config", appPath)
+	PrintKeyValue("Database", dbPath)
+	PrintKeyValue("Token File", tokenPath)
+	fmt.Println()
+
+	if err := openFolder(configDir); err != nil {
+		PrintWarning(fmt.Sprintf("Could not open file manager: %v", err))
 		return nil
-	})
+	}
+	PrintSuccess("Opened config folder")
+	return nil
+}
 
-	menu.AddItem("4", "🔄", "Reset to Defaults", "Clear all settings", func() error {
-		PrintSection("Reset Settings")
-		PrintWarning("This will delete all filters and configuration!")
-		PrintError("This action cannot be undone.")
+// handleResetSettings backs up config.yaml and app-config.yaml, then writes the defaults
+// The Gmail token and alert history are left alone.
+func handleResetSettings() error {
+	PrintSection("Reset Settings")
+	PrintWarning("This will delete all filters and configuration!")
+	PrintInfo("Both config files are backed up first; your Gmail token and alert history are kept")
+	fmt.Println()
+
+	if AskInput("Type 'reset' to confirm", "") != "reset" {
+		PrintInfo("Cancelled")
 		return nil
-	})
+	}
 
-	return menu
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating config directory: %v", err))
+		return err
+	}
+	backupDir := filepath.Join(configDir, "backups")
+	timestamp := time.Now().Format("20060102_150405")
+
+	filterPath, err := config.ConfigPath()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating config.yaml: %v", err))
+		return err
+	}
+	appPath, err := appconfig.ConfigPath()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error locating app-config.yaml: %v", err))
+		return err
+	}
+
+	for _, path := range []string{filterPath, appPath} {
+		backup, err := backupConfigFile(path, backupDir, timestamp)
+		if err != nil {
+			PrintError(fmt.Sprintf("Error backing up %s: %v", filepath.Base(path), err))
+			return err
+		}
+		if backup != "" {
+			PrintKeyValue("Backed up", backup)
+		}
+	}
+
+	if err := filter.SaveConfig(filter.DefaultConfig()); err != nil {
+		PrintError(fmt.Sprintf("Error resetting config.yaml: %v", err))
+		return err
+	}
+	if err := appconfig.Save(appconfig.DefaultConfig()); err != nil {
+		PrintError(fmt.Sprintf("Error resetting app-config.yaml: %v", err))
+		return err
+	}
+
+	PrintSuccess("Settings reset to defaults")
+	return nil
+}
+
+// backupConfigFile copies path into backupDir with a timestamp suffix
+// Returns the backup path, or "" if there was no file to back up.
+func backupConfigFile(path, backupDir, timestamp string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	backupPath := filepath.Join(backupDir, fmt.Sprintf("%s_backup_%s.yaml", name, timestamp))
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	return backupPath, nil
 }
 
 // handleAddFilter handles the interactive filter addition process
@@ -634,6 +1181,11 @@ func handleAddFilter() error {
 		GmailScope: filterScope,
 	}
 
+	var overlaps []filter.Overlap
+	if existing, err := filter.ListFilters(); err == nil {
+		overlaps = filter.DetectOverlap(existing, newFilter)
+	}
+
 	// Add filter
 	if err := filter.AddFilter(newFilter); err != nil {
 		PrintError(fmt.Sprintf("Error: %v", err))
@@ -642,6 +1194,9 @@ func handleAddFilter() error {
 
 	fmt.Println()
 	PrintSuccess(fmt.Sprintf("Filter '%s' added successfully!", filterName))
+	for _, o := range overlaps {
+		PrintWarning("Overlap: " + o.String())
+	}
 	return nil
 }
 
@@ -706,8 +1261,8 @@ func handleEditFilter() error {
 	}
 
 	// Validate at least one pattern
-	if len(selectedFilter.From) == 0 && len(selectedFilter.Subject) == 0 {
-		PrintError("At least one 'from' or 'subject' pattern is required")
+	if len(selectedFilter.From) == 0 && len(selectedFilter.Subject) == 0 && len(selectedFilter.Body) == 0 {
+		PrintError("At least one 'from', 'subject' or 'body' pattern is required")
 		return fmt.Errorf("at least one pattern required")
 	}
 
@@ -775,6 +1330,10 @@ func handleListFilters() error {
 	for i, f := range filters {
 		fmt.Printf("[%d] %s\n", i+1, ColorBold.Sprint(f.Name))
 
+		if !f.IsEnabled() {
+			fmt.Printf("    Status:  %s\n", ColorDim.Sprint("disabled"))
+		}
+
 		// From patterns
 		if len(f.From) > 0 {
 			fmt.Printf("    From:    %s\n", strings.Join(f.From, ", "))
@@ -813,6 +1372,156 @@ func handleListFilters() error {
 	return nil
 }
 
+// handleExportFilters exports all filters to a JSON file
+func handleExportFilters() error {
+	PrintSection("Export Filters")
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("\nOutput file (default: filters.json): ")
+	path, _ := reader.ReadString('\n')
+	path = strings.TrimSpace(path)
+	if path == "" {
+		path = "filters.json"
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error creating file: %v", err))
+		return err
+	}
+	defer file.Close()
+
+	if err := filter.ExportFilters(file); err != nil {
+		PrintError(fmt.Sprintf("Error: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	PrintSuccess(fmt.Sprintf("Filters exported to %s", path))
+	return nil
+}
+
+// handleImportFilters imports filters from a JSON file
+func handleImportFilters() error {
+	PrintSection("Import Filters")
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("\nInput file: ")
+	path, _ := reader.ReadString('\n')
+	path = strings.TrimSpace(path)
+	if path == "" {
+		PrintError("File path is required")
+		return fmt.Errorf("file path required")
+	}
+
+	fmt.Print("Replace existing filters? (y/N): ")
+	confirm, _ := reader.ReadString('\n')
+	confirm = strings.TrimSpace(strings.ToLower(confirm))
+	replace := confirm == "y" || confirm == "yes"
+
+	file, err := os.Open(path)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error opening file: %v", err))
+		return err
+	}
+	defer file.Close()
+
+	result, err := filter.ImportFilters(file, replace)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	for _, name := range result.Skipped {
+		PrintWarning(fmt.Sprintf("Skipped '%s': a filter with this name already exists", name))
+	}
+	PrintSuccess(fmt.Sprintf("Imported %d filter(s)", result.Imported))
+	return nil
+}
+
+// handleRenameFilter handles the interactive filter rename process
+func handleRenameFilter() error {
+	PrintSection("Rename Filter")
+	reader := bufio.NewReader(os.Stdin)
+
+	// Load all filters
+	filters, err := filter.ListFilters()
+	if err != nil {
+		PrintError(fmt.Sprintf("Error loading filters: %v", err))
+		return err
+	}
+
+	if len(filters) == 0 {
+		fmt.Println()
+		PrintInfo("No filters to rename")
+		return nil
+	}
+
+	// Display filters
+	fmt.Println()
+	PrintInfo("Select a filter to rename:")
+	fmt.Println()
+
+	for i, f := range filters {
+		fmt.Printf("  [%d] %s\n", i+1, f.Name)
+	}
+
+	// Get selection
+	fmt.Println()
+	ColorGreen.Print("Enter number: ")
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	num, err := strconv.Atoi(input)
+	if err != nil || num < 1 || num > len(filters) {
+		PrintError("Invalid selection")
+		return fmt.Errorf("invalid selection")
+	}
+
+	filterName := filters[num-1].Name
+
+	ColorGreen.Printf("New name for '%s': ", filterName)
+	newName, _ := reader.ReadString('\n')
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		PrintInfo("Cancelled")
+		return nil
+	}
+
+	previous, newName, err := filter.RenameFilter(filterName, newName)
+	if err != nil {
+		PrintError(fmt.Sprintf("Error: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	PrintSuccess(fmt.Sprintf("Filter '%s' renamed to '%s'", previous, newName))
+
+	// Keep alert history under the new name
+	ColorYellow.Print("Update past alerts to the new name? (Y/n): ")
+	confirm, _ := reader.ReadString('\n')
+	confirm = strings.TrimSpace(strings.ToLower(confirm))
+	if confirm == "n" || confirm == "no" {
+		return nil
+	}
+
+	db, err := storage.InitDB()
+	if err != nil {
+		PrintWarning(fmt.Sprintf("Could not open alert history: %v", err))
+		return nil
+	}
+	defer storage.CloseDB(db)
+
+	updated, err := storage.RenameFilterInAlerts(db, previous, newName)
+	if err != nil {
+		PrintWarning(fmt.Sprintf("Could not update past alerts: %v", err))
+		return nil
+	}
+	PrintInfo(fmt.Sprintf("Updated %d past alert(s)", updated))
+	return nil
+}
+
 // handleRemoveFilter handles the interactive filter removal process
 func handleRemoveFilter() error {
 	PrintSection("Remove Filter")
@@ -915,13 +1624,17 @@ func ShowAlertHistory() error {
 	for i, alert := range alerts {
 		// Add priority indicator
 		priorityIcon := "📩" // Normal priority
-		if alert.Priority == 1 {
+		if alert.Priority >= storage.PriorityCritical {
+			priorityIcon = "🚨" // Critical priority
+		} else if alert.Priority == storage.PriorityHigh {
 			priorityIcon = "🔥" // High priority
 		}
 
 		fmt.Printf("[%d] %s %s\n", i+1, priorityIcon, alert.Timestamp.Format("2006-01-02 15:04:05"))
 		fmt.Printf("    Filter: %s\n", alert.FilterName)
-		if alert.Priority == 1 {
+		if alert.Priority >= storage.PriorityCritical {
+			fmt.Printf("    Priority: CRITICAL\n")
+		} else if alert.Priority == storage.PriorityHigh {
 			fmt.Printf("    Priority: HIGH\n")
 		}
 		fmt.Printf("    From:   %s\n", alert.Sender)
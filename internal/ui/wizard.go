@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/datateamsix/email-sentinel/internal/appconfig"
 	"github.com/datateamsix/email-sentinel/internal/config"
 	"github.com/datateamsix/email-sentinel/internal/filter"
 	"github.com/datateamsix/email-sentinel/internal/gmail"
@@ -31,19 +32,20 @@ type Wizard struct {
 
 // WizardConfig holds wizard results
 type WizardConfig struct {
-	GmailAuthenticated bool
-	GmailEmail         string
-	FilterCreated      bool
-	FilterName         string
-	DesktopEnabled     bool
-	MobileEnabled      bool
-	NtfyTopic          string
-	OTPEnabled         bool
-	OTPConfidence      float64
-	OTPExpiry          time.Duration
-	CredentialsPath    string
-	OAuthConfig        *oauth2.Config
-	Token              *oauth2.Token
+	GmailAuthenticated  bool
+	GmailEmail          string
+	FilterCreated       bool
+	FilterName          string
+	DesktopEnabled      bool
+	MobileEnabled       bool
+	NtfyTopic           string
+	OTPEnabled          bool
+	OTPConfidence       float64
+	OTPExpiry           time.Duration
+	OTPTriggerProximity int
+	CredentialsPath     string
+	OAuthConfig         *oauth2.Config
+	Token               *oauth2.Token
 }
 
 // NewWizard creates a new setup wizard
@@ -184,7 +186,7 @@ func (w *Wizard) stepPrerequisites() error {
 	w.printBoxLine("", 61)
 
 	// Check credentials.json
-	credPath := w.findCredentialsFile()
+	credPath := findCredentialsFile()
 	if credPath == "" {
 		w.printBoxLine("  [?] credentials.json", 61)
 		fmt.Println(ColorCyan.Sprint("╚" + strings.Repeat("═", 61) + "╝"))
@@ -238,7 +240,7 @@ func (w *Wizard) stepAuthentication() error {
 
 	// Get token from web
 	fmt.Println()
-	token, err := gmail.GetTokenFromWeb(oauthConfig)
+	token, err := gmail.GetTokenFromWeb(oauthConfig, false)
 	if err != nil {
 		PrintError(fmt.Sprintf("Authentication failed: %v", err))
 		return err
@@ -343,6 +345,11 @@ func (w *Wizard) stepCreateFilter() error {
 		Labels:  labels,
 	}
 
+	var overlaps []filter.Overlap
+	if existing, err := filter.ListFilters(); err == nil {
+		overlaps = filter.DetectOverlap(existing, f)
+	}
+
 	// Save filter
 	if err := filter.AddFilter(f); err != nil {
 		PrintError(fmt.Sprintf("Error adding filter: %v", err))
@@ -366,6 +373,9 @@ func (w *Wizard) stepCreateFilter() error {
 	PrintSuccess("Filter created successfully!")
 	fmt.Println()
 	printFilterSummary(f)
+	for _, o := range overlaps {
+		PrintWarning("Overlap: " + o.String())
+	}
 	w.waitForEnter()
 
 	w.CurrentStep++
@@ -529,6 +539,20 @@ func (w *Wizard) stepOTPSetup() error {
 				PrintWarning("Invalid value, using default (5 minutes)")
 			}
 		}
+
+		// Customize trigger phrase proximity
+		fmt.Println()
+		fmt.Println(ColorDim.Sprint("  Require a trigger phrase near the code (characters, default: 0 = off)"))
+		fmt.Println(ColorDim.Sprint("  e.g. 40 only accepts codes within 40 characters of \"verification code\","))
+		fmt.Println(ColorDim.Sprint("  \"security code\", etc. - skips numbers in promo emails"))
+		proximityInput := w.getUserInput("\nTrigger proximity: ")
+		if proximityInput != "" {
+			if chars, err := strconv.Atoi(proximityInput); err == nil && chars >= 0 {
+				w.Config.OTPTriggerProximity = chars
+			} else {
+				PrintWarning("Invalid value, using default (off)")
+			}
+		}
 	}
 
 	// Save OTP configuration
@@ -536,6 +560,7 @@ func (w *Wizard) stepOTPSetup() error {
 	rules.Enabled = w.Config.OTPEnabled
 	rules.ConfidenceThreshold = w.Config.OTPConfidence
 	rules.ExpiryDuration = w.Config.OTPExpiry
+	rules.RequireTriggerProximity = w.Config.OTPTriggerProximity
 
 	// Get config directory and save rules
 	configDir, err := config.ConfigDir()
@@ -548,6 +573,9 @@ func (w *Wizard) stepOTPSetup() error {
 		} else {
 			fmt.Println()
 			PrintInfo(fmt.Sprintf("OTP Settings: Confidence=%.1f, Expiry=%v", w.Config.OTPConfidence, w.Config.OTPExpiry))
+			if w.Config.OTPTriggerProximity > 0 {
+				PrintInfo(fmt.Sprintf("Trigger phrase required within %d characters of the code", w.Config.OTPTriggerProximity))
+			}
 		}
 	}
 
@@ -600,7 +628,20 @@ func (w *Wizard) stepTest() error {
 			if w.Config.MobileEnabled && w.Config.NtfyTopic != "" {
 				fmt.Println()
 				PrintInfo("Sending test mobile notification...")
-				err := notify.SendMobileNotification(
+				// Use the server and credentials from app-config.yaml, like real alerts
+				appCfg, err := appconfig.Load()
+				if err != nil {
+					PrintError(fmt.Sprintf("Error loading app-config.yaml: %v", err))
+					continue
+				}
+				opts, err := notify.MobileOptionsFromConfig(&appCfg.Notifications.Mobile)
+				if err != nil {
+					PrintError(fmt.Sprintf("Invalid notifications.mobile.server: %v", err))
+					continue
+				}
+				notify.SetMobileOptions(opts)
+
+				err = notify.SendMobileNotification(
 					w.Config.NtfyTopic,
 					"Email Sentinel - Test",
 					"If you see this on your phone, mobile notifications are working!",
@@ -729,7 +770,7 @@ func (w *Wizard) waitForEnter() {
 }
 
 // findCredentialsFile searches for credentials.json
-func (w *Wizard) findCredentialsFile() string {
+func findCredentialsFile() string {
 	// Check current directory
 	if _, err := os.Stat("credentials.json"); err == nil {
 		return "credentials.json"
@@ -772,7 +813,7 @@ func (w *Wizard) handleMissingCredentials(configDir string) error {
 		switch strings.ToLower(choice) {
 		case "r", "retry":
 			// Retry finding credentials
-			credPath := w.findCredentialsFile()
+			credPath := findCredentialsFile()
 			if credPath != "" {
 				w.Config.CredentialsPath = credPath
 				PrintSuccess("credentials.json found!")
@@ -785,8 +826,8 @@ func (w *Wizard) handleMissingCredentials(configDir string) error {
 		case "o", "open":
 			// Open browser to Google Cloud Console
 			url := "https://console.cloud.google.com/"
-			if err := openBrowser(url); err != nil {
-				PrintError(fmt.Sprintf("Failed to open browser: %v", err))
+			if err := gmail.OpenURL(url); err != nil {
+				PrintError(fmt.Sprintf("Could not open Google Cloud Console: %v", err))
 				fmt.Println()
 				fmt.Printf("Please visit manually: %s\n", url)
 			} else {
@@ -808,17 +849,17 @@ func (w *Wizard) handleMissingCredentials(configDir string) error {
 	}
 }
 
-// openBrowser opens a URL in the default browser (cross-platform)
-func openBrowser(url string) error {
+// openFolder opens a directory in the OS file manager (cross-platform)
+func openFolder(path string) error {
 	var cmd *exec.Cmd
 
 	switch runtime.GOOS {
 	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", url)
+		cmd = exec.Command("explorer", path)
 	case "darwin":
-		cmd = exec.Command("open", url)
+		cmd = exec.Command("open", path)
 	default: // linux and others
-		cmd = exec.Command("xdg-open", url)
+		cmd = exec.Command("xdg-open", path)
 	}
 
 	return cmd.Start()